@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// errDraining fails MCP requests arriving once the server is shutting down.
+var errDraining = errors.New("server is shutting down")
+
+// drainer tracks the MCP requests in flight on any transport so shutdown can let them finish,
+// like tool calls converting large attachments, instead of cutting them off.
+type drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{}
+}
+
+// middleware refuses requests once draining started and counts the others while they run.
+// Notifications pass, so clients can still cancel the requests being drained.
+func (d *drainer) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if strings.HasPrefix(method, "notifications/") {
+			return next(ctx, method, req)
+		}
+
+		d.mu.Lock()
+		if d.draining {
+			d.mu.Unlock()
+			return nil, errDraining
+		}
+		d.inFlight++
+		d.mu.Unlock()
+
+		defer func() {
+			d.mu.Lock()
+			d.inFlight--
+			if d.inFlight == 0 && d.idle != nil {
+				close(d.idle)
+				d.idle = nil
+			}
+			d.mu.Unlock()
+		}()
+		return next(ctx, method, req)
+	}
+}
+
+// drain refuses new requests and waits up to timeout for those in flight to finish.
+func (d *drainer) drain(timeout time.Duration) {
+	d.mu.Lock()
+	d.draining = true
+	if d.inFlight == 0 {
+		d.mu.Unlock()
+		return
+	}
+	idle := make(chan struct{})
+	d.idle = idle
+	log.Printf("Waiting up to %s for %d MCP requests in flight", timeout, d.inFlight)
+	d.mu.Unlock()
+
+	select {
+	case <-idle:
+		log.Println("MCP requests in flight finished")
+	case <-time.After(timeout):
+		d.mu.Lock()
+		log.Printf("Drain timeout passed, cutting off %d MCP requests", d.inFlight)
+		d.mu.Unlock()
+	}
+}
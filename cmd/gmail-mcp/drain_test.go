@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHandler returns a handler that signals started and waits for release.
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) mcp.MethodHandler {
+	return func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		started <- struct{}{}
+		<-release
+		return nil, nil
+	}
+}
+
+func noopHandler(context.Context, string, mcp.Request) (mcp.Result, error) {
+	return nil, nil
+}
+
+func TestDrainerRefusesWhileDraining(t *testing.T) {
+	d := &drainer{}
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := d.middleware(blockingHandler(started, release))
+
+	callDone := make(chan error, 1)
+	go func() {
+		_, err := handler(t.Context(), "tools/call", nil)
+		callDone <- err
+	}()
+	<-started
+
+	drained := make(chan struct{})
+	go func() {
+		d.drain(time.Minute)
+		close(drained)
+	}()
+	require.Eventually(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.draining
+	}, time.Second, time.Millisecond)
+
+	_, err := d.middleware(noopHandler)(t.Context(), "tools/list", nil)
+	require.ErrorIs(t, err, errDraining)
+	_, err = d.middleware(noopHandler)(t.Context(), "notifications/cancelled", nil)
+	require.NoError(t, err, "notifications should pass while draining")
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned with a request in flight")
+	default:
+	}
+
+	close(release)
+	require.NoError(t, <-callDone)
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drain didn't return once the request finished")
+	}
+}
+
+func TestDrainerTimeout(t *testing.T) {
+	d := &drainer{}
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	handler := d.middleware(blockingHandler(started, release))
+
+	go func() { _, _ = handler(t.Context(), "tools/call", nil) }()
+	<-started
+
+	begin := time.Now()
+	d.drain(50 * time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(begin), 50*time.Millisecond)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	assert.Equal(t, 1, d.inFlight, "the request cut off should still be in flight")
+}
+
+func TestDrainerIdle(t *testing.T) {
+	d := &drainer{}
+	done := make(chan struct{})
+	go func() {
+		d.drain(time.Minute)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("drain without requests in flight should return at once")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// logRequests logs the method, path, status and duration of every HTTP request. Query strings
+// carry OAuth codes, states and push tokens and are left out.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("http method=%s path=%s status=%d duration=%s remote=%s",
+			r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond), r.RemoteAddr)
+	})
+}
+
+// statusRecorder keeps the status of a response. It flushes and unwraps to the underlying writer,
+// as streamed MCP responses need.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -3,6 +3,8 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"flag"
 	"fmt"
@@ -10,23 +12,36 @@ import (
 	"log"
 	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"os/exec"
 	"os/signal"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
-	"google.golang.org/api/gmail/v1"
-
-	"github.com/hal9000y/gmail-mcp/internal/auth"
-	"github.com/hal9000y/gmail-mcp/internal/format"
-	"github.com/hal9000y/gmail-mcp/internal/gservice"
-	"github.com/hal9000y/gmail-mcp/internal/tool"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+
+	"github.com/hal9000y/gmail-mcp/internal/push"
+	"github.com/hal9000y/gmail-mcp/internal/ratelimit"
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+	"github.com/hal9000y/gmail-mcp/pkg/diskcache"
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/mailsync"
+	"github.com/hal9000y/gmail-mcp/pkg/redact"
+	"github.com/hal9000y/gmail-mcp/pkg/savedsearch"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+	"github.com/hal9000y/gmail-mcp/pkg/watermark"
 )
 
 func main() {
@@ -36,62 +51,452 @@ func main() {
 	envFileParam := flag.String("env-file", "", "Path to env file")
 	enableStdio := flag.Bool("stdio", false, "Enable stdio transport for MCP (disables stdout logging)")
 	logFile := flag.String("log-file", "", "Path to log file (only used with stdio transport, otherwise logs to stdout)")
+	deviceFlow := flag.Bool("device-flow", false, "Authorize missing tokens with the OAuth device flow, logging a code and verification URL to open on any device instead of opening a local browser; needs a \"TVs and Limited Input devices\" OAuth client")
+	noBrowser := flag.Bool("no-browser", false, "Log the Google authorization URL of missing tokens instead of opening a browser, for servers and containers; /oauth?redirect=1 and the auth_status tool keep offering it")
+	tokenStore := flag.String("token-store", "file", "Where OAuth tokens are kept: file (-oauth-token-file) or keyring (macOS keychain, Secret Service through secret-tool, Windows Credential Manager)")
+	accountsParam := flag.String("accounts", "", "Comma-separated name=token-file pairs of Gmail accounts tools select with their account parameter, the first is the default; replaces -oauth-token-file. Names alone with -token-store=keyring")
+	multiUser := flag.Bool("multi-user", false, "Serve several people: each authorizes at /oauth?redirect=1 and gets a bearer key /mcp requests act on their own mailbox with; excludes -accounts, -mcp-resource, -mailbox, -stdio, -watch, -sync-interval and -incremental-consent")
+	logRequestsParam := flag.Bool("log-requests", false, "Log HTTP requests and MCP tool calls (method, path, tool, argument names, status, duration) without queries, tokens or mail content")
+	rateLimit := flag.Float64("rate-limit", 0, "Requests per second each IP, and each verified bearer token of -multi-user or -mcp-resource, may send to /mcp, refused with 429 above it; 0 for no limit")
+	rateLimitBurst := flag.Int("rate-limit-burst", 20, "Requests a client may send to /mcp at once before -rate-limit applies")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "How long the HTTP server waits for responses being written, like slow clients reading results, before closing their connections on shutdown")
+	tokenPersistInterval := flag.Duration("token-persist-interval", 5*time.Minute, "How often refreshed OAuth tokens are saved to their store while running, so a crash doesn't lose them; 0 to save them at exit only")
+	drainTimeout := flag.Duration("drain-timeout", 30*time.Second, "How long shutdown waits for MCP requests in flight, like long conversions, to finish while refusing new ones")
+	tlsCert := flag.String("tls-cert", "", "PEM certificate file to serve HTTPS with, together with -tls-key")
+	tlsKey := flag.String("tls-key", "", "PEM private key file of -tls-cert")
+	tlsClientCA := flag.String("tls-client-ca", "", "PEM bundle of the CAs whose client certificates may reach /mcp (mutual TLS), requires -tls-cert; /oauth stays reachable by browsers without one")
+	mcpResource := flag.String("mcp-resource", "", "Public URL of the /mcp endpoint, e.g. https://mcp.example.com/mcp; with it /mcp requires access tokens of -mcp-auth-issuer as the MCP authorization spec describes")
+	mcpAuthIssuer := flag.String("mcp-auth-issuer", "", "Issuer URL of the authorization server MCP clients get access tokens for -mcp-resource from")
+	mcpAuthIntrospection := flag.String("mcp-auth-introspection-url", "", "Token introspection endpoint of -mcp-auth-issuer, called with MCP_AUTH_CLIENT_ID and MCP_AUTH_CLIENT_SECRET")
+	mcpAuthScopes := flag.String("mcp-auth-scopes", "", "Comma-separated scopes access tokens for -mcp-resource must have")
+	usersDir := flag.String("users-dir", "./data/users", "Directory keeping the tokens of -multi-user users with -token-store=file, empty to keep them in memory")
+	mailbox := flag.String("mailbox", "", "Address of a mailbox delegated to the authorized account to read and change instead of its own, e.g. a shared inbox; excludes -accounts")
+	exportDir := flag.String("export-dir", "", "Directory export tools may write into, empty to disable saving exports")
+	filesDir := flag.String("files-dir", "", "Directory attachments may be saved into, empty to disable saving attachments")
+	savedSearchesFile := flag.String("saved-searches-file", "./data/saved-searches.json", "Path to store saved searches, empty to keep them in memory")
+	maxAttachmentBytes := flag.Int64("max-attachment-bytes", 10<<20, "Largest attachment preview_attachments downloads, 0 for no limit")
+	maxPDFPages := flag.Int("max-pdf-pages", 20, "PDF pages preview_attachments extracts per call, 0 for no limit")
+	timezone := flag.String("timezone", "", "IANA timezone search dates are resolved in, e.g. Europe/Berlin, empty for the system timezone")
+	watermarksFile := flag.String("watermarks-file", "./data/watermarks.json", "Path to store check_new_mail watermarks, empty to keep them in memory")
+	noExternalTools := flag.Bool("no-external-tools", false, "Never run pandoc or pdftotext, convert HTML and PDF with the built-in converters only")
+	markdownDialect := flag.String("markdown-dialect", "commonmark", "Format pandoc converts HTML and DOCX to: commonmark, gfm or plain")
+	pandocPath := flag.String("pandoc-path", "", "Path to the pandoc binary, empty to look it up on PATH")
+	pdfToTextPath := flag.String("pdftotext-path", "", "Path to the pdftotext binary, empty to look it up on PATH")
+	conversionTimeout := flag.Duration("conversion-timeout", 30*time.Second, "Longest a pandoc or pdftotext run may take before it is killed, 0 for no limit")
+	conversionCPUTime := flag.Duration("conversion-cpu-time", 20*time.Second, "CPU time a pandoc or pdftotext run may use, 0 for no limit")
+	conversionMaxMemory := flag.Int64("conversion-max-memory", 1<<30, "Memory in bytes a pandoc or pdftotext run may allocate, 0 for no limit")
+	conversionMaxOutput := flag.Int64("conversion-max-output", 32<<20, "Output in bytes read from a pandoc or pdftotext run before it is killed, 0 for no limit")
+	conversionCache := flag.Int("conversion-cache-entries", 256, "HTML to markdown conversions kept in memory for repeated fetches of a message, 0 to disable")
+	conversionNetwork := flag.Bool("conversion-network", false, "Let pandoc and pdftotext use the network, by default they run without it where Linux namespaces allow")
+	guardUntrusted := flag.Bool("guard-untrusted-content", false, "Wrap message bodies and attachment content in untrusted-content delimiters and neutralize instruction-like phrases")
+	redactKinds := flag.String("redact", "", "Comma-separated personal data to mask in bodies, snippets and attachment content: email, phone, card, iban")
+	var redactPatterns []string
+	flag.Func("redact-pattern", "Regular expression whose matches are masked like -redact kinds, may be repeated", func(p string) error {
+		redactPatterns = append(redactPatterns, p)
+		return nil
+	})
+	messageWorkers := flag.Int("message-workers", 4, "Messages get_messages converts at once")
+	messageCacheEntries := flag.Int("message-cache-entries", 500, "Messages kept in memory so a search, get and preview of one message fetch it once, 0 to disable")
+	messageCacheTTL := flag.Duration("message-cache-ttl", 5*time.Minute, "How long messages are kept in memory, labels like UNREAD may be this old")
+	apiRetries := flag.Int("api-retries", 4, "Times a Gmail API call failing with 429, 5xx or a network error is retried, 0 to disable")
+	apiRetryDelay := flag.Duration("api-retry-delay", 500*time.Millisecond, "Delay before the first retry of a Gmail API call, doubling with every retry")
+	apiQuota := flag.Float64("api-quota", 200, "Gmail API quota units spent per second at most, calls wait for the budget instead of being throttled, 0 for no limit")
+	syncFile := flag.String("sync-file", "", "Path to store the mailbox snapshot sync_mailbox maintains, empty to keep it in memory")
+	syncInterval := flag.Duration("sync-interval", 0, "How often the mailbox snapshot is synced in the background, 0 to sync only through sync_mailbox")
+	syncMaxMessages := flag.Int("sync-max-messages", mailsync.DefaultMaxMessages, "Newest messages the mailbox snapshot keeps")
+	watch := flag.Bool("watch", false, "Register Gmail push notifications to -watch-topic and notify subscribed MCP sessions of new mail")
+	watchTopic := flag.String("watch-topic", "", "Pub/Sub topic Gmail publishes mailbox changes to, e.g. projects/my-project/topics/gmail")
+	watchToken := flag.String("watch-token", "", "Secret the push subscription passes in the token query parameter of /pubsub/gmail")
+	watchLabels := flag.String("watch-labels", "INBOX", "Comma-separated label IDs whose changes are pushed")
+	modeParam := flag.String("mode", "", "What the server may do in the mailbox, setting the Gmail scopes requested and the tools registered: readonly, modify (labels, archive, mute) or full (https://mail.google.com/); default readonly, or what -scopes allows")
+	incrementalConsent := flag.Bool("incremental-consent", false, "Request read-only scopes only and ask for the scope of a tool changing labels, threads or events when it is first used, through elicitation or the auth_url of its error")
+	scopesParam := flag.String("scopes", "", "Comma-separated Gmail OAuth scopes to request instead of those of -mode, tools are registered for the mode they allow")
+	enableCalendar := flag.Bool("calendar", false, "Request the Google Calendar events scope and add the list_events and create_event tools; an existing token must be authorized again")
+	enableDrive := flag.Bool("drive", false, "Request the Google Drive read-only scope and add the preview_drive_files tool for Drive-linked attachments; an existing token must be authorized again")
+	cacheDir := flag.String("cache-dir", "", "Directory caching messages, converted bodies and attachment text across runs, empty to disable")
+	cacheMessageTTL := flag.Duration("cache-message-ttl", time.Hour, "How long cached messages are used, labels like UNREAD may be this old")
+	cacheConversionTTL := flag.Duration("cache-conversion-ttl", 30*24*time.Hour, "How long cached body and attachment conversions are used")
+	pandocArgs := flag.String("pandoc-args", "", "Extra space-separated arguments passed to pandoc, e.g. \"--columns=100\"")
 
 	flag.Parse()
 
 	persistLogs := setupLogger(enableStdio, logFile)
 	defer persistLogs()
 
+	// Clearing stored tokens needs neither the listener nor the OAuth client credentials.
+	if flag.Arg(0) == "logout" {
+		accountNames, toks := mustLoadTokens(&oauth2.Config{}, *tokenStore, *accountsParam, *oauthTokenFile)
+		logout(accountNames, toks, flag.Args()[1:])
+		return
+	}
+
 	ln := mustListen(httpAddr)
-	config := mustCreateOauthCfg(ln.Addr().String(), envFileParam, oauthURLParam)
+	mode, scopes := mustParseScopes(*modeParam, *scopesParam)
+	if *incrementalConsent {
+		scopes = tool.ModeReadonly.Scopes()
+	}
+	if *enableCalendar && mode.Allows(tool.ModeModify) && !*incrementalConsent {
+		scopes = append(scopes, calendar.CalendarEventsScope)
+	} else if *enableCalendar {
+		scopes = append(scopes, calendar.CalendarEventsReadonlyScope)
+	}
+	if *enableDrive {
+		scopes = append(scopes, drive.DriveReadonlyScope)
+	}
+	tlsConfig := mustTLSConfig(*tlsCert, *tlsKey, *tlsClientCA)
+	baseURL := "http://" + ln.Addr().String()
+	if tlsConfig != nil {
+		baseURL = "https://" + ln.Addr().String()
+	}
+	config := mustCreateOauthCfg(baseURL, envFileParam, oauthURLParam, scopes)
 
 	if oauthTokenFile == nil {
 		panic("-oauth-token-file must be provided")
 	}
-	tok, err := auth.NewToken(config, *oauthTokenFile)
-	if err != nil {
-		panic(fmt.Errorf("auth.NewToken failed: %w", err))
+	if *multiUser && (*accountsParam != "" || *mcpResource != "" || *mailbox != "" || *enableStdio || *watch || *syncInterval > 0 || *incrementalConsent) {
+		panic("-multi-user excludes -accounts, -mcp-resource, -mailbox, -stdio, -watch, -sync-interval and -incremental-consent")
+	}
+	if *mailbox != "" && *accountsParam != "" {
+		panic("-mailbox excludes -accounts, it would apply to the default account only")
+	}
+	// A multi-user server has no token of its own, each user brings theirs.
+	emptyTok, _ := auth.NewStoreToken(config, nil) // fails only loading from a store
+	accountNames, toks := []string{""}, map[string]*auth.Token{"": emptyTok}
+	if !*multiUser {
+		accountNames, toks = mustLoadTokens(config, *tokenStore, *accountsParam, *oauthTokenFile)
+	}
+	tok := toks[accountNames[0]]
+
+	authHTTP := auth.NewHTTPHandler(tok)
+	if *accountsParam != "" {
+		authHTTP = auth.NewAccountsHTTPHandler(accountNames, toks)
 	}
 
+	var users *auth.Users
+	if *multiUser {
+		users = auth.NewUsers(config, mustUserStore(*tokenStore, *usersDir))
+	}
 	defer func() {
-		log.Println("Persisting token if exists")
-		if err := tok.Persist(); err != nil {
-			log.Println(fmt.Errorf("tok.Persist failed: %w", err))
-		}
+		log.Println("Persisting tokens if exist")
+		persistTokens(accountNames, toks, users)
 	}()
-
-	authHTTP := auth.NewHTTPHandler(tok)
+	if *tokenPersistInterval > 0 {
+		defer runInBackground(func(ctx context.Context) {
+			ticker := time.NewTicker(*tokenPersistInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					persistTokens(accountNames, toks, users)
+				}
+			}
+		}, "Token persistence")()
+	}
 
 	mux := http.NewServeMux()
-	mux.Handle("/oauth", authHTTP)
+	if users != nil {
+		mux.Handle("/oauth", users)
+	} else {
+		mux.Handle("/oauth", authHTTP)
+	}
 
-	gmailSvc := gservice.NewGmail(config, tok)
-	gmailT := tool.NewServer(gmailSvc, &format.Converter{})
-	mcpHTTP := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return gmailT }, nil)
+	savedSearches, err := savedsearch.NewStore(*savedSearchesFile)
+	if err != nil {
+		panic(fmt.Errorf("savedsearch.NewStore failed: %w", err))
+	}
+
+	watermarks, err := watermark.NewStore(*watermarksFile)
+	if err != nil {
+		panic(fmt.Errorf("watermark.NewStore failed: %w", err))
+	}
+
+	// time.LoadLocation treats an empty name as UTC, the flag means the system timezone.
+	loc := time.Local
+	if *timezone != "" {
+		if loc, err = time.LoadLocation(*timezone); err != nil {
+			panic(fmt.Errorf("time.LoadLocation failed: %w", err))
+		}
+	}
+
+	dialect, err := format.ParseMarkdownDialect(*markdownDialect)
+	if err != nil {
+		panic(fmt.Errorf("format.ParseMarkdownDialect failed: %w", err))
+	}
+
+	redactor, err := redact.New(strings.Split(*redactKinds, ","), redactPatterns)
+	if err != nil {
+		panic(fmt.Errorf("redact.New failed: %w", err))
+	}
+
+	var cache *diskcache.Cache
+	if *cacheDir != "" {
+		if cache, err = diskcache.New(*cacheDir); err != nil {
+			panic(fmt.Errorf("diskcache.New failed: %w", err))
+		}
+		if removed, err := cache.Prune(); err != nil {
+			log.Println(fmt.Errorf("cache.Prune failed: %w", err))
+		} else if removed > 0 {
+			log.Printf("Removed %d expired cache entries", removed)
+		}
+	}
+
+	calls := &callStats{}
+	newGmail := func(tok *auth.Token, opts ...gservice.Option) *gservice.GMail {
+		return gservice.NewGmail(config, tok, append([]gservice.Option{
+			gservice.WithMessageCache(*messageCacheEntries, *messageCacheTTL),
+			gservice.WithDiskCache(cache, *cacheMessageTTL),
+			gservice.WithRetry(*apiRetries, *apiRetryDelay),
+			gservice.WithQuota(*apiQuota),
+			gservice.WithMetrics(calls),
+		}, opts...)...)
+	}
+	gmailSvc := newGmail(tok, gservice.WithMailbox(strings.TrimSpace(*mailbox)))
+
+	accountTools := tool.WithAccounts(nil)
+	if *accountsParam != "" {
+		accounts := []tool.Account{{Name: accountNames[0], Svc: gmailSvc}}
+		for _, name := range accountNames[1:] {
+			accounts = append(accounts, tool.Account{Name: name, Svc: newGmail(toks[name])})
+		}
+		router, err := tool.NewAccounts(accounts...)
+		if err != nil {
+			panic(fmt.Errorf("tool.NewAccounts failed: %w", err))
+		}
+		accountTools = tool.WithAccounts(router)
+	}
+	syncStore, err := mailsync.NewStore(*syncFile)
+	if err != nil {
+		panic(fmt.Errorf("mailsync.NewStore failed: %w", err))
+	}
+	syncEngine := mailsync.NewEngine(gmailSvc, syncStore, *syncMaxMessages)
+
+	var mailboxEvents *tool.MailboxEvents
+	if *watch {
+		if *watchTopic == "" || *watchToken == "" {
+			panic("-watch requires -watch-topic and -watch-token")
+		}
+		mailboxEvents = tool.NewMailboxEvents()
+		mux.Handle("/pubsub/gmail", push.NewHandler(*watchToken, func(ctx context.Context, n push.Notification) {
+			mailboxEvents.Publish(ctx, n.EmailAddress, n.HistoryID)
+		}))
+	}
+
+	calendarTools := tool.WithCalendar(nil)
+	if *enableCalendar {
+		calendarTools = tool.WithCalendar(gmailSvc)
+	}
+	driveTools := tool.WithDrive(nil)
+	if *enableDrive {
+		driveTools = tool.WithDrive(gmailSvc)
+	}
+
+	cnv := &format.Converter{
+		NoExternalTools: *noExternalTools,
+		Dialect:         dialect,
+		PandocArgs:      strings.Fields(*pandocArgs),
+		PandocPath:      *pandocPath,
+		PdfToTextPath:   *pdfToTextPath,
+		Timeout:         *conversionTimeout,
+		Limits: format.ToolLimits{
+			CPUTime:        *conversionCPUTime,
+			MaxMemoryBytes: *conversionMaxMemory,
+			MaxOutputBytes: *conversionMaxOutput,
+			NoNetwork:      !*conversionNetwork,
+		},
+		Cache:    cache,
+		CacheTTL: *cacheConversionTTL,
+	}
+	consentTools := tool.WithIncrementalConsent(nil)
+	if *incrementalConsent {
+		consentTools = tool.WithIncrementalConsent(func(account string, scopes []string) string {
+			if account == "" {
+				account = accountNames[0]
+			}
+			return authURL(config.RedirectURL, account) + "&scope=" + neturl.QueryEscape(strings.Join(scopes, " "))
+		})
+	}
+	toolOpts := []tool.Option{
+		tool.WithTimezone(loc),
+		tool.WithMaxAttachmentBytes(*maxAttachmentBytes),
+		tool.WithMaxPDFPages(*maxPDFPages),
+		tool.WithUntrustedContentGuard(*guardUntrusted),
+		tool.WithRedactor(redactor),
+		tool.WithConversionCache(*conversionCache),
+		tool.WithMessageWorkers(*messageWorkers),
+		tool.WithMode(mode),
+	}
+	if *logRequestsParam {
+		toolOpts = append(toolOpts, tool.WithCallLog(log.Default()))
+	}
+
+	gmailT := tool.NewServer(gmailSvc, cnv, append(toolOpts,
+		tool.WithExportDir(*exportDir),
+		tool.WithFilesDir(*filesDir),
+		tool.WithSavedSearches(savedSearches),
+		tool.WithWatermarks(watermarks),
+		tool.WithMailboxSync(syncEngine),
+		tool.WithMailboxEvents(mailboxEvents),
+		calendarTools,
+		driveTools,
+		accountTools,
+		tool.WithReauthorization(func(account string) string {
+			if account == "" {
+				account = accountNames[0]
+			}
+			return authURL(config.RedirectURL, account)
+		}),
+		tool.WithClearCredentials(func(account string) error {
+			if account == "" {
+				account = accountNames[0]
+			}
+			return toks[account].Clear()
+		}),
+		tool.WithAuthStatus(func(account string) tool.AuthStatusResponse {
+			if account == "" {
+				account = accountNames[0]
+			}
+			return authStatus(toks[account], config.RedirectURL, account)
+		}),
+		consentTools,
+	)...)
+	drain := &drainer{}
+	gmailT.AddReceivingMiddleware(drain.middleware)
+	// Limited per IP before authentication, so unverified tokens get no buckets of their own, and
+	// per token after it.
+	limitIP, limitToken := func(h http.Handler) http.Handler { return h }, func(h http.Handler) http.Handler { return h }
+	if *rateLimit > 0 {
+		limitIP = ratelimit.New(*rateLimit, *rateLimitBurst).Middleware(ratelimit.RemoteIP)
+		limitToken = ratelimit.New(*rateLimit, *rateLimitBurst).Middleware(ratelimit.BearerToken)
+	}
+	var mcpHTTP http.Handler = mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return gmailT }, nil)
+
+	if users != nil {
+		// Users share the conversion cache, keyed by content, but not the message cache keyed by
+		// message ID, nor stores and directories.
+		mcpHTTP = users.Authenticate(limitToken(&userServers{newServer: func(id string, tok *auth.Token) *mcp.Server {
+			svc := gservice.NewGmail(config, tok,
+				gservice.WithMessageCache(*messageCacheEntries, *messageCacheTTL),
+				gservice.WithRetry(*apiRetries, *apiRetryDelay),
+				gservice.WithQuota(*apiQuota),
+				gservice.WithMetrics(calls),
+			)
+			opts := append(slices.Clone(toolOpts),
+				tool.WithExportDir(userDir(*exportDir, id)),
+				tool.WithFilesDir(userDir(*filesDir, id)),
+			)
+			opts = append(opts,
+				tool.WithClearCredentials(func(_ string) error { return users.Clear(id) }),
+				tool.WithAuthStatus(func(_ string) tool.AuthStatusResponse { return authStatus(tok, config.RedirectURL, "") }),
+			)
+			if *enableCalendar {
+				opts = append(opts, tool.WithCalendar(svc))
+			}
+			if *enableDrive {
+				opts = append(opts, tool.WithDrive(svc))
+			}
+			server := tool.NewServer(svc, cnv, opts...)
+			server.AddReceivingMiddleware(drain.middleware)
+			return server
+		}}))
+	}
+
+	// The status page passes the checks of /mcp other than those of -multi-user users.
+	protectStatus := func(h http.Handler) http.Handler { return h }
+	if *mcpResource != "" {
+		protect := mustProtectResource(mux, *mcpResource, *mcpAuthIssuer, *mcpAuthIntrospection, *mcpAuthScopes)
+		mcpHTTP = protect(limitToken(mcpHTTP))
+		protectStatus = protect
+	}
+
+	if *tlsClientCA != "" {
+		mcpHTTP = requireClientCert(mcpHTTP)
+		next := protectStatus
+		protectStatus = func(h http.Handler) http.Handler { return requireClientCert(next(h)) }
+	}
+	// Limited first, before verifying tokens or certificates costs anything.
+	mcpHTTP = limitIP(mcpHTTP)
 
 	mux.Handle("/mcp", mcpHTTP)
 
+	tools, err := listTools(context.Background(), gmailT)
+	if err != nil {
+		log.Println(fmt.Errorf("listTools failed: %w", err))
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	transports := []string{fmt.Sprintf("Streamable HTTP at %s://%s/mcp", scheme, ln.Addr())}
+	if *enableStdio {
+		transports = append(transports, "stdio")
+	}
+	if statusAllowed(ln.Addr(), *multiUser, *mcpResource != "" || *tlsClientCA != "") {
+		mux.Handle("GET /{$}", limitIP(protectStatus(&statusPage{
+			started:     time.Now(),
+			accounts:    accountNames,
+			toks:        toks,
+			redirectURL: config.RedirectURL,
+			multiUser:   *multiUser,
+			transports:  transports,
+			tools:       tools,
+			server:      gmailT,
+			gmail:       gmailSvc,
+			calls:       calls,
+		})))
+	} else {
+		log.Println("Status page disabled: it is served on loopback addresses, or without -multi-user behind -tls-client-ca or -mcp-resource")
+	}
+
+	var handler http.Handler = mux
+	if *logRequestsParam {
+		handler = logRequests(mux)
+	}
 	srv := &http.Server{
-		Handler: mux,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
 	}
 
 	shutdown := make(chan os.Signal, 1)
 
 	signal.Notify(shutdown, syscall.SIGTERM, syscall.SIGINT)
 
-	if _, err := tok.OAuthToken(); errors.Is(err, auth.ErrTokenNotSet) {
-		openBrowser(config.RedirectURL)
+	for _, name := range accountNames {
+		if _, err := toks[name].OAuthToken(); *multiUser || !errors.Is(err, auth.ErrTokenNotSet) {
+			continue
+		}
+		if *deviceFlow {
+			defer runInBackground(func(ctx context.Context) { authorizeDevice(ctx, toks[name], name) }, "Device authorization")()
+			continue
+		}
+		if *noBrowser {
+			logAuthURL(toks[name], config.RedirectURL, name)
+			continue
+		}
+		openBrowser(config.RedirectURL, name)
 	}
 
-	stopHTTP, errHTTPCh := serveHTTP(srv, ln)
+	stopHTTP, errHTTPCh := serveHTTP(srv, ln, *shutdownTimeout)
 	defer stopHTTP()
 
+	if *watch {
+		watcher := push.NewWatcher(gmailSvc, *watchTopic, strings.Split(*watchLabels, ","))
+		defer runInBackground(watcher.Run, "Gmail push notifications")()
+	}
+
+	if *syncInterval > 0 {
+		defer runInBackground(func(ctx context.Context) { syncEngine.Run(ctx, *syncInterval) }, "Mailbox sync")()
+	}
+
 	var errStdioCh <-chan error
 	if *enableStdio {
 		var stopStdio func()
 		stopStdio, errStdioCh = serveStdio(gmailT)
 		defer stopStdio()
 	}
+	// Runs first on return, before the transports stop.
+	defer drain.drain(*drainTimeout)
 
 	select {
 	case err := <-errHTTPCh:
@@ -103,6 +508,40 @@ func main() {
 	}
 }
 
+// persistTokens saves the tokens of the accounts and of the -multi-user users, if any, that
+// changed since they were last saved.
+func persistTokens(accountNames []string, toks map[string]*auth.Token, users *auth.Users) {
+	for _, name := range accountNames {
+		if err := toks[name].Persist(); err != nil {
+			log.Println(fmt.Errorf("tok.Persist failed: %w", err))
+		}
+	}
+	if users == nil {
+		return
+	}
+	if err := users.Persist(); err != nil {
+		log.Println(fmt.Errorf("users.Persist failed: %w", err))
+	}
+}
+
+// runInBackground starts run and returns a func canceling it and waiting for it to return.
+func runInBackground(run func(ctx context.Context), name string) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.Println("Starting", name)
+		run(ctx)
+	}()
+
+	return func() {
+		cancel()
+
+		<-done
+		log.Println(name, "stopped")
+	}
+}
+
 func serveStdio(srv *mcp.Server) (func(), <-chan error) {
 	errStdioCh := make(chan error, 1)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -124,14 +563,19 @@ func serveStdio(srv *mcp.Server) (func(), <-chan error) {
 	}, errStdioCh
 }
 
-func serveHTTP(srv *http.Server, ln net.Listener) (func(), <-chan error) {
+func serveHTTP(srv *http.Server, ln net.Listener, shutdownTimeout time.Duration) (func(), <-chan error) {
 	errHTTPCh := make(chan error, 1)
 	go func() {
 		defer close(errHTTPCh)
 
 		log.Println("Starting http server on", ln.Addr().String())
 
-		err := srv.Serve(ln)
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ServeTLS(ln, "", "")
+		} else {
+			err = srv.Serve(ln)
+		}
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			err = fmt.Errorf("srv.ListenAndServe failed: %w", err)
 			log.Println(err)
@@ -140,10 +584,11 @@ func serveHTTP(srv *http.Server, ln net.Listener) (func(), <-chan error) {
 	}()
 
 	return func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer cancel()
 		if err := srv.Shutdown(ctx); err != nil {
 			log.Println(fmt.Errorf("srv.Shutdown failed: %w", err))
+			_ = srv.Close()
 		}
 
 		<-errHTTPCh
@@ -164,7 +609,52 @@ func mustListen(httpAddr *string) net.Listener {
 	return ln
 }
 
-func mustCreateOauthCfg(lnAddr string, envFileParam, oauthURLParam *string) *oauth2.Config {
+// mustTLSConfig returns the TLS config serving certFile, verifying client certificates issued by
+// the CAs of clientCAFile if given, or nil to serve plain HTTP without certFile.
+func mustTLSConfig(certFile, keyFile, clientCAFile string) *tls.Config {
+	if certFile == "" && keyFile == "" && clientCAFile == "" {
+		return nil
+	}
+	if certFile == "" || keyFile == "" {
+		panic("-tls-cert and -tls-key must be provided together, also for -tls-client-ca")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		panic(fmt.Errorf("tls.LoadX509KeyPair failed: %w", err))
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCAFile == "" {
+		return cfg
+	}
+
+	pem, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		panic(fmt.Errorf("os.ReadFile failed: %w", err))
+	}
+	cfg.ClientCAs = x509.NewCertPool()
+	if !cfg.ClientCAs.AppendCertsFromPEM(pem) {
+		panic(fmt.Errorf("no certificates found in %s", clientCAFile))
+	}
+	// Browsers completing the OAuth flow have no client certificate, requireClientCert demands
+	// one of /mcp only.
+	cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	return cfg
+}
+
+// requireClientCert refuses requests whose connection presented no client certificate verified
+// against the -tls-client-ca bundle.
+func requireClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			http.Error(w, "Client certificate required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func mustCreateOauthCfg(baseURL string, envFileParam, oauthURLParam *string, scopes []string) *oauth2.Config {
 	if envFileParam != nil && *envFileParam != "" {
 		if err := godotenv.Load(*envFileParam); err != nil {
 			panic(fmt.Errorf("godotenv.Load failed: %w", err))
@@ -178,7 +668,7 @@ func mustCreateOauthCfg(lnAddr string, envFileParam, oauthURLParam *string) *oau
 		panic("Env variables OAUTH_GOOGLE_CLIENT_ID and OAUTH_GOOGLE_CLIENT_SECRET must be set")
 	}
 
-	oauthURL := fmt.Sprintf("http://%s/oauth", lnAddr)
+	oauthURL := baseURL + "/oauth"
 	if oauthURLParam != nil && *oauthURLParam != "" {
 		oauthURL = *oauthURLParam
 	}
@@ -187,7 +677,7 @@ func mustCreateOauthCfg(lnAddr string, envFileParam, oauthURLParam *string) *oau
 		ClientID:     oauthClientID,
 		ClientSecret: oauthClientSec,
 		RedirectURL:  oauthURL,
-		Scopes:       []string{gmail.GmailReadonlyScope},
+		Scopes:       scopes,
 		Endpoint:     google.Endpoint,
 	}
 }
@@ -216,8 +706,200 @@ func setupLogger(enableStdio *bool, logFile *string) func() {
 	return func() {}
 }
 
-func openBrowser(url string) {
-	url = fmt.Sprintf("%s?redirect=1", url)
+// mustProtectResource serves the protected resource metadata of the /mcp endpoint at resource on
+// mux and returns the middleware requiring access tokens of issuer for it, verified by
+// introspection.
+func mustProtectResource(mux *http.ServeMux, resource, issuer, introspectionURL, scopes string) func(http.Handler) http.Handler {
+	if issuer == "" || introspectionURL == "" {
+		panic("-mcp-resource requires -mcp-auth-issuer and -mcp-auth-introspection-url")
+	}
+	clientID, clientSecret := os.Getenv("MCP_AUTH_CLIENT_ID"), os.Getenv("MCP_AUTH_CLIENT_SECRET")
+	if clientID == "" || clientSecret == "" {
+		panic("-mcp-resource requires MCP_AUTH_CLIENT_ID and MCP_AUTH_CLIENT_SECRET for token introspection")
+	}
+
+	protected := auth.ProtectedResource{Resource: resource, AuthorizationServers: []string{issuer}}
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			protected.Scopes = append(protected.Scopes, scope)
+		}
+	}
+	metadataURL, err := protected.MetadataURL()
+	if err != nil {
+		panic(fmt.Errorf("protected.MetadataURL failed: %w", err))
+	}
+	metadata, err := neturl.Parse(metadataURL)
+	if err != nil {
+		panic(fmt.Errorf("neturl.Parse failed: %w", err))
+	}
+	mux.Handle(metadata.Path, protected)
+
+	introspection := auth.Introspection{
+		Endpoint:     introspectionURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Resource:     resource,
+	}
+	return mcpauth.RequireBearerToken(introspection.Verify, &mcpauth.RequireBearerTokenOptions{
+		ResourceMetadataURL: strconv.Quote(metadataURL),
+		Scopes:              protected.Scopes,
+	})
+}
+
+// mustParseScopes returns the mode of the -mode flag and its scopes, or the scopes of the
+// -scopes flag and the mode they allow.
+func mustParseScopes(modeParam, scopesParam string) (tool.Mode, []string) {
+	if modeParam != "" && scopesParam != "" {
+		panic("-mode and -scopes are exclusive")
+	}
+	if scopesParam != "" {
+		var scopes []string
+		for _, scope := range strings.Split(scopesParam, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+		return tool.ModeForScopes(scopes), scopes
+	}
+	// Readonly unless asked for, so upgrading never asks existing deployments for write scopes.
+	if modeParam == "" {
+		modeParam = string(tool.ModeReadonly)
+	}
+
+	mode, err := tool.ParseMode(modeParam)
+	if err != nil {
+		panic(fmt.Errorf("tool.ParseMode failed: %w", err))
+	}
+	return mode, mode.Scopes()
+}
+
+// keyringService names the secrets of tokens kept in the OS keyring.
+const keyringService = "gmail-mcp"
+
+// mustLoadTokens loads the token of every account of the -accounts flag, or of tokenFile under
+// an empty name without accounts, from files or the keyring, and returns the account names in
+// order with their tokens.
+func mustLoadTokens(config *oauth2.Config, store, accounts, tokenFile string) ([]string, map[string]*auth.Token) {
+	if store != "file" && store != "keyring" {
+		panic(fmt.Errorf("unknown -token-store %q, expected file or keyring", store))
+	}
+
+	files := map[string]string{"": tokenFile}
+	names := []string{""}
+	if accounts != "" {
+		files, names = map[string]string{}, nil
+		for _, pair := range strings.Split(accounts, ",") {
+			name, file, _ := strings.Cut(strings.TrimSpace(pair), "=")
+			name, file = strings.TrimSpace(name), strings.TrimSpace(file)
+			if name == "" || (file == "" && store == "file") {
+				panic(fmt.Errorf("invalid -accounts entry %q, expected name=token-file", pair))
+			}
+			if _, ok := files[name]; ok {
+				panic(fmt.Errorf("duplicate -accounts name %q", name))
+			}
+			files[name] = file
+			names = append(names, name)
+		}
+	}
+
+	toks := make(map[string]*auth.Token, len(names))
+	for _, name := range names {
+		var tokStore auth.Store
+		switch {
+		case store == "keyring" && name == "":
+			tokStore = auth.KeyringStore{Service: keyringService, Account: "default"}
+		case store == "keyring":
+			tokStore = auth.KeyringStore{Service: keyringService, Account: name}
+		case files[name] != "":
+			tokStore = auth.FileStore(files[name])
+		}
+		tok, err := auth.NewStoreToken(config, tokStore)
+		if err != nil {
+			panic(fmt.Errorf("auth.NewStoreToken failed: %w", err))
+		}
+		toks[name] = tok
+	}
+	return names, toks
+}
+
+// authorizeDevice runs the device flow of tok, logging the code to enter, and persists the
+// token it obtains.
+func authorizeDevice(ctx context.Context, tok *auth.Token, account string) {
+	err := tok.AuthorizeDevice(ctx, func(resp *oauth2.DeviceAuthResponse) {
+		url := resp.VerificationURIComplete
+		if url == "" {
+			url = resp.VerificationURI
+		}
+		if account != "" {
+			account = fmt.Sprintf(" account %q", account)
+		}
+		log.Printf("To authorize%s visit %s and enter code %s (expires %s)", account, url, resp.UserCode, resp.Expiry.Format(time.RFC3339))
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Println(fmt.Errorf("tok.AuthorizeDevice failed: %w", err))
+		}
+		return
+	}
+
+	log.Println("Device authorization succeeded")
+	if err := tok.Persist(); err != nil {
+		log.Println(fmt.Errorf("tok.Persist failed: %w", err))
+	}
+}
+
+// logout clears the tokens of the named accounts, all without names, for the logout subcommand.
+func logout(accountNames []string, toks map[string]*auth.Token, names []string) {
+	if len(names) == 0 {
+		names = accountNames
+	}
+	for _, name := range names {
+		tok, ok := toks[name]
+		if !ok {
+			panic(fmt.Errorf("unknown account %q", name))
+		}
+		if err := tok.Clear(); err != nil {
+			panic(fmt.Errorf("tok.Clear failed: %w", err))
+		}
+		log.Printf("Cleared credentials of account %q", name)
+	}
+}
+
+// authURL returns the URL of the /oauth handler starting the flow of account.
+func authURL(redirectURL, account string) string {
+	url := fmt.Sprintf("%s?redirect=1", redirectURL)
+	if account != "" {
+		url += "&account=" + neturl.QueryEscape(account)
+	}
+	return url
+}
+
+// authStatus reports whether tok is authorized, or else the URL authorizing account.
+func authStatus(tok *auth.Token, redirectURL, account string) tool.AuthStatusResponse {
+	t, err := tok.OAuthToken()
+	if err != nil {
+		return tool.AuthStatusResponse{AuthURL: authURL(redirectURL, account)}
+	}
+	return tool.AuthStatusResponse{Authorized: true, Expiry: t.Expiry.Format(time.RFC3339)}
+}
+
+// logAuthURL logs the Google authorization URL of account for -no-browser. Its state expires, so
+// the /oauth link issuing a new one is logged too.
+func logAuthURL(tok *auth.Token, redirectURL, account string) {
+	url, err := tok.RedirectURL()
+	if err != nil {
+		log.Println(fmt.Errorf("tok.RedirectURL failed: %w", err))
+		url = authURL(redirectURL, account)
+	}
+	who := "the server"
+	if account != "" {
+		who = fmt.Sprintf("account %q", account)
+	}
+	log.Printf("Authorize %s by opening in a browser: %s (or later %s)\n", who, url, authURL(redirectURL, account))
+}
+
+func openBrowser(redirectURL, account string) {
+	url := authURL(redirectURL, account)
 	var err error
 	switch runtime.GOOS {
 	case "linux":
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCert issues a certificate for 127.0.0.1 signed by parent, self-signed without one, and
+// returns it with its key.
+func testCert(t *testing.T, name string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+// writePEM writes cert and key as PEM files in dir and returns their paths.
+func writePEM(t *testing.T, dir, name string, cert *x509.Certificate, key *ecdsa.PrivateKey) (string, string) {
+	t.Helper()
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0600))
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	return certFile, keyFile
+}
+
+func TestTLSClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := testCert(t, "ca", true, nil, nil)
+	caFile, _ := writePEM(t, dir, "ca", ca, caKey)
+	serverCert, serverKey := testCert(t, "server", false, ca, caKey)
+	certFile, keyFile := writePEM(t, dir, "server", serverCert, serverKey)
+	clientCert, clientKey := testCert(t, "client", false, ca, caKey)
+
+	mux := http.NewServeMux()
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	mux.Handle("/mcp", requireClientCert(ok))
+	mux.Handle("/oauth", ok)
+	server := httptest.NewUnstartedServer(mux)
+	server.TLS = mustTLSConfig(certFile, keyFile, caFile)
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	get := func(path string, certs ...tls.Certificate) int {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs, MinVersion: tls.VersionTLS12}}}
+		resp, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		return resp.StatusCode
+	}
+	withCert := tls.Certificate{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}
+
+	assert.Equal(t, http.StatusOK, get("/mcp", withCert))
+	assert.Equal(t, http.StatusForbidden, get("/mcp"), "/mcp requires a client certificate")
+	assert.Equal(t, http.StatusOK, get("/oauth"), "browsers completing the OAuth flow have no client certificate")
+}
+
+func TestMustTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := testCert(t, "server", false, nil, nil)
+	certFile, keyFile := writePEM(t, dir, "server", cert, key)
+	emptyCA := filepath.Join(dir, "empty.pem")
+	require.NoError(t, os.WriteFile(emptyCA, []byte("no certificates"), 0600))
+
+	assert.Nil(t, mustTLSConfig("", "", ""), "TLS is off without flags")
+
+	cfg := mustTLSConfig(certFile, keyFile, "")
+	require.NotNil(t, cfg)
+	assert.Len(t, cfg.Certificates, 1)
+	assert.Equal(t, tls.NoClientCert, cfg.ClientAuth)
+
+	assert.Panics(t, func() { mustTLSConfig(certFile, "", "") }, "a certificate needs its key")
+	assert.Panics(t, func() { mustTLSConfig("", "", emptyCA) }, "client CAs need a server certificate")
+	assert.Panics(t, func() { mustTLSConfig(certFile, keyFile, emptyCA) }, "a client CA bundle must hold certificates")
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+)
+
+// recentErrors is the number of failed API calls the status page lists.
+const recentErrors = 10
+
+// callStats counts the API calls of the Gmail services and keeps the last failures for the
+// status page. Only the method, status and kind of a failure are kept, its message may quote
+// queries or addresses.
+type callStats struct {
+	mu     sync.Mutex
+	calls  int
+	failed int
+	errors []callError
+}
+
+type callError struct {
+	Time   time.Time
+	Method string
+	Code   int
+	Kind   string
+}
+
+func (c *callStats) ObserveCall(call gservice.Call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls++
+	if call.Err == nil {
+		return
+	}
+	c.failed++
+	c.errors = append(c.errors, callError{Time: time.Now(), Method: call.Method, Code: call.Code, Kind: errorKind(call.Err)})
+	if len(c.errors) > recentErrors {
+		c.errors = c.errors[len(c.errors)-recentErrors:]
+	}
+}
+
+// errorKind names the gservice error err matches, or "error".
+func errorKind(err error) string {
+	for _, kind := range []error{gservice.ErrNotFound, gservice.ErrQuotaExceeded, gservice.ErrAuthExpired, gservice.ErrPermission} {
+		if errors.Is(err, kind) {
+			return kind.Error()
+		}
+	}
+	return "error"
+}
+
+// statusPage serves a page at / showing whether the accounts are authorized, the transports
+// served, the tools enabled, the use of the message cache and the last failed API calls. It shows
+// no tokens and no mail content.
+type statusPage struct {
+	started     time.Time
+	accounts    []string
+	toks        map[string]*auth.Token
+	redirectURL string
+	multiUser   bool
+	transports  []string
+	tools       []string
+	server      *mcp.Server
+	gmail       *gservice.GMail
+	calls       *callStats
+}
+
+type accountStatus struct {
+	Name       string
+	Authorized bool
+	Expiry     time.Time
+	AuthURL    string
+}
+
+var statusTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>gmail-mcp status</title>
+<style>body{font-family:sans-serif;margin:2em}table{border-collapse:collapse}td,th{padding:.2em 1em;text-align:left}</style>
+</head><body>
+<h1>gmail-mcp</h1>
+<p>Up since {{.Started.Format "2006-01-02 15:04:05 MST"}}.</p>
+<h2>Authorization</h2>
+{{if .MultiUser}}<p>Multi-user: every user authorizes at <a href="/oauth?redirect=1">/oauth</a>.</p>
+{{else}}<table><tr><th>Account</th><th>Status</th></tr>
+{{range .Accounts}}<tr><td>{{if .Name}}{{.Name}}{{else}}default{{end}}</td><td>{{if .Authorized}}authorized, token expires {{.Expiry.Format "2006-01-02 15:04:05 MST"}}{{else}}not authorized, <a href="{{.AuthURL}}">authorize</a>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Transports</h2>
+<ul>{{range .Transports}}<li>{{.}}</li>{{end}}</ul>
+<p>{{.Sessions}} MCP sessions.</p>
+<h2>Tools</h2>
+<p>{{range $i, $t := .Tools}}{{if $i}}, {{end}}{{$t}}{{end}}</p>
+<h2>Cache</h2>
+<p>{{.Cache.Entries}} messages in memory, {{.Cache.Hits}} hits, {{.Cache.Misses}} misses.</p>
+<h2>API calls</h2>
+<p>{{.Calls}} calls, {{.Failed}} failed.</p>
+{{if .Errors}}<table><tr><th>Time</th><th>Method</th><th>Status</th><th>Error</th></tr>
+{{range .Errors}}<tr><td>{{.Time.Format "2006-01-02 15:04:05"}}</td><td>{{.Method}}</td><td>{{if .Code}}{{.Code}}{{end}}</td><td>{{.Kind}}</td></tr>
+{{end}}</table>{{end}}
+</body></html>
+`))
+
+func (s *statusPage) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var accounts []accountStatus
+	if !s.multiUser {
+		for _, name := range s.accounts {
+			account := accountStatus{Name: name, AuthURL: authURL(s.redirectURL, name)}
+			if t, err := s.toks[name].OAuthToken(); err == nil {
+				account.Authorized, account.Expiry = true, t.Expiry
+			}
+			accounts = append(accounts, account)
+		}
+	}
+
+	s.calls.mu.Lock()
+	calls, failed, errs := s.calls.calls, s.calls.failed, slices.Clone(s.calls.errors)
+	s.calls.mu.Unlock()
+	slices.Reverse(errs)
+
+	sessions := 0
+	for range s.server.Sessions() {
+		sessions++
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := statusTemplate.Execute(w, map[string]any{
+		"Started":    s.started,
+		"MultiUser":  s.multiUser,
+		"Accounts":   accounts,
+		"Transports": s.transports,
+		"Sessions":   sessions,
+		"Tools":      s.tools,
+		"Cache":      s.gmail.CacheStats(),
+		"Calls":      calls,
+		"Failed":     failed,
+		"Errors":     errs,
+	})
+	if err != nil {
+		log.Println(fmt.Errorf("statusTemplate.Execute failed: %w", err))
+	}
+}
+
+// statusAllowed reports whether the status page may be served on addr: always on loopback
+// addresses, elsewhere only behind the client certificate or bearer token checks of /mcp. The
+// stats of a -multi-user server combine all its users, so it is shown on loopback only.
+func statusAllowed(addr net.Addr, multiUser, protected bool) bool {
+	if tcp, ok := addr.(*net.TCPAddr); ok && tcp.IP.IsLoopback() {
+		return true
+	}
+	return protected && !multiUser
+}
+
+// listTools returns the names of the tools of srv, listed through an in-memory session.
+func listTools(ctx context.Context, srv *mcp.Server) ([]string, error) {
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := srv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("srv.Connect failed: %w", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gmail-mcp-status"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("client.Connect failed: %w", err)
+	}
+	defer func() { _ = clientSession.Close() }()
+
+	var names []string
+	for listed, err := range clientSession.Tools(ctx, nil) {
+		if err != nil {
+			return nil, fmt.Errorf("clientSession.Tools failed: %w", err)
+		}
+		names = append(names, listed.Name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+)
+
+func TestCallStats(t *testing.T) {
+	var calls callStats
+	calls.ObserveCall(gservice.Call{Method: "gmail.messages.List"})
+	calls.ObserveCall(gservice.Call{Method: "gmail.messages.Get", Code: 404, Err: fmt.Errorf("messages.Get failed: %w", gservice.ErrNotFound)})
+	for range recentErrors {
+		calls.ObserveCall(gservice.Call{Method: "gmail.users.GetProfile", Err: errors.New("connection reset")})
+	}
+
+	assert.Equal(t, 2+recentErrors, calls.calls)
+	assert.Equal(t, 1+recentErrors, calls.failed)
+	require.Len(t, calls.errors, recentErrors, "only the last failures are kept")
+	assert.Equal(t, "gmail.users.GetProfile", calls.errors[0].Method)
+	assert.Equal(t, "error", calls.errors[0].Kind, "messages of unknown errors are not kept")
+
+	assert.Equal(t, gservice.ErrNotFound.Error(), errorKind(fmt.Errorf("wrapped: %w", gservice.ErrNotFound)))
+	assert.Equal(t, gservice.ErrQuotaExceeded.Error(), errorKind(gservice.ErrQuotaExceeded))
+}
+
+// staticStore loads a fixed token and keeps nothing.
+type staticStore struct{ token *oauth2.Token }
+
+func (s staticStore) Load() (*oauth2.Token, error) { return s.token, nil }
+func (staticStore) Save(*oauth2.Token) error       { return nil }
+func (staticStore) Delete() error                  { return nil }
+
+func TestStatusPage(t *testing.T) {
+	cfg := &oauth2.Config{}
+	work, err := auth.NewStoreToken(cfg, staticStore{&oauth2.Token{AccessToken: "secret-access", RefreshToken: "secret-refresh", Expiry: time.Now().Add(time.Hour)}})
+	require.NoError(t, err)
+	home, err := auth.NewStoreToken(cfg, nil)
+	require.NoError(t, err)
+
+	calls := &callStats{}
+	calls.ObserveCall(gservice.Call{Method: "gmail.messages.Get", Code: 429, Err: gservice.ErrQuotaExceeded})
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	page := &statusPage{
+		started:     time.Now(),
+		accounts:    []string{"work", "home"},
+		toks:        map[string]*auth.Token{"work": work, "home": home},
+		redirectURL: "http://localhost:8080/oauth",
+		transports:  []string{"streamable HTTP at /mcp"},
+		tools:       []string{"get_messages", "search_messages"},
+		server:      server,
+		gmail:       gservice.NewGmail(cfg, work),
+		calls:       calls,
+	}
+
+	rec := httptest.NewRecorder()
+	page.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, body, "<td>work</td><td>authorized, token expires")
+	assert.Contains(t, body, `<td>home</td><td>not authorized, <a href="http://localhost:8080/oauth?redirect=1&amp;account=home">authorize</a>`)
+	assert.Contains(t, body, "<li>streamable HTTP at /mcp</li>")
+	assert.Contains(t, body, "get_messages, search_messages")
+	assert.Contains(t, body, "1 calls, 1 failed.")
+	assert.Contains(t, body, "<td>gmail.messages.Get</td><td>429</td><td>"+gservice.ErrQuotaExceeded.Error()+"</td>")
+	assert.NotContains(t, body, "secret-access")
+	assert.NotContains(t, body, "secret-refresh")
+}
+
+func TestListTools(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	for _, name := range []string{"search_messages", "get_messages"} {
+		mcp.AddTool(server, &mcp.Tool{Name: name}, func(_ context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+			return nil, nil, nil
+		})
+	}
+
+	tools, err := listTools(t.Context(), server)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"get_messages", "search_messages"}, tools)
+}
+
+func TestStatusAllowed(t *testing.T) {
+	loopback := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080}
+	public := &net.TCPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 8080}
+
+	assert.True(t, statusAllowed(loopback, false, false))
+	assert.True(t, statusAllowed(&net.TCPAddr{IP: net.IPv6loopback}, true, false))
+	assert.False(t, statusAllowed(public, false, false), "an unprotected page is served on loopback only")
+	assert.True(t, statusAllowed(public, false, true))
+	assert.False(t, statusAllowed(public, true, true), "the stats of all users are shown on loopback only")
+	assert.False(t, statusAllowed(&net.TCPAddr{IP: net.IPv4zero, Port: 8080}, false, false), "all interfaces aren't loopback")
+}
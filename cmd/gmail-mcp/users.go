@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+)
+
+// userServers serves /mcp of a -multi-user server behind auth.Users.Authenticate: every user gets
+// an MCP server of their own and a streamable HTTP handler holding only their sessions, so a
+// session can't be reached with the key of another user.
+type userServers struct {
+	newServer func(id string, tok *auth.Token) *mcp.Server
+
+	mu       sync.Mutex
+	handlers map[string]http.Handler
+}
+
+func (s *userServers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, tok, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	h, ok := s.handlers[id]
+	if !ok {
+		server := s.newServer(id, tok)
+		h = mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil)
+		if s.handlers == nil {
+			s.handlers = map[string]http.Handler{}
+		}
+		s.handlers[id] = h
+	}
+	s.mu.Unlock()
+
+	h.ServeHTTP(w, r)
+}
+
+// mustUserStore returns the stores of the tokens of -multi-user users: files named by their IDs
+// in dir, entries of the keyring, or nil to keep them in memory when dir is empty.
+func mustUserStore(store, dir string) func(id string) auth.Store {
+	switch {
+	case store == "keyring":
+		return func(id string) auth.Store {
+			return auth.KeyringStore{Service: keyringService, Account: "user-" + id}
+		}
+	case store != "file":
+		panic(fmt.Errorf("unknown -token-store %q, expected file or keyring", store))
+	case dir == "":
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		panic(fmt.Errorf("os.MkdirAll failed: %w", err))
+	}
+	return func(id string) auth.Store {
+		return auth.FileStore(filepath.Join(dir, id+".json"))
+	}
+}
+
+// userDir returns the subdirectory of dir a -multi-user user writes exports or attachments into,
+// creating it, or empty if dir is or it can't be created.
+func userDir(dir, id string) string {
+	if dir == "" {
+		return ""
+	}
+
+	sub := filepath.Join(dir, id[:16])
+	if err := os.MkdirAll(sub, 0700); err != nil {
+		log.Println(fmt.Errorf("os.MkdirAll failed: %w", err))
+		return ""
+	}
+	return sub
+}
@@ -3,6 +3,7 @@ module github.com/hal9000y/gmail-mcp
 go 1.25.0
 
 require (
+	github.com/google/jsonschema-go v0.2.1-0.20250825175020-748c325cec76
 	github.com/joho/godotenv v1.5.1
 	github.com/modelcontextprotocol/go-sdk v0.4.0
 	github.com/stretchr/testify v1.10.0
@@ -19,7 +20,6 @@ require (
 	github.com/felixge/httpsnoop v1.0.4 // indirect
 	github.com/go-logr/logr v1.4.3 // indirect
 	github.com/go-logr/stdr v1.2.2 // indirect
-	github.com/google/jsonschema-go v0.2.1-0.20250825175020-748c325cec76 // indirect
 	github.com/google/s2a-go v0.1.9 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/googleapis/enterprise-certificate-proxy v0.3.6 // indirect
@@ -0,0 +1,279 @@
+// Package ics reads the events of iCalendar (RFC 5545) invitations attached to emails.
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a VEVENT of an iCalendar object.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+	Organizer   string
+	Attendees   []string
+	Start       time.Time
+	End         time.Time
+	// AllDay events have dates instead of times, Start and End are midnight in UTC.
+	AllDay bool
+}
+
+// property is a content line like DTSTART;TZID=Europe/Berlin:20250102T090000.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// Parse returns the events of data. Floating times and times in timezones unknown to the system,
+// like Windows timezone names, are read in loc.
+func Parse(data []byte, loc *time.Location) ([]Event, error) {
+	var (
+		events []Event
+		stack  []string
+		event  *Event
+		// duration applies DURATION once DTSTART is known, which may follow it.
+		duration time.Duration
+	)
+	for line := range unfold(data) {
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case prop.name == "BEGIN":
+			stack = append(stack, strings.ToUpper(prop.value))
+			if stack[len(stack)-1] == "VEVENT" {
+				event, duration = &Event{}, 0
+			}
+		case prop.name == "END":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("END:%s without BEGIN", prop.value)
+			}
+			if stack[len(stack)-1] == "VEVENT" && event != nil {
+				events = append(events, event.finish(duration))
+				event = nil
+			}
+			stack = stack[:len(stack)-1]
+		case event != nil && stack[len(stack)-1] == "VEVENT":
+			if err := event.set(prop, loc, &duration); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(events) == 0 {
+		return nil, errors.New("no events found")
+	}
+	return events, nil
+}
+
+func (e *Event) set(prop property, loc *time.Location, duration *time.Duration) error {
+	if field := e.textField(prop.name); field != nil {
+		*field = unescape(prop.value)
+		return nil
+	}
+
+	var err error
+	switch prop.name {
+	case "ORGANIZER":
+		e.Organizer = address(prop.value)
+	case "ATTENDEE":
+		e.Attendees = append(e.Attendees, address(prop.value))
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseTime(prop, loc)
+	case "DTEND":
+		e.End, _, err = parseTime(prop, loc)
+	case "DURATION":
+		*duration, err = parseDuration(prop.value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", prop.name, err)
+	}
+	return nil
+}
+
+// textField returns the field of a TEXT property, nil for other properties.
+func (e *Event) textField(name string) *string {
+	switch name {
+	case "UID":
+		return &e.UID
+	case "SUMMARY":
+		return &e.Summary
+	case "DESCRIPTION":
+		return &e.Description
+	case "LOCATION":
+		return &e.Location
+	case "STATUS":
+		return &e.Status
+	}
+	return nil
+}
+
+// finish sets End of events given a duration or none: events without either last a day if all
+// day and are instants otherwise.
+func (e *Event) finish(duration time.Duration) Event {
+	switch {
+	case !e.End.IsZero():
+	case duration != 0:
+		e.End = e.Start.Add(duration)
+	case e.AllDay:
+		e.End = e.Start.AddDate(0, 0, 1)
+	default:
+		e.End = e.Start
+	}
+	return *e
+}
+
+// unfold yields the logical lines of data, joining lines continued with a leading space or tab.
+func unfold(data []byte) func(yield func(string) bool) {
+	return func(yield func(string) bool) {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, 1<<20)
+		var current strings.Builder
+		for scanner.Scan() {
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+				current.WriteString(line[1:])
+				continue
+			}
+			if current.Len() > 0 && !yield(current.String()) {
+				return
+			}
+			current.Reset()
+			current.WriteString(line)
+		}
+		if current.Len() > 0 {
+			yield(current.String())
+		}
+	}
+}
+
+// parseLine splits a content line into name, parameters and value; parameter values may be
+// quoted and contain ':' and ';'.
+func parseLine(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+	i := strings.IndexAny(line, ";:")
+	if i < 0 {
+		return property{}, fmt.Errorf("invalid content line %q", line)
+	}
+	prop.name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		rest := line[i+1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			return property{}, fmt.Errorf("invalid parameter in %q", line)
+		}
+		key := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value string
+		var n int
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return property{}, fmt.Errorf("unterminated quote in %q", line)
+			}
+			value, n = rest[1:end+1], end+2
+		} else {
+			n = strings.IndexAny(rest, ";:")
+			if n < 0 {
+				return property{}, fmt.Errorf("invalid content line %q", line)
+			}
+			value = rest[:n]
+		}
+		prop.params[key] = value
+
+		i += 1 + eq + 1 + n
+		if i >= len(line) {
+			return property{}, fmt.Errorf("invalid content line %q", line)
+		}
+	}
+
+	prop.value = line[i+1:]
+	return prop, nil
+}
+
+func parseTime(prop property, loc *time.Location) (time.Time, bool, error) {
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(prop.value) == len("20060102") {
+		t, err := time.Parse("20060102", prop.value)
+		return t, true, err
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		t, err := time.Parse("20060102T150405Z", prop.value)
+		return t, false, err
+	}
+	if tzid := strings.Trim(prop.params["TZID"], "/"); tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", prop.value, loc)
+	return t, false, err
+}
+
+// parseDuration parses durations like PT1H30M or P1D; days count as 24 hours.
+func parseDuration(value string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(value)
+	if m == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("strconv.Atoi failed: %w", err)
+		}
+		d += time.Duration(n) * unit
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, nil
+}
+
+// unescape decodes TEXT values, which escape newlines, commas, semicolons and backslashes.
+func unescape(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// address returns the email address of a CAL-ADDRESS like mailto:alice@example.com.
+func address(value string) string {
+	if len(value) >= len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return value[len("mailto:"):]
+	}
+	return value
+}
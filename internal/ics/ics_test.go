@@ -0,0 +1,90 @@
+package ics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/internal/ics"
+)
+
+const invitation = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"METHOD:REQUEST\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:review-42@example.com\r\n" +
+	"SUMMARY:Quarterly review\\, Q3\r\n" +
+	"DESCRIPTION:Agenda:\\n1. Numbers\\n2. Plans that are long enough to be fol\r\n" +
+	" ded over two lines\r\n" +
+	"LOCATION:Room 4\\; Building B\r\n" +
+	"DTSTART;TZID=\"Europe/Berlin\":20250310T090000\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"ORGANIZER;CN=\"Ada: Lovelace\":mailto:ada@example.com\r\n" +
+	"ATTENDEE;ROLE=REQ-PARTICIPANT;CN=Bob:MAILTO:bob@example.com\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday@example.com\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"DTSTART;VALUE=DATE:20250401\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	events, err := ics.Parse([]byte(invitation), time.UTC)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	review := events[0]
+	assert.Equal(t, "review-42@example.com", review.UID)
+	assert.Equal(t, "Quarterly review, Q3", review.Summary)
+	assert.Equal(t, "Agenda:\n1. Numbers\n2. Plans that are long enough to be folded over two lines", review.Description)
+	assert.Equal(t, "Room 4; Building B", review.Location)
+	assert.Equal(t, "ada@example.com", review.Organizer)
+	assert.Equal(t, []string{"bob@example.com"}, review.Attendees)
+	assert.True(t, review.Start.Equal(time.Date(2025, 3, 10, 9, 0, 0, 0, berlin)))
+	assert.Equal(t, 90*time.Minute, review.End.Sub(review.Start))
+	assert.False(t, review.AllDay)
+
+	holiday := events[1]
+	assert.True(t, holiday.AllDay)
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), holiday.Start)
+	assert.Equal(t, time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC), holiday.End, "all-day events last a day")
+}
+
+func TestParseTimes(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	events, err := ics.Parse([]byte("BEGIN:VCALENDAR\n"+
+		"BEGIN:VEVENT\nDTSTART:20250102T030405Z\nDTEND:20250102T040405Z\nEND:VEVENT\n"+
+		"BEGIN:VEVENT\nDTSTART;TZID=Pacific Standard Time:20250102T090000\nEND:VEVENT\n"+
+		"END:VCALENDAR\n"), tokyo)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), events[0].Start)
+	assert.Equal(t, time.Hour, events[0].End.Sub(events[0].Start))
+	assert.True(t, events[1].Start.Equal(time.Date(2025, 1, 2, 9, 0, 0, 0, tokyo)), "unknown timezones fall back to loc")
+}
+
+func TestParseInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"no events":     "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+		"bad start":     "BEGIN:VEVENT\r\nDTSTART:tomorrow\r\nEND:VEVENT\r\n",
+		"bad duration":  "BEGIN:VEVENT\r\nDTSTART:20250102\r\nDURATION:P\r\nEND:VEVENT\r\n",
+		"unmatched end": "END:VEVENT\r\n",
+		"not ics":       "hello world\r\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ics.Parse([]byte(data), time.UTC)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package jsonfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/internal/jsonfile"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "state.json")
+
+	values := []string{"default"}
+	require.NoError(t, jsonfile.Load(path, &values))
+	assert.Equal(t, []string{"default"}, values, "a missing file should leave the value unchanged")
+
+	require.NoError(t, jsonfile.Save(path, []string{"a", "b"}))
+	require.NoError(t, jsonfile.Save(path, []string{"c"}))
+
+	require.NoError(t, jsonfile.Load(path, &values))
+	assert.Equal(t, []string{"c"}, values)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should not be left behind")
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+
+	var values []string
+	assert.ErrorContains(t, jsonfile.Load(path, &values), "json.Unmarshal failed")
+}
+
+func TestWriteErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "state.json"), 0700))
+
+	err := jsonfile.Write(filepath.Join(dir, "state.json"), []byte("{}"))
+	assert.ErrorContains(t, err, "os.Rename failed")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the temporary file should be removed on failure")
+}
@@ -0,0 +1,43 @@
+package lru_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hal9000y/gmail-mcp/internal/lru"
+)
+
+func TestCache(t *testing.T) {
+	cache := lru.New[string, int](2)
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// "b" is now the least recently used entry.
+	cache.Add("c", 3)
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Add("a", 10)
+	value, _ = cache.Get("a")
+	assert.Equal(t, 10, value)
+
+	cache.Remove("a")
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestCacheDisabled(t *testing.T) {
+	cache := lru.New[string, int](0)
+	cache.Add("a", 1)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
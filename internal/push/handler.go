@@ -0,0 +1,86 @@
+// Package push receives Gmail push notifications delivered through Cloud Pub/Sub and keeps the
+// Users.Watch registration behind them alive.
+package push
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+// maxPushBody bounds the size of a push request; Gmail notifications are a few hundred bytes.
+const maxPushBody = 64 << 10
+
+// Notification tells that the mailbox of EmailAddress changed up to HistoryID.
+type Notification struct {
+	EmailAddress string `json:"emailAddress"`
+	HistoryID    uint64 `json:"historyId"`
+}
+
+// pushRequest is the body of a Pub/Sub push subscription request.
+type pushRequest struct {
+	Message struct {
+		Data      string `json:"data"`
+		MessageID string `json:"messageId"`
+	} `json:"message"`
+	Subscription string `json:"subscription"`
+}
+
+// NewHandler creates the endpoint of a Pub/Sub push subscription. Requests must carry token in
+// the token query parameter, which the subscription's push URL is configured with. Every
+// notification is passed to notify.
+func NewHandler(token string, notify func(context.Context, Notification)) http.Handler {
+	return &handler{token: token, notify: notify}
+}
+
+type handler struct {
+	token  string
+	notify func(context.Context, Notification)
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(h.token)) != 1 {
+		http.Error(w, "invalid token", http.StatusForbidden)
+		return
+	}
+
+	n, err := decode(io.LimitReader(r.Body, maxPushBody))
+	if err != nil {
+		// Pub/Sub redelivers anything not acknowledged, which would never decode either.
+		log.Println(fmt.Errorf("decode push notification failed: %w", err))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	h.notify(r.Context(), n)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decode(body io.Reader) (Notification, error) {
+	var req pushRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return Notification{}, fmt.Errorf("json.Decode failed: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(req.Message.Data)
+	if err != nil {
+		return Notification{}, fmt.Errorf("base64.DecodeString failed: %w", err)
+	}
+	var n Notification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return Notification{}, fmt.Errorf("json.Unmarshal failed: %w", err)
+	}
+	if n.HistoryID == 0 {
+		return Notification{}, fmt.Errorf("message %s has no history ID", req.Message.MessageID)
+	}
+	return n, nil
+}
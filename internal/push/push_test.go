@@ -0,0 +1,85 @@
+package push
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	data := base64.StdEncoding.EncodeToString([]byte(`{"emailAddress":"user@example.com","historyId":9876}`))
+	validBody := `{"message":{"data":"` + data + `","messageId":"1"},"subscription":"projects/p/subscriptions/s"}`
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		want       *Notification
+	}{
+		{
+			name:       "notification",
+			method:     http.MethodPost,
+			target:     "/pubsub/gmail?token=secret",
+			body:       validBody,
+			wantStatus: http.StatusNoContent,
+			want:       &Notification{EmailAddress: "user@example.com", HistoryID: 9876},
+		},
+		{
+			name:       "wrong token",
+			method:     http.MethodPost,
+			target:     "/pubsub/gmail?token=guess",
+			body:       validBody,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "missing token",
+			method:     http.MethodPost,
+			target:     "/pubsub/gmail",
+			body:       validBody,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "malformed data is acknowledged",
+			method:     http.MethodPost,
+			target:     "/pubsub/gmail?token=secret",
+			body:       `{"message":{"data":"not base64!"}}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			target:     "/pubsub/gmail?token=secret",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *Notification
+			h := NewHandler("secret", func(_ context.Context, n Notification) {
+				got = &n
+			})
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRenewAfter(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, renewInterval, renewAfter(now.Add(7*24*time.Hour), now))
+	assert.Equal(t, 2*time.Hour, renewAfter(now.Add(3*time.Hour), now))
+	assert.Equal(t, retryInterval, renewAfter(now.Add(-time.Hour), now), "expired watches are renewed soon")
+}
@@ -0,0 +1,80 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	// renewInterval is how often the watch is renewed; Gmail recommends daily renewal of watches
+	// expiring after seven days.
+	renewInterval = 24 * time.Hour
+	// retryInterval is how long a failed registration waits, e.g. until the user authorized.
+	retryInterval = time.Minute
+)
+
+type watchSvc interface {
+	Watch(ctx context.Context, topicName string, labelIDs []string) (*gmail.WatchResponse, error)
+	StopWatch(ctx context.Context) error
+}
+
+// Watcher keeps a Users.Watch registration publishing mailbox changes to a Pub/Sub topic.
+type Watcher struct {
+	svc      watchSvc
+	topic    string
+	labelIDs []string
+}
+
+// NewWatcher creates a Watcher publishing changes of messages with any of labelIDs to topic, a
+// full topic name like projects/my-project/topics/gmail.
+func NewWatcher(svc watchSvc, topic string, labelIDs []string) *Watcher {
+	return &Watcher{svc: svc, topic: topic, labelIDs: labelIDs}
+}
+
+// Run registers the watch and renews it until ctx is done, then stops it.
+func (w *Watcher) Run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(w.register(ctx))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.stop(ctx)
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// register calls Users.Watch and returns how long to wait before registering again.
+func (w *Watcher) register(ctx context.Context) time.Duration {
+	resp, err := w.svc.Watch(ctx, w.topic, w.labelIDs)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Println(fmt.Errorf("svc.Watch failed, retrying in %v: %w", retryInterval, err))
+		}
+		return retryInterval
+	}
+
+	expires := time.UnixMilli(resp.Expiration)
+	log.Printf("Watching the mailbox from history %d until %s", resp.HistoryId, expires.Format(time.RFC3339))
+	return renewAfter(expires, time.Now())
+}
+
+func (w *Watcher) stop(ctx context.Context) {
+	// The context is done, stopping must outlive it.
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := w.svc.StopWatch(stopCtx); err != nil {
+		log.Println(fmt.Errorf("svc.StopWatch failed: %w", err))
+	}
+}
+
+// renewAfter returns when to renew a watch expiring at expires: after renewInterval, or an hour
+// before it expires if that is sooner.
+func renewAfter(expires, now time.Time) time.Duration {
+	return max(min(renewInterval, expires.Add(-time.Hour).Sub(now)), retryInterval)
+}
@@ -0,0 +1,43 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hal9000y/gmail-mcp/internal/ratelimit"
+)
+
+func TestMiddleware(t *testing.T) {
+	var handler http.Handler
+	call := func(remoteAddr, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	handler = ratelimit.New(0.01, 2).Middleware(ratelimit.RemoteIP)(noContent)
+	assert.Equal(t, http.StatusNoContent, call("10.0.0.1:1000", "").Code)
+	assert.Equal(t, http.StatusNoContent, call("10.0.0.1:1001", "").Code, "the burst covers a second request")
+	rec := call("10.0.0.1:1002", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "ports of one IP share the bucket")
+	assert.Equal(t, "100", rec.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusNoContent, call("10.0.0.2:1000", "").Code, "other IPs have their own bucket")
+	assert.Equal(t, http.StatusTooManyRequests, call("10.0.0.1:1003", "random").Code, "made up tokens get no bucket of their own")
+
+	handler = ratelimit.New(0.01, 2).Middleware(ratelimit.BearerToken)(noContent)
+	assert.Equal(t, http.StatusNoContent, call("10.0.0.1:1003", "key-a").Code, "verified tokens have their own bucket")
+	assert.Equal(t, http.StatusNoContent, call("10.0.0.1:1003", "key-a").Code)
+	assert.Equal(t, http.StatusTooManyRequests, call("10.0.0.3:1000", "key-a").Code, "a token is limited from any IP")
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type tok interface {
+	AuthorizeCode(context.Context, string, string) error
+	OAuthToken() (*oauth2.Token, error)
+	RedirectURL() (string, error)
+	ConsentURL(scopes []string) (string, error)
+}
+
+// HTTPHandler handles OAuth2 authentication flow via HTTP.
+type HTTPHandler struct {
+	names []string
+	toks  map[string]tok
+}
+
+// NewHTTPHandler creates an HTTP handler for OAuth2 flow.
+func NewHTTPHandler(t tok) *HTTPHandler {
+	return &HTTPHandler{names: []string{""}, toks: map[string]tok{"": t}}
+}
+
+// NewAccountsHTTPHandler creates an HTTP handler for the OAuth2 flows of the tokens of several
+// accounts, listed in the order of names: ?redirect=1&account=<name> starts the flow of an
+// account and callbacks are matched to it by their state.
+func NewAccountsHTTPHandler(names []string, toks map[string]*Token) *HTTPHandler {
+	h := &HTTPHandler{names: names, toks: make(map[string]tok, len(names))}
+	for _, name := range names {
+		h.toks[name] = toks[name]
+	}
+	return h
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("redirect") != "" {
+		t, ok := h.account(r.URL.Query().Get("account"))
+		if !ok {
+			http.Error(w, "Unknown account", http.StatusNotFound)
+			return
+		}
+		// ?scope= asks for scopes in addition to those granted, for incremental consent.
+		var rURL string
+		var err error
+		if scopes := strings.Fields(r.URL.Query().Get("scope")); len(scopes) > 0 {
+			rURL, err = t.ConsentURL(scopes)
+		} else {
+			rURL, err = t.RedirectURL()
+		}
+		if err != nil {
+			log.Println(fmt.Errorf("h.tok.RedirectURL failed: %w", err))
+			http.Error(w, "Unable to generate RedirectURL", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, rURL, http.StatusMovedPermanently)
+		return
+	}
+
+	if code := r.URL.Query().Get("code"); code != "" {
+		if err := h.authorizeCode(r.Context(), code, r.URL.Query().Get("state")); err != nil {
+			log.Println(fmt.Errorf("h.tok.AuthorizeCode failed: %w", err))
+			http.Error(w, "Unable to authorize provided code", http.StatusBadRequest)
+			return
+		}
+		http.Redirect(w, r, r.URL.EscapedPath(), http.StatusFound)
+		return
+	}
+
+	if len(h.names) == 1 {
+		t, err := h.toks[h.names[0]].OAuthToken()
+		if errors.Is(err, ErrTokenNotSet) {
+			http.Error(w, "Token not found", http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "Token: %s, expires: %s", maskLeft(t.AccessToken), t.Expiry.Format(time.RFC3339))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	for _, name := range h.names {
+		t, err := h.toks[name].OAuthToken()
+		if errors.Is(err, ErrTokenNotSet) {
+			_, _ = fmt.Fprintf(w, "%s: Token not found, authorize at ?redirect=1&account=%s\n", name, name)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s: Token: %s, expires: %s\n", name, maskLeft(t.AccessToken), t.Expiry.Format(time.RFC3339))
+	}
+}
+
+// account returns the token of the named account, the only one if name is empty.
+func (h *HTTPHandler) account(name string) (tok, bool) {
+	if name == "" && len(h.names) == 1 {
+		name = h.names[0]
+	}
+	t, ok := h.toks[name]
+	return t, ok
+}
+
+// authorizeCode passes the code to the token that issued state.
+func (h *HTTPHandler) authorizeCode(ctx context.Context, code, state string) error {
+	for _, name := range h.names {
+		err := h.toks[name].AuthorizeCode(ctx, code, state)
+		if !errors.Is(err, ErrInvalidState) {
+			return err
+		}
+	}
+	return ErrInvalidState
+}
+
+func maskLeft(s string) string {
+	rs := []rune(s)
+	for i := 0; i < len(rs)-4; i++ {
+		rs[i] = 'X'
+	}
+	return string(rs)
+}
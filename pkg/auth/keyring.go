@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+// errKeyringNotFound is returned by keyringGet when no secret is stored.
+var errKeyringNotFound = errors.New("secret not found in keyring")
+
+// KeyringStore keeps the token in the credential store of the OS instead of a plain file: the
+// macOS keychain through security, the Secret Service (GNOME Keyring, KWallet) through
+// secret-tool elsewhere on Unix, and the Windows Credential Manager.
+type KeyringStore struct {
+	// Service and Account identify the secret, e.g. "gmail-mcp" and the account name.
+	Service string
+	Account string
+}
+
+// Load reads the token from the keyring, nil if none is stored.
+func (k KeyringStore) Load() (*oauth2.Token, error) {
+	secret, err := keyringGet(k.Service, k.Account)
+	if errors.Is(err, errKeyringNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keyringGet failed: %w", err)
+	}
+
+	token := &oauth2.Token{}
+	if err := json.Unmarshal([]byte(secret), token); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal failed: %w", err)
+	}
+
+	return token, nil
+}
+
+// Delete removes the token from the keyring, if stored.
+func (k KeyringStore) Delete() error {
+	if err := keyringDelete(k.Service, k.Account); err != nil {
+		return fmt.Errorf("keyringDelete failed: %w", err)
+	}
+
+	return nil
+}
+
+// Save writes the token to the keyring, replacing the stored one.
+func (k KeyringStore) Save(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("json.Marshal failed: %w", err)
+	}
+
+	if err := keyringSet(k.Service, k.Account, string(data)); err != nil {
+		return fmt.Errorf("keyringSet failed: %w", err)
+	}
+
+	return nil
+}
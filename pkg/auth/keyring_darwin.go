@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// securityNotFound is the exit code of security when no keychain item matches.
+const securityNotFound = 44
+
+func keyringGet(service, account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityNotFound {
+		return "", errKeyringNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("security find-generic-password failed: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// keyringSet passes the secret hex encoded through the standard input of an interactive
+// security session, keeping it out of the process arguments.
+func keyringSet(service, account, secret string) error {
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		strconv.Quote(service), strconv.Quote(account), hex.EncodeToString([]byte(secret)))
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(command)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("security add-generic-password failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func keyringDelete(service, account string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", service, "-a", account).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("security delete-generic-password failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+//go:build !darwin && !windows
+
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func keyringGet(service, account string) (string, error) {
+	cmd := exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	// secret-tool exits with 1 and no message when nothing matches.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+		return "", errKeyringNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("secret-tool lookup failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// keyringSet passes the secret through the standard input of secret-tool, keeping it out of the
+// process arguments.
+func keyringSet(service, account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", service+" token ("+account+")",
+		"service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret-tool store failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func keyringDelete(service, account string) error {
+	cmd := exec.Command("secret-tool", "clear", "service", service, "account", account)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret-tool clear failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
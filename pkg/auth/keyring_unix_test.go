@@ -0,0 +1,62 @@
+//go:build !darwin && !windows
+
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// fakeSecretTool is a secret-tool keeping secrets in files of $SECRETS named after their
+// service and account.
+const fakeSecretTool = `#!/bin/sh
+cmd=$1; shift
+[ "$1" = --label ] && shift 2
+file="$SECRETS/$2-$4"
+case $cmd in
+lookup) [ -f "$file" ] || exit 1; cat "$file" ;;
+store) cat > "$file" ;;
+clear) rm -f "$file" ;;
+esac
+`
+
+func TestKeyringStore(t *testing.T) {
+	bin, secrets := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "secret-tool"), []byte(fakeSecretTool), 0700))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("SECRETS", secrets)
+
+	store := KeyringStore{Service: "gmail-mcp", Account: "work"}
+	token, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, token, "no secret holds no token")
+
+	require.NoError(t, store.Save(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}))
+	assert.FileExists(t, filepath.Join(secrets, "gmail-mcp-work"))
+
+	token, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.Equal(t, "refresh", token.RefreshToken)
+
+	other, err := KeyringStore{Service: "gmail-mcp", Account: "home"}.Load()
+	require.NoError(t, err)
+	assert.Nil(t, other, "accounts should have their own secrets")
+
+	require.NoError(t, store.Delete())
+	token, err = store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestKeyringStoreUnavailable(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	_, err := KeyringStore{Service: "gmail-mcp", Account: "work"}.Load()
+	assert.ErrorContains(t, err, "secret-tool lookup failed")
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// ProtectedResource serves the OAuth 2.0 protected resource metadata (RFC 9728) of the MCP
+// endpoint, which MCP clients discover through the WWW-Authenticate challenge of a 401 response
+// to find the authorization server to get access tokens from.
+type ProtectedResource struct {
+	// Resource is the canonical URL of the MCP endpoint, e.g. https://mcp.example.com/mcp.
+	Resource string
+	// AuthorizationServers are the issuer URLs of the authorization servers issuing tokens for it.
+	AuthorizationServers []string
+	// Scopes are the scopes clients need, if any.
+	Scopes []string
+}
+
+// MetadataURL returns the well-known URL the metadata of the resource is served at.
+func (p ProtectedResource) MetadataURL() (string, error) {
+	u, err := url.Parse(p.Resource)
+	if err != nil {
+		return "", fmt.Errorf("url.Parse failed: %w", err)
+	}
+	u.Path = "/.well-known/oauth-protected-resource" + strings.TrimSuffix(u.Path, "/")
+	u.RawQuery, u.Fragment = "", ""
+	return u.String(), nil
+}
+
+func (p ProtectedResource) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	_ = json.NewEncoder(w).Encode(struct {
+		Resource               string   `json:"resource"`
+		AuthorizationServers   []string `json:"authorization_servers"`
+		ScopesSupported        []string `json:"scopes_supported,omitempty"`
+		BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	}{
+		Resource:               p.Resource,
+		AuthorizationServers:   p.AuthorizationServers,
+		ScopesSupported:        p.Scopes,
+		BearerMethodsSupported: []string{"header"},
+	})
+}
+
+// Introspection verifies access tokens for the MCP endpoint with the token introspection
+// endpoint (RFC 7662) of the authorization server, accepting only active tokens issued for
+// Resource so tokens meant for other services can't be replayed.
+type Introspection struct {
+	Endpoint     string
+	ClientID     string
+	ClientSecret string
+	// Resource is the canonical URL of the MCP endpoint the audience of tokens must contain.
+	Resource string
+	// Client makes the introspection requests, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// introspectionResponse holds the fields of an introspection response Verify checks.
+type introspectionResponse struct {
+	Active   bool            `json:"active"`
+	Scope    string          `json:"scope"`
+	Exp      int64           `json:"exp"`
+	Aud      json.RawMessage `json:"aud"`
+	Sub      string          `json:"sub"`
+	ClientID string          `json:"client_id"`
+}
+
+// audiences returns aud, which is either a string or an array of strings.
+func (r introspectionResponse) audiences() []string {
+	var one string
+	if json.Unmarshal(r.Aud, &one) == nil {
+		return []string{one}
+	}
+	var many []string
+	_ = json.Unmarshal(r.Aud, &many)
+	return many
+}
+
+// Verify is a TokenVerifier of the MCP SDK returning the scopes and expiry of token, with its
+// subject and client under the sub and client_id Extra keys.
+func (i Introspection) Verify(ctx context.Context, token string, _ *http.Request) (*mcpauth.TokenInfo, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("http.NewRequestWithContext failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(i.ClientID), url.QueryEscape(i.ClientSecret))
+
+	client := i.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client.Do failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection failed with status %d", resp.StatusCode)
+	}
+
+	var result introspectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("json.NewDecoder.Decode failed: %w", err)
+	}
+	if !result.Active {
+		return nil, fmt.Errorf("%w: token is not active", mcpauth.ErrInvalidToken)
+	}
+	if !slices.Contains(result.audiences(), i.Resource) {
+		return nil, fmt.Errorf("%w: token was not issued for %s", mcpauth.ErrInvalidToken, i.Resource)
+	}
+
+	return &mcpauth.TokenInfo{
+		Scopes:     strings.Fields(result.Scope),
+		Expiration: time.Unix(result.Exp, 0),
+		Extra:      map[string]any{"sub": result.Sub, "client_id": result.ClientID},
+	}, nil
+}
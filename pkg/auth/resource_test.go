@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectedResource(t *testing.T) {
+	resource := ProtectedResource{
+		Resource:             "https://mcp.example.com/mcp",
+		AuthorizationServers: []string{"https://login.example.com"},
+		Scopes:               []string{"mail"},
+	}
+	metadataURL, err := resource.MetadataURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://mcp.example.com/.well-known/oauth-protected-resource/mcp", metadataURL)
+
+	rec := httptest.NewRecorder()
+	resource.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-protected-resource/mcp", nil))
+	var metadata map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &metadata))
+	assert.Equal(t, "https://mcp.example.com/mcp", metadata["resource"])
+	assert.Equal(t, []any{"https://login.example.com"}, metadata["authorization_servers"])
+	assert.Equal(t, []any{"header"}, metadata["bearer_methods_supported"])
+}
+
+func TestIntrospection(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	introspection := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, "resource-server", id)
+		assert.Equal(t, "s3cret", secret)
+		require.NoError(t, r.ParseForm())
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Form.Get("token") {
+		case "good":
+			_ = json.NewEncoder(w).Encode(map[string]any{"active": true, "scope": "mail profile", "exp": exp, "aud": "https://mcp.example.com/mcp", "sub": "alice"})
+		case "other-audience":
+			_ = json.NewEncoder(w).Encode(map[string]any{"active": true, "scope": "mail", "exp": exp, "aud": []string{"https://api.example.com"}})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]any{"active": false})
+		}
+	}))
+	defer introspection.Close()
+
+	verifier := Introspection{
+		Endpoint:     introspection.URL,
+		ClientID:     "resource-server",
+		ClientSecret: "s3cret",
+		Resource:     "https://mcp.example.com/mcp",
+	}
+	info, err := verifier.Verify(t.Context(), "good", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mail", "profile"}, info.Scopes)
+	assert.Equal(t, exp, info.Expiration.Unix())
+	assert.Equal(t, "alice", info.Extra["sub"])
+
+	_, err = verifier.Verify(t.Context(), "other-audience", nil)
+	assert.ErrorIs(t, err, mcpauth.ErrInvalidToken)
+	_, err = verifier.Verify(t.Context(), "revoked", nil)
+	assert.ErrorIs(t, err, mcpauth.ErrInvalidToken)
+
+	protect := mcpauth.RequireBearerToken(verifier.Verify, &mcpauth.RequireBearerTokenOptions{
+		ResourceMetadataURL: `"https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`,
+		Scopes:              []string{"mail"},
+	})
+	handler := protect(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+	call := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, call("good").Code)
+	rec := call("")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer resource_metadata="https://mcp.example.com/.well-known/oauth-protected-resource/mcp"`, rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized, call("other-audience").Code)
+}
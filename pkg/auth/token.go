@@ -0,0 +1,318 @@
+// Package auth handles OAuth2 token management and persistence.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrTokenNotSet indicates no OAuth token is available.
+	ErrTokenNotSet = errors.New("no token defined")
+	// ErrInvalidState indicates an authorization callback whose state this Token didn't issue or
+	// which expired.
+	ErrInvalidState = errors.New("invalid or expired state parameter")
+)
+
+// Store persists the token between runs.
+type Store interface {
+	// Load returns the stored token, nil if none is stored yet.
+	Load() (*oauth2.Token, error)
+	Save(token *oauth2.Token) error
+	// Delete removes the stored token, if any.
+	Delete() error
+}
+
+// Token manages OAuth2 tokens with thread-safe operations.
+type Token struct {
+	mu         sync.RWMutex
+	cfg        *oauth2.Config
+	token      *oauth2.Token
+	store      Store
+	stateStore map[string]time.Time
+	// saved is the token last loaded from or saved to the store, so Persist skips unchanged ones.
+	saved *oauth2.Token
+}
+
+// NewToken creates a Token manager, loading from disk if path provided.
+func NewToken(cfg *oauth2.Config, persistPath string) (*Token, error) {
+	if persistPath == "" {
+		return NewStoreToken(cfg, nil)
+	}
+	return NewStoreToken(cfg, FileStore(persistPath))
+}
+
+// NewStoreToken creates a Token manager persisted in store, loading the stored token. A nil
+// store keeps the token in memory.
+func NewStoreToken(cfg *oauth2.Config, store Store) (*Token, error) {
+	t := &Token{
+		cfg:        cfg,
+		store:      store,
+		stateStore: make(map[string]time.Time),
+	}
+	if store == nil {
+		return t, nil
+	}
+
+	token, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("store.Load failed: %w", err)
+	}
+	t.token, t.saved = token, token
+
+	return t, nil
+}
+
+// FileStore keeps the token as JSON in a file readable by its owner only.
+type FileStore string
+
+// Load reads the token file, nil if it doesn't exist yet.
+func (f FileStore) Load() (*oauth2.Token, error) {
+	file, err := os.Open(string(f))
+	defer func() { _ = file.Close() }()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("File %s doesn't exist, but will be created at the end", string(f))
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("os.Open failed: %w", err)
+	}
+
+	token := &oauth2.Token{}
+	if err := json.NewDecoder(file).Decode(token); err != nil {
+		return nil, fmt.Errorf("json.NewDecoder.Decode failed: %w", err)
+	}
+
+	return token, nil
+}
+
+// Save writes the token file.
+func (f FileStore) Save(token *oauth2.Token) error {
+	file, err := os.OpenFile(string(f), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	defer func() { _ = file.Close() }()
+	if err != nil {
+		return fmt.Errorf("os.OpenFile failed: %w", err)
+	}
+
+	if err := json.NewEncoder(file).Encode(token); err != nil {
+		return fmt.Errorf("json.NewEncoder.Encode failed: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the token file, if it exists.
+func (f FileStore) Delete() error {
+	if err := os.Remove(string(f)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("os.Remove failed: %w", err)
+	}
+
+	return nil
+}
+
+// RedirectURL generates the OAuth2 authorization URL with a secure random state.
+func (t *Token) RedirectURL() (string, error) {
+	state, err := t.generateState()
+	if err != nil {
+		return "", fmt.Errorf("generateState failed: %w", err)
+	}
+
+	return t.cfg.AuthCodeURL(state, oauth2.AccessTypeOffline), nil
+}
+
+// ConsentURL generates the OAuth2 authorization URL granting scopes in addition to those of the
+// config and those granted before, through incremental authorization.
+func (t *Token) ConsentURL(scopes []string) (string, error) {
+	state, err := t.generateState()
+	if err != nil {
+		return "", fmt.Errorf("generateState failed: %w", err)
+	}
+
+	all := slices.Compact(slices.Sorted(slices.Values(append(slices.Clone(t.cfg.Scopes), scopes...))))
+	return t.cfg.AuthCodeURL(state, oauth2.AccessTypeOffline,
+		oauth2.SetAuthURLParam("include_granted_scopes", "true"),
+		oauth2.SetAuthURLParam("scope", strings.Join(all, " "))), nil
+}
+
+func (t *Token) generateState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rand.Read failed: %w", err)
+	}
+	state := base64.URLEncoding.EncodeToString(b)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.stateStore[state] = now.Add(5 * time.Minute)
+
+	for s, exp := range t.stateStore {
+		if exp.Before(now) {
+			delete(t.stateStore, s)
+		}
+	}
+
+	return state, nil
+}
+
+func (t *Token) validateState(state string) bool {
+	if state == "" {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	expiry, exists := t.stateStore[state]
+	if !exists {
+		return false
+	}
+
+	delete(t.stateStore, state)
+
+	return !time.Now().After(expiry)
+}
+
+// AuthorizeCode exchanges an authorization code for an access token after validating state.
+func (t *Token) AuthorizeCode(ctx context.Context, code string, state string) error {
+	if !t.validateState(state) {
+		return ErrInvalidState
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tok, err := t.cfg.Exchange(ctx, code)
+	if err != nil {
+		return fmt.Errorf("cfg.Exchange failed: %w", err)
+	}
+
+	t.token = tok
+
+	return nil
+}
+
+// AuthorizeDevice obtains a token through the OAuth2 device authorization flow, for servers
+// without a browser or a redirect URL the browser can reach: prompt is passed the code the user
+// enters at the verification URL on another device, and the token endpoint is polled until they
+// do, the code expires or ctx is done.
+func (t *Token) AuthorizeDevice(ctx context.Context, prompt func(*oauth2.DeviceAuthResponse)) error {
+	resp, err := t.cfg.DeviceAuth(ctx, oauth2.AccessTypeOffline)
+	if err != nil {
+		return fmt.Errorf("cfg.DeviceAuth failed: %w", err)
+	}
+	prompt(resp)
+
+	tok, err := t.cfg.DeviceAccessToken(ctx, resp)
+	if err != nil {
+		return fmt.Errorf("cfg.DeviceAccessToken failed: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.token = tok
+
+	return nil
+}
+
+// OAuthToken returns the current OAuth2 token.
+func (t *Token) OAuthToken() (*oauth2.Token, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.token == nil {
+		return nil, ErrTokenNotSet
+	}
+
+	return t.token, nil
+}
+
+// Clear forgets the token and removes it from its store, so the server has to be authorized
+// again. Tokens issued for other accounts or stores are kept.
+func (t *Token) Clear() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.token, t.saved = nil, nil
+	if t.store == nil {
+		return nil
+	}
+
+	if err := t.store.Delete(); err != nil {
+		return fmt.Errorf("store.Delete failed: %w", err)
+	}
+
+	return nil
+}
+
+// Persist saves the token to its store, unless it is the one stored already.
+func (t *Token) Persist() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.store == nil || t.token == nil || t.token == t.saved {
+		return nil
+	}
+
+	if err := t.store.Save(t.token); err != nil {
+		return fmt.Errorf("store.Save failed: %w", err)
+	}
+	t.saved = t.token
+
+	return nil
+}
+
+// TokenSource returns a source of valid tokens starting from token, as returned by OAuthToken,
+// and refreshing it with ctx. Refreshed tokens replace token, so Persist saves them instead of
+// the one loaded at start, unless the Token was authorized again meanwhile.
+func (t *Token) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return &refreshingSource{t: t, base: t.cfg.TokenSource(ctx, token), last: token}
+}
+
+// refreshingSource keeps the tokens its base refreshes in the Token.
+type refreshingSource struct {
+	t    *Token
+	base oauth2.TokenSource
+
+	mu   sync.Mutex
+	last *oauth2.Token
+}
+
+func (s *refreshingSource) Token() (*oauth2.Token, error) {
+	token, err := s.base.Token()
+	if err != nil {
+		return nil, fmt.Errorf("base.Token failed: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token == s.last {
+		return token, nil
+	}
+
+	s.t.mu.Lock()
+	if s.t.token == s.last {
+		s.t.token = token
+	}
+	s.t.mu.Unlock()
+	s.last = token
+
+	return token, nil
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestTokenSourceKeepsRefreshedToken(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh", r.Form.Get("refresh_token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"refreshed","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	cfg := &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{TokenURL: tokenServer.URL}}
+	store := &memStore{token: &oauth2.Token{AccessToken: "expired", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}}
+	tok, err := NewStoreToken(cfg, store)
+	require.NoError(t, err)
+
+	loaded, err := tok.OAuthToken()
+	require.NoError(t, err)
+	refreshed, err := tok.TokenSource(context.Background(), loaded).Token()
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed", refreshed.AccessToken)
+
+	current, err := tok.OAuthToken()
+	require.NoError(t, err)
+	assert.Same(t, refreshed, current)
+
+	require.NoError(t, tok.Persist())
+	assert.Equal(t, "refreshed", store.token.AccessToken)
+	assert.Equal(t, "refresh", store.token.RefreshToken, "the refresh token is kept when none is issued")
+
+	store.token = nil
+	require.NoError(t, tok.Persist())
+	assert.Nil(t, store.token, "a saved token is not saved again")
+}
+
+func TestFileStore(t *testing.T) {
+	store := FileStore(filepath.Join(t.TempDir(), "token.json"))
+
+	token, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, token, "a missing file holds no token")
+
+	require.NoError(t, store.Save(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}))
+	info, err := os.Stat(string(store))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	token, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.Equal(t, "refresh", token.RefreshToken)
+
+	require.NoError(t, store.Delete())
+	require.NoError(t, store.Delete(), "deleting a missing file succeeds")
+	token, err = store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, token)
+}
+
+func TestAuthorizeDevice(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client", r.Form.Get("client_id"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"device_code":"device","user_code":"ABCD-EFGH","verification_url":"https://example.com/device","expires_in":600,"interval":1}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "device", r.Form.Get("device_code"))
+		w.Header().Set("Content-Type", "application/json")
+		if polls++; polls == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"device-access","token_type":"Bearer","expires_in":3600}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{DeviceAuthURL: server.URL + "/device", TokenURL: server.URL + "/token"}}
+	store := &memStore{}
+	tok, err := NewStoreToken(cfg, store)
+	require.NoError(t, err)
+
+	var prompted *oauth2.DeviceAuthResponse
+	require.NoError(t, tok.AuthorizeDevice(context.Background(), func(resp *oauth2.DeviceAuthResponse) { prompted = resp }))
+	require.NotNil(t, prompted)
+	assert.Equal(t, "ABCD-EFGH", prompted.UserCode)
+	assert.Equal(t, "https://example.com/device", prompted.VerificationURI)
+	assert.Equal(t, 2, polls, "the token endpoint is polled until the user enters the code")
+
+	current, err := tok.OAuthToken()
+	require.NoError(t, err)
+	assert.Equal(t, "device-access", current.AccessToken)
+
+	require.NoError(t, tok.Persist())
+	assert.Equal(t, "device-access", store.token.AccessToken)
+}
+
+func TestAuthorizeDeviceCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/device" {
+			_, _ = w.Write([]byte(`{"device_code":"device","user_code":"ABCD-EFGH","verification_url":"https://example.com/device","expires_in":600,"interval":1}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"authorization_pending"}`))
+	}))
+	defer server.Close()
+
+	cfg := &oauth2.Config{ClientID: "client", Endpoint: oauth2.Endpoint{DeviceAuthURL: server.URL + "/device", TokenURL: server.URL + "/token"}}
+	tok, err := NewStoreToken(cfg, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = tok.AuthorizeDevice(ctx, func(*oauth2.DeviceAuthResponse) { cancel() })
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = tok.OAuthToken()
+	assert.ErrorIs(t, err, ErrTokenNotSet, "a canceled flow leaves the token unset")
+}
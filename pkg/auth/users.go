@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrUnknownKey indicates a bearer key no user authorized the server with.
+var ErrUnknownKey = errors.New("unknown key")
+
+// Users keeps a token per user of a server several people share. A user authorizes the server
+// through the OAuth flow of its HTTP handler and is issued a random bearer key; requests carrying
+// the key act with the token of that user only. Users are identified by a hash of their key, so
+// the key itself is never stored.
+type Users struct {
+	cfg   *oauth2.Config
+	store func(id string) Store
+	// flows issues and validates the states of OAuth flows in progress.
+	flows *Token
+
+	mu     sync.Mutex
+	tokens map[string]*Token
+}
+
+type userKey struct{}
+
+type user struct {
+	id    string
+	token *Token
+}
+
+// NewUsers creates a token manager of several users, whose tokens are persisted in the stores
+// store returns for their IDs; a nil store keeps them in memory.
+func NewUsers(cfg *oauth2.Config, store func(id string) Store) *Users {
+	return &Users{
+		cfg:    cfg,
+		store:  store,
+		flows:  &Token{cfg: cfg, stateStore: make(map[string]time.Time)},
+		tokens: make(map[string]*Token),
+	}
+}
+
+// userID derives the ID of the user holding key.
+func userID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuthorizeCode exchanges an authorization code for the token of a new user after validating
+// state, and returns the bearer key of the user.
+func (u *Users) AuthorizeCode(ctx context.Context, code, state string) (string, error) {
+	if !u.flows.validateState(state) {
+		return "", ErrInvalidState
+	}
+
+	oauthTok, err := u.cfg.Exchange(ctx, code)
+	if err != nil {
+		return "", fmt.Errorf("cfg.Exchange failed: %w", err)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("rand.Read failed: %w", err)
+	}
+	key := base64.RawURLEncoding.EncodeToString(b)
+	id := userID(key)
+
+	t := &Token{cfg: u.cfg, token: oauthTok, stateStore: make(map[string]time.Time)}
+	if u.store != nil {
+		t.store = u.store(id)
+	}
+	if err := t.Persist(); err != nil {
+		return "", fmt.Errorf("t.Persist failed: %w", err)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.tokens[id] = t
+
+	return key, nil
+}
+
+// Token returns the ID and token of the user holding key, loading the token from its store the
+// first time, or ErrUnknownKey.
+func (u *Users) Token(key string) (string, *Token, error) {
+	id := userID(key)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if t, ok := u.tokens[id]; ok {
+		return id, t, nil
+	}
+	if u.store == nil {
+		return "", nil, ErrUnknownKey
+	}
+
+	t, err := NewStoreToken(u.cfg, u.store(id))
+	if err != nil {
+		return "", nil, fmt.Errorf("NewStoreToken failed: %w", err)
+	}
+	if t.token == nil {
+		return "", nil, ErrUnknownKey
+	}
+	u.tokens[id] = t
+
+	return id, t, nil
+}
+
+// Clear forgets the token of the user id and removes it from its store, so the key of the user
+// no longer authenticates.
+func (u *Users) Clear(id string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	t, ok := u.tokens[id]
+	if !ok {
+		return nil
+	}
+	delete(u.tokens, id)
+
+	if err := t.Clear(); err != nil {
+		return fmt.Errorf("t.Clear failed: %w", err)
+	}
+
+	return nil
+}
+
+// Persist saves the tokens of all users loaded so far.
+func (u *Users) Persist() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	var errs []error
+	for _, t := range u.tokens {
+		errs = append(errs, t.Persist())
+	}
+	return errors.Join(errs...)
+}
+
+// Authenticate passes requests carrying the bearer key of a user in their Authorization header
+// on to next with the user in their context, see UserFromContext, and refuses others.
+func (u *Users) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || key == "" {
+			http.Error(w, "Missing bearer key, authorize at /oauth?redirect=1", http.StatusUnauthorized)
+			return
+		}
+
+		id, t, err := u.Token(key)
+		if errors.Is(err, ErrUnknownKey) {
+			http.Error(w, "Unknown bearer key, authorize at /oauth?redirect=1", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			log.Println(fmt.Errorf("u.Token failed: %w", err))
+			http.Error(w, "Unable to load token", http.StatusInternalServerError)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user{id: id, token: t})))
+	})
+}
+
+// UserFromContext returns the ID and token of the user Authenticate passed a request on for.
+func UserFromContext(ctx context.Context) (string, *Token, bool) {
+	u, ok := ctx.Value(userKey{}).(user)
+	return u.id, u.token, ok
+}
+
+// ServeHTTP runs the OAuth flow of a new user: ?redirect=1 starts it and the callback shows the
+// bearer key the user configures their MCP client with.
+func (u *Users) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("redirect") != "" {
+		rURL, err := u.flows.RedirectURL()
+		if err != nil {
+			log.Println(fmt.Errorf("u.flows.RedirectURL failed: %w", err))
+			http.Error(w, "Unable to generate RedirectURL", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, rURL, http.StatusFound)
+		return
+	}
+
+	if code := r.URL.Query().Get("code"); code != "" {
+		key, err := u.AuthorizeCode(r.Context(), code, r.URL.Query().Get("state"))
+		if err != nil {
+			log.Println(fmt.Errorf("u.AuthorizeCode failed: %w", err))
+			http.Error(w, "Unable to authorize provided code", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintf(w, "<p>Authorized. Configure your MCP client to send this header, it is shown only once:</p><pre>Authorization: Bearer %s</pre>",
+			html.EscapeString(key))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, "Authorize at ?redirect=1 to get a bearer key for /mcp")
+}
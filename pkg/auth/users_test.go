@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestUsers(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"access-` + r.Form.Get("code") + `","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	cfg := &oauth2.Config{
+		ClientID:    "client",
+		RedirectURL: "http://localhost/oauth",
+		Endpoint:    oauth2.Endpoint{AuthURL: "http://accounts.example/auth", TokenURL: tokenServer.URL},
+	}
+	stored := map[string]*memStore{}
+	users := NewUsers(cfg, func(id string) Store {
+		if stored[id] == nil {
+			stored[id] = &memStore{}
+		}
+		return stored[id]
+	})
+
+	authorize := func(code string) string {
+		rec := httptest.NewRecorder()
+		users.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth?redirect=1", nil))
+		require.Equal(t, http.StatusFound, rec.Code)
+		redirect, err := url.Parse(rec.Header().Get("Location"))
+		require.NoError(t, err)
+
+		rec = httptest.NewRecorder()
+		users.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth?code="+code+"&state="+url.QueryEscape(redirect.Query().Get("state")), nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		key := regexp.MustCompile(`Bearer (\S+)</pre>`).FindStringSubmatch(rec.Body.String())
+		require.Len(t, key, 2)
+		return key[1]
+	}
+	aliceKey := authorize("alice")
+	bobKey := authorize("bob")
+	assert.NotEqual(t, aliceKey, bobKey)
+
+	var seen string
+	handler := users.Authenticate(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, tok, ok := UserFromContext(r.Context())
+		require.True(t, ok)
+		oauthTok, err := tok.OAuthToken()
+		require.NoError(t, err)
+		seen = oauthTok.AccessToken
+	}))
+	call := func(key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call(aliceKey))
+	assert.Equal(t, "access-alice", seen)
+	assert.Equal(t, http.StatusOK, call(bobKey))
+	assert.Equal(t, "access-bob", seen)
+	assert.Equal(t, http.StatusUnauthorized, call(""))
+	assert.Equal(t, http.StatusUnauthorized, call("guess"))
+
+	// A restarted server finds the user in the store by the key.
+	restarted := NewUsers(cfg, func(id string) Store { return stored[id] })
+	_, tok, err := restarted.Token(aliceKey)
+	require.NoError(t, err)
+	oauthTok, err := tok.OAuthToken()
+	require.NoError(t, err)
+	assert.Equal(t, "access-alice", oauthTok.AccessToken)
+
+	rec := httptest.NewRecorder()
+	users.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth?code=eve&state=forged", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Clearing credentials logs the user out, here and after a restart.
+	id, _, err := users.Token(bobKey)
+	require.NoError(t, err)
+	require.NoError(t, users.Clear(id))
+	assert.Equal(t, http.StatusUnauthorized, call(bobKey))
+	_, _, err = NewUsers(cfg, func(id string) Store { return stored[id] }).Token(bobKey)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+type memStore struct {
+	token *oauth2.Token
+}
+
+func (s *memStore) Load() (*oauth2.Token, error) { return s.token, nil }
+
+func (s *memStore) Save(token *oauth2.Token) error {
+	s.token = token
+	return nil
+}
+
+func (s *memStore) Delete() error {
+	s.token = nil
+	return nil
+}
@@ -0,0 +1,130 @@
+// Package diskcache persists values with expiry times as JSON files, so repeated sessions over the
+// same mailbox skip API calls and conversions done before.
+package diskcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hal9000y/gmail-mcp/internal/jsonfile"
+)
+
+// Cache stores each entry as a file named by the digest of its key, grouped in a directory per
+// bucket. A nil Cache stores nothing, so callers can leave caching off without checks.
+type Cache struct {
+	dir string
+	now func() time.Time
+}
+
+type record struct {
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// New creates a Cache in dir, creating the directory if needed.
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll failed: %w", err)
+	}
+	return &Cache{dir: dir, now: time.Now}, nil
+}
+
+// Get decodes the unexpired entry of key in bucket into value and reports whether it was found.
+// Expired and unreadable entries are removed.
+func (c *Cache) Get(bucket, key string, value any) bool {
+	if c == nil {
+		return false
+	}
+
+	path := c.path(bucket, key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("Reading cache entry %s failed: %v", path, err)
+		}
+		return false
+	}
+
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil || c.now().After(rec.Expires) || json.Unmarshal(rec.Value, value) != nil {
+		_ = os.Remove(path)
+		return false
+	}
+	return true
+}
+
+// Put stores value as the entry of key in bucket for ttl.
+func (c *Cache) Put(bucket, key string, value any, ttl time.Duration) error {
+	if c == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("json.Marshal failed: %w", err)
+	}
+	data, err := json.Marshal(record{Expires: c.now().Add(ttl), Value: encoded})
+	if err != nil {
+		return fmt.Errorf("json.Marshal failed: %w", err)
+	}
+
+	if err := jsonfile.Write(c.path(bucket, key), data); err != nil {
+		return fmt.Errorf("jsonfile.Write failed: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the entry of key in bucket, if any.
+func (c *Cache) Delete(bucket, key string) error {
+	if c == nil {
+		return nil
+	}
+	if err := os.Remove(c.path(bucket, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("os.Remove failed: %w", err)
+	}
+	return nil
+}
+
+// Prune removes expired entries of every bucket and returns how many it removed.
+func (c *Cache) Prune() (int, error) {
+	if c == nil {
+		return 0, nil
+	}
+
+	removed := 0
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var rec record
+		if json.Unmarshal(data, &rec) == nil && !c.now().After(rec.Expires) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("filepath.WalkDir failed: %w", err)
+	}
+	return removed, nil
+}
+
+func (c *Cache) path(bucket, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, bucket, hex.EncodeToString(sum[:])+".json")
+}
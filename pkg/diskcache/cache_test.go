@@ -0,0 +1,60 @@
+package diskcache_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/diskcache"
+)
+
+type message struct {
+	ID     string   `json:"id"`
+	Labels []string `json:"labels"`
+}
+
+func TestCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	cache, err := diskcache.New(dir)
+	require.NoError(t, err)
+
+	stored := message{ID: "m-1", Labels: []string{"INBOX"}}
+	require.NoError(t, cache.Put("messages", "m-1", stored, time.Hour))
+	require.NoError(t, cache.Put("messages", "m-2", message{ID: "m-2"}, -time.Second))
+
+	// Entries survive reopening the cache.
+	reopened, err := diskcache.New(dir)
+	require.NoError(t, err)
+
+	var loaded message
+	assert.True(t, reopened.Get("messages", "m-1", &loaded))
+	assert.Equal(t, stored, loaded)
+
+	assert.False(t, reopened.Get("messages", "m-2", &loaded), "expired entry should not be returned")
+	assert.False(t, reopened.Get("bodies", "m-1", &loaded), "buckets should be separate")
+
+	require.NoError(t, reopened.Delete("messages", "m-1"))
+	assert.False(t, reopened.Get("messages", "m-1", &loaded))
+	require.NoError(t, reopened.Delete("messages", "missing"))
+
+	require.NoError(t, cache.Put("messages", "m-1", stored, time.Hour))
+	require.NoError(t, cache.Put("messages", "m-3", message{ID: "m-3"}, -time.Second))
+	removed, err := reopened.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.True(t, reopened.Get("messages", "m-1", &loaded))
+}
+
+func TestNilCache(t *testing.T) {
+	var cache *diskcache.Cache
+
+	require.NoError(t, cache.Put("messages", "m-1", message{ID: "m-1"}, time.Hour))
+	var loaded message
+	assert.False(t, cache.Get("messages", "m-1", &loaded))
+	removed, err := cache.Prune()
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
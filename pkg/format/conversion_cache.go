@@ -0,0 +1,35 @@
+package format
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+)
+
+// cachedConversion returns the cached result of converting raw with the given kind of conversion
+// and options, running convert on a miss. Keys cover the converter settings that shape the
+// output, so changing the dialect or pandoc arguments doesn't return stale conversions. Failed
+// conversions are not cached.
+func cachedConversion[T any](c Converter, kind string, raw []byte, opts any, convert func() (T, error)) (T, error) {
+	if c.Cache == nil {
+		return convert()
+	}
+
+	sum := sha256.Sum256(raw)
+	key := fmt.Sprintf("%s|%s|%t|%q|%+v", hex.EncodeToString(sum[:]), c.Dialect, c.NoExternalTools, c.PandocArgs, opts)
+
+	var result T
+	if c.Cache.Get(kind, key, &result) {
+		return result, nil
+	}
+
+	result, err := convert()
+	if err != nil {
+		return result, err
+	}
+	if err := c.Cache.Put(kind, key, result, c.CacheTTL); err != nil {
+		log.Printf("Caching %s conversion failed: %v", kind, err)
+	}
+	return result, nil
+}
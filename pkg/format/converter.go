@@ -0,0 +1,282 @@
+// Package format provides document format conversion utilities.
+package format
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hal9000y/gmail-mcp/pkg/diskcache"
+)
+
+const (
+	cmdPandoc    = "pandoc"
+	cmdPdfToText = "pdftotext"
+)
+
+// MarkdownDialect is the pandoc output format HTML and DOCX are converted to.
+type MarkdownDialect string
+
+// Markdown dialects pandoc can write.
+const (
+	// DialectCommonMark balances structure preservation and token efficiency: it keeps links,
+	// emphasis and lists while being about half the size of the original HTML.
+	DialectCommonMark MarkdownDialect = "commonmark"
+	// DialectGFM is GitHub Flavored Markdown, which adds pipe tables, strikethrough and autolinks.
+	DialectGFM MarkdownDialect = "gfm"
+	// DialectPlain is plain text without markup.
+	DialectPlain MarkdownDialect = "plain"
+)
+
+// ParseMarkdownDialect validates a dialect name, an empty name means DialectCommonMark.
+func ParseMarkdownDialect(name string) (MarkdownDialect, error) {
+	switch d := MarkdownDialect(strings.ToLower(strings.TrimSpace(name))); d {
+	case "":
+		return DialectCommonMark, nil
+	case DialectCommonMark, DialectGFM, DialectPlain:
+		return d, nil
+	default:
+		return "", fmt.Errorf("unknown markdown dialect %q, expected %s, %s or %s", name, DialectCommonMark, DialectGFM, DialectPlain)
+	}
+}
+
+// Converter handles document format conversions.
+type Converter struct {
+	// NoExternalTools disables pandoc and pdftotext, leaving only the built-in converters.
+	NoExternalTools bool
+	// Dialect is the pandoc output format, empty for DialectCommonMark. Data tables of HTML
+	// bodies are pipe tables in both markdown dialects, and the built-in HTML converter always
+	// writes CommonMark with pipe tables.
+	Dialect MarkdownDialect
+	// PandocArgs are extra arguments passed to pandoc, like --columns or --lua-filter.
+	PandocArgs []string
+	// PandocPath and PdfToTextPath locate the tools, empty to look them up on PATH.
+	PandocPath    string
+	PdfToTextPath string
+	// Timeout bounds each external tool run, zero for no limit. The process is killed when
+	// it runs longer.
+	Timeout time.Duration
+	// Limits restricts the resources of external tool runs.
+	Limits ToolLimits
+	// Cache keeps conversions of HTML, DOCX and PDF content for CacheTTL across runs, nil to
+	// convert every time.
+	Cache    *diskcache.Cache
+	CacheTTL time.Duration
+}
+
+// HTML2MD converts HTML content to Markdown. Without pandoc it falls back to HTMLToMarkdown.
+func (c Converter) HTML2MD(raw []byte) (string, error) {
+	return cachedConversion(c, "html2md", raw, nil, func() (string, error) {
+		return c.html2md(raw)
+	})
+}
+
+func (c Converter) html2md(raw []byte) (string, error) {
+	simplified := SimplifyHTML(raw)
+	path, err := c.externalTool(cmdPandoc, c.PandocPath)
+	if err != nil {
+		return HTMLToMarkdown(simplified)
+	}
+	if c.Dialect == DialectPlain {
+		return c.pandoc(path, bytes.NewReader(simplified), "html")
+	}
+
+	// Pandoc writes tables CommonMark can't express as raw HTML, and GFM tables with complex
+	// cells too, so data tables are rendered here.
+	protected, tables := extractDataTables(simplified)
+	markdown, err := c.pandoc(path, bytes.NewReader(protected), "html")
+	if err != nil {
+		return "", err
+	}
+	return restoreDataTables(markdown, tables), nil
+}
+
+// DOCX2MD converts a Word document to Markdown.
+func (c Converter) DOCX2MD(raw []byte) (string, error) {
+	return cachedConversion(c, "docx2md", raw, nil, func() (string, error) {
+		return c.docx2md(raw)
+	})
+}
+
+func (c Converter) docx2md(raw []byte) (string, error) {
+	path, err := c.externalTool(cmdPandoc, c.PandocPath)
+	if err != nil {
+		return "", err
+	}
+	// Binary formats like docx can't be read from stdin.
+	return withTempFile(raw, "docx-*.docx", func(name string) (string, error) {
+		return c.pandoc(path, nil, "docx", name)
+	})
+}
+
+// externalTool resolves the named command, at the configured path if set, or reports why it
+// can't be run.
+func (c Converter) externalTool(name, configured string) (string, error) {
+	if c.NoExternalTools {
+		return "", fmt.Errorf("%s disabled: external tools are turned off", name)
+	}
+	if configured == "" {
+		configured = name
+	}
+	path, err := exec.LookPath(configured)
+	if err != nil {
+		return "", fmt.Errorf("%s not available: %w", name, err)
+	}
+	return path, nil
+}
+
+// run runs an external tool within the configured limits, streaming stdin to it, and returns its
+// output, killing it once the timeout passes or the output grows too large.
+func (c Converter) run(name, path string, stdin io.Reader, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if c.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, c.Timeout)
+		defer cancelTimeout()
+	}
+
+	output := &cappedBuffer{max: c.Limits.MaxOutputBytes, exceeded: cancel}
+	start := func(isolate bool) (*exec.Cmd, error) {
+		output.buf.Reset()
+		cmd := c.Limits.command(ctx, path, args, isolate)
+		cmd.Stdin = stdin
+		cmd.Stdout = output
+		log.Printf("Running command: %s", cmd.String())
+		return cmd, cmd.Start()
+	}
+
+	cmd, err := start(c.Limits.NoNetwork)
+	if err != nil && c.Limits.NoNetwork {
+		// User namespaces may be disabled, e.g. inside containers.
+		log.Printf("Network isolation unavailable, running %s without it: %v", name, err)
+		cmd, err = start(false)
+	}
+	if err == nil {
+		err = cmd.Wait()
+	}
+
+	switch {
+	case output.overflow:
+		return nil, fmt.Errorf("%s output exceeds %d bytes", name, c.Limits.MaxOutputBytes)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("%s timed out after %s", name, c.Timeout)
+	case err != nil:
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+	return output.buf.Bytes(), nil
+}
+
+// pandoc converts input of the given pandoc format to the configured dialect. Input is streamed
+// through stdin unless input files are given.
+func (c Converter) pandoc(path string, stdin io.Reader, from string, inputFiles ...string) (string, error) {
+	dialect := c.Dialect
+	if dialect == "" {
+		dialect = DialectCommonMark
+	}
+	args := append([]string{"-f", from, "-t", string(dialect), "--wrap=none"}, c.PandocArgs...)
+	output, err := c.run(cmdPandoc, path, stdin, append(args, inputFiles...)...)
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+// withTempFile writes raw to a temporary file for the duration of fn.
+func withTempFile(raw []byte, pattern string, fn func(name string) (string, error)) (string, error) {
+	tmpFile, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("os.CreateTemp failed: %w", err)
+	}
+	defer func() {
+		if err := tmpFile.Close(); err != nil {
+			log.Println(fmt.Errorf("tmpFile.Close failed: %w", err))
+		}
+		if err := os.Remove(tmpFile.Name()); err != nil {
+			log.Println(fmt.Errorf("os.Remove(%s) failed: %w", tmpFile.Name(), err))
+		}
+	}()
+
+	if _, err := tmpFile.Write(raw); err != nil {
+		return "", fmt.Errorf("tmpFile.Write failed: %w", err)
+	}
+	return fn(tmpFile.Name())
+}
+
+// PDFOptions selects the pages PDF2Text extracts.
+type PDFOptions struct {
+	// FirstPage is the 1-based first page, zero for the first page of the document.
+	FirstPage int
+	// LastPage is the inclusive last page, zero for the last page of the document.
+	LastPage int
+}
+
+// pageRange resolves the options against a document of total pages. The range is empty, with
+// first above last, when it lies past the end.
+func (o PDFOptions) pageRange(total int) (first, last int) {
+	first, last = max(o.FirstPage, 1), total
+	if o.LastPage > 0 {
+		last = min(o.LastPage, total)
+	}
+	return first, last
+}
+
+// PDFText is the text of the selected pages of a PDF.
+type PDFText struct {
+	// Text holds the pages, each followed by a form feed.
+	Text string
+	// TotalPages is the page count of the document, zero when it couldn't be determined.
+	TotalPages int
+}
+
+// PDF2Text extracts plain text from the selected pages of a PDF. Without pdftotext it falls back
+// to PDFToText.
+func (c Converter) PDF2Text(raw []byte, opts PDFOptions) (PDFText, error) {
+	return cachedConversion(c, "pdf2text", raw, opts, func() (PDFText, error) {
+		return c.pdf2Text(raw, opts)
+	})
+}
+
+func (c Converter) pdf2Text(raw []byte, opts PDFOptions) (PDFText, error) {
+	path, err := c.externalTool(cmdPdfToText, c.PdfToTextPath)
+	if err != nil {
+		return PDFToText(raw, opts)
+	}
+
+	// pdftotext doesn't report the page count, the built-in parser reads it from the page tree.
+	var result PDFText
+	if total, err := PDFPageCount(raw); err == nil {
+		result.TotalPages = total
+		// pdftotext rejects ranges past the end of the document.
+		if first, last := opts.pageRange(total); first > last {
+			return result, nil
+		}
+	}
+
+	// -layout: maintain original physical layout
+	// -f/-l: first and last page to convert
+	// - -: read the PDF from stdin and write text to stdout
+	args := []string{"-layout"}
+	if opts.FirstPage > 0 {
+		args = append(args, "-f", strconv.Itoa(opts.FirstPage))
+	}
+	if opts.LastPage > 0 {
+		args = append(args, "-l", strconv.Itoa(opts.LastPage))
+	}
+	output, err := c.run(cmdPdfToText, path, bytes.NewReader(raw), append(args, "-", "-")...)
+	if err != nil {
+		return PDFText{}, err
+	}
+
+	result.Text = string(output)
+	return result, nil
+}
@@ -0,0 +1,333 @@
+package format_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/diskcache"
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestPDF2Text(t *testing.T) {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		t.Skip("pdftotext not found in PATH")
+	}
+
+	override := os.Getenv("OVERRIDE") != ""
+	cases := []struct {
+		name     string
+		pdfFile  string
+		textFile string
+	}{
+		{
+			name:     "general",
+			pdfFile:  "./testdata/test.pdf",
+			textFile: "./testdata/test.txt",
+		},
+	}
+
+	cnv := format.Converter{}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pdfData, err := os.ReadFile(tc.pdfFile)
+			require.NoError(t, err, "failed to read PDF file")
+
+			pdfText, err := cnv.PDF2Text(pdfData, format.PDFOptions{})
+			require.NoError(t, err, "PDF2Text failed")
+			result := pdfText.Text
+
+			if override {
+				err := os.WriteFile(tc.textFile, []byte(result), 0644)
+				require.NoError(t, err, "failed to write override file")
+				t.Log("Override mode: wrote output to", tc.textFile)
+				return
+			}
+
+			expected, err := os.ReadFile(tc.textFile)
+			require.NoError(t, err, "failed to read expected text file")
+
+			assert.Equal(t, strings.TrimSpace(string(expected)), strings.TrimSpace(result), "PDF2Text output mismatch")
+		})
+	}
+}
+
+func TestHTML2MD(t *testing.T) {
+	if _, err := exec.LookPath("pandoc"); err != nil {
+		t.Skip("pandoc not found in PATH")
+	}
+
+	override := os.Getenv("OVERRIDE") != ""
+	cases := []struct {
+		name     string
+		htmlFile string
+		mdFile   string
+	}{
+		{
+			name:     "email_with_layout_tables",
+			htmlFile: "./testdata/email_layout.html",
+			mdFile:   "./testdata/email_layout.md",
+		},
+		{
+			name:     "semantic_content_with_data_tables",
+			htmlFile: "./testdata/semantic_content.html",
+			mdFile:   "./testdata/semantic_content.md",
+		},
+	}
+
+	cnv := format.Converter{}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			htmlData, err := os.ReadFile(tc.htmlFile)
+			require.NoError(t, err, "failed to read HTML file")
+
+			result, err := cnv.HTML2MD(htmlData)
+			require.NoError(t, err, "HTML2MD failed")
+
+			if override {
+				err := os.WriteFile(tc.mdFile, []byte(result), 0644)
+				require.NoError(t, err, "failed to write override file")
+				t.Log("Override mode: wrote output to", tc.mdFile)
+				return
+			}
+
+			expected, err := os.ReadFile(tc.mdFile)
+			require.NoError(t, err, "failed to read expected MD file")
+
+			assert.Equal(t, strings.TrimSpace(string(expected)), strings.TrimSpace(result), "HTML2MD output mismatch")
+		})
+	}
+}
+
+func TestDOCX2MD(t *testing.T) {
+	if _, err := exec.LookPath("pandoc"); err != nil {
+		t.Skip("pandoc not found in PATH")
+	}
+
+	override := os.Getenv("OVERRIDE") != ""
+	cases := []struct {
+		name     string
+		docxFile string
+		mdFile   string
+	}{
+		{
+			name:     "paragraphs_with_emphasis",
+			docxFile: "./testdata/test.docx",
+			mdFile:   "./testdata/test_docx.md",
+		},
+	}
+
+	cnv := format.Converter{}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			docxData, err := os.ReadFile(tc.docxFile)
+			require.NoError(t, err, "failed to read DOCX file")
+
+			result, err := cnv.DOCX2MD(docxData)
+			require.NoError(t, err, "DOCX2MD failed")
+
+			if override {
+				err := os.WriteFile(tc.mdFile, []byte(result), 0644)
+				require.NoError(t, err, "failed to write override file")
+				t.Log("Override mode: wrote output to", tc.mdFile)
+				return
+			}
+
+			expected, err := os.ReadFile(tc.mdFile)
+			require.NoError(t, err, "failed to read expected MD file")
+
+			assert.Equal(t, strings.TrimSpace(string(expected)), strings.TrimSpace(result), "DOCX2MD output mismatch")
+		})
+	}
+}
+
+func TestHTML2MDDialects(t *testing.T) {
+	if _, err := exec.LookPath("pandoc"); err != nil {
+		t.Skip("pandoc not found in PATH")
+	}
+
+	html := []byte(`<p>Hello <b>world</b></p><table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>`)
+	cases := []struct {
+		name     string
+		dialect  format.MarkdownDialect
+		contains string
+		excludes string
+	}{
+		{name: "commonmark", dialect: format.DialectCommonMark, contains: "| A | B |", excludes: "<table>"},
+		{name: "gfm", dialect: format.DialectGFM, contains: "| A", excludes: "<table>"},
+		{name: "plain", dialect: format.DialectPlain, contains: "Hello world", excludes: "**"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cnv := format.Converter{Dialect: tc.dialect}
+
+			result, err := cnv.HTML2MD(html)
+			require.NoError(t, err, "HTML2MD failed")
+			assert.Contains(t, result, tc.contains)
+			assert.NotContains(t, result, tc.excludes)
+		})
+	}
+}
+
+func TestParseMarkdownDialect(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected format.MarkdownDialect
+		err      bool
+	}{
+		{input: "", expected: format.DialectCommonMark},
+		{input: "commonmark", expected: format.DialectCommonMark},
+		{input: " GFM ", expected: format.DialectGFM},
+		{input: "plain", expected: format.DialectPlain},
+		{input: "markdown_strict", err: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := format.ParseMarkdownDialect(tc.input)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestConverterToolPathAndTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("tool stubs are shell scripts")
+	}
+
+	t.Run("configured path and arguments", func(t *testing.T) {
+		cnv := format.Converter{
+			PandocPath: toolStub(t, `echo "$@"`),
+			Dialect:    format.DialectGFM,
+			PandocArgs: []string{"--columns=80"},
+		}
+
+		result, err := cnv.DOCX2MD([]byte("docx"))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result, "-f docx -t gfm --wrap=none --columns=80 "), result)
+	})
+
+	t.Run("input streamed through stdin", func(t *testing.T) {
+		cnv := format.Converter{
+			PandocPath:    toolStub(t, `echo "$@"; cat`),
+			PdfToTextPath: toolStub(t, `echo "$@"; cat`),
+		}
+
+		result, err := cnv.HTML2MD([]byte("<p>Hello</p>"))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result, "-f html -t commonmark --wrap=none\n"), result)
+		assert.Contains(t, result, "<p>Hello</p>")
+
+		pdfText, err := cnv.PDF2Text([]byte("%PDF-1.4 content"), format.PDFOptions{FirstPage: 2, LastPage: 3})
+		require.NoError(t, err)
+		assert.Equal(t, format.PDFText{Text: "-layout -f 2 -l 3 - -\n%PDF-1.4 content"}, pdfText)
+	})
+
+	t.Run("missing configured path", func(t *testing.T) {
+		cnv := format.Converter{PandocPath: filepath.Join(t.TempDir(), "missing")}
+
+		_, err := cnv.DOCX2MD([]byte("docx"))
+		assert.ErrorContains(t, err, "pandoc not available")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		cnv := format.Converter{
+			PdfToTextPath: toolStub(t, "sleep 10"),
+			Timeout:       100 * time.Millisecond,
+		}
+
+		start := time.Now()
+		_, err := cnv.PDF2Text([]byte("%PDF-1.4"), format.PDFOptions{})
+		assert.ErrorContains(t, err, "pdftotext timed out after 100ms")
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
+func TestConverterToolLimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("tool stubs are shell scripts")
+	}
+
+	t.Run("output cap", func(t *testing.T) {
+		cnv := format.Converter{
+			PandocPath: toolStub(t, "yes"),
+			Limits:     format.ToolLimits{MaxOutputBytes: 1 << 16},
+			Timeout:    10 * time.Second,
+		}
+
+		_, err := cnv.DOCX2MD([]byte("docx"))
+		assert.ErrorContains(t, err, "pandoc output exceeds 65536 bytes")
+	})
+
+	t.Run("cpu time", func(t *testing.T) {
+		cnv := format.Converter{
+			PandocPath: toolStub(t, "while :; do :; done"),
+			Limits:     format.ToolLimits{CPUTime: time.Second},
+			Timeout:    10 * time.Second,
+		}
+
+		_, err := cnv.DOCX2MD([]byte("docx"))
+		assert.ErrorContains(t, err, "pandoc failed")
+	})
+
+	t.Run("no network", func(t *testing.T) {
+		cnv := format.Converter{
+			PandocPath: toolStub(t, "echo converted"),
+			Limits:     format.ToolLimits{NoNetwork: true, MaxMemoryBytes: 256 << 20},
+		}
+
+		result, err := cnv.DOCX2MD([]byte("docx"))
+		require.NoError(t, err)
+		assert.Equal(t, "converted\n", result)
+	})
+}
+
+// toolStub writes a shell script standing in for an external tool.
+func toolStub(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tool")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	return path
+}
+
+func TestConverterDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := diskcache.New(dir)
+	require.NoError(t, err)
+
+	html := []byte(`<p>Hello <b>world</b></p>`)
+	cnv := format.Converter{NoExternalTools: true, Cache: cache, CacheTTL: time.Hour}
+
+	first, err := cnv.HTML2MD(html)
+	require.NoError(t, err)
+	second, err := cnv.HTML2MD(html)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	entries, err := os.ReadDir(filepath.Join(dir, "html2md"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "repeated conversion should be served from the cache")
+
+	cnv.Dialect = format.DialectGFM
+	_, err = cnv.HTML2MD(html)
+	require.NoError(t, err)
+	entries, err = os.ReadDir(filepath.Join(dir, "html2md"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "converter settings should be part of the key")
+}
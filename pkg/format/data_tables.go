@@ -0,0 +1,165 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// dataTablePlaceholder stands in for a data table while pandoc converts the rest of a body. It
+// is plain alphanumeric text, so every dialect passes it through unchanged.
+const dataTablePlaceholder = "GMAILMCPDATATABLE%dEND"
+
+// maxColspan bounds how many columns one cell may span.
+const maxColspan = 50
+
+// tableRows returns the cells of the rows of a table, without the rows of nested tables.
+func tableRows(table *html.Node) [][]*html.Node {
+	var rows [][]*html.Node
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "tr":
+				var cells []*html.Node
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						cells = append(cells, cell)
+					}
+				}
+				if len(cells) > 0 {
+					rows = append(rows, cells)
+				}
+			case "thead", "tbody", "tfoot":
+				collect(child)
+			}
+		}
+	}
+	collect(table)
+	return rows
+}
+
+// isDataTable reports whether rows hold tabular data: more than one column and no nested tables,
+// which only lay out content.
+func isDataTable(rows [][]*html.Node) bool {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+		for _, cell := range row {
+			if containsElement(cell, "table") {
+				return false
+			}
+		}
+	}
+	return width > 1
+}
+
+func containsElement(n *html.Node, name string) bool {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && (child.Data == name || containsElement(child, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// dataTableMarkdown renders a data table as a pipe table. Cells spanning columns are padded with
+// empty cells. When a cell holds more than a line, like a list, which pipe tables can't show, the
+// table is rendered as a CSV code block instead.
+func dataTableMarkdown(rows [][]*html.Node) string {
+	textRows := make([][]string, len(rows))
+	multiline := false
+	for i, row := range rows {
+		for _, cell := range row {
+			text := strings.ReplaceAll(strings.Join(markdownBlocks(cell), "\n"), "\\\n", " ")
+			multiline = multiline || strings.Contains(text, "\n")
+			textRows[i] = append(textRows[i], text)
+			for range colspan(cell) - 1 {
+				textRows[i] = append(textRows[i], "")
+			}
+		}
+	}
+
+	if multiline {
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.WriteAll(textRows); err == nil {
+			return "```csv\n" + buf.String() + "```"
+		}
+	}
+
+	for i, row := range textRows {
+		for j, text := range row {
+			textRows[i][j] = strings.ReplaceAll(text, "\n", " ")
+		}
+	}
+	var sb strings.Builder
+	writeMarkdownTable(&sb, textRows)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func colspan(cell *html.Node) int {
+	n, err := strconv.Atoi(strings.TrimSpace(attrValue(cell, "colspan")))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return min(n, maxColspan)
+}
+
+// extractDataTables renders the data tables of an HTML document as markdown and replaces them
+// with placeholders, for converters that would write them as raw HTML or lose their structure.
+// Tables wrapping other tables are left in place.
+func extractDataTables(htmlContent []byte) ([]byte, []string) {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent, nil
+	}
+
+	var tables []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; {
+			next := child.NextSibling
+			if child.Type == html.ElementNode && child.Data == "table" && !containsElement(child, "table") {
+				if rows := tableRows(child); isDataTable(rows) {
+					placeholder := &html.Node{Type: html.ElementNode, Data: "p"}
+					placeholder.AppendChild(&html.Node{
+						Type: html.TextNode,
+						Data: fmt.Sprintf(dataTablePlaceholder, len(tables)),
+					})
+					tables = append(tables, dataTableMarkdown(rows))
+					n.InsertBefore(placeholder, child)
+					n.RemoveChild(child)
+				}
+			} else {
+				walk(child)
+			}
+			child = next
+		}
+	}
+	walk(doc)
+
+	if len(tables) == 0 {
+		return htmlContent, nil
+	}
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return htmlContent, nil
+	}
+	return buf.Bytes(), tables
+}
+
+// restoreDataTables puts the tables taken out by extractDataTables back into converted text.
+func restoreDataTables(text string, tables []string) string {
+	for i, table := range tables {
+		text = strings.Replace(text, fmt.Sprintf(dataTablePlaceholder, i), table, 1)
+	}
+	return text
+}
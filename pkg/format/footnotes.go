@@ -0,0 +1,77 @@
+package format
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// footnoteLinkPattern matches, in order of preference, markdown inline links and images with an
+// optional title, autolinks and bare URLs.
+var footnoteLinkPattern = regexp.MustCompile(
+	`\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\((<[^>\n]*>|[^()\s]+)(\s+"[^"\n]*")?\)|<(https?://[^>\s]+)>|https?://[^\s<>"'()\[\]]+`,
+)
+
+// FootnoteLinks moves URLs of at least minLength characters out of markdown or plain text into
+// numbered reference definitions at the end, so long tracking links don't drown the text.
+// Inline links keep their text as [text][n], bare URLs and autolinks become [n]. Repeated URLs
+// share a number. A minLength of zero moves every URL.
+func FootnoteLinks(text string, minLength int) string {
+	var definitions []string
+	numbers := make(map[string]int)
+	footnote := func(u, title string) (string, bool) {
+		if len(u) < minLength {
+			return "", false
+		}
+		definition := "<" + u + ">" + title
+		n, ok := numbers[definition]
+		if !ok {
+			definitions = append(definitions, definition)
+			n = len(definitions)
+			numbers[definition] = n
+		}
+		return "[" + strconv.Itoa(n) + "]", true
+	}
+
+	replaced := footnoteLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := footnoteLinkPattern.FindStringSubmatch(match)
+		var label, trailing string
+		ok := false
+		switch {
+		case groups[2] != "":
+			destination := strings.TrimSuffix(strings.TrimPrefix(groups[2], "<"), ">")
+			if label, ok = footnote(destination, groups[3]); ok {
+				label = "[" + groups[1] + "]" + label
+			}
+		case groups[4] != "":
+			label, ok = footnote(groups[4], "")
+		default:
+			var u string
+			u, trailing = splitTrailingPunctuation(match)
+			label, ok = footnote(u, "")
+		}
+		if !ok {
+			return match
+		}
+		return label + trailing
+	})
+
+	if len(definitions) == 0 {
+		return text
+	}
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(replaced, "\n"))
+	sb.WriteString("\n\n")
+	for i, definition := range definitions {
+		fmt.Fprintf(&sb, "[%d]: %s\n", i+1, definition)
+	}
+	return sb.String()
+}
+
+// splitTrailingPunctuation separates sentence punctuation following a bare URL.
+func splitTrailingPunctuation(u string) (string, string) {
+	trimmed := strings.TrimRight(u, ".,;:!?")
+	return trimmed, u[len(trimmed):]
+}
@@ -0,0 +1,63 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestFootnoteLinks(t *testing.T) {
+	const long = "https://example.com/campaign/2025/spring?id=1234567890"
+
+	cases := []struct {
+		name      string
+		input     string
+		minLength int
+		expected  string
+	}{
+		{
+			name:      "inline link",
+			input:     "Read [the report](" + long + ") today.",
+			minLength: 30,
+			expected:  "Read [the report][1] today.\n\n[1]: <" + long + ">\n",
+		},
+		{
+			name:      "short links stay inline",
+			input:     "See [home](https://example.com) and " + long + ".",
+			minLength: 30,
+			expected:  "See [home](https://example.com) and [1].\n\n[1]: <" + long + ">\n",
+		},
+		{
+			name:      "repeated url shares a number",
+			input:     "[Buy](" + long + ")\n\n[Buy now](" + long + ")\n\n<" + long + "/x>",
+			minLength: 30,
+			expected:  "[Buy][1]\n\n[Buy now][1]\n\n[2]\n\n[1]: <" + long + ">\n[2]: <" + long + "/x>\n",
+		},
+		{
+			name:      "title and nested brackets",
+			input:     "![logo [v2]](<" + long + "> \"Logo\")",
+			minLength: 30,
+			expected:  "![logo [v2]][1]\n\n[1]: <" + long + "> \"Logo\"\n",
+		},
+		{
+			name:      "zero length moves every url",
+			input:     "Visit https://a.io, then [b](https://b.io).\n",
+			minLength: 0,
+			expected:  "Visit [1], then [b][2].\n\n[1]: <https://a.io>\n[2]: <https://b.io>\n",
+		},
+		{
+			name:      "no links",
+			input:     "Plain text without links.",
+			minLength: 0,
+			expected:  "Plain text without links.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, format.FootnoteLinks(tc.input, tc.minLength))
+		})
+	}
+}
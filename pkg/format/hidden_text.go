@@ -0,0 +1,211 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// defaultBackground is the page color mail clients render bodies on.
+const defaultBackground = "#ffffff"
+
+// HiddenContent counts what StripHiddenHTML and StripInvisibleChars removed. Text a reader can't
+// see is a common carrier of prompt injection payloads, so its presence is worth reporting.
+type HiddenContent struct {
+	// InvisibleChars counts zero-width, bidirectional control and Unicode tag characters.
+	InvisibleChars int
+	// HiddenElements counts elements with text that is not displayed, transparent, zero sized
+	// or colored like its background.
+	HiddenElements int
+}
+
+// Found reports whether any hidden content was removed.
+func (h HiddenContent) Found() bool {
+	return h.InvisibleChars > 0 || h.HiddenElements > 0
+}
+
+// StripHiddenHTML removes the text of an HTML body a reader doesn't see: elements that are not
+// displayed, transparent, sized or clipped to nothing, or whose text color matches the
+// background, and invisible characters.
+func StripHiddenHTML(htmlContent []byte) ([]byte, HiddenContent) {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent, HiddenContent{}
+	}
+
+	var hidden HiddenContent
+	stripHiddenNodes(doc, defaultBackground, &hidden)
+	if !hidden.Found() {
+		return htmlContent, hidden
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return htmlContent, HiddenContent{}
+	}
+	return buf.Bytes(), hidden
+}
+
+func stripHiddenNodes(n *html.Node, background string, hidden *HiddenContent) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch child.Type {
+		case html.TextNode:
+			var removed int
+			child.Data, removed = StripInvisibleChars(child.Data)
+			hidden.InvisibleChars += removed
+		case html.ElementNode:
+			style := styleProperties(child)
+			childBackground := background
+			if color := elementBackground(child, style); color != "" {
+				childBackground = color
+			}
+
+			if isInvisibleElement(child, style, childBackground) {
+				if hasTextContent(child) {
+					hidden.HiddenElements++
+				}
+				n.RemoveChild(child)
+				break
+			}
+			stripHiddenNodes(child, childBackground, hidden)
+		}
+		child = next
+	}
+}
+
+func isInvisibleElement(n *html.Node, style map[string]string, background string) bool {
+	if isHidden(n) {
+		return true
+	}
+	if opacity, err := strconv.ParseFloat(style["opacity"], 64); err == nil && opacity <= 0.01 {
+		return true
+	}
+	if isZeroLength(style["font-size"]) {
+		return true
+	}
+	if style["overflow"] == "hidden" {
+		for _, dimension := range []string{"height", "max-height", "width", "max-width"} {
+			if isZeroLength(style[dimension]) {
+				return true
+			}
+		}
+	}
+
+	textColor := parseColor(style["color"])
+	if textColor == "" && n.Data == "font" {
+		textColor = parseColor(attrValue(n, "color"))
+	}
+	return textColor != "" && textColor == background
+}
+
+// elementBackground returns the background color an element sets, empty when it sets none.
+func elementBackground(n *html.Node, style map[string]string) string {
+	if color := parseColor(style["background-color"]); color != "" {
+		return color
+	}
+	for _, value := range strings.Fields(style["background"]) {
+		if color := parseColor(value); color != "" {
+			return color
+		}
+	}
+	return parseColor(attrValue(n, "bgcolor"))
+}
+
+// isZeroLength reports CSS lengths too small to show text: zero in any unit, or below one pixel
+// or point.
+func isZeroLength(value string) bool {
+	number := strings.TrimRight(value, "abcdefghijklmnopqrstuvwxyz%")
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return false
+	}
+	switch unit := value[len(number):]; unit {
+	case "", "px", "pt":
+		return size < 1
+	default:
+		return size == 0
+	}
+}
+
+var namedColors = map[string]string{
+	"white": "#ffffff", "black": "#000000", "red": "#ff0000", "green": "#008000", "blue": "#0000ff",
+	"gray": "#808080", "grey": "#808080", "silver": "#c0c0c0", "yellow": "#ffff00",
+}
+
+// parseColor normalizes a CSS color to #rrggbb, empty for transparent or unrecognized colors.
+func parseColor(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if named, ok := namedColors[value]; ok {
+		return named
+	}
+
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		switch len(hex) {
+		case 3:
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		case 6:
+		default:
+			return ""
+		}
+		if _, err := strconv.ParseUint(hex, 16, 32); err != nil {
+			return ""
+		}
+		return "#" + hex
+	}
+
+	if args, ok := strings.CutPrefix(value, "rgb("); ok {
+		parts := strings.Split(strings.TrimSuffix(args, ")"), ",")
+		if len(parts) != 3 {
+			return ""
+		}
+		var rgb [3]uint64
+		for i, part := range parts {
+			c, err := strconv.ParseUint(strings.TrimSpace(part), 10, 8)
+			if err != nil {
+				return ""
+			}
+			rgb[i] = c
+		}
+		return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+	}
+
+	return ""
+}
+
+// StripInvisibleChars removes characters that render as nothing but reach a model: zero-width
+// spaces and joiners, the byte order mark, soft hyphens, bidirectional controls and Unicode tag
+// characters. It returns the text and the number of characters removed.
+func StripInvisibleChars(text string) (string, int) {
+	if !strings.ContainsFunc(text, isInvisibleChar) {
+		return text, 0
+	}
+
+	removed := 0
+	var sb strings.Builder
+	sb.Grow(len(text))
+	for _, r := range text {
+		if isInvisibleChar(r) {
+			removed++
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String(), removed
+}
+
+func isInvisibleChar(r rune) bool {
+	switch {
+	case r == '\u00ad', r == '\u180e', r == '\ufeff':
+		return true
+	case r >= '\u200b' && r <= '\u200f', r >= '\u202a' && r <= '\u202e':
+		return true
+	case r >= '\u2060' && r <= '\u2064', r >= '\u2066' && r <= '\u2069':
+		return true
+	default:
+		return r >= 0xe0000 && r <= 0xe007f
+	}
+}
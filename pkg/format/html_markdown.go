@@ -0,0 +1,319 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// lineBreak marks <br> in inline content until whitespace is collapsed.
+const lineBreak = "\u2028"
+
+// blockElements start a new block when they appear among inline content.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true, "center": true,
+	"dd": true, "details": true, "dialog": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "head": true, "header": true, "hr": true, "html": true, "li": true,
+	"main": true, "nav": true, "noscript": true, "ol": true, "p": true, "pre": true, "script": true,
+	"section": true, "style": true, "summary": true, "table": true, "tbody": true, "td": true,
+	"template": true, "tfoot": true, "th": true, "thead": true, "title": true, "tr": true, "ul": true,
+}
+
+// skippedElements carry no readable content.
+var skippedElements = map[string]bool{
+	"head": true, "noscript": true, "script": true, "style": true, "template": true, "title": true,
+}
+
+// blockMarkerStart matches paragraph text that CommonMark would read as a list, heading or quote.
+var blockMarkerStart = regexp.MustCompile(`^([-+#>]|\d{1,9}[.)])( |$)`)
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "[", `\[`, "]", `\]`)
+
+// HTMLToMarkdown converts HTML to CommonMark without external tools. It covers the elements
+// emails use (headings, paragraphs, emphasis, links, images, lists, quotes, code and tables)
+// and is the fallback when pandoc is unavailable.
+func HTMLToMarkdown(htmlContent []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return "", fmt.Errorf("html.Parse failed: %w", err)
+	}
+
+	blocks := markdownBlocks(doc)
+	if len(blocks) == 0 {
+		return "", nil
+	}
+	return strings.Join(blocks, "\n\n") + "\n", nil
+}
+
+// markdownBlocks renders the children of n as blocks, gathering runs of inline content into paragraphs.
+func markdownBlocks(n *html.Node) []string {
+	var blocks []string
+	var inline strings.Builder
+
+	flush := func() {
+		if text := finishInline(inline.String()); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline.Reset()
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && blockElements[child.Data] {
+			flush()
+			blocks = append(blocks, markdownBlock(child)...)
+			continue
+		}
+		inline.WriteString(markdownInline(child))
+	}
+	flush()
+
+	return blocks
+}
+
+func markdownBlock(n *html.Node) []string {
+	if skippedElements[n.Data] {
+		return nil
+	}
+
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := strings.ReplaceAll(finishInline(inlineChildren(n)), "\\\n", " ")
+		if text == "" {
+			return nil
+		}
+		return []string{strings.Repeat("#", int(n.Data[1]-'0')) + " " + text}
+
+	case "ul", "ol":
+		if list := markdownList(n); list != "" {
+			return []string{list}
+		}
+		return nil
+
+	case "blockquote":
+		inner := markdownBlocks(n)
+		if len(inner) == 0 {
+			return nil
+		}
+		lines := strings.Split(strings.Join(inner, "\n\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return []string{strings.Join(lines, "\n")}
+
+	case "pre":
+		text := strings.Trim(textContent(n), "\n")
+		if text == "" {
+			return nil
+		}
+		return []string{"```\n" + text + "\n```"}
+
+	case "hr":
+		return []string{"---"}
+
+	case "table":
+		return markdownTableBlocks(n)
+
+	default:
+		return markdownBlocks(n)
+	}
+}
+
+// markdownList renders list items as a tight list, indenting continuation lines under the marker.
+func markdownList(n *html.Node) string {
+	var items []string
+	number := 1
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.Data != "li" {
+			continue
+		}
+
+		marker := "- "
+		if n.Data == "ol" {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+
+		content := strings.Join(markdownBlocks(child), "\n")
+		if content == "" {
+			continue
+		}
+		lines := strings.Split(content, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = strings.Repeat(" ", len(marker)) + lines[i]
+			}
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// markdownTableBlocks renders data tables with dataTableMarkdown. Single-column tables and
+// tables wrapping other tables only lay out content, so their cells become blocks of their own.
+func markdownTableBlocks(table *html.Node) []string {
+	rows := tableRows(table)
+	if isDataTable(rows) {
+		return []string{dataTableMarkdown(rows)}
+	}
+
+	var blocks []string
+	for _, row := range rows {
+		for _, cell := range row {
+			blocks = append(blocks, markdownBlocks(cell)...)
+		}
+	}
+	return blocks
+}
+
+func markdownInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return markdownEscaper.Replace(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	if skippedElements[n.Data] {
+		return ""
+	}
+
+	switch n.Data {
+	case "br":
+		return lineBreak
+
+	case "strong", "b":
+		return wrapInline(inlineChildren(n), "**")
+
+	case "em", "i", "cite":
+		return wrapInline(inlineChildren(n), "*")
+
+	case "code", "tt", "kbd", "samp":
+		text := collapseWhitespace(textContent(n))
+		if text == "" {
+			return ""
+		}
+		return "`" + text + "`"
+
+	case "a":
+		text := inlineChildren(n)
+		href := strings.TrimSpace(attrValue(n, "href"))
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			return text
+		}
+		if label := collapseWhitespace(text); label == "" || label == markdownEscaper.Replace(href) {
+			return replaceInline(text, func(string) string { return "<" + href + ">" })
+		}
+		return replaceInline(text, func(trimmed string) string { return "[" + trimmed + "](" + markdownURL(href) + ")" })
+
+	case "img":
+		alt := markdownEscaper.Replace(collapseWhitespace(attrValue(n, "alt")))
+		src := strings.TrimSpace(attrValue(n, "src"))
+		if src == "" || strings.HasPrefix(strings.ToLower(src), "data:") {
+			return alt
+		}
+		return "![" + alt + "](" + markdownURL(src) + ")"
+
+	default:
+		if blockElements[n.Data] {
+			return " " + strings.Join(markdownBlocks(n), " ") + " "
+		}
+		return inlineChildren(n)
+	}
+}
+
+func inlineChildren(n *html.Node) string {
+	var sb strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		sb.WriteString(markdownInline(child))
+	}
+	return sb.String()
+}
+
+// wrapInline wraps text in emphasis markers, keeping surrounding whitespace outside the markers
+// since CommonMark doesn't recognize emphasis that starts or ends with a space.
+func wrapInline(text, marker string) string {
+	if strings.TrimFunc(text, isInlineSpace) == "" {
+		return text
+	}
+	return replaceInline(text, func(trimmed string) string { return marker + trimmed + marker })
+}
+
+// replaceInline replaces text with render of its trimmed content, keeping one space on each side
+// that had whitespace.
+func replaceInline(text string, render func(trimmed string) string) string {
+	var prefix, suffix string
+	if strings.IndexFunc(text, isInlineSpace) == 0 {
+		prefix = " "
+	}
+	if text != "" && strings.LastIndexFunc(text, isInlineSpace) == len(text)-1 {
+		suffix = " "
+	}
+	return prefix + render(strings.TrimFunc(text, isInlineSpace)) + suffix
+}
+
+func isInlineSpace(r rune) bool {
+	return unicode.IsSpace(r) && string(r) != lineBreak
+}
+
+// finishInline collapses whitespace of rendered inline content and turns <br> into hard line
+// breaks; blank lines between breaks separate paragraphs.
+func finishInline(text string) string {
+	var sb strings.Builder
+	pendingBreak, pendingParagraph := false, false
+	for _, line := range strings.Split(text, lineBreak) {
+		line = escapeLineStart(collapseWhitespace(line))
+		if line == "" {
+			pendingParagraph = pendingBreak
+			continue
+		}
+		switch {
+		case sb.Len() == 0:
+		case pendingParagraph:
+			sb.WriteString("\n\n")
+		default:
+			sb.WriteString("\\\n")
+		}
+		sb.WriteString(line)
+		pendingBreak, pendingParagraph = true, false
+	}
+	return sb.String()
+}
+
+func escapeLineStart(line string) string {
+	m := blockMarkerStart.FindStringSubmatchIndex(line)
+	if m == nil {
+		return line
+	}
+	marker := m[3] - 1
+	return line[:marker] + `\` + line[marker:]
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var sb strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "br" {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString(textContent(child))
+	}
+	return sb.String()
+}
+
+// markdownURL encloses URLs containing spaces or parentheses in angle brackets.
+func markdownURL(u string) string {
+	if strings.ContainsAny(u, " ()") {
+		return "<" + u + ">"
+	}
+	return u
+}
@@ -0,0 +1,90 @@
+package format_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "headings paragraphs and emphasis",
+			input:    `<html><head><title>x</title><style>p{}</style></head><body><h2>Weekly <i>update</i></h2><p>Hello <b>team </b>,<br>all  good.</p><script>alert(1)</script></body></html>`,
+			expected: "## Weekly *update*\n\nHello **team** ,\\\nall good.\n",
+		},
+		{
+			name:     "links and images",
+			input:    `<p>See <a href="https://example.com/a b">the docs</a>, <a href="https://example.com">https://example.com</a> and <a href="#top">top</a>.</p><p><img src="https://example.com/logo.png" alt="Logo"><img src="data:image/png;base64,AA" alt="pixel"></p>`,
+			expected: "See [the docs](<https://example.com/a b>), <https://example.com> and top.\n\n![Logo](https://example.com/logo.png)pixel\n",
+		},
+		{
+			name:     "nested lists",
+			input:    `<ul><li>One</li><li>Two<ol><li>first</li><li>second</li></ol></li></ul>`,
+			expected: "- One\n- Two\n  1. first\n  2. second\n",
+		},
+		{
+			name:     "quote code and rule",
+			input:    "<blockquote><p>Quoted</p><p>- signed</p></blockquote><hr><pre>line 1\n  line 2</pre><p>Run <code>make  test</code> *now*</p>",
+			expected: "> Quoted\n>\n> \\- signed\n\n---\n\n```\nline 1\n  line 2\n```\n\nRun `make test` \\*now\\*\n",
+		},
+		{
+			name:     "data and layout tables",
+			input:    `<table><tr><td><p>Layout cell</p></td></tr></table><table><tr><th>Item</th><th>Price</th></tr><tr><td>Tea | green</td><td><b>$4</b></td></tr></table>`,
+			expected: "Layout cell\n\n| Item | Price |\n| --- | --- |\n| Tea \\| green | **$4** |\n",
+		},
+		{
+			name:     "data table with spanned cells",
+			input:    `<table><tr><th>Flight</th><th>Time</th></tr><tr><td colspan="2">Day 1</td></tr><tr><td>LH 400</td><td>10:05<br>arrives 12:40</td></tr></table>`,
+			expected: "| Flight | Time |\n| --- | --- |\n| Day 1 |  |\n| LH 400 | 10:05 arrives 12:40 |\n",
+		},
+		{
+			name:     "data table with list cell as csv",
+			input:    `<table><tr><th>Plan</th><th>Includes</th></tr><tr><td>Pro</td><td><ul><li>Support</li><li>Backups</li></ul></td></tr></table>`,
+			expected: "```csv\nPlan,Includes\nPro,\"- Support\n- Backups\"\n```\n",
+		},
+		{
+			name:     "layout table wrapping data table",
+			input:    `<table><tr><td><p>Logo</p></td><td><table><tr><td>Room</td><td>$90</td></tr></table></td></tr></table>`,
+			expected: "Logo\n\n| Room | $90 |\n| --- | --- |\n",
+		},
+		{
+			name:     "empty",
+			input:    `<html><body> </body></html>`,
+			expected: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := format.HTMLToMarkdown([]byte(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestConverterNoExternalTools(t *testing.T) {
+	cnv := format.Converter{NoExternalTools: true}
+
+	result, err := cnv.HTML2MD([]byte(`<p>Hello <b>world</b></p>`))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello **world**\n", result)
+
+	_, err = cnv.DOCX2MD([]byte("docx"))
+	assert.ErrorContains(t, err, "external tools are turned off")
+
+	pdfData, err := os.ReadFile("./testdata/test.pdf")
+	require.NoError(t, err, "failed to read PDF file")
+	pdfText, err := cnv.PDF2Text(pdfData, format.PDFOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, pdfText.Text, "Lorem ipsum dolor sit amet")
+}
@@ -2,11 +2,142 @@ package format
 
 import (
 	"bytes"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
 )
 
+// maxSimplifyIterations bounds the passes over nested layout tables.
+const maxSimplifyIterations = 10
+
+// beaconMarkers are URL parts of open tracking images.
+var beaconMarkers = []string{
+	"/open?", "/open.", "/track/open", "/wf/open", "/e/o/", "/beacon", "/pixel", "pixel.gif", "pixel.png", "spacer.gif",
+}
+
+// SimplifyHTML prepares an email body for conversion: it removes content a reader never sees,
+// like style sheets, scripts, comments, tracking pixels, beacon images and display:none blocks, cleans tracking links with
+// CleanURL and unwraps layout tables.
+func SimplifyHTML(htmlContent []byte) []byte {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent
+	}
+
+	removeInvisible(doc)
+	cleanLinks(doc)
+	for range maxSimplifyIterations {
+		if !simplifyNode(doc) {
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return htmlContent
+	}
+
+	return buf.Bytes()
+}
+
+// removeInvisible drops comments, including Outlook's conditional blocks, the head, style
+// sheets, scripts, hidden elements and tracking images, which only add noise and leak CSS or
+// tracker URLs into the converted text.
+func removeInvisible(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch {
+		case child.Type == html.CommentNode:
+			n.RemoveChild(child)
+		case child.Type != html.ElementNode:
+		case child.Data == "head":
+			for c := child.FirstChild; c != nil; c = child.FirstChild {
+				child.RemoveChild(c)
+			}
+		case isNonContent(child) || isHidden(child) || isTrackingImage(child):
+			n.RemoveChild(child)
+		default:
+			removeInvisible(child)
+		}
+		child = next
+	}
+}
+
+// isNonContent reports style sheets, scripts and Outlook's XML and VML markup.
+func isNonContent(n *html.Node) bool {
+	switch n.Data {
+	case "style", "script", "template", "xml":
+		return true
+	}
+	return strings.HasPrefix(n.Data, "v:")
+}
+
+func cleanLinks(n *html.Node) {
+	if n.Type == html.ElementNode && n.Data == "a" {
+		for i, attr := range n.Attr {
+			if attr.Key == "href" {
+				n.Attr[i].Val = CleanURL(strings.TrimSpace(attr.Val))
+			}
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		cleanLinks(child)
+	}
+}
+
+func isHidden(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "hidden" {
+			return true
+		}
+	}
+	style := styleProperties(n)
+	return style["display"] == "none" || style["visibility"] == "hidden"
+}
+
+// isTrackingImage reports images of at most one pixel and images loaded from open tracking URLs.
+func isTrackingImage(n *html.Node) bool {
+	if n.Data != "img" {
+		return false
+	}
+	if containsAny(strings.ToLower(attrValue(n, "src")), beaconMarkers) {
+		return true
+	}
+
+	style := styleProperties(n)
+	for _, dimension := range []string{"width", "height"} {
+		if size, ok := pixelSize(attrValue(n, dimension)); ok && size <= 1 {
+			return true
+		}
+		if size, ok := pixelSize(style[dimension]); ok && size <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// styleProperties parses the inline style of an element, with lowercased names and values.
+func styleProperties(n *html.Node) map[string]string {
+	properties := map[string]string{}
+	for _, declaration := range strings.Split(attrValue(n, "style"), ";") {
+		name, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		properties[strings.ToLower(strings.TrimSpace(name))] = strings.ToLower(value)
+	}
+	return properties
+}
+
+// pixelSize parses a length like "1" or "1px".
+func pixelSize(value string) (int, bool) {
+	value = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), "px")
+	size, err := strconv.Atoi(value)
+	return size, err == nil
+}
+
 // UnwrapTableLayout removes unnecessary single-column layout tables from HTML content.
 // It recursively unwraps tables that are used purely for layout purposes while preserving
 // semantic tables that contain actual data.
@@ -16,8 +147,7 @@ func UnwrapTableLayout(htmlContent []byte) []byte {
 		return htmlContent
 	}
 
-	maxIterations := 10
-	for range maxIterations {
+	for range maxSimplifyIterations {
 		changed := simplifyNode(doc)
 		if !changed {
 			break
@@ -6,7 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/hal9000y/gmail-mcp/internal/format"
+	"github.com/hal9000y/gmail-mcp/pkg/format"
 )
 
 func TestUnwrapTableLayout(t *testing.T) {
@@ -351,3 +351,66 @@ func TestUnwrapTableLayoutComplexNesting(t *testing.T) {
 	result := format.UnwrapTableLayout([]byte(input))
 	assert.Equal(t, expected, string(result))
 }
+
+func TestSimplifyHTML(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "tracking pixel by size",
+			input:    `<p>Hello</p><img src="https://example.com/a.gif" width="1" height="1" alt="">`,
+			expected: `<html><head></head><body><p>Hello</p></body></html>`,
+		},
+		{
+			name:     "tracking pixel by style",
+			input:    `<p>Hello</p><img src="https://example.com/a.gif" style="width: 0px; height: 0px">`,
+			expected: `<html><head></head><body><p>Hello</p></body></html>`,
+		},
+		{
+			name:     "beacon image",
+			input:    `<p>Hello</p><img src="https://mail.example.com/wf/open?upn=abc" width="600">`,
+			expected: `<html><head></head><body><p>Hello</p></body></html>`,
+		},
+		{
+			name:     "hidden content",
+			input:    `<div style="display:none !important">Preheader text</div><span hidden>x</span><p style="visibility: hidden">y</p><p>Hello</p>`,
+			expected: `<html><head></head><body><p>Hello</p></body></html>`,
+		},
+		{
+			name:     "visible images kept",
+			input:    `<img src="https://example.com/logo.png" width="120" height="40" alt="Logo"><img src="cid:chart">`,
+			expected: `<html><head></head><body><img src="https://example.com/logo.png" width="120" height="40" alt="Logo"/><img src="cid:chart"/></body></html>`,
+		},
+		{
+			name: "style script head and comments",
+			input: `<html><head><title>Sale</title><style>.x{color:red}</style></head><body>` +
+				`<!--[if mso]><table><tr><td>Outlook only</td></tr></table><![endif]-->` +
+				`<style>p{margin:0}</style><script>track()</script><p>Hello</p><!-- footer --></body></html>`,
+			expected: `<html><head></head><body><p>Hello</p></body></html>`,
+		},
+		{
+			name: "downlevel revealed content kept",
+			input: `<!--[if !mso]><!--><p>Hello</p><!--<![endif]-->` +
+				`<xml><o:OfficeDocumentSettings></o:OfficeDocumentSettings></xml><v:rect><v:fill/></v:rect>`,
+			expected: `<html><head></head><body><p>Hello</p></body></html>`,
+		},
+		{
+			name:     "tracking links cleaned",
+			input:    `<a href="https://example.com/post?id=7&amp;utm_source=mail">Post</a>`,
+			expected: `<html><head></head><body><a href="https://example.com/post?id=7">Post</a></body></html>`,
+		},
+		{
+			name:     "layout table unwrapped",
+			input:    `<table><tr><td><p>Hello</p><img src="https://example.com/o.gif" height="1"></td></tr></table>`,
+			expected: "<html><head></head><body><p>Hello</p>\n</body></html>",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, string(format.SimplifyHTML([]byte(tc.input))))
+		})
+	}
+}
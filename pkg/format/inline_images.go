@@ -0,0 +1,87 @@
+package format
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const cidScheme = "cid:"
+
+// InlineImage is an image embedded in an HTML body by Content-ID reference.
+type InlineImage struct {
+	ContentID string
+	Alt       string
+}
+
+// ReplaceInlineImages swaps every <img src="cid:..."> for the text returned by placeholder, so
+// converters don't drop the image or leave a broken reference. It returns the referenced images
+// in document order; without any, the content is returned unchanged.
+func ReplaceInlineImages(htmlContent []byte, placeholder func(InlineImage) string) ([]byte, []InlineImage) {
+	if !bytes.Contains(bytes.ToLower(htmlContent), []byte(cidScheme)) {
+		return htmlContent, nil
+	}
+
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent, nil
+	}
+
+	var images []InlineImage
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		child := n.FirstChild
+		for child != nil {
+			next := child.NextSibling
+			walk(child)
+			child = next
+		}
+
+		if n.Type != html.ElementNode || n.Data != "img" {
+			return
+		}
+		contentID, ok := ContentIDFromURL(attrValue(n, "src"))
+		if !ok {
+			return
+		}
+
+		image := InlineImage{ContentID: contentID, Alt: collapseWhitespace(attrValue(n, "alt"))}
+		images = append(images, image)
+		n.Parent.InsertBefore(&html.Node{Type: html.TextNode, Data: placeholder(image)}, n)
+		n.Parent.RemoveChild(n)
+	}
+	walk(doc)
+
+	if len(images) == 0 {
+		return htmlContent, nil
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return htmlContent, nil
+	}
+
+	return buf.Bytes(), images
+}
+
+// ContentIDFromURL extracts the Content-ID from a cid: URL.
+func ContentIDFromURL(src string) (string, bool) {
+	src = strings.TrimSpace(src)
+	if len(src) < len(cidScheme) || !strings.EqualFold(src[:len(cidScheme)], cidScheme) {
+		return "", false
+	}
+
+	contentID := src[len(cidScheme):]
+	if unescaped, err := url.PathUnescape(contentID); err == nil {
+		contentID = unescaped
+	}
+
+	return NormalizeContentID(contentID), contentID != ""
+}
+
+// NormalizeContentID strips the angle brackets a Content-ID header wraps the ID in.
+func NormalizeContentID(contentID string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(contentID), "<"), ">")
+}
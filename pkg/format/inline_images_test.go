@@ -0,0 +1,39 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestReplaceInlineImages(t *testing.T) {
+	input := `<html><head></head><body><p>Hi <img src="cid:logo@example.com" alt="Company  logo"></p>` +
+		`<p><img src="CID:chart%401"><img src="https://example.com/banner.png"></p></body></html>`
+
+	replaced, images := format.ReplaceInlineImages([]byte(input), func(image format.InlineImage) string {
+		return "[image " + image.ContentID + "]"
+	})
+
+	assert.Equal(t, `<html><head></head><body><p>Hi [image logo@example.com]</p>`+
+		`<p>[image chart@1]<img src="https://example.com/banner.png"/></p></body></html>`, string(replaced))
+	assert.Equal(t, []format.InlineImage{
+		{ContentID: "logo@example.com", Alt: "Company logo"},
+		{ContentID: "chart@1"},
+	}, images)
+}
+
+func TestReplaceInlineImagesWithoutReferences(t *testing.T) {
+	input := []byte(`<p><img src="https://example.com/banner.png"></p>`)
+
+	replaced, images := format.ReplaceInlineImages(input, func(format.InlineImage) string { return "" })
+
+	assert.Equal(t, input, replaced)
+	assert.Empty(t, images)
+}
+
+func TestNormalizeContentID(t *testing.T) {
+	assert.Equal(t, "logo@example.com", format.NormalizeContentID(" <logo@example.com> "))
+	assert.Equal(t, "logo@example.com", format.NormalizeContentID("logo@example.com"))
+}
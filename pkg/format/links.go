@@ -0,0 +1,209 @@
+package format
+
+import (
+	"bytes"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Link kinds assigned by ClassifyLink.
+const (
+	LinkUnsubscribe = "unsubscribe"
+	LinkTracking    = "tracking"
+	LinkDocument    = "document"
+	LinkLogin       = "login"
+	LinkOther       = "other"
+)
+
+// Link is a hyperlink found in an email body.
+type Link struct {
+	URL  string
+	Text string
+	Kind string
+}
+
+var (
+	textURLPattern = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+	driveIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{10,}$`)
+
+	documentExtensions = []string{
+		".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".odt", ".ods", ".csv", ".txt", ".zip",
+	}
+	documentHosts = []string{
+		"docs.google.com", "drive.google.com", "dropbox.com", "onedrive.live.com", "sharepoint.com", "box.com",
+	}
+	trackingMarkers = []string{
+		"/track/", "/click", "/ls/click", "/wf/click", "/redirect", "utm_", "mc_eid=", "trk=", "/e/c/",
+	}
+	trackingHostPrefixes = []string{"click.", "clicks.", "track.", "tracking.", "links.", "email.", "t."}
+	loginMarkers         = []string{
+		"login", "log-in", "signin", "sign-in", "sign_in", "/auth", "oauth", "password", "verify", "account/confirm",
+	}
+)
+
+// ExtractLinks returns anchors of the HTML document in order of appearance.
+// Fragment-only, javascript and mailto links are skipped, and repeated URL/text pairs are reported once.
+func ExtractLinks(htmlContent []byte) []Link {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
+
+	var links []Link
+	seen := make(map[Link]bool)
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			if href := attrValue(n, "href"); isNavigableURL(href) {
+				text := collapseWhitespace(nodeText(n))
+				if text == "" {
+					text = attrValue(n, "title")
+				}
+				link := Link{URL: href, Text: text, Kind: ClassifyLink(href, text)}
+				if !seen[link] {
+					seen[link] = true
+					links = append(links, link)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return links
+}
+
+// ExtractTextLinks returns http(s) URLs found in a plain-text body.
+func ExtractTextLinks(text string) []Link {
+	var links []Link
+	seen := make(map[string]bool)
+
+	for _, u := range textURLPattern.FindAllString(text, -1) {
+		u = strings.TrimRight(u, ".,;:!?")
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		links = append(links, Link{URL: u, Kind: ClassifyLink(u, "")})
+	}
+
+	return links
+}
+
+// ClassifyLink guesses the purpose of a link from its URL and anchor text.
+// Unsubscribe wins over tracking because list-unsubscribe links are usually wrapped by trackers.
+func ClassifyLink(rawURL, text string) string {
+	lowerURL := strings.ToLower(rawURL)
+	lowerText := strings.ToLower(text)
+
+	if strings.Contains(lowerURL, "unsubscribe") || strings.Contains(lowerText, "unsubscribe") ||
+		strings.Contains(lowerURL, "opt-out") || strings.Contains(lowerURL, "optout") {
+		return LinkUnsubscribe
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return LinkOther
+	}
+	host := strings.ToLower(u.Hostname())
+	urlPath := strings.ToLower(u.EscapedPath())
+
+	if containsAny(strings.ToLower(u.Path), loginMarkers) || containsAny(lowerText, []string{"log in", "login", "sign in"}) {
+		return LinkLogin
+	}
+	if hasAnyPrefix(host, trackingHostPrefixes) || containsAny(urlPath+"?"+strings.ToLower(u.RawQuery), trackingMarkers) {
+		return LinkTracking
+	}
+	if hasAnySuffix(host, documentHosts) || hasAnySuffix(path.Ext(urlPath), documentExtensions) {
+		return LinkDocument
+	}
+
+	return LinkOther
+}
+
+// DriveFileID returns the ID of the Google Drive file a link opens, like a Docs, Sheets or
+// Slides document or an uploaded file, or "" for other links. Folder and published links
+// aren't files.
+func DriveFileID(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	if host := strings.ToLower(u.Hostname()); host != "docs.google.com" && host != "drive.google.com" {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "d" && driveIDPattern.MatchString(segments[i+1]) {
+			return segments[i+1]
+		}
+	}
+	if id := u.Query().Get("id"); driveIDPattern.MatchString(id) && !strings.Contains(u.Path, "folders") {
+		return id
+	}
+	return ""
+}
+
+func isNavigableURL(href string) bool {
+	href = strings.TrimSpace(strings.ToLower(href))
+	return strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://")
+}
+
+func attrValue(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(nodeText(c))
+	}
+	return b.String()
+}
+
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if s != "" && strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
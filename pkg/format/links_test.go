@@ -0,0 +1,65 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestExtractLinks(t *testing.T) {
+	input := `<html><body>
+		<p>Your <a href="https://example.com/files/invoice-2025-03.pdf">March   invoice</a> is ready.</p>
+		<p><a href="https://example.com/login?next=/billing">Open billing</a></p>
+		<p><a href="https://click.example.net/ls/click?upn=abc"><img src="x.png" alt=""></a></p>
+		<p><a href="https://docs.google.com/document/d/123/edit" title="Shared doc"></a></p>
+		<p><a href="#top">Back to top</a> <a href="mailto:help@example.com">Help</a></p>
+		<p><a href="https://example.com/prefs?action=unsubscribe">Manage preferences</a></p>
+		<p><a href="https://example.com/blog">Read the blog</a></p>
+		<p><a href="https://example.com/blog">Read the blog</a></p>
+	</body></html>`
+
+	expected := []format.Link{
+		{URL: "https://example.com/files/invoice-2025-03.pdf", Text: "March invoice", Kind: format.LinkDocument},
+		{URL: "https://example.com/login?next=/billing", Text: "Open billing", Kind: format.LinkLogin},
+		{URL: "https://click.example.net/ls/click?upn=abc", Text: "", Kind: format.LinkTracking},
+		{URL: "https://docs.google.com/document/d/123/edit", Text: "Shared doc", Kind: format.LinkDocument},
+		{URL: "https://example.com/prefs?action=unsubscribe", Text: "Manage preferences", Kind: format.LinkUnsubscribe},
+		{URL: "https://example.com/blog", Text: "Read the blog", Kind: format.LinkOther},
+	}
+
+	assert.Equal(t, expected, format.ExtractLinks([]byte(input)))
+}
+
+func TestExtractTextLinks(t *testing.T) {
+	input := "See https://example.com/report.xlsx, or sign in at (https://example.com/signin).\n" +
+		"Stop these emails: https://example.com/unsubscribe?id=1 https://example.com/report.xlsx"
+
+	expected := []format.Link{
+		{URL: "https://example.com/report.xlsx", Kind: format.LinkDocument},
+		{URL: "https://example.com/signin", Kind: format.LinkLogin},
+		{URL: "https://example.com/unsubscribe?id=1", Kind: format.LinkUnsubscribe},
+	}
+
+	assert.Equal(t, expected, format.ExtractTextLinks(input))
+}
+
+func TestDriveFileID(t *testing.T) {
+	cases := map[string]string{
+		"https://docs.google.com/document/d/1AbCdEfGhIjKlMnOp/edit?usp=sharing":     "1AbCdEfGhIjKlMnOp",
+		"https://docs.google.com/spreadsheets/u/0/d/1Sheet_Id-123456/edit#gid=0":    "1Sheet_Id-123456",
+		"https://docs.google.com/presentation/d/1SlidesId12345/present":             "1SlidesId12345",
+		"https://drive.google.com/file/d/0BFileId1234567/view?usp=drive_link":       "0BFileId1234567",
+		"https://drive.google.com/open?id=0BOpenId12345678":                         "0BOpenId12345678",
+		"https://drive.google.com/uc?export=download&id=0BDownload1234":             "0BDownload1234",
+		"https://drive.google.com/drive/folders/1FolderId123456":                    "",
+		"https://drive.google.com/drive/folders?id=1FolderId123456":                 "",
+		"https://docs.google.com/document/d/e/2PACX-1vPublished/pub":                "",
+		"https://example.com/document/d/1AbCdEfGhIjKlMnOp/edit":                     "",
+		"https://docs.google.com/forms/d/1FormId123456/viewform?usp=pp_url&id=none": "1FormId123456",
+	}
+	for url, expected := range cases {
+		assert.Equal(t, expected, format.DriveFileID(url), url)
+	}
+}
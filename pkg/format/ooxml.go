@@ -0,0 +1,68 @@
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Office Open XML documents (xlsx, pptx) are zip archives of XML parts linked by relationship files.
+
+// zipFiles indexes the entries of a zip archive by name.
+func zipFiles(raw []byte) (map[string]*zip.File, error) {
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("zip.NewReader failed: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	return files, nil
+}
+
+// readRelationships maps relationship IDs to part names; relative targets resolve against baseDir.
+func readRelationships(files map[string]*zip.File, name, baseDir string) (map[string]string, error) {
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(files, name, &rels); err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join(baseDir, target)
+		}
+		targets[rel.ID] = target
+	}
+	return targets, nil
+}
+
+func decodeZipXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s not found", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("open %s failed: %w", name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("decode %s failed: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,716 @@
+package format
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxPDFDepth bounds reference chains, page tree depth and nested form XObjects.
+	maxPDFDepth = 32
+	// pdfWordGap is the gap between text runs, as a fraction of the font size, read as a word break.
+	pdfWordGap = 0.15
+)
+
+var (
+	pdfObjectHeader  = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfTrailerHeader = regexp.MustCompile(`trailer\s*<<`)
+)
+
+// pdfDocument holds the objects of a PDF file, read by scanning for object headers rather
+// than trusting the cross-reference table, which keeps damaged files readable.
+type pdfDocument struct {
+	objects map[int]any
+	trailer pdfDict
+	fonts   map[pdfRef]*pdfFont
+}
+
+// PDFToText extracts the text of the selected pages of a PDF without external tools, separating
+// pages with form feeds like pdftotext. It handles unencrypted documents with Flate, ASCII85 or
+// ASCIIHex compressed content and decodes text through ToUnicode maps or the font encoding. Text
+// is grouped into lines by position, but columns and indentation aren't kept.
+func PDFToText(raw []byte, opts PDFOptions) (PDFText, error) {
+	doc, err := parsePDF(raw)
+	if err != nil {
+		return PDFText{}, err
+	}
+
+	if doc.trailer["Encrypt"] != nil {
+		return PDFText{}, errors.New("encrypted PDFs are not supported")
+	}
+
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return PDFText{}, errors.New("no pages found")
+	}
+
+	first, last := opts.pageRange(len(pages))
+	var sb strings.Builder
+	for i := first; i <= last; i++ {
+		ex := newPDFTextExtractor(doc)
+		ex.runContent(doc.pageContent(pages[i-1].dict), pages[i-1].resources, 0)
+		sb.WriteString(ex.pageText())
+		sb.WriteString("\n\f")
+	}
+
+	return PDFText{Text: sb.String(), TotalPages: len(pages)}, nil
+}
+
+// PDFPageCount returns the number of pages of a PDF.
+func PDFPageCount(raw []byte) (int, error) {
+	doc, err := parsePDF(raw)
+	if err != nil {
+		return 0, err
+	}
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return 0, errors.New("no pages found")
+	}
+	return len(pages), nil
+}
+
+func parsePDF(raw []byte) (*pdfDocument, error) {
+	if !bytes.Contains(raw[:min(len(raw), 1024)], []byte("%PDF-")) {
+		return nil, errors.New("not a PDF document")
+	}
+
+	doc := &pdfDocument{objects: map[int]any{}, trailer: pdfDict{}, fonts: map[pdfRef]*pdfFont{}}
+	var objectStreams []*pdfStream
+
+	for _, m := range pdfObjectHeader.FindAllSubmatchIndex(raw, -1) {
+		var num int
+		if _, err := fmt.Sscan(string(raw[m[2]:m[3]]), &num); err != nil {
+			continue
+		}
+
+		l := &pdfLexer{data: raw, pos: m[1]}
+		obj, err := l.readObject()
+		if err != nil {
+			continue
+		}
+
+		if dict, ok := obj.(pdfDict); ok {
+			if stream := readPDFStream(l, dict); stream != nil {
+				obj = stream
+				switch dict["Type"] {
+				case pdfName("ObjStm"):
+					objectStreams = append(objectStreams, stream)
+				case pdfName("XRef"):
+					// Cross-reference streams carry the trailer of files without a trailer keyword.
+					mergePDFTrailer(doc.trailer, dict)
+				}
+			}
+		}
+		doc.objects[num] = obj
+	}
+
+	for _, stream := range objectStreams {
+		doc.readObjectStream(stream)
+	}
+
+	for _, idx := range pdfTrailerHeader.FindAllIndex(raw, -1) {
+		l := &pdfLexer{data: raw, pos: idx[0] + len("trailer")}
+		if obj, err := l.readObject(); err == nil {
+			if dict, ok := obj.(pdfDict); ok {
+				mergePDFTrailer(doc.trailer, dict)
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// mergePDFTrailer keeps the keys the extractor needs; later trailers of incremental updates win.
+func mergePDFTrailer(trailer, dict pdfDict) {
+	for _, key := range []pdfName{"Root", "Encrypt"} {
+		if v, ok := dict[key]; ok {
+			trailer[key] = v
+		}
+	}
+}
+
+// readPDFStream reads the data of a stream object whose dictionary was just read, if one follows.
+func readPDFStream(l *pdfLexer, dict pdfDict) *pdfStream {
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		return nil
+	}
+	start := l.pos + len("stream")
+	if bytes.HasPrefix(l.data[start:], []byte("\r\n")) {
+		start += 2
+	} else if start < len(l.data) && (l.data[start] == '\n' || l.data[start] == '\r') {
+		start++
+	}
+
+	if length, ok := dict["Length"].(int); ok && length >= 0 && start+length <= len(l.data) {
+		rest := bytes.TrimLeft(l.data[start+length:], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return &pdfStream{dict: dict, raw: l.data[start : start+length]}
+		}
+	}
+
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		return &pdfStream{dict: dict, raw: l.data[start:]}
+	}
+	data := l.data[start : start+end]
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	data = bytes.TrimSuffix(data, []byte("\r"))
+	return &pdfStream{dict: dict, raw: data}
+}
+
+// readObjectStream adds compressed objects not already defined directly in the file.
+func (d *pdfDocument) readObjectStream(stream *pdfStream) {
+	data, err := d.decodeStream(stream)
+	if err != nil {
+		return
+	}
+	n, _ := d.resolve(stream.dict["N"]).(int)
+	first, _ := d.resolve(stream.dict["First"]).(int)
+	if first <= 0 || first > len(data) {
+		return
+	}
+
+	header := &pdfLexer{data: data[:first]}
+	for range n {
+		num, err := header.readInt()
+		if err != nil {
+			return
+		}
+		offset, err := header.readInt()
+		if err != nil {
+			return
+		}
+		if _, exists := d.objects[num]; exists || first+offset >= len(data) {
+			continue
+		}
+
+		l := &pdfLexer{data: data, pos: first + offset}
+		if obj, err := l.readObject(); err == nil {
+			d.objects[num] = obj
+		}
+	}
+}
+
+func (l *pdfLexer) readInt() (int, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return 0, errPDFEOF
+	}
+	n, ok := l.readNumber().(int)
+	if !ok {
+		return 0, errors.New("expected integer")
+	}
+	return n, nil
+}
+
+// resolve follows indirect references.
+func (d *pdfDocument) resolve(v any) any {
+	for range maxPDFDepth {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.objects[ref.num]
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(v any) pdfDict {
+	switch obj := d.resolve(v).(type) {
+	case pdfDict:
+		return obj
+	case *pdfStream:
+		return obj.dict
+	default:
+		return nil
+	}
+}
+
+// decodeStream applies the stream filters.
+func (d *pdfDocument) decodeStream(stream *pdfStream) ([]byte, error) {
+	var filters []any
+	switch f := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []any{f}
+	case pdfArray:
+		filters = f
+	}
+
+	data := stream.raw
+	for _, f := range filters {
+		var err error
+		switch d.resolve(f) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflatePDF(data)
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85PDF(data)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data, err = decodeASCIIHexPDF(data)
+		default:
+			return nil, fmt.Errorf("unsupported stream filter %v", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// inflatePDF decompresses Flate data, keeping what was decoded from truncated streams.
+func inflatePDF(data []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("zlib.NewReader failed: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	out, err := io.ReadAll(zr)
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("inflate failed: %w", err)
+	}
+	return out, nil
+}
+
+func decodeASCII85PDF(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	// z expands to four zero bytes, so the output may be four times the input.
+	out := make([]byte, 4*len(data)+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, fmt.Errorf("ascii85.Decode failed: %w", err)
+	}
+	return out[:n], nil
+}
+
+func decodeASCIIHexPDF(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out, err := hex.DecodeString(string(digits))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString failed: %w", err)
+	}
+	return out, nil
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages lists pages in document order, resolving inherited resources. Files without a usable
+// page tree fall back to all page objects in file order.
+func (d *pdfDocument) pages() []pdfPage {
+	var pages []pdfPage
+
+	var walk func(node pdfDict, resources pdfDict, depth int)
+	walk = func(node pdfDict, resources pdfDict, depth int) {
+		if node == nil || depth > maxPDFDepth {
+			return
+		}
+		if res := d.dict(node["Resources"]); res != nil {
+			resources = res
+		}
+
+		kids, isTree := d.resolve(node["Kids"]).(pdfArray)
+		if !isTree {
+			if node["Type"] == pdfName("Page") || node["Contents"] != nil {
+				pages = append(pages, pdfPage{dict: node, resources: resources})
+			}
+			return
+		}
+		for _, kid := range kids {
+			walk(d.dict(kid), resources, depth+1)
+		}
+	}
+
+	if root := d.dict(d.trailer["Root"]); root != nil {
+		walk(d.dict(root["Pages"]), nil, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	for num := range d.maxObjectNumber() + 1 {
+		if dict, ok := d.objects[num].(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			pages = append(pages, pdfPage{dict: dict, resources: d.inheritedResources(dict)})
+		}
+	}
+	return pages
+}
+
+func (d *pdfDocument) maxObjectNumber() int {
+	highest := 0
+	for num := range d.objects {
+		highest = max(highest, num)
+	}
+	return highest
+}
+
+func (d *pdfDocument) inheritedResources(node pdfDict) pdfDict {
+	for range maxPDFDepth {
+		if node == nil {
+			return nil
+		}
+		if res := d.dict(node["Resources"]); res != nil {
+			return res
+		}
+		node = d.dict(node["Parent"])
+	}
+	return nil
+}
+
+// pageContent concatenates the decoded content streams of a page.
+func (d *pdfDocument) pageContent(page pdfDict) []byte {
+	var refs pdfArray
+	switch contents := d.resolve(page["Contents"]).(type) {
+	case pdfArray:
+		refs = contents
+	case *pdfStream:
+		refs = pdfArray{contents}
+	}
+
+	var buf bytes.Buffer
+	for _, ref := range refs {
+		stream, ok := d.resolve(ref).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// pdfTextExtractor interprets content stream text operators, collecting the text runs with their
+// position on the page.
+type pdfTextExtractor struct {
+	doc   *pdfDocument
+	runs  []pdfTextRun
+	state pdfGraphicsState
+	saved []pdfGraphicsState
+
+	font        *pdfFont
+	fontSize    float64
+	charSpacing float64
+	wordSpacing float64
+	scaling     float64
+	leading     float64
+	textMatrix  pdfMatrix
+	lineMatrix  pdfMatrix
+}
+
+// pdfMatrix is an affine transformation [a b c d e f].
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+// multiply returns m applied before n.
+func (m pdfMatrix) multiply(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func (m pdfMatrix) apply(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+type pdfGraphicsState struct {
+	ctm pdfMatrix
+}
+
+// pdfTextRun is text shown by one operator, positioned in page space.
+type pdfTextRun struct {
+	text       string
+	x, y, endX float64
+	size       float64
+}
+
+func matrixOperands(operands []any) (pdfMatrix, bool) {
+	var m pdfMatrix
+	if len(operands) < 6 {
+		return m, false
+	}
+	for i, v := range operands[len(operands)-6:] {
+		n, ok := pdfNumber(v)
+		if !ok {
+			return m, false
+		}
+		m[i] = n
+	}
+	return m, true
+}
+
+func newPDFTextExtractor(doc *pdfDocument) *pdfTextExtractor {
+	return &pdfTextExtractor{
+		doc:        doc,
+		state:      pdfGraphicsState{ctm: pdfIdentity},
+		scaling:    1,
+		textMatrix: pdfIdentity,
+		lineMatrix: pdfIdentity,
+	}
+}
+
+func (e *pdfTextExtractor) runContent(content []byte, resources pdfDict, depth int) {
+	if depth > maxPDFDepth {
+		return
+	}
+
+	l := &pdfLexer{data: content}
+	var operands []any
+	for {
+		obj, err := l.readObject()
+		if err != nil {
+			return
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		e.apply(string(op), operands, resources, depth)
+		if op == "ID" {
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+func (e *pdfTextExtractor) apply(op string, operands []any, resources pdfDict, depth int) {
+	number := func(i int) float64 {
+		if i >= len(operands) {
+			return 0
+		}
+		n, _ := pdfNumber(operands[i])
+		return n
+	}
+
+	switch op {
+	case "q":
+		if len(e.saved) < maxPDFNesting*maxPDFDepth {
+			e.saved = append(e.saved, e.state)
+		}
+	case "Q":
+		if n := len(e.saved); n > 0 {
+			e.state, e.saved = e.saved[n-1], e.saved[:n-1]
+		}
+	case "cm":
+		if m, ok := matrixOperands(operands); ok {
+			e.state.ctm = m.multiply(e.state.ctm)
+		}
+
+	case "BT":
+		e.textMatrix, e.lineMatrix = pdfIdentity, pdfIdentity
+	case "Tf":
+		if len(operands) >= 2 {
+			if name, ok := operands[0].(pdfName); ok {
+				e.font = e.loadFont(resources, name)
+			}
+			e.fontSize = number(1)
+		}
+	case "Tc":
+		e.charSpacing = number(0)
+	case "Tw":
+		e.wordSpacing = number(0)
+	case "Tz":
+		e.scaling = number(0) / 100
+	case "TL":
+		e.leading = number(0)
+
+	case "Td":
+		e.moveLine(number(0), number(1))
+	case "TD":
+		e.leading = -number(1)
+		e.moveLine(number(0), number(1))
+	case "Tm":
+		if m, ok := matrixOperands(operands); ok {
+			e.textMatrix, e.lineMatrix = m, m
+		}
+	case "T*":
+		e.moveLine(0, -e.leading)
+
+	case "Tj":
+		if len(operands) >= 1 {
+			e.show(operands[0])
+		}
+	case "'":
+		e.moveLine(0, -e.leading)
+		if len(operands) >= 1 {
+			e.show(operands[len(operands)-1])
+		}
+	case "\"":
+		if len(operands) >= 3 {
+			e.wordSpacing, e.charSpacing = number(0), number(1)
+		}
+		e.moveLine(0, -e.leading)
+		if len(operands) >= 1 {
+			e.show(operands[len(operands)-1])
+		}
+	case "TJ":
+		if len(operands) >= 1 {
+			items, _ := operands[0].(pdfArray)
+			for _, item := range items {
+				if n, ok := pdfNumber(item); ok {
+					e.advance(-n / 1000 * e.fontSize * e.scaling)
+					continue
+				}
+				e.show(item)
+			}
+		}
+
+	case "Do":
+		if len(operands) >= 1 {
+			e.runForm(resources, operands[0], depth)
+		}
+	}
+}
+
+func (e *pdfTextExtractor) moveLine(tx, ty float64) {
+	e.lineMatrix = pdfMatrix{1, 0, 0, 1, tx, ty}.multiply(e.lineMatrix)
+	e.textMatrix = e.lineMatrix
+}
+
+func (e *pdfTextExtractor) advance(tx float64) {
+	e.textMatrix = pdfMatrix{1, 0, 0, 1, tx, 0}.multiply(e.textMatrix)
+}
+
+// runForm extracts the text of a form XObject, which often holds headers, footers or whole pages.
+func (e *pdfTextExtractor) runForm(resources pdfDict, nameObj any, depth int) {
+	name, ok := nameObj.(pdfName)
+	if !ok {
+		return
+	}
+	stream, ok := e.doc.resolve(e.doc.dict(resources["XObject"])[name]).(*pdfStream)
+	if !ok || stream.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := e.doc.decodeStream(stream)
+	if err != nil {
+		return
+	}
+
+	formResources := e.doc.dict(stream.dict["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+
+	saved := e.state
+	if matrix, ok := e.doc.resolve(stream.dict["Matrix"]).(pdfArray); ok {
+		if m, ok := matrixOperands(matrix); ok {
+			e.state.ctm = m.multiply(e.state.ctm)
+		}
+	}
+	e.runContent(data, formResources, depth+1)
+	e.state = saved
+}
+
+// show records a shown string as a text run and moves the text position past it.
+func (e *pdfTextExtractor) show(obj any) {
+	s, ok := obj.([]byte)
+	if !ok {
+		return
+	}
+	if e.font == nil {
+		e.font = newPDFFont(e.doc, nil)
+	}
+
+	text, width, spaces := e.font.decode(s)
+	codes := len(s)
+	if e.font.composite {
+		codes = len(s) / 2
+	}
+	tx := (width*e.fontSize + float64(codes)*e.charSpacing + float64(spaces)*e.wordSpacing) * e.scaling
+
+	trm := e.textMatrix.multiply(e.state.ctm)
+	x, y := trm.apply(0, 0)
+	endX, _ := trm.apply(tx, 0)
+	_, top := trm.apply(0, e.fontSize)
+	e.runs = append(e.runs, pdfTextRun{text: text, x: x, y: y, endX: endX, size: math.Abs(top - y)})
+
+	e.advance(tx)
+}
+
+// pageText lays the text runs out into lines: runs on the same baseline join, with a space where
+// they are apart by more than a fraction of the font size.
+func (e *pdfTextExtractor) pageText() string {
+	var sb strings.Builder
+	var last *pdfTextRun
+	for i := range e.runs {
+		run := &e.runs[i]
+		if strings.TrimSpace(run.text) == "" && !strings.Contains(run.text, " ") {
+			continue
+		}
+
+		size := max(run.size, 1)
+		switch {
+		case last == nil:
+		case math.Abs(run.y-last.y) > size/2:
+			sb.WriteString("\n")
+		case run.x-last.endX > size*pdfWordGap && !endsWithSpace(sb.String()) && !strings.HasPrefix(run.text, " "):
+			sb.WriteString(" ")
+		}
+		sb.WriteString(run.text)
+		last = run
+	}
+
+	lines := strings.Split(sb.String(), "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if line = collapseWhitespace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func endsWithSpace(s string) bool {
+	return strings.HasSuffix(s, " ") || strings.HasSuffix(s, "\n")
+}
+
+// loadFont returns the named font of the resources; fonts shared by reference are parsed once.
+func (e *pdfTextExtractor) loadFont(resources pdfDict, name pdfName) *pdfFont {
+	ref := e.doc.dict(resources["Font"])[name]
+	if r, ok := ref.(pdfRef); ok {
+		if font, ok := e.doc.fonts[r]; ok {
+			return font
+		}
+		font := newPDFFont(e.doc, e.doc.dict(r))
+		e.doc.fonts[r] = font
+		return font
+	}
+	return newPDFFont(e.doc, e.doc.dict(ref))
+}
@@ -0,0 +1,369 @@
+package format
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxCMapRange bounds the codes a single bfrange entry may expand to.
+const maxCMapRange = 1 << 16
+
+// maxCodeLength is the longest character code of a CMap, in bytes. Longer codes are malformed
+// and would overflow the code values ranges are computed with.
+const maxCodeLength = 4
+
+// maxCID is the largest CID a CIDFont width may be given for.
+const maxCID = 0xFFFF
+
+// pdfFont maps character codes of shown strings to text.
+type pdfFont struct {
+	// toUnicode maps codes of the font's ToUnicode CMap, keyed by the raw code bytes.
+	toUnicode map[string]string
+	// codeLengths are the byte lengths of codes, longest first.
+	codeLengths []int
+	// composite fonts use multi-byte codes that only a ToUnicode map can decode.
+	composite bool
+	// encoding maps single-byte codes of simple fonts.
+	encoding *[256]string
+	// widths are glyph widths in thousandths of the font size, keyed by code value.
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+// pdfDefaultGlyphWidth stands in for the width of glyphs a font doesn't list.
+const pdfDefaultGlyphWidth = 500
+
+func newPDFFont(doc *pdfDocument, dict pdfDict) *pdfFont {
+	font := &pdfFont{encoding: &winAnsiEncoding, widths: map[int]float64{}, defaultWidth: pdfDefaultGlyphWidth}
+	if dict == nil {
+		return font
+	}
+
+	font.composite = dict["Subtype"] == pdfName("Type0")
+	if font.composite {
+		descendants, _ := doc.resolve(dict["DescendantFonts"]).(pdfArray)
+		if len(descendants) > 0 {
+			font.readCIDWidths(doc, doc.dict(descendants[0]))
+		}
+	} else {
+		first, _ := doc.resolve(dict["FirstChar"]).(int)
+		widths, _ := doc.resolve(dict["Widths"]).(pdfArray)
+		for i, w := range widths {
+			if n, ok := pdfNumber(doc.resolve(w)); ok {
+				font.widths[first+i] = n
+			}
+		}
+	}
+
+	if stream, ok := doc.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := doc.decodeStream(stream); err == nil {
+			font.toUnicode, font.codeLengths = parseToUnicode(data)
+		}
+	}
+
+	if encDict := doc.dict(dict["Encoding"]); encDict != nil {
+		differences, _ := doc.resolve(encDict["Differences"]).(pdfArray)
+		if len(differences) > 0 {
+			encoding := winAnsiEncoding
+			code := 0
+			for _, item := range differences {
+				switch v := doc.resolve(item).(type) {
+				case int:
+					code = v
+				case pdfName:
+					if code >= 0 && code < len(encoding) {
+						if text, ok := glyphText(string(v)); ok {
+							encoding[code] = text
+						}
+					}
+					code++
+				}
+			}
+			font.encoding = &encoding
+		}
+	}
+
+	return font
+}
+
+// readCIDWidths reads the W array of a CIDFont, which lists widths either as "c [w1 w2 ...]"
+// or as "cFirst cLast w". Codes are taken to be CIDs, as with the usual Identity encodings.
+func (f *pdfFont) readCIDWidths(doc *pdfDocument, cidFont pdfDict) {
+	if dw, ok := pdfNumber(doc.resolve(cidFont["DW"])); ok {
+		f.defaultWidth = dw
+	} else if cidFont != nil {
+		f.defaultWidth = 1000
+	}
+
+	w, _ := doc.resolve(cidFont["W"]).(pdfArray)
+	for i := 0; i+1 < len(w); {
+		first, ok := doc.resolve(w[i]).(int)
+		if !ok || first < 0 || first > maxCID {
+			return
+		}
+		switch next := doc.resolve(w[i+1]).(type) {
+		case pdfArray:
+			for j, v := range next[:min(len(next), maxCID-first+1)] {
+				if n, ok := pdfNumber(doc.resolve(v)); ok {
+					f.widths[first+j] = n
+				}
+			}
+			i += 2
+		case int:
+			if i+2 >= len(w) || next < first || next > maxCID {
+				return
+			}
+			n, _ := pdfNumber(doc.resolve(w[i+2]))
+			for c := first; c <= next; c++ {
+				f.widths[c] = n
+			}
+			i += 3
+		default:
+			return
+		}
+	}
+}
+
+// decode returns the text of a shown string, its width in units of the font size and the
+// number of single-byte space codes, which word spacing applies to.
+func (f *pdfFont) decode(s []byte) (string, float64, int) {
+	var sb strings.Builder
+	width, spaces := 0.0, 0
+	for i := 0; i < len(s); {
+		n := 1
+		if f.composite {
+			n = 2
+		}
+		text, matched := "", false
+		if f.toUnicode != nil {
+			for _, length := range f.codeLengths {
+				if i+length > len(s) {
+					continue
+				}
+				if t, ok := f.toUnicode[string(s[i:i+length])]; ok {
+					text, n, matched = t, length, true
+					break
+				}
+			}
+		}
+		if !matched && !f.composite {
+			text = f.encoding[s[i]]
+		}
+		n = min(n, len(s)-i)
+
+		code := codeValue(s[i : i+n])
+		if w, ok := f.widths[code]; ok {
+			width += w / 1000
+		} else {
+			width += f.defaultWidth / 1000
+		}
+		if n == 1 && s[i] == ' ' {
+			spaces++
+		}
+
+		sb.WriteString(text)
+		i += n
+	}
+	return sb.String(), width, spaces
+}
+
+// parseToUnicode reads the bfchar and bfrange mappings of a ToUnicode CMap.
+func parseToUnicode(data []byte) (map[string]string, []int) {
+	mapping := map[string]string{}
+	var lengths []int
+
+	l := &pdfLexer{data: data}
+	var operands []any
+	for {
+		obj, err := l.readObject()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				if lo, ok := operands[i].([]byte); ok && len(lo) > 0 && len(lo) <= maxCodeLength {
+					lengths = append(lengths, len(lo))
+				}
+			}
+
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok := operands[i].([]byte)
+				if !ok || len(src) > maxCodeLength {
+					continue
+				}
+				switch dst := operands[i+1].(type) {
+				case []byte:
+					mapping[string(src)] = decodeUTF16BE(dst)
+				case pdfName:
+					if text, ok := glyphText(string(dst)); ok {
+						mapping[string(src)] = text
+					}
+				}
+			}
+
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, okLo := operands[i].([]byte)
+				hi, okHi := operands[i+1].([]byte)
+				if !okLo || !okHi || len(lo) == 0 || len(lo) > maxCodeLength || len(lo) != len(hi) {
+					continue
+				}
+				addBFRange(mapping, lo, codeValue(lo), codeValue(hi), operands[i+2])
+			}
+		}
+		operands = operands[:0]
+	}
+
+	for code := range mapping {
+		lengths = append(lengths, len(code))
+	}
+	slices.Sort(lengths)
+	lengths = slices.Compact(lengths)
+	slices.Reverse(lengths)
+
+	return mapping, lengths
+}
+
+// addBFRange maps the codes lo..hi either onto consecutive characters starting at a string,
+// or onto the strings of an array. Codes of up to maxCodeLength bytes keep hi-lo from
+// overflowing.
+func addBFRange(mapping map[string]string, loBytes []byte, lo, hi int, dst any) {
+	if hi < lo || hi-lo >= maxCMapRange {
+		return
+	}
+
+	for code := lo; code <= hi; code++ {
+		key := codeBytes(code, len(loBytes))
+		switch d := dst.(type) {
+		case []byte:
+			runes := []rune(decodeUTF16BE(d))
+			if len(runes) == 0 {
+				return
+			}
+			runes[len(runes)-1] += rune(code - lo)
+			mapping[key] = string(runes)
+		case pdfArray:
+			if code-lo < len(d) {
+				if s, ok := d[code-lo].([]byte); ok {
+					mapping[key] = decodeUTF16BE(s)
+				}
+			}
+		}
+	}
+}
+
+// codeValue returns the big-endian value of a code of at most maxCodeLength bytes.
+func codeValue(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func codeBytes(v, n int) string {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return string(b)
+}
+
+func decodeUTF16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// glyphText resolves a glyph name of an encoding Differences array.
+func glyphText(name string) (string, bool) {
+	if text, ok := glyphNames[name]; ok {
+		return text, true
+	}
+	if len(name) == 1 {
+		return name, true
+	}
+	if hexCode, ok := strings.CutPrefix(name, "uni"); ok && len(hexCode) >= 4 {
+		if v, err := strconv.ParseUint(hexCode[:4], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	} else if hexCode, ok := strings.CutPrefix(name, "u"); ok && len(hexCode) >= 4 && len(hexCode) <= 6 {
+		if v, err := strconv.ParseUint(hexCode, 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+	return "", false
+}
+
+// winAnsiEncoding is the Windows-1252 code page PDF uses for simple fonts without another encoding.
+var winAnsiEncoding = func() [256]string {
+	var enc [256]string
+	for c := 0x20; c < 0x7F; c++ {
+		enc[c] = string(rune(c))
+	}
+	for c := 0xA0; c <= 0xFF; c++ {
+		enc[c] = string(rune(c))
+	}
+	for c, r := range map[int]string{
+		0x80: "€", 0x82: "‚", 0x83: "ƒ", 0x84: "„", 0x85: "…", 0x86: "†", 0x87: "‡", 0x88: "ˆ",
+		0x89: "‰", 0x8A: "Š", 0x8B: "‹", 0x8C: "Œ", 0x8E: "Ž", 0x91: "‘", 0x92: "’", 0x93: "“",
+		0x94: "”", 0x95: "•", 0x96: "–", 0x97: "—", 0x98: "˜", 0x99: "™", 0x9A: "š", 0x9B: "›",
+		0x9C: "œ", 0x9E: "ž", 0x9F: "Ÿ",
+	} {
+		enc[c] = r
+	}
+	enc['\t'], enc['\n'], enc['\r'] = " ", "\n", "\n"
+	return enc
+}()
+
+// glyphNames covers the Adobe glyph names of printable ASCII and common punctuation; Latin-1
+// letters are added from latin1GlyphNames.
+var glyphNames = func() map[string]string {
+	names := map[string]string{
+		"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$", "percent": "%",
+		"ampersand": "&", "quotesingle": "'", "parenleft": "(", "parenright": ")", "asterisk": "*",
+		"plus": "+", "comma": ",", "hyphen": "-", "period": ".", "slash": "/", "zero": "0", "one": "1",
+		"two": "2", "three": "3", "four": "4", "five": "5", "six": "6", "seven": "7", "eight": "8",
+		"nine": "9", "colon": ":", "semicolon": ";", "less": "<", "equal": "=", "greater": ">",
+		"question": "?", "at": "@", "bracketleft": "[", "backslash": "\\", "bracketright": "]",
+		"asciicircum": "^", "underscore": "_", "grave": "`", "braceleft": "{", "bar": "|",
+		"braceright": "}", "asciitilde": "~", "quoteleft": "‘", "quoteright": "’", "quotedblleft": "“",
+		"quotedblright": "”", "quotesinglbase": "‚", "quotedblbase": "„", "endash": "–", "emdash": "—",
+		"bullet": "•", "ellipsis": "…", "dagger": "†", "daggerdbl": "‡", "perthousand": "‰",
+		"trademark": "™", "copyright": "©", "registered": "®", "degree": "°", "Euro": "€",
+		"sterling": "£", "yen": "¥", "cent": "¢", "section": "§", "paragraph": "¶", "minus": "−",
+		"nbspace": " ", "fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl",
+		"guillemotleft": "«", "guillemotright": "»", "periodcentered": "·", "multiply": "×",
+		"divide": "÷", "plusminus": "±", "mu": "µ", "OE": "Œ", "oe": "œ", "Scaron": "Š", "scaron": "š",
+		"Zcaron": "Ž", "zcaron": "ž", "Ydieresis": "Ÿ", "dotlessi": "ı",
+	}
+	for i, name := range latin1GlyphNames {
+		names[name] = string(rune(0xC0 + i))
+	}
+	return names
+}()
+
+// latin1GlyphNames are the glyph names of U+00C0 to U+00FF in order.
+var latin1GlyphNames = []string{
+	"Agrave", "Aacute", "Acircumflex", "Atilde", "Adieresis", "Aring", "AE", "Ccedilla",
+	"Egrave", "Eacute", "Ecircumflex", "Edieresis", "Igrave", "Iacute", "Icircumflex", "Idieresis",
+	"Eth", "Ntilde", "Ograve", "Oacute", "Ocircumflex", "Otilde", "Odieresis", "multiply",
+	"Oslash", "Ugrave", "Uacute", "Ucircumflex", "Udieresis", "Yacute", "Thorn", "germandbls",
+	"agrave", "aacute", "acircumflex", "atilde", "adieresis", "aring", "ae", "ccedilla",
+	"egrave", "eacute", "ecircumflex", "edieresis", "igrave", "iacute", "icircumflex", "idieresis",
+	"eth", "ntilde", "ograve", "oacute", "ocircumflex", "otilde", "odieresis", "divide",
+	"oslash", "ugrave", "uacute", "ucircumflex", "udieresis", "yacute", "thorn", "ydieresis",
+}
@@ -0,0 +1,126 @@
+package format_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestPDFToText(t *testing.T) {
+	override := os.Getenv("OVERRIDE") != ""
+	const textFile = "./testdata/test_pdf_fallback.txt"
+
+	raw, err := os.ReadFile("./testdata/test.pdf")
+	require.NoError(t, err, "failed to read PDF file")
+
+	result, err := format.PDFToText(raw, format.PDFOptions{})
+	require.NoError(t, err, "PDFToText failed")
+	assert.Equal(t, 2, result.TotalPages)
+	assert.Equal(t, 2, strings.Count(result.Text, "\f"), "pages should end with form feeds")
+
+	if override {
+		err := os.WriteFile(textFile, []byte(result.Text), 0644)
+		require.NoError(t, err, "failed to write override file")
+		t.Log("Override mode: wrote output to", textFile)
+		return
+	}
+
+	expected, err := os.ReadFile(textFile)
+	require.NoError(t, err, "failed to read expected text file")
+	assert.Equal(t, string(expected), result.Text, "PDFToText output mismatch")
+
+	_, err = format.PDFToText([]byte("not a PDF"), format.PDFOptions{})
+	assert.ErrorContains(t, err, "not a PDF document")
+}
+
+func TestPDFToTextPageRange(t *testing.T) {
+	raw, err := os.ReadFile("./testdata/test.pdf")
+	require.NoError(t, err, "failed to read PDF file")
+
+	all, err := format.PDFToText(raw, format.PDFOptions{})
+	require.NoError(t, err)
+	pages := strings.SplitAfter(all.Text, "\f")
+
+	result, err := format.PDFToText(raw, format.PDFOptions{FirstPage: 2})
+	require.NoError(t, err)
+	assert.Equal(t, format.PDFText{Text: pages[1], TotalPages: 2}, result)
+
+	result, err = format.PDFToText(raw, format.PDFOptions{LastPage: 1})
+	require.NoError(t, err)
+	assert.Equal(t, format.PDFText{Text: pages[0], TotalPages: 2}, result)
+
+	result, err = format.PDFToText(raw, format.PDFOptions{FirstPage: 3})
+	require.NoError(t, err)
+	assert.Equal(t, format.PDFText{TotalPages: 2}, result)
+
+	total, err := format.PDFPageCount(raw)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
+
+// compositeFontPDF builds a one page PDF showing <0041> in a Type0 font with the given W array
+// and bfrange line in its ToUnicode CMap.
+func compositeFontPDF(widths, bfrange string) []byte {
+	cmap := "begincmap\n1 begincodespacerange <0000> <FFFF> endcodespacerange\n1 beginbfrange " + bfrange + " endbfrange\nendcmap"
+	content := "BT /F1 12 Tf 72 700 Td <0041> Tj ET"
+	return []byte(fmt.Sprintf(`%%PDF-1.7
+1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj
+2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj
+3 0 obj << /Type /Page /Parent 2 0 R /Resources << /Font << /F1 4 0 R >> >> /Contents 7 0 R >> endobj
+4 0 obj << /Type /Font /Subtype /Type0 /DescendantFonts [5 0 R] /ToUnicode 6 0 R >> endobj
+5 0 obj << /Type /Font /Subtype /CIDFontType2 /W %s >> endobj
+6 0 obj << /Length %d >>
+stream
+%s
+endstream
+endobj
+7 0 obj << /Length %d >>
+stream
+%s
+endstream
+endobj
+trailer << /Root 1 0 R >>
+%%%%EOF
+`, widths, len(cmap), cmap, len(content), content))
+}
+
+func TestPDFToTextMalformedFont(t *testing.T) {
+	cases := map[string]struct {
+		widths  string
+		bfrange string
+	}{
+		"well formed":              {widths: "[65 [600]]", bfrange: "<0041> <0042> <0041>"},
+		"8-byte bfrange codes":     {widths: "[65 [600]]", bfrange: "<8000000000000000> <7fffffffffffffff> <0041>"},
+		"overflowing width range":  {widths: "[-9223372036854775808 9223372036854775807 500]", bfrange: "<0041> <0042> <0041>"},
+		"width range beyond CIDs":  {widths: "[0 9223372036854775807 500]", bfrange: "<0041> <0042> <0041>"},
+		"width array beyond CIDs":  {widths: "[65535 [500 500 500]]", bfrange: "<0041> <0042> <0041>"},
+		"negative width range CID": {widths: "[-5 [500]]", bfrange: "<0041> <0042> <0041>"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			done := make(chan format.PDFText, 1)
+			go func() {
+				result, err := format.PDFToText(compositeFontPDF(tc.widths, tc.bfrange), format.PDFOptions{})
+				assert.NoError(t, err)
+				done <- result
+			}()
+
+			select {
+			case result := <-done:
+				assert.Equal(t, 1, result.TotalPages)
+				if name == "well formed" {
+					assert.Equal(t, "A\n\f", result.Text)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("PDFToText did not finish on a malformed font")
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package format
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// drawingMLNS is the namespace of text paragraphs, runs and line breaks.
+const drawingMLNS = "http://schemas.openxmlformats.org/drawingml/2006/main"
+
+// pptxSlide is the text of one slide.
+type pptxSlide struct {
+	Title string
+	Body  []string
+}
+
+// PPTX2Text extracts the title and body text of every slide of a PowerPoint presentation,
+// one markdown section per slide in presentation order.
+func (c Converter) PPTX2Text(raw []byte) (string, error) {
+	slides, err := readPPTX(raw)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, slide := range slides {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "## Slide %d", i+1)
+		if slide.Title != "" {
+			sb.WriteString(": " + slide.Title)
+		}
+		sb.WriteString("\n")
+
+		if len(slide.Body) > 0 {
+			sb.WriteString("\n" + strings.Join(slide.Body, "\n") + "\n")
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func readPPTX(raw []byte) ([]pptxSlide, error) {
+	files, err := zipFiles(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var presentation struct {
+		Slides []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sldIdLst>sldId"`
+	}
+	if err := decodeZipXML(files, "ppt/presentation.xml", &presentation); err != nil {
+		return nil, err
+	}
+
+	targets, err := readRelationships(files, "ppt/_rels/presentation.xml.rels", "ppt")
+	if err != nil {
+		return nil, err
+	}
+
+	slides := make([]pptxSlide, 0, len(presentation.Slides))
+	for i, ref := range presentation.Slides {
+		target, ok := targets[ref.RID]
+		if !ok {
+			return nil, fmt.Errorf("slide %d has no slide part", i+1)
+		}
+		slide, err := readSlide(files, target)
+		if err != nil {
+			return nil, fmt.Errorf("read slide %d failed: %w", i+1, err)
+		}
+		slides = append(slides, slide)
+	}
+
+	return slides, nil
+}
+
+// readSlide collects the paragraphs of a slide. Text of the title placeholder becomes the title,
+// text of all other shapes and tables the body, in document order.
+func readSlide(files map[string]*zip.File, name string) (pptxSlide, error) {
+	f, ok := files[name]
+	if !ok {
+		return pptxSlide{}, fmt.Errorf("%s not found", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return pptxSlide{}, fmt.Errorf("open %s failed: %w", name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	var (
+		slide      pptxSlide
+		shapeDepth int
+		isTitle    bool
+		inText     bool
+		paragraph  strings.Builder
+		shapeText  []string
+	)
+
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return pptxSlide{}, fmt.Errorf("decode %s failed: %w", name, err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch textElement(el.Name) {
+			case "sp":
+				shapeDepth++
+				if shapeDepth == 1 {
+					isTitle = false
+					shapeText = nil
+				}
+			case "ph":
+				for _, attr := range el.Attr {
+					if attr.Name.Local == "type" && (attr.Value == "title" || attr.Value == "ctrTitle") {
+						isTitle = true
+					}
+				}
+			case "p":
+				paragraph.Reset()
+			case "t":
+				inText = true
+			case "br":
+				paragraph.WriteString(" ")
+			}
+
+		case xml.CharData:
+			if inText {
+				paragraph.Write(el)
+			}
+
+		case xml.EndElement:
+			switch textElement(el.Name) {
+			case "t":
+				inText = false
+			case "p":
+				text := collapseWhitespace(paragraph.String())
+				if text == "" {
+					continue
+				}
+				if shapeDepth > 0 {
+					shapeText = append(shapeText, text)
+				} else {
+					slide.Body = append(slide.Body, text)
+				}
+			case "sp":
+				shapeDepth--
+				if shapeDepth > 0 {
+					continue
+				}
+				if isTitle && slide.Title == "" {
+					slide.Title = strings.Join(shapeText, " ")
+				} else {
+					slide.Body = append(slide.Body, shapeText...)
+				}
+			}
+		}
+	}
+
+	return slide, nil
+}
+
+// textElement returns the local name of shape and DrawingML text elements the slide reader
+// handles, and an empty string for any other element.
+func textElement(name xml.Name) string {
+	switch name.Local {
+	case "sp", "ph":
+		return name.Local
+	case "p", "t", "br":
+		if name.Space == drawingMLNS {
+			return name.Local
+		}
+	}
+	return ""
+}
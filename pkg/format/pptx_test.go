@@ -0,0 +1,30 @@
+package format_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestPPTX2Text(t *testing.T) {
+	raw, err := os.ReadFile("./testdata/test.pptx")
+	require.NoError(t, err, "failed to read PPTX file")
+
+	cnv := format.Converter{}
+
+	result, err := cnv.PPTX2Text(raw)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"## Slide 1: Q3 Review\n\nFinance team\n"+
+			"\n## Slide 2: Highlights\n\nRevenue grew\nCosts flat\nRevenue\n+12%\n"+
+			"\n## Slide 3\n\nQuestions?\n",
+		result,
+	)
+
+	_, err = cnv.PPTX2Text([]byte("not a presentation"))
+	assert.Error(t, err)
+}
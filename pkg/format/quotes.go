@@ -0,0 +1,193 @@
+package format
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// quoteAttribution matches the line mail clients put above a quoted reply, like
+// "On Mon, Jan 5, 2026 at 10:00 AM Jane <jane@example.com> wrote:" or, with the sender after
+// the verb, "Am 05.01.2026 um 10:00 schrieb Jane:".
+var quoteAttribution = regexp.MustCompile(`(?i)^(on|am|le|el|il|op)\s.*\s(wrote|schrieb|a écrit|escribió|ha scritto|schreef)(\s.*)?\s?:$`)
+
+// replySeparators start the quoted original below a reply in Outlook style clients.
+var replySeparators = []string{"-----Original Message-----", "________________________________"}
+
+// forwardMarkers start forwarded content, which is kept since it's the point of the message.
+var forwardMarkers = []string{"Forwarded message", "Begin forwarded message", "Forwarded Message"}
+
+// StripQuotedHTML removes the quoted history of replies from an HTML body: Gmail's gmail_quote
+// blocks, cited blockquotes of Apple Mail and Thunderbird, Yahoo quotes and everything from
+// Outlook's reply header on. Forwarded messages are kept.
+func StripQuotedHTML(htmlContent []byte) []byte {
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
+	if err != nil {
+		return htmlContent
+	}
+
+	if !stripQuoteNodes(doc) {
+		return htmlContent
+	}
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return htmlContent
+	}
+	return buf.Bytes()
+}
+
+func stripQuoteNodes(n *html.Node) bool {
+	changed := false
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch {
+		case child.Type != html.ElementNode:
+		case attrValue(child, "id") == "divRplyFwdMsg":
+			if isForward(child) {
+				break
+			}
+			// Outlook puts the quoted original after its reply header, often below a rule.
+			if prev := child.PrevSibling; prev != nil && prev.Type == html.ElementNode && prev.Data == "hr" {
+				n.RemoveChild(prev)
+			}
+			for next != nil {
+				following := next.NextSibling
+				n.RemoveChild(next)
+				next = following
+			}
+			n.RemoveChild(child)
+			changed = true
+		case isQuoteNode(child) && !isForward(child):
+			if prev := previousElement(child); prev != nil && quoteAttribution.MatchString(collapseWhitespace(nodeText(prev))) {
+				n.RemoveChild(prev)
+			}
+			n.RemoveChild(child)
+			changed = true
+		default:
+			if stripQuoteNodes(child) {
+				changed = true
+			}
+		}
+		child = next
+	}
+	return changed
+}
+
+func isQuoteNode(n *html.Node) bool {
+	classes := strings.Fields(attrValue(n, "class"))
+	for _, class := range classes {
+		switch class {
+		case "gmail_quote", "gmail_quote_container", "yahoo_quoted", "moz-cite-prefix":
+			return true
+		}
+	}
+	return n.Data == "blockquote" && strings.EqualFold(attrValue(n, "type"), "cite")
+}
+
+// previousElement returns the element before n, skipping whitespace between them.
+func previousElement(n *html.Node) *html.Node {
+	for prev := n.PrevSibling; prev != nil; prev = prev.PrevSibling {
+		switch {
+		case prev.Type == html.ElementNode:
+			return prev
+		case prev.Type == html.TextNode && strings.TrimSpace(prev.Data) != "":
+			return nil
+		}
+	}
+	return nil
+}
+
+func isForward(n *html.Node) bool {
+	return containsAny(nodeText(n), forwardMarkers)
+}
+
+// StripQuotedText removes the quoted history at the end of a plain text or markdown body: the
+// trailing run of "> " lines with the attribution line above it, and everything below an
+// Outlook style separator. Quotes interleaved with replies are kept, as are forwards. A body
+// that is all quote is returned unchanged.
+func StripQuotedText(text string) string {
+	lines := strings.Split(text, "\n")
+
+	cut := len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if containsAny(trimmed, forwardMarkers) {
+			break
+		}
+		if hasAnyPrefix(trimmed, replySeparators) && followedByHeaders(lines[i+1:]) {
+			cut = i
+			break
+		}
+	}
+
+	// Walk back over the trailing quote block.
+	end := cut
+	for end > 0 && (isQuotedLine(lines[end-1]) || strings.TrimSpace(lines[end-1]) == "") {
+		end--
+	}
+	if end < cut && hasQuotedLine(lines[end:cut]) {
+		cut = end
+		if start, ok := attributionStart(lines[:cut]); ok {
+			cut = start
+		}
+	}
+
+	stripped := strings.TrimRight(strings.Join(lines[:cut], "\n"), " \t\n")
+	if strings.TrimSpace(stripped) == "" {
+		return text
+	}
+	if strings.HasSuffix(text, "\n") {
+		stripped += "\n"
+	}
+	return stripped
+}
+
+func isQuotedLine(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+func hasQuotedLine(lines []string) bool {
+	for _, line := range lines {
+		if isQuotedLine(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// followedByHeaders reports whether a separator introduces a quoted original, which starts
+// with From: or Sent: headers.
+func followedByHeaders(lines []string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.Trim(line, "*"))
+		if line == "" {
+			continue
+		}
+		return hasAnyPrefix(line, []string{"From:", "Sent:"})
+	}
+	return false
+}
+
+// attributionStart finds the attribution above a quote block among the last non-blank lines;
+// clients wrap long attributions over two lines.
+func attributionStart(lines []string) (int, bool) {
+	last := len(lines) - 1
+	for last >= 0 && strings.TrimSpace(lines[last]) == "" {
+		last--
+	}
+	if last < 0 {
+		return 0, false
+	}
+
+	line := strings.TrimSpace(lines[last])
+	if quoteAttribution.MatchString(line) {
+		return last, true
+	}
+	if last > 0 && quoteAttribution.MatchString(strings.TrimSpace(lines[last-1])+" "+line) {
+		return last - 1, true
+	}
+	return 0, false
+}
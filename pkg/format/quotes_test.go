@@ -0,0 +1,100 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestStripQuotedHTML(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "gmail quote",
+			input: `<div dir="ltr">Sounds good.</div><br><div class="gmail_quote gmail_quote_container">` +
+				`<div dir="ltr" class="gmail_attr">On Mon, Jan 5, 2026 at 10:00 AM Jane &lt;jane@example.com&gt; wrote:<br></div>` +
+				`<blockquote class="gmail_quote">Can we meet?</blockquote></div>`,
+			expected: `<html><head></head><body><div dir="ltr">Sounds good.</div><br/></body></html>`,
+		},
+		{
+			name: "apple mail cite with attribution",
+			input: `<div>Yes.</div><div><br><div>On Jan 5, 2026, at 10:00, Jane wrote:</div>` +
+				`<blockquote type="cite"><div>Can we meet?</div></blockquote></div>`,
+			expected: `<html><head></head><body><div>Yes.</div><div><br/></div></body></html>`,
+		},
+		{
+			name: "outlook reply header",
+			input: `<div>Thanks</div><hr><div id="divRplyFwdMsg"><b>From:</b> Jane</div>` +
+				`<div>Original text</div>`,
+			expected: `<html><head></head><body><div>Thanks</div></body></html>`,
+		},
+		{
+			name: "forward kept",
+			input: `<div>FYI</div><div class="gmail_quote"><div class="gmail_attr">---------- Forwarded message ---------<br>` +
+				`From: Jane</div><div>Report attached</div></div>`,
+			expected: `<div>FYI</div><div class="gmail_quote"><div class="gmail_attr">---------- Forwarded message ---------<br>` +
+				`From: Jane</div><div>Report attached</div></div>`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, string(format.StripQuotedHTML([]byte(tc.input))))
+		})
+	}
+}
+
+func TestStripQuotedText(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "trailing quote with attribution",
+			input:    "Sounds good.\n\nOn Mon, Jan 5, 2026 at 10:00 AM Jane <jane@example.com> wrote:\n> Can we meet?\n>\n> Jane\n",
+			expected: "Sounds good.\n",
+		},
+		{
+			name:     "attribution wrapped over two lines",
+			input:    "Sounds good.\n\nOn Mon, Jan 5, 2026 at 10:00 AM Jane Doe <jane@example.com>\nwrote:\n\n> Can we meet?",
+			expected: "Sounds good.",
+		},
+		{
+			name:     "localized attribution",
+			input:    "Passt.\n\nAm 05.01.2026 um 10:00 schrieb Jane:\n> Treffen wir uns?\n",
+			expected: "Passt.\n",
+		},
+		{
+			name:     "outlook separator",
+			input:    "Thanks\n\n-----Original Message-----\nFrom: Jane\nSent: Monday\n\nOriginal text\n",
+			expected: "Thanks\n",
+		},
+		{
+			name:     "interleaved quotes kept",
+			input:    "> Can we meet?\nYes, at 3.\n> Where?\nMy office.\n",
+			expected: "> Can we meet?\nYes, at 3.\n> Where?\nMy office.\n",
+		},
+		{
+			name:     "quote only body kept",
+			input:    "> Can we meet?\n",
+			expected: "> Can we meet?\n",
+		},
+		{
+			name:     "forward kept",
+			input:    "FYI\n\n---------- Forwarded message ---------\nFrom: Jane\n\n> earlier note\n",
+			expected: "FYI\n\n---------- Forwarded message ---------\nFrom: Jane\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, format.StripQuotedText(tc.input))
+		})
+	}
+}
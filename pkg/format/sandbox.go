@@ -0,0 +1,74 @@
+package format
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ToolLimits restricts external tool runs, since pandoc and pdftotext parse attachments from
+// arbitrary senders. Zero values leave a resource unlimited.
+type ToolLimits struct {
+	// CPUTime caps the processor time of a run, rounded up to whole seconds.
+	CPUTime time.Duration
+	// MaxMemoryBytes caps the data segment of a run.
+	MaxMemoryBytes int64
+	// MaxOutputBytes caps the output read from a run; it's killed once it writes more.
+	MaxOutputBytes int64
+	// NoNetwork runs tools in an empty network namespace where the system supports it.
+	NoNetwork bool
+}
+
+// command builds the command for a tool run. CPU and memory limits are set with the shell's
+// ulimit, which then execs the tool, so they aren't available on Windows.
+func (l ToolLimits) command(ctx context.Context, path string, args []string, isolate bool) *exec.Cmd {
+	if script := l.ulimitScript(); script != "" {
+		args = append([]string{"-c", script + `exec "$0" "$@"`, path}, args...)
+		path = "/bin/sh"
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	// Children of the tool may hold the output pipe open after it is killed.
+	cmd.WaitDelay = time.Second
+	if isolate {
+		isolateNetwork(cmd)
+	}
+	return cmd
+}
+
+func (l ToolLimits) ulimitScript() string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+
+	var sb strings.Builder
+	if l.CPUTime > 0 {
+		fmt.Fprintf(&sb, "ulimit -t %d && ", int64((l.CPUTime+time.Second-1)/time.Second))
+	}
+	if l.MaxMemoryBytes > 0 {
+		fmt.Fprintf(&sb, "ulimit -d %d && ", max(l.MaxMemoryBytes/1024, 1))
+	}
+	return sb.String()
+}
+
+// cappedBuffer collects tool output up to max bytes, calling exceeded once more is written.
+// The buffer isn't embedded so that io.Copy can't bypass Write through bytes.Buffer.ReadFrom.
+type cappedBuffer struct {
+	buf      bytes.Buffer
+	max      int64
+	exceeded func()
+	overflow bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.max > 0 && int64(b.buf.Len()+len(p)) > b.max {
+		b.overflow = true
+		b.exceeded()
+		return 0, fmt.Errorf("output exceeds %d bytes", b.max)
+	}
+	return b.buf.Write(p)
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package format
+
+import "os/exec"
+
+// isolateNetwork is a no-op where network namespaces aren't available.
+func isolateNetwork(*exec.Cmd) {}
@@ -0,0 +1,112 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Table limits applied when TableOptions leaves them unset.
+const (
+	DefaultMaxTableRows    = 100
+	DefaultMaxTableColumns = 20
+)
+
+// utf8BOM is the byte order mark spreadsheet programs prepend to exported CSV files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// TableOptions bounds the size of rendered tables.
+type TableOptions struct {
+	// MaxRows is the number of data rows rendered below the header, DefaultMaxTableRows if zero.
+	MaxRows int
+	// MaxColumns is the number of leading columns rendered, DefaultMaxTableColumns if zero.
+	MaxColumns int
+}
+
+// CSV2MD renders CSV content as a markdown table with the first record as header. Rows and
+// columns beyond the limits are dropped and noted below the table.
+func CSV2MD(raw []byte, opts TableOptions) (string, error) {
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = DefaultMaxTableRows
+	}
+	if opts.MaxColumns <= 0 {
+		opts.MaxColumns = DefaultMaxTableColumns
+	}
+
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(raw, utf8BOM)))
+	r.FieldsPerRecord = -1
+
+	var rows [][]string
+	totalRows, totalColumns := 0, 0
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("csv.Read failed: %w", err)
+		}
+
+		totalRows++
+		totalColumns = max(totalColumns, len(record))
+		if len(rows) <= opts.MaxRows {
+			rows = append(rows, record[:min(len(record), opts.MaxColumns)])
+		}
+	}
+
+	if len(rows) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	writeMarkdownTable(&sb, rows)
+
+	dataRows := totalRows - 1
+	shownRows := len(rows) - 1
+	var omitted []string
+	if shownRows < dataRows {
+		omitted = append(omitted, fmt.Sprintf("%d of %d rows", shownRows, dataRows))
+	}
+	if opts.MaxColumns < totalColumns {
+		omitted = append(omitted, fmt.Sprintf("%d of %d columns", opts.MaxColumns, totalColumns))
+	}
+	if len(omitted) > 0 {
+		fmt.Fprintf(&sb, "\n(showing %s)\n", strings.Join(omitted, ", "))
+	}
+
+	return sb.String(), nil
+}
+
+// writeMarkdownTable renders rows as a table with the first row as header.
+func writeMarkdownTable(sb *strings.Builder, rows [][]string) {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := range width {
+			cell := ""
+			if i < len(row) {
+				cell = markdownCell(row[i])
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+}
+
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return collapseWhitespace(value)
+}
@@ -0,0 +1,52 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestCSV2MD(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		opts     format.TableOptions
+		expected string
+	}{
+		{
+			name:  "quoted fields and ragged rows",
+			input: "\xEF\xBB\xBFname,amount,note\r\nAcme,100,\"net 30, paid\"\r\nGlobex,250\r\nInitech,75,a|b\r\n",
+			expected: "| name | amount | note |\n" +
+				"| --- | --- | --- |\n" +
+				"| Acme | 100 | net 30, paid |\n" +
+				"| Globex | 250 |  |\n" +
+				"| Initech | 75 | a\\|b |\n",
+		},
+		{
+			name:  "row and column limits",
+			input: "a,b,c\n1,2,3\n4,5,6\n7,8,9\n",
+			opts:  format.TableOptions{MaxRows: 2, MaxColumns: 2},
+			expected: "| a | b |\n" +
+				"| --- | --- |\n" +
+				"| 1 | 2 |\n" +
+				"| 4 | 5 |\n" +
+				"\n(showing 2 of 3 rows, 2 of 3 columns)\n",
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := format.CSV2MD([]byte(tc.input), tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package format
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// maxRedirectUnwraps bounds redirectors wrapped in other redirectors.
+const maxRedirectUnwraps = 5
+
+var (
+	// trackingParams are query parameters added for analytics that don't affect the destination.
+	trackingParams = []string{
+		"fbclid", "gclid", "dclid", "msclkid", "yclid", "igshid", "mc_eid", "mc_cid", "_hsenc", "_hsmi",
+		"mkt_tok", "trk", "trkcampaign", "oly_enc_id", "oly_anon_id", "vero_id", "__s", "ss_source",
+	}
+	trackingParamPrefixes = []string{"utm_", "pk_", "mtm_"}
+
+	// redirectParams carry the real destination of click tracking redirectors.
+	redirectParams = []string{"url", "u", "q", "target", "dest", "destination", "redirect", "redirect_url", "link"}
+	// redirectHosts are known redirectors, besides hosts and paths that look like click tracking.
+	redirectHosts = []string{"safelinks.protection.outlook.com", "l.facebook.com", "lm.facebook.com", "l.instagram.com"}
+)
+
+// CleanURL makes a link readable: it unwraps click tracking redirectors that embed the real
+// destination and removes analytics query parameters like utm_source. URLs it can't parse are
+// returned unchanged.
+func CleanURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return rawURL
+	}
+
+	for range maxRedirectUnwraps {
+		target, ok := redirectTarget(u)
+		if !ok {
+			break
+		}
+		u = target
+	}
+
+	u.RawQuery = stripTrackingParams(u.RawQuery)
+	return u.String()
+}
+
+// CleanTextURLs applies CleanURL to the URLs of plain text.
+func CleanTextURLs(text string) string {
+	return textURLPattern.ReplaceAllStringFunc(text, CleanURL)
+}
+
+// redirectTarget returns the destination embedded in a redirector URL.
+func redirectTarget(u *url.URL) (*url.URL, bool) {
+	if !isRedirector(u) {
+		return nil, false
+	}
+	query := u.Query()
+	for _, param := range redirectParams {
+		target, err := url.Parse(query.Get(param))
+		if err == nil && (target.Scheme == "http" || target.Scheme == "https") && target.Host != "" {
+			return target, true
+		}
+	}
+	return nil, false
+}
+
+func isRedirector(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	urlPath := strings.ToLower(u.EscapedPath())
+	switch {
+	case hasAnySuffix(host, redirectHosts):
+		return true
+	case (host == "google.com" || strings.HasSuffix(host, ".google.com")) && urlPath == "/url":
+		return true
+	default:
+		return hasAnyPrefix(host, trackingHostPrefixes) || containsAny(urlPath, trackingMarkers)
+	}
+}
+
+// stripTrackingParams removes analytics parameters, keeping the order and encoding of the rest.
+func stripTrackingParams(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	var kept []string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		name, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		name = strings.ToLower(name)
+		if pair == "" || hasAnyPrefix(name, trackingParamPrefixes) || slices.Contains(trackingParams, name) {
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	return strings.Join(kept, "&")
+}
@@ -0,0 +1,69 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestCleanURL(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "utm parameters",
+			input:    "https://example.com/post?id=7&utm_source=newsletter&utm_medium=email&fbclid=abc",
+			expected: "https://example.com/post?id=7",
+		},
+		{
+			name:     "only tracking parameters",
+			input:    "https://example.com/?utm_campaign=spring",
+			expected: "https://example.com/",
+		},
+		{
+			name:     "google redirect",
+			input:    "https://www.google.com/url?q=https://example.com/doc%3Futm_source%3Dx%26page%3D2&sa=D&ust=1",
+			expected: "https://example.com/doc?page=2",
+		},
+		{
+			name:     "outlook safelinks",
+			input:    "https://nam12.safelinks.protection.outlook.com/?url=https%3A%2F%2Fexample.com%2Freport&data=05",
+			expected: "https://example.com/report",
+		},
+		{
+			name:     "click tracker embedding destination",
+			input:    "https://click.mailer.example.net/redirect?url=https%3A%2F%2Fshop.example.com%2Fsale",
+			expected: "https://shop.example.com/sale",
+		},
+		{
+			name:     "url parameter of a normal link kept",
+			input:    "https://twitter.com/intent/tweet?url=https%3A%2F%2Fexample.com",
+			expected: "https://twitter.com/intent/tweet?url=https%3A%2F%2Fexample.com",
+		},
+		{
+			name:     "opaque tracker kept",
+			input:    "https://click.example.com/ls/click?upn=abc123",
+			expected: "https://click.example.com/ls/click?upn=abc123",
+		},
+		{
+			name:     "non http link unchanged",
+			input:    "mailto:jane@example.com?utm_source=x",
+			expected: "mailto:jane@example.com?utm_source=x",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, format.CleanURL(tc.input))
+		})
+	}
+}
+
+func TestCleanTextURLs(t *testing.T) {
+	input := "Read it at https://example.com/post?utm_source=mail&id=7 today."
+	assert.Equal(t, "Read it at https://example.com/post?id=7 today.", format.CleanTextURLs(input))
+}
@@ -0,0 +1,304 @@
+package format
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Spreadsheet output formats.
+const (
+	SheetFormatMarkdown = "markdown"
+	SheetFormatCSV      = "csv"
+)
+
+// Spreadsheet limits applied when SheetOptions leaves them unset.
+const (
+	DefaultMaxSheets    = 10
+	DefaultMaxSheetRows = 100
+)
+
+// maxSheetColumns bounds the width of a row, so a stray cell far to the right can't blow up the output.
+const maxSheetColumns = 256
+
+// SheetOptions controls how spreadsheets are rendered.
+type SheetOptions struct {
+	// Format is SheetFormatMarkdown (default) or SheetFormatCSV.
+	Format string
+	// MaxSheets is the number of sheets rendered, DefaultMaxSheets if zero.
+	MaxSheets int
+	// MaxRows is the number of rows rendered per sheet, DefaultMaxSheetRows if zero.
+	MaxRows int
+}
+
+// worksheet is a sheet read from a workbook.
+type worksheet struct {
+	Name string
+	Rows [][]string
+	// TotalRows is the number of rows in the sheet, Rows may hold fewer.
+	TotalRows int
+}
+
+// XLSX2Text renders the worksheets of an Excel workbook as markdown tables or CSV, one section per sheet.
+// Cells are rendered with their stored values, formulas with their cached results.
+func (c Converter) XLSX2Text(raw []byte, opts SheetOptions) (string, error) {
+	if opts.Format == "" {
+		opts.Format = SheetFormatMarkdown
+	}
+	if opts.Format != SheetFormatMarkdown && opts.Format != SheetFormatCSV {
+		return "", fmt.Errorf("unsupported sheet format %q", opts.Format)
+	}
+	if opts.MaxSheets <= 0 {
+		opts.MaxSheets = DefaultMaxSheets
+	}
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = DefaultMaxSheetRows
+	}
+
+	sheets, total, err := readXLSX(raw, opts.MaxSheets, opts.MaxRows)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for i, sheet := range sheets {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("## " + sheet.Name + "\n\n")
+
+		if len(sheet.Rows) == 0 {
+			sb.WriteString("(empty sheet)\n")
+			continue
+		}
+
+		if opts.Format == SheetFormatCSV {
+			if err := writeCSV(&sb, sheet.Rows); err != nil {
+				return "", err
+			}
+		} else {
+			writeMarkdownTable(&sb, sheet.Rows)
+		}
+
+		if sheet.TotalRows > len(sheet.Rows) {
+			fmt.Fprintf(&sb, "\n(showing %d of %d rows)\n", len(sheet.Rows), sheet.TotalRows)
+		}
+	}
+
+	if total > len(sheets) {
+		fmt.Fprintf(&sb, "\n(showing %d of %d sheets)\n", len(sheets), total)
+	}
+
+	return sb.String(), nil
+}
+
+// readXLSX reads up to maxSheets worksheets in workbook order, keeping the first maxRows rows of each.
+// It also returns the number of worksheets in the workbook.
+func readXLSX(raw []byte, maxSheets, maxRows int) ([]worksheet, int, error) {
+	files, err := zipFiles(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, 0, err
+	}
+
+	targets, err := readRelationships(files, "xl/_rels/workbook.xml.rels", "xl")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sharedStrings, err := readSharedStrings(files)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sheets := make([]worksheet, 0, min(len(workbook.Sheets), maxSheets))
+	for _, ws := range workbook.Sheets[:min(len(workbook.Sheets), maxSheets)] {
+		target, ok := targets[ws.RID]
+		if !ok {
+			return nil, 0, fmt.Errorf("sheet %q has no worksheet part", ws.Name)
+		}
+		sheet, err := readWorksheet(files, target, sharedStrings, maxRows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("read sheet %q failed: %w", ws.Name, err)
+		}
+		sheet.Name = ws.Name
+		sheets = append(sheets, sheet)
+	}
+
+	return sheets, len(workbook.Sheets), nil
+}
+
+// readSharedStrings loads the shared string table; rich text runs are concatenated.
+func readSharedStrings(files map[string]*zip.File) ([]string, error) {
+	if _, ok := files["xl/sharedStrings.xml"]; !ok {
+		return nil, nil
+	}
+
+	var table struct {
+		Items []struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := decodeZipXML(files, "xl/sharedStrings.xml", &table); err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(table.Items))
+	for i, item := range table.Items {
+		text := item.Text
+		for _, run := range item.Runs {
+			text += run.Text
+		}
+		strs[i] = text
+	}
+
+	return strs, nil
+}
+
+type xlsxCell struct {
+	Ref       string `xml:"r,attr"`
+	Type      string `xml:"t,attr"`
+	Value     string `xml:"v"`
+	InlineStr struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"is"`
+}
+
+// readWorksheet streams the rows of a worksheet, so only the kept rows are held in memory.
+func readWorksheet(files map[string]*zip.File, name string, sharedStrings []string, maxRows int) (worksheet, error) {
+	f, ok := files[name]
+	if !ok {
+		return worksheet{}, fmt.Errorf("%s not found", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return worksheet{}, fmt.Errorf("open %s failed: %w", name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	var sheet worksheet
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return worksheet{}, fmt.Errorf("decode %s failed: %w", name, err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row struct {
+			Cells []xlsxCell `xml:"c"`
+		}
+		if err := dec.DecodeElement(&row, &start); err != nil {
+			return worksheet{}, fmt.Errorf("decode %s failed: %w", name, err)
+		}
+
+		values := rowValues(row.Cells, sharedStrings)
+		if len(values) == 0 {
+			continue
+		}
+		sheet.TotalRows++
+		if len(sheet.Rows) < maxRows {
+			sheet.Rows = append(sheet.Rows, values)
+		}
+	}
+
+	return sheet, nil
+}
+
+// rowValues places cells at their referenced columns and drops trailing empty cells.
+func rowValues(cells []xlsxCell, sharedStrings []string) []string {
+	var values []string
+	for _, cell := range cells {
+		col := len(values)
+		if cell.Ref != "" {
+			if c, ok := columnIndex(cell.Ref); ok {
+				col = c
+			}
+		}
+		if col >= maxSheetColumns {
+			continue
+		}
+		for len(values) <= col {
+			values = append(values, "")
+		}
+		values[col] = cellValue(cell, sharedStrings)
+	}
+
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+func cellValue(cell xlsxCell, sharedStrings []string) string {
+	switch cell.Type {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+		if err != nil || i < 0 || i >= len(sharedStrings) {
+			return ""
+		}
+		return sharedStrings[i]
+	case "inlineStr":
+		text := cell.InlineStr.Text
+		for _, run := range cell.InlineStr.Runs {
+			text += run.Text
+		}
+		return text
+	case "b":
+		if cell.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return cell.Value
+	}
+}
+
+// columnIndex converts the column letters of a cell reference like "AB12" to a zero-based index.
+func columnIndex(ref string) (int, bool) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	return col - 1, n > 0
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("csv.WriteAll failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package format_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+func TestXLSX2Text(t *testing.T) {
+	raw, err := os.ReadFile("./testdata/test.xlsx")
+	require.NoError(t, err, "failed to read XLSX file")
+
+	cases := []struct {
+		name        string
+		opts        format.SheetOptions
+		expected    string
+		expectedErr string
+	}{
+		{
+			name: "markdown",
+			opts: format.SheetOptions{},
+			expected: "## Summary\n\n" +
+				"| Quarter | Revenue | Approved |\n" +
+				"| --- | --- | --- |\n" +
+				"| Q1 | 1200.5 | TRUE |\n" +
+				"| Q2 (est.) | 1350 | FALSE |\n" +
+				"| Total | 2550.5 |  |\n" +
+				"\n## Notes\n\n" +
+				"| Note |  |\n" +
+				"| --- | --- |\n" +
+				"|  | figures \\| unaudited, \"draft\" |\n",
+		},
+		{
+			name: "csv",
+			opts: format.SheetOptions{Format: format.SheetFormatCSV},
+			expected: "## Summary\n\n" +
+				"Quarter,Revenue,Approved\n" +
+				"Q1,1200.5,TRUE\n" +
+				"Q2 (est.),1350,FALSE\n" +
+				"Total,2550.5\n" +
+				"\n## Notes\n\n" +
+				"Note\n" +
+				",\"figures | unaudited, \"\"draft\"\"\"\n",
+		},
+		{
+			name: "row and sheet limits",
+			opts: format.SheetOptions{Format: format.SheetFormatCSV, MaxSheets: 1, MaxRows: 2},
+			expected: "## Summary\n\n" +
+				"Quarter,Revenue,Approved\n" +
+				"Q1,1200.5,TRUE\n" +
+				"\n(showing 2 of 4 rows)\n" +
+				"\n(showing 1 of 2 sheets)\n",
+		},
+		{
+			name:        "unsupported format",
+			opts:        format.SheetOptions{Format: "json"},
+			expectedErr: `unsupported sheet format "json"`,
+		},
+	}
+
+	cnv := format.Converter{}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := cnv.XLSX2Text(raw, tc.opts)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	_, err = cnv.XLSX2Text([]byte("not a workbook"), format.SheetOptions{})
+	assert.Error(t, err)
+}
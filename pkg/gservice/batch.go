@@ -0,0 +1,65 @@
+package gservice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// maxParallelGets bounds the messages.Get calls in flight for one batch, well below the per-user
+// concurrency Gmail allows.
+const maxParallelGets = 10
+
+// GetMessagesMetadata retrieves the metadata of several messages, like GetMessageMetadata, with
+// calls in parallel. Messages are returned in the order of msgIDs.
+func (m *GMail) GetMessagesMetadata(ctx context.Context, msgIDs []string) ([]*gmail.Message, error) {
+	return getParallel(ctx, msgIDs, m.GetMessageMetadata)
+}
+
+// GetMessages retrieves several complete messages, like GetMessage, with calls in parallel.
+// Messages are returned in the order of msgIDs.
+func (m *GMail) GetMessages(ctx context.Context, msgIDs []string) ([]*gmail.Message, error) {
+	return getParallel(ctx, msgIDs, m.GetMessage)
+}
+
+// getParallel runs get for every ID with at most maxParallelGets calls at once. The first
+// failure cancels the calls not started yet and is returned.
+func getParallel(
+	ctx context.Context,
+	msgIDs []string,
+	get func(ctx context.Context, msgID string) (*gmail.Message, error),
+) ([]*gmail.Message, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	messages := make([]*gmail.Message, len(msgIDs))
+	sem := make(chan struct{}, maxParallelGets)
+	var wg sync.WaitGroup
+	for i, msgID := range msgIDs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+			msg, err := get(ctx, msgID)
+			if err != nil {
+				cancel(fmt.Errorf("get message %s failed: %w", msgID, err))
+				return
+			}
+			messages[i] = msg
+		})
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
@@ -0,0 +1,79 @@
+package gservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Calendar calls share the token, HTTP client and retries of the Gmail calls but not the quota
+// limiter: Calendar quota is accounted separately. They need the calendar.events scope, which
+// the server only requests with -calendar.
+
+// ListEvents lists the events of a calendar overlapping [timeMin, timeMax) ordered by start,
+// with recurring events expanded into their instances. A non-empty Q filters by free text.
+func (m *GMail) ListEvents(ctx context.Context, calendarID string, timeMin, timeMax time.Time, Q string, maxResults int64) (*calendar.Events, error) {
+	svc, err := m.newCalendarSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newCalendarSvc failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Events.List(calendarID).
+		TimeMin(timeMin.Format(time.RFC3339)).
+		TimeMax(timeMax.Format(time.RFC3339)).
+		Q(Q).
+		SingleEvents(true).
+		OrderBy("startTime").
+		MaxResults(maxResults).
+		Context(ctx).
+		Do()
+	m.observe("calendar.events.List", 0, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("events.List failed: %w", apiError(err))
+	}
+
+	return result, nil
+}
+
+// CreateEvent adds an event to a calendar without notifying its attendees.
+func (m *GMail) CreateEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error) {
+	svc, err := m.newCalendarSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newCalendarSvc failed: %w", err)
+	}
+
+	start := time.Now()
+	created, err := svc.Events.Insert(calendarID, event).SendUpdates("none").Context(ctx).Do()
+	m.observe("calendar.events.Insert", 0, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("events.Insert failed: %w", apiError(err))
+	}
+
+	return created, nil
+}
+
+// ImportEvent adds a private copy of an event received as an iCalendar invitation, identified
+// by its ICalUID; importing the same UID again updates the copy instead of duplicating it.
+func (m *GMail) ImportEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error) {
+	svc, err := m.newCalendarSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newCalendarSvc failed: %w", err)
+	}
+
+	start := time.Now()
+	imported, err := svc.Events.Import(calendarID, event).Context(ctx).Do()
+	m.observe("calendar.events.Import", 0, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("events.Import failed: %w", apiError(err))
+	}
+
+	return imported, nil
+}
+
+// newCalendarSvc returns the Calendar service of the current token.
+func (m *GMail) newCalendarSvc(ctx context.Context) (*calendar.Service, error) {
+	return sharedSvc(ctx, m, &m.calSvc, &m.calToken, calendar.NewService)
+}
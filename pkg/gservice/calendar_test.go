@@ -0,0 +1,61 @@
+package gservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestCalendarEvents(t *testing.T) {
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/calendars/primary/events":
+			query := r.URL.Query()
+			assert.Equal(t, "2025-03-01T00:00:00Z", query.Get("timeMin"))
+			assert.Equal(t, "2025-03-08T00:00:00Z", query.Get("timeMax"))
+			assert.Equal(t, "true", query.Get("singleEvents"))
+			assert.Equal(t, "startTime", query.Get("orderBy"))
+			writeJSON(t, w, &calendar.Events{Items: []*calendar.Event{{Id: "e1", Summary: "Standup"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/calendars/primary/events":
+			assert.Equal(t, "none", r.URL.Query().Get("sendUpdates"))
+			var event calendar.Event
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+			event.Id = "e2"
+			writeJSON(t, w, &event)
+		case r.Method == http.MethodPost && r.URL.Path == "/calendars/primary/events/import":
+			var event calendar.Event
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+			assert.Equal(t, "uid-1@example.com", event.ICalUID)
+			event.Id = "e3"
+			writeJSON(t, w, &event)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events, err := m.ListEvents(ctx, "primary", start, start.AddDate(0, 0, 7), "", 10)
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	assert.Equal(t, "Standup", events.Items[0].Summary)
+
+	created, err := m.CreateEvent(ctx, "primary", &calendar.Event{
+		Summary: "Flight LH 400",
+		Start:   &calendar.EventDateTime{DateTime: "2025-03-02T10:00:00+01:00"},
+		End:     &calendar.EventDateTime{DateTime: "2025-03-02T18:00:00-05:00"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "e2", created.Id)
+
+	imported, err := m.ImportEvent(ctx, "primary", &calendar.Event{ICalUID: "uid-1@example.com", Summary: "Review"})
+	require.NoError(t, err)
+	assert.Equal(t, "e3", imported.Id)
+}
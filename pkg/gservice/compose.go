@@ -0,0 +1,243 @@
+package gservice
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// base64LineLength is the line length RFC 2045 allows for base64 bodies.
+const base64LineLength = 76
+
+// Email is a message to assemble into RFC 2822 form for drafts and sending.
+type Email struct {
+	From    string
+	To      []string
+	Cc      []string
+	Bcc     []string
+	Subject string
+	// TextBody and HTMLBody are the plain text and HTML versions of the body; with both, the
+	// message is multipart/alternative.
+	TextBody string
+	HTMLBody string
+	// InReplyTo and References thread a reply, holding Message-IDs with angle brackets.
+	InReplyTo   string
+	References  string
+	Attachments []EmailAttachment
+	// Date defaults to now.
+	Date time.Time
+}
+
+// EmailAttachment is a file attached to an Email.
+type EmailAttachment struct {
+	Filename string
+	MIMEType string
+	Data     []byte
+}
+
+// Bytes assembles the message with encoded headers, quoted-printable text parts and base64
+// attachments.
+func (e Email) Bytes() ([]byte, error) {
+	if len(e.To)+len(e.Cc)+len(e.Bcc) == 0 {
+		return nil, errors.New("at least one recipient is required")
+	}
+
+	var buf bytes.Buffer
+	if err := e.writeHeaders(&buf); err != nil {
+		return nil, err
+	}
+
+	header, body, err := e.body()
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	for _, key := range []string{"Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+func (e Email) writeHeaders(w io.Writer) error {
+	date := e.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	headers := [][2]string{{"Date", date.Format(time.RFC1123Z)}}
+
+	if e.From != "" {
+		from, err := formatAddresses([]string{e.From})
+		if err != nil {
+			return fmt.Errorf("invalid From: %w", err)
+		}
+		headers = append(headers, [2]string{"From", from})
+	}
+	for _, field := range []struct {
+		name      string
+		addresses []string
+	}{{"To", e.To}, {"Cc", e.Cc}, {"Bcc", e.Bcc}} {
+		if len(field.addresses) == 0 {
+			continue
+		}
+		value, err := formatAddresses(field.addresses)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", field.name, err)
+		}
+		headers = append(headers, [2]string{field.name, value})
+	}
+
+	headers = append(headers, [2]string{"Subject", mime.QEncoding.Encode("utf-8", e.Subject)})
+	if e.InReplyTo != "" {
+		headers = append(headers, [2]string{"In-Reply-To", e.InReplyTo})
+	}
+	if e.References != "" {
+		headers = append(headers, [2]string{"References", e.References})
+	}
+
+	for _, h := range headers {
+		if strings.ContainsAny(h[1], "\r\n") {
+			return fmt.Errorf("header %s contains a line break", h[0])
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s\r\n", h[0], h[1]); err != nil {
+			return fmt.Errorf("write header failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// formatAddresses parses addresses like "Name <user@example.com>" and formats them with
+// encoded names.
+func formatAddresses(addresses []string) (string, error) {
+	formatted := make([]string, 0, len(addresses))
+	for _, a := range addresses {
+		list, err := mail.ParseAddressList(a)
+		if err != nil {
+			return "", fmt.Errorf("mail.ParseAddressList failed for %q: %w", a, err)
+		}
+		for _, addr := range list {
+			formatted = append(formatted, addr.String())
+		}
+	}
+	return strings.Join(formatted, ", "), nil
+}
+
+// body returns the content headers and the encoded body: the text, wrapped in multipart/mixed
+// with attachments.
+func (e Email) body() (textproto.MIMEHeader, []byte, error) {
+	header, text, err := e.textBody()
+	if err != nil || len(e.Attachments) == 0 {
+		return header, text, err
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := writePart(mw, header, text); err != nil {
+		return nil, nil, err
+	}
+	for _, a := range e.Attachments {
+		if err := writeAttachment(mw, a); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, fmt.Errorf("mw.Close failed: %w", err)
+	}
+	return multipartHeader("multipart/mixed", mw), buf.Bytes(), nil
+}
+
+// textBody returns the text or HTML version of the body, or both as multipart/alternative.
+func (e Email) textBody() (textproto.MIMEHeader, []byte, error) {
+	if e.HTMLBody == "" || e.TextBody == "" {
+		if e.HTMLBody != "" {
+			return textPart("html", e.HTMLBody)
+		}
+		return textPart("plain", e.TextBody)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, alt := range []struct{ subtype, text string }{{"plain", e.TextBody}, {"html", e.HTMLBody}} {
+		header, text, err := textPart(alt.subtype, alt.text)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := writePart(mw, header, text); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, fmt.Errorf("mw.Close failed: %w", err)
+	}
+	return multipartHeader("multipart/alternative", mw), buf.Bytes(), nil
+}
+
+func textPart(subtype, text string) (textproto.MIMEHeader, []byte, error) {
+	var buf bytes.Buffer
+	qw := quotedprintable.NewWriter(&buf)
+	if _, err := io.WriteString(qw, text); err != nil {
+		return nil, nil, fmt.Errorf("qw.Write failed: %w", err)
+	}
+	if err := qw.Close(); err != nil {
+		return nil, nil, fmt.Errorf("qw.Close failed: %w", err)
+	}
+	return textproto.MIMEHeader{
+		"Content-Type":              {"text/" + subtype + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}, buf.Bytes(), nil
+}
+
+func multipartHeader(mediaType string, mw *multipart.Writer) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType(mediaType, map[string]string{"boundary": mw.Boundary()})},
+	}
+}
+
+func writePart(mw *multipart.Writer, header textproto.MIMEHeader, body []byte) error {
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("mw.CreatePart failed: %w", err)
+	}
+	if _, err := part.Write(body); err != nil {
+		return fmt.Errorf("part.Write failed: %w", err)
+	}
+	return nil
+}
+
+func writeAttachment(mw *multipart.Writer, a EmailAttachment) error {
+	mimeType := a.MIMEType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	filename := mime.QEncoding.Encode("utf-8", a.Filename)
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mimeType, map[string]string{"name": filename})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return fmt.Errorf("mw.CreatePart failed: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	for len(encoded) > 0 {
+		n := min(base64LineLength, len(encoded))
+		if _, err := io.WriteString(part, encoded[:n]+"\r\n"); err != nil {
+			return fmt.Errorf("part.Write failed: %w", err)
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
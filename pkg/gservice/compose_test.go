@@ -0,0 +1,113 @@
+package gservice
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailBytes(t *testing.T) {
+	raw, err := Email{
+		From:       "Ada <ada@example.com>",
+		To:         []string{"Bob <bob@example.com>, carol@example.com"},
+		Cc:         []string{"Dörte <doerte@example.com>"},
+		Subject:    "Grüße",
+		TextBody:   "Hello",
+		HTMLBody:   "<p>Hello</p>",
+		InReplyTo:  "<id-1@mail.example.com>",
+		References: "<id-0@mail.example.com> <id-1@mail.example.com>",
+		Date:       time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}.Bytes()
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Grüße", subject)
+	to, err := msg.Header.AddressList("To")
+	require.NoError(t, err)
+	assert.Equal(t, []*mail.Address{{Name: "Bob", Address: "bob@example.com"}, {Address: "carol@example.com"}}, to)
+	cc, err := msg.Header.AddressList("Cc")
+	require.NoError(t, err)
+	assert.Equal(t, "Dörte", cc[0].Name)
+	assert.Equal(t, "<id-1@mail.example.com>", msg.Header.Get("In-Reply-To"))
+	assert.Equal(t, "Thu, 02 Jan 2025 03:04:05 +0000", msg.Header.Get("Date"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	parts := readParts(t, msg.Body, params["boundary"])
+	assert.Equal(t, []string{"Hello", "<p>Hello</p>"}, parts)
+}
+
+func TestEmailBytesAttachments(t *testing.T) {
+	data := bytes.Repeat([]byte{0, 1, 2, 3}, 100)
+	raw, err := Email{
+		To:          []string{"bob@example.com"},
+		Subject:     "Report",
+		TextBody:    strings.Repeat("long line ", 20),
+		Attachments: []EmailAttachment{{Filename: "report.bin", Data: data}},
+	}.Bytes()
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	text, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", text.Header.Get("Content-Type"))
+	body, err := io.ReadAll(text)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("long line ", 20), string(body))
+
+	attachment, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "report.bin", attachment.FileName())
+	assert.Equal(t, "application/octet-stream; name=report.bin", attachment.Header.Get("Content-Type"))
+	encoded, err := io.ReadAll(attachment)
+	require.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		assert.LessOrEqual(t, len(line), base64LineLength)
+	}
+}
+
+func TestEmailBytesInvalid(t *testing.T) {
+	_, err := Email{Subject: "No recipients"}.Bytes()
+	require.Error(t, err)
+
+	_, err = Email{To: []string{"not an address"}}.Bytes()
+	require.Error(t, err)
+
+	_, err = Email{To: []string{"bob@example.com"}, InReplyTo: "<a>\r\nBcc: eve@example.com"}.Bytes()
+	require.Error(t, err, "header injection")
+}
+
+func readParts(t *testing.T, body io.Reader, boundary string) []string {
+	t.Helper()
+	var parts []string
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		require.NoError(t, err)
+		// The reader decodes quoted-printable parts.
+		text, err := io.ReadAll(part)
+		require.NoError(t, err)
+		parts = append(parts, string(text))
+	}
+}
@@ -0,0 +1,155 @@
+package gservice
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// ListDrafts lists drafts matching the query, carrying their ID and message and thread IDs.
+func (m *GMail) ListDrafts(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListDraftsResponse, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaDraftsList); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.Drafts.List(m.userID).
+		Q(Q).
+		PageToken(pageToken).
+		MaxResults(maxResults).
+		Fields(draftListFields).
+		Context(ctx).
+		Do()
+	m.observe("gmail.drafts.List", quotaDraftsList, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("drafts.List failed: %w", apiError(err))
+	}
+
+	return result, nil
+}
+
+// GetDraft retrieves a draft with its message in full format.
+func (m *GMail) GetDraft(ctx context.Context, draftID string) (*gmail.Draft, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaDraftsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	draft, err := svc.Users.Drafts.Get(m.userID, draftID).Format("full").Context(ctx).Do()
+	m.observe("gmail.drafts.Get", quotaDraftsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("drafts.Get failed: %w", apiError(err))
+	}
+
+	return draft, nil
+}
+
+// CreateDraft saves the RFC 2822 message raw, as assembled by Email.Bytes, as a draft. A
+// non-empty threadID files the draft as a reply in that thread.
+func (m *GMail) CreateDraft(ctx context.Context, raw []byte, threadID string) (*gmail.Draft, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaDraftsCreate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	draft, err := svc.Users.Drafts.Create(m.userID, newDraft("", raw, threadID)).Context(ctx).Do()
+	m.observe("gmail.drafts.Create", quotaDraftsCreate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("drafts.Create failed: %w", apiError(err))
+	}
+
+	return draft, nil
+}
+
+// UpdateDraft replaces the message of a draft with raw; the draft keeps its ID but its message
+// gets a new one.
+func (m *GMail) UpdateDraft(ctx context.Context, draftID string, raw []byte, threadID string) (*gmail.Draft, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaDraftsUpdate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	draft, err := svc.Users.Drafts.Update(m.userID, draftID, newDraft(draftID, raw, threadID)).Context(ctx).Do()
+	m.observe("gmail.drafts.Update", quotaDraftsUpdate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("drafts.Update failed: %w", apiError(err))
+	}
+
+	return draft, nil
+}
+
+// SendDraft sends a draft to its recipients, which deletes the draft, and returns the sent
+// message.
+func (m *GMail) SendDraft(ctx context.Context, draftID string) (*gmail.Message, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaDraftsSend); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	msg, err := svc.Users.Drafts.Send(m.userID, &gmail.Draft{Id: draftID}).Context(ctx).Do()
+	m.observe("gmail.drafts.Send", quotaDraftsSend, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("drafts.Send failed: %w", apiError(err))
+	}
+
+	return msg, nil
+}
+
+// DeleteDraft permanently deletes a draft without sending it.
+func (m *GMail) DeleteDraft(ctx context.Context, draftID string) error {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaDraftsDelete); err != nil {
+		return fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	err = svc.Users.Drafts.Delete(m.userID, draftID).Context(ctx).Do()
+	m.observe("gmail.drafts.Delete", quotaDraftsDelete, start, err)
+	if err != nil {
+		return fmt.Errorf("drafts.Delete failed: %w", apiError(err))
+	}
+
+	return nil
+}
+
+func newDraft(draftID string, raw []byte, threadID string) *gmail.Draft {
+	return &gmail.Draft{
+		Id: draftID,
+		Message: &gmail.Message{
+			Raw:      base64.URLEncoding.EncodeToString(raw),
+			ThreadId: threadID,
+		},
+	}
+}
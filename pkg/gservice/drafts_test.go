@@ -0,0 +1,76 @@
+package gservice
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestDrafts(t *testing.T) {
+	var sent, deleted string
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/drafts":
+			assert.Equal(t, "to:bob", r.URL.Query().Get("q"))
+			writeJSON(t, w, &gmail.ListDraftsResponse{Drafts: []*gmail.Draft{{Id: "d1"}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/drafts/d1":
+			assert.Equal(t, "full", r.URL.Query().Get("format"))
+			writeJSON(t, w, &gmail.Draft{Id: "d1", Message: &gmail.Message{Id: "m1"}})
+		case r.Method == http.MethodPost && r.URL.Path == "/gmail/v1/users/me/drafts":
+			var draft gmail.Draft
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&draft))
+			raw, err := base64.URLEncoding.DecodeString(draft.Message.Raw)
+			assert.NoError(t, err)
+			assert.Equal(t, "raw message", string(raw))
+			assert.Equal(t, "t1", draft.Message.ThreadId)
+			writeJSON(t, w, &gmail.Draft{Id: "d2", Message: &gmail.Message{Id: "m2", ThreadId: "t1"}})
+		case r.Method == http.MethodPut && r.URL.Path == "/gmail/v1/users/me/drafts/d2":
+			var draft gmail.Draft
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&draft))
+			assert.Equal(t, "d2", draft.Id)
+			writeJSON(t, w, &gmail.Draft{Id: "d2", Message: &gmail.Message{Id: "m3"}})
+		case r.Method == http.MethodPost && r.URL.Path == "/gmail/v1/users/me/drafts/send":
+			var draft gmail.Draft
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&draft))
+			sent = draft.Id
+			writeJSON(t, w, &gmail.Message{Id: "m3", LabelIds: []string{"SENT"}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/gmail/v1/users/me/drafts/d1":
+			deleted = "d1"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	list, err := m.ListDrafts(ctx, "to:bob", "", 10)
+	require.NoError(t, err)
+	assert.Len(t, list.Drafts, 1)
+
+	draft, err := m.GetDraft(ctx, "d1")
+	require.NoError(t, err)
+	assert.Equal(t, "m1", draft.Message.Id)
+
+	created, err := m.CreateDraft(ctx, []byte("raw message"), "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "d2", created.Id)
+
+	updated, err := m.UpdateDraft(ctx, "d2", []byte("new raw message"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "m3", updated.Message.Id)
+
+	msg, err := m.SendDraft(ctx, "d2")
+	require.NoError(t, err)
+	assert.Equal(t, "d2", sent)
+	assert.Equal(t, []string{"SENT"}, msg.LabelIds)
+
+	require.NoError(t, m.DeleteDraft(ctx, "d1"))
+	assert.Equal(t, "d1", deleted)
+}
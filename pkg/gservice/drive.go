@@ -0,0 +1,98 @@
+package gservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// Drive calls share the token, HTTP client and retries of the Gmail calls; Drive quota is
+// accounted separately. They need the drive.readonly scope, which the server only requests
+// with -drive.
+
+const driveFileFields googleapi.Field = "id,name,mimeType,size,webViewLink"
+
+// ErrTooLarge is returned for Drive downloads and exports exceeding the size limit.
+var ErrTooLarge = errors.New("file too large")
+
+// GetDriveFile retrieves the name, MIME type and size of a Drive file, including files of
+// shared drives.
+func (m *GMail) GetDriveFile(ctx context.Context, fileID string) (*drive.File, error) {
+	svc, err := m.newDriveSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newDriveSvc failed: %w", err)
+	}
+
+	start := time.Now()
+	file, err := svc.Files.Get(fileID).SupportsAllDrives(true).Fields(driveFileFields).Context(ctx).Do()
+	m.observe("drive.files.Get", 0, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("files.Get failed: %w", apiError(err))
+	}
+
+	return file, nil
+}
+
+// ExportDriveFile exports a Google Docs, Sheets or Slides file to mimeType, reading at most
+// maxBytes; zero disables the limit.
+func (m *GMail) ExportDriveFile(ctx context.Context, fileID, mimeType string, maxBytes int64) ([]byte, error) {
+	svc, err := m.newDriveSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newDriveSvc failed: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := svc.Files.Export(fileID, mimeType).Context(ctx).Download()
+	m.observe("drive.files.Export", 0, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("files.Export failed: %w", apiError(err))
+	}
+
+	return readLimited(resp, maxBytes)
+}
+
+// DownloadDriveFile downloads the content of a binary Drive file like a PDF, reading at most
+// maxBytes; zero disables the limit.
+func (m *GMail) DownloadDriveFile(ctx context.Context, fileID string, maxBytes int64) ([]byte, error) {
+	svc, err := m.newDriveSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newDriveSvc failed: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := svc.Files.Get(fileID).SupportsAllDrives(true).Context(ctx).Download()
+	m.observe("drive.files.Get", 0, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("files.Get failed: %w", apiError(err))
+	}
+
+	return readLimited(resp, maxBytes)
+}
+
+// newDriveSvc returns the Drive service of the current token.
+func (m *GMail) newDriveSvc(ctx context.Context) (*drive.Service, error) {
+	return sharedSvc(ctx, m, &m.driveSvc, &m.driveTok, drive.NewService)
+}
+
+func readLimited(resp *http.Response, maxBytes int64) ([]byte, error) {
+	defer func() { _ = resp.Body.Close() }()
+
+	body := io.Reader(resp.Body)
+	if maxBytes > 0 {
+		body = io.LimitReader(resp.Body, maxBytes+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll failed: %w", err)
+	}
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("%w: above the %d byte limit", ErrTooLarge, maxBytes)
+	}
+	return data, nil
+}
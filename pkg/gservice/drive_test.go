@@ -0,0 +1,54 @@
+package gservice
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/drive/v3"
+)
+
+func TestDrive(t *testing.T) {
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/files/doc-1" && query.Get("alt") == "json":
+			assert.Equal(t, "true", query.Get("supportsAllDrives"))
+			writeJSON(t, w, &drive.File{Id: "doc-1", Name: "Plan", MimeType: "application/vnd.google-apps.document"})
+		case r.URL.Path == "/files/doc-1/export":
+			assert.Equal(t, "text/html", query.Get("mimeType"))
+			_, _ = w.Write([]byte("<h1>Plan</h1>"))
+		case r.URL.Path == "/files/pdf-1" && query.Get("alt") == "media":
+			_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+		case r.URL.Path == "/files/missing":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"File not found: missing."}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	file, err := m.GetDriveFile(ctx, "doc-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Plan", file.Name)
+
+	exported, err := m.ExportDriveFile(ctx, "doc-1", "text/html", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>Plan</h1>", string(exported))
+
+	data, err := m.DownloadDriveFile(ctx, "pdf-1", 100)
+	require.NoError(t, err)
+	assert.Len(t, data, 100)
+
+	_, err = m.DownloadDriveFile(ctx, "pdf-1", 99)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = m.GetDriveFile(ctx, "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
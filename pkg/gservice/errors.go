@@ -0,0 +1,69 @@
+package gservice
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+)
+
+// Kinds of API failures callers can react to, matched with errors.Is. Errors of GMail methods
+// wrap one of them when the failure is recognized.
+var (
+	// ErrNotFound means the message, thread, attachment or label doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrQuotaExceeded means Gmail throttled the call; it may succeed later.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrAuthExpired means there is no usable token and the user has to authorize again.
+	ErrAuthExpired = errors.New("authorization expired")
+	// ErrPermission means the token lacks the scope or the account the access the call needs.
+	ErrPermission = errors.New("permission denied")
+)
+
+// rateLimitReasons are the 403 reasons Gmail reports throttling with.
+var rateLimitReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"quotaExceeded":         true,
+	"dailyLimitExceeded":    true,
+}
+
+// apiError wraps err with the kind of failure it is, if recognized.
+func apiError(err error) error {
+	if kind := errorKind(err); kind != nil {
+		return fmt.Errorf("%w: %w", kind, err)
+	}
+	return err
+}
+
+func errorKind(err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.Is(err, auth.ErrTokenNotSet) || errors.As(err, &retrieveErr) {
+		return ErrAuthExpired
+	}
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	switch apiErr.Code {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrQuotaExceeded
+	case http.StatusUnauthorized:
+		return ErrAuthExpired
+	case http.StatusForbidden:
+		for _, item := range apiErr.Errors {
+			if rateLimitReasons[item.Reason] {
+				return ErrQuotaExceeded
+			}
+		}
+		return ErrPermission
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package gservice
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "not found", err: &googleapi.Error{Code: http.StatusNotFound}, want: ErrNotFound},
+		{name: "too many requests", err: &googleapi.Error{Code: http.StatusTooManyRequests}, want: ErrQuotaExceeded},
+		{
+			name: "rate limited 403",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}},
+			want: ErrQuotaExceeded,
+		},
+		{
+			name: "forbidden",
+			err:  &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "insufficientPermissions"}}},
+			want: ErrPermission,
+		},
+		{name: "unauthorized", err: &googleapi.Error{Code: http.StatusUnauthorized}, want: ErrAuthExpired},
+		{name: "refresh failed", err: fmt.Errorf("Get: %w", &oauth2.RetrieveError{}), want: ErrAuthExpired},
+		{name: "no token", err: auth.ErrTokenNotSet, want: ErrAuthExpired},
+		{name: "bad request", err: &googleapi.Error{Code: http.StatusBadRequest}},
+		{name: "network", err: errors.New("connection reset")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := apiError(tt.err)
+			assert.ErrorIs(t, err, tt.err)
+			for _, kind := range []error{ErrNotFound, ErrQuotaExceeded, ErrAuthExpired, ErrPermission} {
+				assert.Equal(t, kind == tt.want, errors.Is(err, kind), "errors.Is(err, %v)", kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,484 @@
+// Package gservice provides a Gmail API facade for simplified email operations.
+package gservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	"github.com/hal9000y/gmail-mcp/internal/lru"
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+	"github.com/hal9000y/gmail-mcp/pkg/diskcache"
+)
+
+// defaultUserID is the Gmail user ID of the authorized account.
+const defaultUserID = "me"
+
+// metadataHeaders are the headers fetched for message summaries.
+var metadataHeaders = []string{
+	"From", "To", "Cc", "Bcc", "Reply-To", "Subject", "Date",
+	"Message-ID", "In-Reply-To", "References",
+}
+
+// Partial responses requesting only the fields tools read, which keeps list and metadata
+// responses of large mailboxes small.
+const (
+	listFields           googleapi.Field = "messages(id,threadId),nextPageToken,resultSizeEstimate"
+	historyFields        googleapi.Field = "history(id,messagesAdded/message(id,threadId,labelIds)),historyId,nextPageToken"
+	metadataFields       googleapi.Field = "id,threadId,labelIds,snippet,historyId,internalDate,sizeEstimate,payload(mimeType,headers)"
+	threadMetadataFields googleapi.Field = "id,historyId,messages(" + metadataFields + ")"
+	threadListFields     googleapi.Field = "threads(id,snippet,historyId),nextPageToken,resultSizeEstimate"
+	modifyThreadFields   googleapi.Field = "id,historyId,messages(id,labelIds)"
+	attachmentFields     googleapi.Field = "attachmentId,data,size"
+	draftListFields      googleapi.Field = "drafts(id,message(id,threadId)),nextPageToken,resultSizeEstimate"
+
+	mailboxHistoryFields googleapi.Field = "history(id,messagesAdded/message(id,threadId,labelIds),messagesDeleted/message(id)," +
+		"labelsAdded/message(id,labelIds),labelsRemoved/message(id,labelIds)),historyId,nextPageToken"
+)
+
+// Option configures optional GMail features.
+type Option func(*GMail)
+
+// WithDiskCache keeps messages and message metadata read from the API in cache for ttl. Bodies
+// never change, but labels like UNREAD may be up to ttl old, except for threads changed through
+// this facade.
+func WithDiskCache(cache *diskcache.Cache, ttl time.Duration) Option {
+	return func(m *GMail) {
+		m.cache = cache
+		m.cacheTTL = ttl
+	}
+}
+
+// WithMessageCache keeps up to entries messages and message metadata in memory for ttl, so a
+// search, get and preview of the same message fetch it once. Cached messages are shared between
+// callers, which must not modify them.
+func WithMessageCache(entries int, ttl time.Duration) Option {
+	return func(m *GMail) {
+		m.messages = lru.New[messageKey, cachedMessage](entries)
+		m.messageTTL = ttl
+	}
+}
+
+// WithMailbox reads and changes the mailbox of address instead of the authorized account's,
+// which must have been granted access to it through Gmail delegation. Empty keeps the
+// authorized account's mailbox.
+func WithMailbox(address string) Option {
+	return func(m *GMail) {
+		if address != "" {
+			m.userID = address
+		}
+	}
+}
+
+// NewGmail creates a new Gmail service facade.
+func NewGmail(cfg *oauth2.Config, tok *auth.Token, opts ...Option) *GMail {
+	m := &GMail{
+		cfg:      cfg,
+		tok:      tok,
+		userID:   defaultUserID,
+		messages: lru.New[messageKey, cachedMessage](0),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// GMail provides simplified access to Gmail API operations.
+type GMail struct {
+	cfg        *oauth2.Config
+	tok        *auth.Token
+	userID     string
+	cache      *diskcache.Cache
+	cacheTTL   time.Duration
+	messages   *lru.Cache[messageKey, cachedMessage]
+	messageTTL time.Duration
+	hits       atomic.Int64
+	misses     atomic.Int64
+	retries    int
+	retryDelay time.Duration
+	quota      *quotaLimiter
+	metrics    Metrics
+	// endpoint overrides the base URL of the Gmail, Calendar and Drive APIs, for tests.
+	endpoint string
+
+	mu       sync.Mutex
+	svc      *gmail.Service
+	svcToken *oauth2.Token
+	client   *http.Client
+	calSvc   *calendar.Service
+	calToken *oauth2.Token
+	driveSvc *drive.Service
+	driveTok *oauth2.Token
+}
+
+// ListMessages searches for messages matching the query.
+func (m *GMail) ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaMessagesList); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	call := svc.Users.Messages.List(m.userID).
+		Q(Q).
+		PageToken(pageToken).
+		MaxResults(maxResults).
+		Fields(listFields)
+
+	start := time.Now()
+	result, err := call.Context(ctx).Do()
+	m.observe("gmail.messages.List", quotaMessagesList, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("messages.List failed: %w", apiError(err))
+	}
+
+	return result, nil
+}
+
+// ListLabelMessages lists messages carrying the given label ID.
+func (m *GMail) ListLabelMessages(ctx context.Context, labelID, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaMessagesList); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	call := svc.Users.Messages.List(m.userID).
+		LabelIds(labelID).
+		PageToken(pageToken).
+		MaxResults(maxResults).
+		Fields(listFields)
+
+	start := time.Now()
+	result, err := call.Context(ctx).Do()
+	m.observe("gmail.messages.List", quotaMessagesList, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("messages.List failed: %w", apiError(err))
+	}
+
+	return result, nil
+}
+
+// ListHistory lists mailbox changes after startHistoryID, limited to added messages carrying labelID.
+func (m *GMail) ListHistory(ctx context.Context, startHistoryID uint64, labelID, pageToken string) (*gmail.ListHistoryResponse, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaHistoryList); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	call := svc.Users.History.List(m.userID).
+		StartHistoryId(startHistoryID).
+		HistoryTypes("messageAdded").
+		PageToken(pageToken).
+		Fields(historyFields)
+	if labelID != "" {
+		call = call.LabelId(labelID)
+	}
+
+	start := time.Now()
+	result, err := call.Context(ctx).Do()
+	m.observe("gmail.history.List", quotaHistoryList, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("history.List failed: %w", apiError(err))
+	}
+
+	return result, nil
+}
+
+// ListMailboxHistory lists every mailbox change after startHistoryID: added and deleted
+// messages and label changes.
+func (m *GMail) ListMailboxHistory(ctx context.Context, startHistoryID uint64, pageToken string) (*gmail.ListHistoryResponse, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaHistoryList); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.History.List(m.userID).
+		StartHistoryId(startHistoryID).
+		HistoryTypes("messageAdded", "messageDeleted", "labelAdded", "labelRemoved").
+		PageToken(pageToken).
+		MaxResults(500).
+		Fields(mailboxHistoryFields).
+		Context(ctx).
+		Do()
+	m.observe("gmail.history.List", quotaHistoryList, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("history.List failed: %w", apiError(err))
+	}
+	// Cached messages carry the labels they had when fetched.
+	for _, record := range result.History {
+		for _, changed := range record.LabelsAdded {
+			if changed.Message != nil {
+				m.forgetMessage(changed.Message.Id)
+			}
+		}
+		for _, changed := range record.LabelsRemoved {
+			if changed.Message != nil {
+				m.forgetMessage(changed.Message.Id)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Watch asks Gmail to publish changes of messages carrying any of labelIDs to the Pub/Sub
+// topicName. The watch expires after about a week unless renewed by calling Watch again.
+func (m *GMail) Watch(ctx context.Context, topicName string, labelIDs []string) (*gmail.WatchResponse, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaWatch); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.Watch(m.userID, &gmail.WatchRequest{
+		TopicName:           topicName,
+		LabelIds:            labelIDs,
+		LabelFilterBehavior: "include",
+	}).Context(ctx).Do()
+	m.observe("gmail.users.Watch", quotaWatch, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("users.Watch failed: %w", apiError(err))
+	}
+
+	return result, nil
+}
+
+// StopWatch stops push notifications of the mailbox.
+func (m *GMail) StopWatch(ctx context.Context) error {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaStop); err != nil {
+		return fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	err = svc.Users.Stop(m.userID).Context(ctx).Do()
+	m.observe("gmail.users.Stop", quotaStop, start, err)
+	if err != nil {
+		return fmt.Errorf("users.Stop failed: %w", apiError(err))
+	}
+
+	return nil
+}
+
+// GetProfile retrieves the mailbox profile including its current history ID.
+func (m *GMail) GetProfile(ctx context.Context) (*gmail.Profile, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaGetProfile); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	profile, err := svc.Users.GetProfile(m.userID).Context(ctx).Do()
+	m.observe("gmail.users.GetProfile", quotaGetProfile, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("users.GetProfile failed: %w", apiError(err))
+	}
+
+	return profile, nil
+}
+
+// GetMessageMetadata retrieves message headers (addressing, subject, date and threading identifiers) along with label IDs.
+func (m *GMail) GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error) {
+	if cached, ok := m.cachedMessage(cacheBucketMetadata, msgID); ok {
+		return cached, nil
+	}
+
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaMessagesGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	msg, err := svc.Users.Messages.Get(m.userID, msgID).
+		Format("METADATA").
+		MetadataHeaders(metadataHeaders...).
+		Fields(metadataFields).
+		Context(ctx).
+		Do()
+	m.observe("gmail.messages.Get", quotaMessagesGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("messages.Get failed: %w", apiError(err))
+	}
+	m.cacheMessage(cacheBucketMetadata, msgID, msg)
+
+	return msg, nil
+}
+
+// GetMessage retrieves a complete message including body and attachments.
+func (m *GMail) GetMessage(ctx context.Context, msgID string) (*gmail.Message, error) {
+	if cached, ok := m.cachedMessage(cacheBucketMessages, msgID); ok {
+		return cached, nil
+	}
+
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaMessagesGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	msg, err := svc.Users.Messages.Get(m.userID, msgID).Context(ctx).Do()
+	m.observe("gmail.messages.Get", quotaMessagesGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("messages.Get failed: %w", apiError(err))
+	}
+	m.cacheMessage(cacheBucketMessages, msgID, msg)
+
+	return msg, nil
+}
+
+// GetMessageRaw retrieves a message in RAW format with the full RFC 2822 source.
+func (m *GMail) GetMessageRaw(ctx context.Context, msgID string) (*gmail.Message, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaMessagesGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	msg, err := svc.Users.Messages.Get(m.userID, msgID).Format("RAW").Context(ctx).Do()
+	m.observe("gmail.messages.Get", quotaMessagesGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("messages.Get failed: %w", apiError(err))
+	}
+
+	return msg, nil
+}
+
+// GetAttachment retrieves attachment content by message and attachment IDs.
+func (m *GMail) GetAttachment(ctx context.Context, msgID, attachmentID string) (*gmail.MessagePartBody, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaAttachmentsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	attachment, err := svc.Users.Messages.Attachments.Get(m.userID, msgID, attachmentID).Fields(attachmentFields).Context(ctx).Do()
+	m.observe("gmail.attachments.Get", quotaAttachmentsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("attachments.Get failed: %w", apiError(err))
+	}
+
+	return attachment, nil
+}
+
+// newSvc returns the Gmail service of the current token. The service and its HTTP client, with
+// their connections, are built once per token and reused until the token is replaced.
+func (m *GMail) newSvc(ctx context.Context) (*gmail.Service, error) {
+	t, err := m.tok.OAuthToken()
+	if err != nil {
+		return nil, fmt.Errorf("tok.OAuthToken failed: %w", apiError(err))
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.svc != nil && m.svcToken == t {
+		return m.svc, nil
+	}
+
+	// The client outlives this call and refreshes the token with its context, so it must not
+	// be canceled with the call.
+	clientCtx := context.WithoutCancel(ctx)
+	clt := oauth2.NewClient(clientCtx, m.tok.TokenSource(clientCtx, t))
+	if m.retries > 0 {
+		clt.Transport = &retryTransport{base: clt.Transport, retries: m.retries, baseDelay: m.retryDelay}
+	}
+
+	opts := []option.ClientOption{option.WithHTTPClient(clt)}
+	if m.endpoint != "" {
+		opts = append(opts, option.WithEndpoint(m.endpoint))
+	}
+	svc, err := gmail.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("gmail.NewService failed: %w", err)
+	}
+	m.svc, m.svcToken, m.client = svc, t, clt
+
+	return svc, nil
+}
+
+// sharedSvc returns the service of another Google API cached in svc for the current token,
+// building it with newService on the HTTP client of the Gmail service, so all APIs share the
+// token, connections and retries.
+func sharedSvc[S comparable](
+	ctx context.Context, m *GMail, svc *S, svcToken **oauth2.Token,
+	newService func(context.Context, ...option.ClientOption) (S, error),
+) (S, error) {
+	var zero S
+	if _, err := m.newSvc(ctx); err != nil {
+		return zero, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if *svc != zero && *svcToken == m.svcToken {
+		return *svc, nil
+	}
+
+	opts := []option.ClientOption{option.WithHTTPClient(m.client)}
+	if m.endpoint != "" {
+		opts = append(opts, option.WithEndpoint(m.endpoint))
+	}
+	built, err := newService(ctx, opts...)
+	if err != nil {
+		return zero, fmt.Errorf("newService failed: %w", err)
+	}
+	*svc, *svcToken = built, m.svcToken
+
+	return built, nil
+}
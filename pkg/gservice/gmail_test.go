@@ -0,0 +1,131 @@
+package gservice
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+)
+
+func TestServiceReusedUntilTokenChanges(t *testing.T) {
+	var authorizations []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, map[string]any{"access_token": "second", "token_type": "Bearer", "expires_in": 3600})
+	})
+	mux.HandleFunc("/gmail/v1/users/me/profile", func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		writeJSON(t, w, &gmail.Profile{EmailAddress: "me@example.com"})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	cfg := &oauth2.Config{Endpoint: oauth2.Endpoint{AuthURL: server.URL + "/auth", TokenURL: server.URL + "/token"}}
+	tok, err := auth.NewStoreToken(cfg, staticStore{&oauth2.Token{AccessToken: "first", Expiry: time.Now().Add(time.Hour)}})
+	require.NoError(t, err)
+	m := NewGmail(cfg, tok)
+	m.endpoint = server.URL + "/"
+	ctx := context.Background()
+
+	first, err := m.newSvc(ctx)
+	require.NoError(t, err)
+	again, err := m.newSvc(ctx)
+	require.NoError(t, err)
+	assert.Same(t, first, again, "the service should be reused while the token is unchanged")
+	_, err = m.GetProfile(ctx)
+	require.NoError(t, err)
+
+	redirect, err := tok.RedirectURL()
+	require.NoError(t, err)
+	parsed, err := url.Parse(redirect)
+	require.NoError(t, err)
+	require.NoError(t, tok.AuthorizeCode(ctx, "code", parsed.Query().Get("state")))
+
+	second, err := m.newSvc(ctx)
+	require.NoError(t, err)
+	assert.NotSame(t, first, second, "a new token should get a new service")
+	_, err = m.GetProfile(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Bearer first", "Bearer second"}, authorizations)
+}
+
+// staticStore loads a fixed token and keeps nothing.
+type staticStore struct{ token *oauth2.Token }
+
+func (s staticStore) Load() (*oauth2.Token, error) { return s.token, nil }
+func (staticStore) Save(*oauth2.Token) error       { return nil }
+func (staticStore) Delete() error                  { return nil }
+
+func TestPartialResponses(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		fields googleapi.Field
+		call   func(ctx context.Context, m *GMail) error
+	}{
+		{
+			name: "list messages", path: "/gmail/v1/users/me/messages", fields: listFields,
+			call: func(ctx context.Context, m *GMail) error {
+				_, err := m.ListMessages(ctx, "from:alice", "", 10)
+				return err
+			},
+		},
+		{
+			name: "list label messages", path: "/gmail/v1/users/me/messages", fields: listFields,
+			call: func(ctx context.Context, m *GMail) error {
+				_, err := m.ListLabelMessages(ctx, "INBOX", "", 10)
+				return err
+			},
+		},
+		{
+			name: "list history", path: "/gmail/v1/users/me/history", fields: historyFields,
+			call: func(ctx context.Context, m *GMail) error {
+				_, err := m.ListHistory(ctx, 100, "INBOX", "")
+				return err
+			},
+		},
+		{
+			name: "message metadata", path: "/gmail/v1/users/me/messages/m1", fields: metadataFields,
+			call: func(ctx context.Context, m *GMail) error {
+				_, err := m.GetMessageMetadata(ctx, "m1")
+				return err
+			},
+		},
+		{
+			name: "thread metadata", path: "/gmail/v1/users/me/threads/t1", fields: threadMetadataFields,
+			call: func(ctx context.Context, m *GMail) error {
+				_, err := m.GetThreadMetadata(ctx, "t1")
+				return err
+			},
+		},
+		{
+			name: "attachment", path: "/gmail/v1/users/me/messages/m1/attachments/a1", fields: attachmentFields,
+			call: func(ctx context.Context, m *GMail) error {
+				_, err := m.GetAttachment(ctx, "m1", "a1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.path, r.URL.Path)
+				assert.Equal(t, string(tt.fields), r.URL.Query().Get("fields"))
+				writeJSON(t, w, map[string]any{})
+			}))
+			require.NoError(t, tt.call(context.Background(), m))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package gservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// ListLabels lists all system and user labels of the mailbox.
+func (m *GMail) ListLabels(ctx context.Context) ([]*gmail.Label, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaLabelsList); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.Labels.List(m.userID).Context(ctx).Do()
+	m.observe("gmail.labels.List", quotaLabelsList, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("labels.List failed: %w", apiError(err))
+	}
+
+	return result.Labels, nil
+}
+
+// GetLabel retrieves a label including its message and thread counters.
+func (m *GMail) GetLabel(ctx context.Context, labelID string) (*gmail.Label, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaLabelsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	label, err := svc.Users.Labels.Get(m.userID, labelID).Context(ctx).Do()
+	m.observe("gmail.labels.Get", quotaLabelsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("labels.Get failed: %w", apiError(err))
+	}
+
+	return label, nil
+}
+
+// CreateLabel creates a user label; nested labels use "/" separated names.
+func (m *GMail) CreateLabel(ctx context.Context, name string) (*gmail.Label, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaLabelsCreate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	label, err := svc.Users.Labels.Create(m.userID, &gmail.Label{
+		Name:                  name,
+		LabelListVisibility:   "labelShow",
+		MessageListVisibility: "show",
+	}).Context(ctx).Do()
+	m.observe("gmail.labels.Create", quotaLabelsCreate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("labels.Create failed: %w", apiError(err))
+	}
+
+	return label, nil
+}
+
+// RenameLabel changes the name of a user label.
+func (m *GMail) RenameLabel(ctx context.Context, labelID, name string) (*gmail.Label, error) {
+	return m.PatchLabel(ctx, labelID, &gmail.Label{Name: name})
+}
+
+// PatchLabel changes the fields of a user label set in patch, like its name, visibility or
+// color, and leaves the others.
+func (m *GMail) PatchLabel(ctx context.Context, labelID string, patch *gmail.Label) (*gmail.Label, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaLabelsPatch); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	label, err := svc.Users.Labels.Patch(m.userID, labelID, patch).Context(ctx).Do()
+	m.observe("gmail.labels.Patch", quotaLabelsPatch, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("labels.Patch failed: %w", apiError(err))
+	}
+
+	return label, nil
+}
+
+// DeleteLabel removes a user label; messages keep existing but lose the label.
+func (m *GMail) DeleteLabel(ctx context.Context, labelID string) error {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaLabelsDelete); err != nil {
+		return fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	err = svc.Users.Labels.Delete(m.userID, labelID).Context(ctx).Do()
+	m.observe("gmail.labels.Delete", quotaLabelsDelete, start, err)
+	if err != nil {
+		return fmt.Errorf("labels.Delete failed: %w", apiError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package gservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestLabels(t *testing.T) {
+	var deleted string
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/labels":
+			writeJSON(t, w, &gmail.ListLabelsResponse{Labels: []*gmail.Label{{Id: "INBOX"}, {Id: "Label_1", Name: "Work"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/gmail/v1/users/me/labels":
+			var label gmail.Label
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&label))
+			assert.Equal(t, "Work/Reports", label.Name)
+			assert.Equal(t, "labelShow", label.LabelListVisibility)
+			label.Id = "Label_2"
+			writeJSON(t, w, &label)
+		case r.Method == http.MethodPatch && r.URL.Path == "/gmail/v1/users/me/labels/Label_1":
+			var patch map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			assert.Equal(t, map[string]any{"name": "Projects"}, patch, "only set fields are sent")
+			writeJSON(t, w, &gmail.Label{Id: "Label_1", Name: "Projects"})
+		case r.Method == http.MethodDelete && r.URL.Path == "/gmail/v1/users/me/labels/Label_1":
+			deleted = "Label_1"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	labels, err := m.ListLabels(ctx)
+	require.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	created, err := m.CreateLabel(ctx, "Work/Reports")
+	require.NoError(t, err)
+	assert.Equal(t, "Label_2", created.Id)
+
+	renamed, err := m.RenameLabel(ctx, "Label_1", "Projects")
+	require.NoError(t, err)
+	assert.Equal(t, "Projects", renamed.Name)
+
+	require.NoError(t, m.DeleteLabel(ctx, "Label_1"))
+	assert.Equal(t, "Label_1", deleted)
+}
+
+func TestWithMailbox(t *testing.T) {
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gmail/v1/users/boss@example.com/labels", r.URL.Path)
+		writeJSON(t, w, &gmail.ListLabelsResponse{Labels: []*gmail.Label{{Id: "INBOX"}}})
+	}), WithMailbox("boss@example.com"))
+
+	labels, err := m.ListLabels(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, labels, 1)
+}
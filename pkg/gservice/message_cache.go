@@ -0,0 +1,77 @@
+package gservice
+
+import (
+	"log"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// Cache buckets of messages by format.
+const (
+	cacheBucketMetadata = "metadata"
+	cacheBucketMessages = "messages"
+)
+
+type messageKey struct {
+	bucket string
+	id     string
+}
+
+type cachedMessage struct {
+	msg     *gmail.Message
+	expires time.Time
+}
+
+// CacheStats describes the use of the message caches.
+type CacheStats struct {
+	// Entries are the messages and message metadata held in memory.
+	Entries int
+	// Hits and Misses count the lookups answered from memory or disk and those going to the API.
+	Hits   int64
+	Misses int64
+}
+
+// CacheStats returns the current use of the message caches.
+func (m *GMail) CacheStats() CacheStats {
+	return CacheStats{Entries: m.messages.Len(), Hits: m.hits.Load(), Misses: m.misses.Load()}
+}
+
+// cachedMessage looks a message up in memory, then on disk.
+func (m *GMail) cachedMessage(bucket, msgID string) (*gmail.Message, bool) {
+	key := messageKey{bucket: bucket, id: msgID}
+	if cached, ok := m.messages.Get(key); ok {
+		if time.Now().Before(cached.expires) {
+			m.hits.Add(1)
+			return cached.msg, true
+		}
+		m.messages.Remove(key)
+	}
+
+	var msg gmail.Message
+	if !m.cache.Get(bucket, msgID, &msg) {
+		m.misses.Add(1)
+		return nil, false
+	}
+	m.hits.Add(1)
+	m.messages.Add(key, cachedMessage{msg: &msg, expires: time.Now().Add(m.messageTTL)})
+	return &msg, true
+}
+
+// cacheMessage stores msg in memory and on disk. Failing to cache doesn't fail the call.
+func (m *GMail) cacheMessage(bucket, msgID string, msg *gmail.Message) {
+	m.messages.Add(messageKey{bucket: bucket, id: msgID}, cachedMessage{msg: msg, expires: time.Now().Add(m.messageTTL)})
+	if err := m.cache.Put(bucket, msgID, msg, m.cacheTTL); err != nil {
+		log.Printf("Caching message %s failed: %v", msgID, err)
+	}
+}
+
+// forgetMessage drops the cached copies of a message whose labels changed.
+func (m *GMail) forgetMessage(msgID string) {
+	for _, bucket := range []string{cacheBucketMetadata, cacheBucketMessages} {
+		m.messages.Remove(messageKey{bucket: bucket, id: msgID})
+		if err := m.cache.Delete(bucket, msgID); err != nil {
+			log.Printf("Dropping cached message %s failed: %v", msgID, err)
+		}
+	}
+}
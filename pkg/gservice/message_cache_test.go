@@ -0,0 +1,75 @@
+package gservice
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/diskcache"
+)
+
+// countingMessages serves message m1 and counts the requests for it.
+func countingMessages(t *testing.T, calls *atomic.Int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gmail/v1/users/me/messages/m1", r.URL.Path)
+		calls.Add(1)
+		writeJSON(t, w, &gmail.Message{Id: "m1", Snippet: "hello"})
+	})
+}
+
+func TestMessageCache(t *testing.T) {
+	var calls atomic.Int64
+	m := newTestGmail(t, countingMessages(t, &calls), WithMessageCache(10, time.Hour))
+	ctx := context.Background()
+
+	for range 2 {
+		msg, err := m.GetMessageMetadata(ctx, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, "hello", msg.Snippet)
+	}
+	assert.Equal(t, int64(1), calls.Load(), "metadata should be fetched once")
+
+	_, err := m.GetMessage(ctx, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), calls.Load(), "full messages are cached apart from metadata")
+
+	assert.Equal(t, CacheStats{Entries: 2, Hits: 1, Misses: 2}, m.CacheStats())
+}
+
+func TestMessageCacheExpiry(t *testing.T) {
+	var calls atomic.Int64
+	m := newTestGmail(t, countingMessages(t, &calls), WithMessageCache(10, time.Millisecond))
+
+	_, err := m.GetMessageMetadata(context.Background(), "m1")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = m.GetMessageMetadata(context.Background(), "m1")
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), calls.Load(), "expired messages should be fetched again")
+	assert.Equal(t, CacheStats{Entries: 1, Misses: 2}, m.CacheStats())
+}
+
+func TestMessageCacheDisk(t *testing.T) {
+	cache, err := diskcache.New(t.TempDir())
+	require.NoError(t, err)
+
+	var calls atomic.Int64
+	first := newTestGmail(t, countingMessages(t, &calls), WithDiskCache(cache, time.Hour), WithMessageCache(10, time.Hour))
+	_, err = first.GetMessageMetadata(context.Background(), "m1")
+	require.NoError(t, err)
+
+	// A restarted server starts with an empty memory cache and reads the message from disk.
+	second := newTestGmail(t, countingMessages(t, &calls), WithDiskCache(cache, time.Hour), WithMessageCache(10, time.Hour))
+	msg, err := second.GetMessageMetadata(context.Background(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", msg.Snippet)
+	assert.Equal(t, int64(1), calls.Load())
+	assert.Equal(t, CacheStats{Entries: 1, Hits: 1}, second.CacheStats())
+}
@@ -0,0 +1,56 @@
+package gservice
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Metrics receives every Gmail, Calendar and Drive API call the facade makes, so operators can
+// export call counters, error counters and latency histograms to Prometheus, OpenTelemetry or
+// similar. ObserveCall is called concurrently and must not block.
+type Metrics interface {
+	ObserveCall(call Call)
+}
+
+// Call describes a completed API call.
+type Call struct {
+	// Method is the API method, e.g. "gmail.messages.Get" or "drive.files.Export".
+	Method string
+	// QuotaUnits are the Gmail quota units the call was charged, zero for Calendar and Drive.
+	QuotaUnits int
+	// Latency is the time the call took, including retries but not waiting for quota.
+	Latency time.Duration
+	// Code is the HTTP status of a failed call, zero for successful calls and for failures
+	// without a response, like network and token errors.
+	Code int
+	// Err is the error the call failed with, matching ErrNotFound and the other errors of the
+	// package.
+	Err error
+}
+
+// WithMetrics reports every API call to metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(m *GMail) {
+		m.metrics = metrics
+	}
+}
+
+// observe reports a call of method charged units started at start and failed with err, if not
+// nil, to the metrics.
+func (m *GMail) observe(method string, units int, start time.Time, err error) {
+	if m.metrics == nil {
+		return
+	}
+
+	call := Call{Method: method, QuotaUnits: units, Latency: time.Since(start)}
+	if err != nil {
+		call.Err = apiError(err)
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) {
+			call.Code = apiErr.Code
+		}
+	}
+	m.metrics.ObserveCall(call)
+}
@@ -0,0 +1,53 @@
+package gservice
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+type recordedMetrics struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recordedMetrics) ObserveCall(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func TestWithMetrics(t *testing.T) {
+	metrics := &recordedMetrics{}
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gmail/v1/users/me/messages/m1":
+			writeJSON(t, w, &gmail.Message{Id: "m1"})
+		default:
+			http.Error(w, `{"error":{"code":404,"message":"Not Found"}}`, http.StatusNotFound)
+		}
+	}), WithMetrics(metrics))
+	ctx := context.Background()
+
+	_, err := m.GetMessage(ctx, "m1")
+	require.NoError(t, err)
+	_, err = m.GetLabel(ctx, "Label_9")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.Len(t, metrics.calls, 2)
+	assert.Equal(t, "gmail.messages.Get", metrics.calls[0].Method)
+	assert.Equal(t, quotaMessagesGet, metrics.calls[0].QuotaUnits)
+	assert.Zero(t, metrics.calls[0].Code)
+	assert.NoError(t, metrics.calls[0].Err)
+	assert.Positive(t, metrics.calls[0].Latency)
+
+	assert.Equal(t, "gmail.labels.Get", metrics.calls[1].Method)
+	assert.Equal(t, quotaLabelsGet, metrics.calls[1].QuotaUnits)
+	assert.Equal(t, http.StatusNotFound, metrics.calls[1].Code)
+	assert.ErrorIs(t, metrics.calls[1].Err, ErrNotFound)
+}
@@ -0,0 +1,109 @@
+package gservice
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Quota units Gmail charges per method, from its usage limits. The per-user limit is 250 units
+// per second.
+const (
+	quotaGetProfile     = 1
+	quotaHistoryList    = 2
+	quotaMessagesList   = 5
+	quotaMessagesGet    = 5
+	quotaAttachmentsGet = 5
+	quotaThreadsList    = 10
+	quotaThreadsGet     = 10
+	quotaThreadsModify  = 10
+	quotaLabelsList     = 1
+	quotaLabelsGet      = 1
+	quotaLabelsCreate   = 5
+	quotaLabelsPatch    = 5
+	quotaLabelsDelete   = 5
+	quotaDraftsList     = 5
+	quotaDraftsGet      = 5
+	quotaDraftsCreate   = 10
+	quotaDraftsUpdate   = 15
+	quotaDraftsSend     = 100
+	quotaDraftsDelete   = 10
+	quotaSettingsGet    = 1
+	quotaSettingsUpdate = 5
+	quotaWatch          = 100
+	quotaStop           = 50
+)
+
+// WithQuota limits API calls to unitsPerSecond quota units, with bursts of up to a second worth
+// of units, so bulk operations wait instead of failing with userRateLimitExceeded. Zero disables
+// the limit.
+func WithQuota(unitsPerSecond float64) Option {
+	return func(m *GMail) {
+		m.quota = newQuotaLimiter(unitsPerSecond)
+	}
+}
+
+// quotaLimiter is a token bucket of quota units. A nil quotaLimiter never waits.
+type quotaLimiter struct {
+	rate float64
+	now  func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newQuotaLimiter(unitsPerSecond float64) *quotaLimiter {
+	if unitsPerSecond <= 0 {
+		return nil
+	}
+	return &quotaLimiter{rate: unitsPerSecond, tokens: unitsPerSecond, now: time.Now, last: time.Now()}
+}
+
+// wait blocks until units are available and takes them. Callers are served in the order they
+// ask, as each takes its units up front and waits for the bucket to refill.
+func (l *quotaLimiter) wait(ctx context.Context, units float64) error {
+	if l == nil {
+		return nil
+	}
+
+	delay := l.reserve(units)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(units)
+		return context.Cause(ctx)
+	}
+}
+
+// reserve takes units from the bucket, which may go negative, and returns how long it takes to
+// refill to zero.
+func (l *quotaLimiter) reserve(units float64) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.rate)
+	l.last = now
+	// A call costing more than the bucket holds must still be able to run.
+	l.tokens -= min(units, l.rate)
+
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// release gives back units of a canceled wait.
+func (l *quotaLimiter) release(units float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.tokens+min(units, l.rate), l.rate)
+}
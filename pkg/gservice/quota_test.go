@@ -0,0 +1,40 @@
+package gservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaLimiterReserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := newQuotaLimiter(10)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	assert.Zero(t, l.reserve(5), "a second worth of units is available at once")
+	assert.Zero(t, l.reserve(5))
+	assert.Equal(t, 500*time.Millisecond, l.reserve(5), "an empty bucket refills at the rate")
+	assert.Equal(t, time.Second, l.reserve(5), "later callers wait behind earlier ones")
+
+	now = now.Add(time.Second)
+	assert.Zero(t, l.reserve(0), "waited for units are paid back")
+	assert.Equal(t, time.Second, l.reserve(50), "calls above the bucket size cost the bucket size")
+}
+
+func TestQuotaLimiterWaitCanceled(t *testing.T) {
+	l := newQuotaLimiter(1)
+	require.NoError(t, l.wait(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, l.wait(ctx, 1), context.Canceled)
+	assert.InDelta(t, 0, l.tokens, 0.1, "units of a canceled wait are given back")
+
+	var disabled *quotaLimiter
+	assert.NoError(t, disabled.wait(ctx, 100))
+	assert.Nil(t, newQuotaLimiter(0))
+}
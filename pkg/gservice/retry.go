@@ -0,0 +1,179 @@
+package gservice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// maxBackoff caps the delay between two attempts growing exponentially.
+	maxBackoff = 30 * time.Second
+	// maxRetryAfter is the longest Retry-After wait honored; longer waits fail the call instead.
+	maxRetryAfter = time.Minute
+	// maxErrorBody bounds how much of an error response is read to look for its reason.
+	maxErrorBody = 64 << 10
+)
+
+// WithRetry retries API calls failing with 429, 5xx, rate limit 403 or transient network errors
+// up to retries times, waiting about baseDelay, doubling with every attempt, or as long as a
+// Retry-After header asks. Zero retries disables it.
+func WithRetry(retries int, baseDelay time.Duration) Option {
+	return func(m *GMail) {
+		m.retries = retries
+		m.retryDelay = baseDelay
+	}
+}
+
+// retryTransport repeats requests failing transiently with jittered exponential backoff.
+type retryTransport struct {
+	base      http.RoundTripper
+	retries   int
+	baseDelay time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewind(req); err != nil {
+				return nil, fmt.Errorf("rewind failed: %w", err)
+			}
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.retries {
+			return resp, err
+		}
+		delay, retry := t.retryDelay(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		var status string
+		if resp != nil {
+			status = resp.Status
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+			_ = resp.Body.Close()
+		} else {
+			status = err.Error()
+		}
+		log.Printf("%s %s failed (%s), retrying in %v", req.Method, req.URL.Path, status, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryDelay reports whether the outcome of an attempt is worth retrying and how long to wait.
+func (t *retryTransport) retryDelay(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		if req.Context().Err() != nil || !isTransient(err) || (req.Body != nil && req.GetBody == nil) {
+			return 0, false
+		}
+		return t.backoff(attempt), true
+	}
+	if !isRetryableStatus(resp) || (req.Body != nil && req.GetBody == nil) {
+		return 0, false
+	}
+
+	if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+		return after, after <= maxRetryAfter
+	}
+	return t.backoff(attempt), true
+}
+
+// backoff doubles baseDelay with every attempt up to maxBackoff and picks a random delay in its
+// upper half, so clients throttled together don't retry together.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := min(t.baseDelay<<min(attempt, 30), maxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// isRetryableStatus reports throttled and failed responses: 429, 5xx, and 403 with a rate limit
+// reason, which Gmail uses for per-user limits. The body of a 403 is restored after reading it.
+func isRetryableStatus(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		return true
+	case resp.StatusCode != http.StatusForbidden:
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(body, []byte("RateLimitExceeded")) || bytes.Contains(body, []byte("rateLimitExceeded"))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// isTransient reports network failures a repeated request may not hit: timeouts, reset or
+// refused connections and connections closed early. Token refresh failures are not transient.
+func isTransient(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// rewind copies req with a fresh body for another attempt.
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body == nil || req.GetBody == nil {
+		return clone, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("req.GetBody failed: %w", err)
+	}
+	clone.Body = body
+	return clone, nil
+}
@@ -0,0 +1,146 @@
+package gservice
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		failures   []int
+		header     http.Header
+		body       string
+		wantStatus int
+		wantCalls  int32
+	}{
+		{
+			name:       "retries server errors",
+			failures:   []int{http.StatusServiceUnavailable, http.StatusInternalServerError},
+			wantStatus: http.StatusOK,
+			wantCalls:  3,
+		},
+		{
+			name:       "honors Retry-After",
+			failures:   []int{http.StatusTooManyRequests},
+			header:     http.Header{"Retry-After": {"0"}},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "retries rate limited 403",
+			failures:   []int{http.StatusForbidden},
+			body:       `{"error":{"errors":[{"reason":"userRateLimitExceeded"}]}}`,
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "returns other 403",
+			failures:   []int{http.StatusForbidden},
+			body:       `{"error":{"errors":[{"reason":"insufficientPermissions"}]}}`,
+			wantStatus: http.StatusForbidden,
+			wantCalls:  1,
+		},
+		{
+			name:       "returns client errors",
+			failures:   []int{http.StatusNotFound},
+			wantStatus: http.StatusNotFound,
+			wantCalls:  1,
+		},
+		{
+			name:       "gives up after retries",
+			failures:   []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  3,
+		},
+		{
+			name:       "fails when Retry-After is too long",
+			failures:   []int{http.StatusTooManyRequests},
+			header:     http.Header{"Retry-After": {"3600"}},
+			wantStatus: http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, "payload", string(body))
+
+				n := int(calls.Add(1))
+				if n > len(tt.failures) {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tt.failures[n-1])
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			clt := &http.Client{Transport: &retryTransport{
+				base:      http.DefaultTransport,
+				retries:   2,
+				baseDelay: time.Millisecond,
+			}}
+			resp, err := clt.Post(server.URL, "text/plain", strings.NewReader("payload"))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			if tt.wantStatus != http.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.body, string(body), "error body should stay readable")
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter("7")
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, d)
+
+	d, ok = retryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Zero(t, d)
+
+	_, ok = retryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestRetryCanceledInBackoff(t *testing.T) {
+	attempts := make(chan struct{}, 10)
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts <- struct{}{}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}), WithRetry(3, time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-attempts
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := m.GetProfile(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 10*time.Second, "canceling should end the backoff")
+	assert.Empty(t, attempts, "no attempt should follow the cancellation")
+}
@@ -0,0 +1,310 @@
+package gservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// Settings calls read with the scopes the server requests by default; changing them needs the
+// gmail.settings.basic scope, and auto-forwarding the gmail.settings.sharing scope.
+
+// ListFilters lists the filters applied to incoming mail.
+func (m *GMail) ListFilters(ctx context.Context) ([]*gmail.Filter, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.Settings.Filters.List(m.userID).Context(ctx).Do()
+	m.observe("gmail.settings.filters.List", quotaSettingsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.filters.List failed: %w", apiError(err))
+	}
+
+	return result.Filter, nil
+}
+
+// CreateFilter creates a filter applying its action to incoming mail matching its criteria.
+func (m *GMail) CreateFilter(ctx context.Context, filter *gmail.Filter) (*gmail.Filter, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsUpdate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	created, err := svc.Users.Settings.Filters.Create(m.userID, filter).Context(ctx).Do()
+	m.observe("gmail.settings.filters.Create", quotaSettingsUpdate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.filters.Create failed: %w", apiError(err))
+	}
+
+	return created, nil
+}
+
+// DeleteFilter removes a filter; messages it already applied to keep their labels.
+func (m *GMail) DeleteFilter(ctx context.Context, filterID string) error {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsUpdate); err != nil {
+		return fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	err = svc.Users.Settings.Filters.Delete(m.userID, filterID).Context(ctx).Do()
+	m.observe("gmail.settings.filters.Delete", quotaSettingsUpdate, start, err)
+	if err != nil {
+		return fmt.Errorf("settings.filters.Delete failed: %w", apiError(err))
+	}
+
+	return nil
+}
+
+// GetVacation retrieves the vacation responder settings.
+func (m *GMail) GetVacation(ctx context.Context) (*gmail.VacationSettings, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	vacation, err := svc.Users.Settings.GetVacation(m.userID).Context(ctx).Do()
+	m.observe("gmail.settings.GetVacation", quotaSettingsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.GetVacation failed: %w", apiError(err))
+	}
+
+	return vacation, nil
+}
+
+// UpdateVacation replaces the vacation responder settings.
+func (m *GMail) UpdateVacation(ctx context.Context, vacation *gmail.VacationSettings) (*gmail.VacationSettings, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsUpdate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	updated, err := svc.Users.Settings.UpdateVacation(m.userID, vacation).Context(ctx).Do()
+	m.observe("gmail.settings.UpdateVacation", quotaSettingsUpdate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.UpdateVacation failed: %w", apiError(err))
+	}
+
+	return updated, nil
+}
+
+// ListSendAs lists the addresses mail can be sent from, the primary address included, with
+// their display names and signatures.
+func (m *GMail) ListSendAs(ctx context.Context) ([]*gmail.SendAs, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.Settings.SendAs.List(m.userID).Context(ctx).Do()
+	m.observe("gmail.settings.sendAs.List", quotaSettingsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.sendAs.List failed: %w", apiError(err))
+	}
+
+	return result.SendAs, nil
+}
+
+// PatchSendAs changes the fields of a send-as alias set in patch, like its display name or
+// signature, and leaves the others.
+func (m *GMail) PatchSendAs(ctx context.Context, sendAsEmail string, patch *gmail.SendAs) (*gmail.SendAs, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsUpdate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	sendAs, err := svc.Users.Settings.SendAs.Patch(m.userID, sendAsEmail, patch).Context(ctx).Do()
+	m.observe("gmail.settings.sendAs.Patch", quotaSettingsUpdate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.sendAs.Patch failed: %w", apiError(err))
+	}
+
+	return sendAs, nil
+}
+
+// ListForwardingAddresses lists the addresses mail may be forwarded to, with their
+// verification status.
+func (m *GMail) ListForwardingAddresses(ctx context.Context) ([]*gmail.ForwardingAddress, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.Settings.ForwardingAddresses.List(m.userID).Context(ctx).Do()
+	m.observe("gmail.settings.forwardingAddresses.List", quotaSettingsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.forwardingAddresses.List failed: %w", apiError(err))
+	}
+
+	return result.ForwardingAddresses, nil
+}
+
+// GetAutoForwarding retrieves whether and where incoming mail is forwarded.
+func (m *GMail) GetAutoForwarding(ctx context.Context) (*gmail.AutoForwarding, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	forwarding, err := svc.Users.Settings.GetAutoForwarding(m.userID).Context(ctx).Do()
+	m.observe("gmail.settings.GetAutoForwarding", quotaSettingsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.GetAutoForwarding failed: %w", apiError(err))
+	}
+
+	return forwarding, nil
+}
+
+// UpdateAutoForwarding replaces the auto-forwarding settings; the address must be a verified
+// forwarding address.
+func (m *GMail) UpdateAutoForwarding(ctx context.Context, forwarding *gmail.AutoForwarding) (*gmail.AutoForwarding, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsUpdate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	updated, err := svc.Users.Settings.UpdateAutoForwarding(m.userID, forwarding).Context(ctx).Do()
+	m.observe("gmail.settings.UpdateAutoForwarding", quotaSettingsUpdate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.UpdateAutoForwarding failed: %w", apiError(err))
+	}
+
+	return updated, nil
+}
+
+// GetPop retrieves the POP access settings.
+func (m *GMail) GetPop(ctx context.Context) (*gmail.PopSettings, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	pop, err := svc.Users.Settings.GetPop(m.userID).Context(ctx).Do()
+	m.observe("gmail.settings.GetPop", quotaSettingsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.GetPop failed: %w", apiError(err))
+	}
+
+	return pop, nil
+}
+
+// UpdatePop replaces the POP access settings.
+func (m *GMail) UpdatePop(ctx context.Context, pop *gmail.PopSettings) (*gmail.PopSettings, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsUpdate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	updated, err := svc.Users.Settings.UpdatePop(m.userID, pop).Context(ctx).Do()
+	m.observe("gmail.settings.UpdatePop", quotaSettingsUpdate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.UpdatePop failed: %w", apiError(err))
+	}
+
+	return updated, nil
+}
+
+// GetImap retrieves the IMAP access settings.
+func (m *GMail) GetImap(ctx context.Context) (*gmail.ImapSettings, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	imap, err := svc.Users.Settings.GetImap(m.userID).Context(ctx).Do()
+	m.observe("gmail.settings.GetImap", quotaSettingsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.GetImap failed: %w", apiError(err))
+	}
+
+	return imap, nil
+}
+
+// UpdateImap replaces the IMAP access settings.
+func (m *GMail) UpdateImap(ctx context.Context, imap *gmail.ImapSettings) (*gmail.ImapSettings, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaSettingsUpdate); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	updated, err := svc.Users.Settings.UpdateImap(m.userID, imap).Context(ctx).Do()
+	m.observe("gmail.settings.UpdateImap", quotaSettingsUpdate, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("settings.UpdateImap failed: %w", apiError(err))
+	}
+
+	return updated, nil
+}
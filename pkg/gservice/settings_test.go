@@ -0,0 +1,81 @@
+package gservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+)
+
+func TestSettings(t *testing.T) {
+	var deleted string
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/settings/filters":
+			writeJSON(t, w, &gmail.ListFiltersResponse{Filter: []*gmail.Filter{{Id: "f1"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/gmail/v1/users/me/settings/filters":
+			var filter gmail.Filter
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&filter))
+			assert.Equal(t, "news@example.com", filter.Criteria.From)
+			filter.Id = "f2"
+			writeJSON(t, w, &filter)
+		case r.Method == http.MethodDelete && r.URL.Path == "/gmail/v1/users/me/settings/filters/f1":
+			deleted = "f1"
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPut && r.URL.Path == "/gmail/v1/users/me/settings/vacation":
+			var vacation gmail.VacationSettings
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&vacation))
+			writeJSON(t, w, &vacation)
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/settings/sendAs":
+			writeJSON(t, w, &gmail.ListSendAsResponse{SendAs: []*gmail.SendAs{{SendAsEmail: "ada@example.com", IsPrimary: true}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/gmail/v1/users/me/settings/imap":
+			writeJSON(t, w, &gmail.ImapSettings{Enabled: true})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	filters, err := m.ListFilters(ctx)
+	require.NoError(t, err)
+	assert.Len(t, filters, 1)
+
+	created, err := m.CreateFilter(ctx, &gmail.Filter{
+		Criteria: &gmail.FilterCriteria{From: "news@example.com"},
+		Action:   &gmail.FilterAction{RemoveLabelIds: []string{"INBOX"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "f2", created.Id)
+
+	require.NoError(t, m.DeleteFilter(ctx, "f1"))
+	assert.Equal(t, "f1", deleted)
+
+	vacation, err := m.UpdateVacation(ctx, &gmail.VacationSettings{EnableAutoReply: true, ResponseSubject: "Away"})
+	require.NoError(t, err)
+	assert.True(t, vacation.EnableAutoReply)
+
+	sendAs, err := m.ListSendAs(ctx)
+	require.NoError(t, err)
+	assert.True(t, sendAs[0].IsPrimary)
+
+	imap, err := m.GetImap(ctx)
+	require.NoError(t, err)
+	assert.True(t, imap.Enabled)
+}
+
+func TestSettingsPermission(t *testing.T) {
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":403,"message":"Request had insufficient authentication scopes.",` +
+			`"errors":[{"reason":"insufficientPermissions"}]}}`))
+	}))
+
+	_, err := m.UpdatePop(context.Background(), &gmail.PopSettings{AccessWindow: "disabled"})
+	require.ErrorIs(t, err, ErrPermission)
+}
@@ -0,0 +1,111 @@
+package gservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// ListThreads searches for threads matching the query. Threads carry their ID, snippet and
+// history ID; GetThread or GetThreadMetadata fetch their messages.
+func (m *GMail) ListThreads(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListThreadsResponse, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaThreadsList); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	result, err := svc.Users.Threads.List(m.userID).
+		Q(Q).
+		PageToken(pageToken).
+		MaxResults(maxResults).
+		Fields(threadListFields).
+		Context(ctx).
+		Do()
+	m.observe("gmail.threads.List", quotaThreadsList, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("threads.List failed: %w", apiError(err))
+	}
+
+	return result, nil
+}
+
+// GetThread retrieves a thread with all its messages in full format.
+func (m *GMail) GetThread(ctx context.Context, threadID string) (*gmail.Thread, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaThreadsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	thread, err := svc.Users.Threads.Get(m.userID, threadID).Format("FULL").Context(ctx).Do()
+	m.observe("gmail.threads.Get", quotaThreadsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("threads.Get failed: %w", apiError(err))
+	}
+
+	return thread, nil
+}
+
+// GetThreadMetadata retrieves headers (addressing, subject, date and threading identifiers) of every message in a thread.
+func (m *GMail) GetThreadMetadata(ctx context.Context, threadID string) (*gmail.Thread, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaThreadsGet); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	thread, err := svc.Users.Threads.Get(m.userID, threadID).
+		Format("METADATA").
+		MetadataHeaders(metadataHeaders...).
+		Fields(threadMetadataFields).
+		Context(ctx).
+		Do()
+	m.observe("gmail.threads.Get", quotaThreadsGet, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("threads.Get failed: %w", apiError(err))
+	}
+
+	return thread, nil
+}
+
+// ModifyThread adds and removes labels on every message of a thread.
+func (m *GMail) ModifyThread(ctx context.Context, threadID string, addLabelIDs, removeLabelIDs []string) (*gmail.Thread, error) {
+	svc, err := m.newSvc(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("newSvc failed: %w", err)
+	}
+
+	if err := m.quota.wait(ctx, quotaThreadsModify); err != nil {
+		return nil, fmt.Errorf("quota.wait failed: %w", err)
+	}
+
+	start := time.Now()
+	thread, err := svc.Users.Threads.Modify(m.userID, threadID, &gmail.ModifyThreadRequest{
+		AddLabelIds:    addLabelIDs,
+		RemoveLabelIds: removeLabelIDs,
+	}).Fields(modifyThreadFields).Context(ctx).Do()
+	m.observe("gmail.threads.Modify", quotaThreadsModify, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("threads.Modify failed: %w", apiError(err))
+	}
+	for _, msg := range thread.Messages {
+		m.forgetMessage(msg.Id)
+	}
+
+	return thread, nil
+}
@@ -0,0 +1,101 @@
+package gservice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+)
+
+// newTestGmail creates a GMail calling handler instead of the Gmail API.
+func newTestGmail(t *testing.T, handler http.Handler, opts ...Option) *GMail {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	tokenPath := filepath.Join(t.TempDir(), "token.json")
+	data, err := json.Marshal(&oauth2.Token{AccessToken: "access", Expiry: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(tokenPath, data, 0600))
+
+	cfg := &oauth2.Config{}
+	tok, err := auth.NewToken(cfg, tokenPath)
+	require.NoError(t, err)
+
+	m := NewGmail(cfg, tok, opts...)
+	m.endpoint = server.URL + "/"
+	return m
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, v any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	assert.NoError(t, json.NewEncoder(w).Encode(v))
+}
+
+func TestListThreads(t *testing.T) {
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gmail/v1/users/me/threads", r.URL.Path)
+		assert.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+		assert.Equal(t, "from:alice", r.URL.Query().Get("q"))
+		assert.Equal(t, "next", r.URL.Query().Get("pageToken"))
+		assert.Equal(t, "20", r.URL.Query().Get("maxResults"))
+		assert.Equal(t, string(threadListFields), r.URL.Query().Get("fields"))
+		writeJSON(t, w, &gmail.ListThreadsResponse{
+			Threads:       []*gmail.Thread{{Id: "t1", Snippet: "hello"}},
+			NextPageToken: "after",
+		})
+	}))
+
+	result, err := m.ListThreads(context.Background(), "from:alice", "next", 20)
+	require.NoError(t, err)
+	require.Len(t, result.Threads, 1)
+	assert.Equal(t, "t1", result.Threads[0].Id)
+	assert.Equal(t, "after", result.NextPageToken)
+}
+
+func TestModifyThread(t *testing.T) {
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/gmail/v1/users/me/threads/t1/modify", r.URL.Path)
+
+		var req gmail.ModifyThreadRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []string{"Label_1"}, req.AddLabelIds)
+		assert.Equal(t, []string{"INBOX"}, req.RemoveLabelIds)
+
+		writeJSON(t, w, &gmail.Thread{Id: "t1", Messages: []*gmail.Message{{Id: "m1"}, {Id: "m2"}}})
+	}), WithMessageCache(10, time.Hour))
+	m.cacheMessage(cacheBucketMetadata, "m1", &gmail.Message{Id: "m1", LabelIds: []string{"INBOX"}})
+
+	thread, err := m.ModifyThread(context.Background(), "t1", []string{"Label_1"}, []string{"INBOX"})
+	require.NoError(t, err)
+	assert.Len(t, thread.Messages, 2)
+
+	_, cached := m.cachedMessage(cacheBucketMetadata, "m1")
+	assert.False(t, cached, "messages of a modified thread are fetched again")
+	assert.Equal(t, CacheStats{Misses: 1}, m.CacheStats())
+}
+
+func TestGetThreadNotFound(t *testing.T) {
+	m := newTestGmail(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found."}}`))
+	}))
+
+	_, err := m.GetThread(context.Background(), "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
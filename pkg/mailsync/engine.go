@@ -0,0 +1,298 @@
+package mailsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+)
+
+const (
+	// DefaultMaxMessages is how many of the newest messages a snapshot keeps by default.
+	DefaultMaxMessages = 5000
+	listPageSize       = 500
+	metadataBatchSize  = 100
+)
+
+type gmailSvc interface {
+	GetProfile(ctx context.Context) (*gmail.Profile, error)
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	ListMailboxHistory(ctx context.Context, startHistoryID uint64, pageToken string) (*gmail.ListHistoryResponse, error)
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+	GetMessagesMetadata(ctx context.Context, msgIDs []string) ([]*gmail.Message, error)
+}
+
+// Result reports what a sync pass changed.
+type Result struct {
+	// Full is true when the snapshot was rebuilt from a listing rather than from history.
+	Full         bool
+	Added        int
+	Deleted      int
+	LabelChanges int
+	HistoryID    uint64
+	Messages     int
+	SyncedAt     time.Time
+}
+
+// Engine brings a Store up to date with the mailbox. Passes run one at a time.
+type Engine struct {
+	svc         gmailSvc
+	store       *Store
+	maxMessages int
+	now         func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewEngine creates an Engine syncing up to maxMessages of the newest messages into store.
+// A maxMessages of zero or less uses DefaultMaxMessages.
+func NewEngine(svc gmailSvc, store *Store, maxMessages int) *Engine {
+	if maxMessages <= 0 {
+		maxMessages = DefaultMaxMessages
+	}
+	return &Engine{svc: svc, store: store, maxMessages: maxMessages, now: time.Now}
+}
+
+// Store returns the snapshot the engine maintains.
+func (e *Engine) Store() *Store {
+	return e.store
+}
+
+// Sync applies the mailbox changes since the last pass. The first pass, a full one, or a pass
+// after Gmail expired the history of the stored history ID lists the mailbox instead.
+func (e *Engine) Sync(ctx context.Context, full bool) (Result, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !full && e.store.HistoryID() != 0 {
+		result, err := e.syncHistory(ctx)
+		if !errors.Is(err, gservice.ErrNotFound) {
+			return result, err
+		}
+		log.Printf("History after %d expired, syncing the mailbox again", e.store.HistoryID())
+	}
+	return e.syncFull(ctx)
+}
+
+// Run syncs every interval until ctx is done, logging failed passes.
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Sync(ctx, false); err != nil && ctx.Err() == nil {
+			log.Println(fmt.Errorf("mailbox sync failed: %w", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Engine) syncFull(ctx context.Context) (Result, error) {
+	// The profile is read before listing, so changes made meanwhile are applied again rather than lost.
+	profile, err := e.svc.GetProfile(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("svc.GetProfile failed: %w", err)
+	}
+
+	var ids []string
+	pageToken := ""
+	for len(ids) < e.maxMessages {
+		result, err := e.svc.ListMessages(ctx, "", pageToken, int64(min(listPageSize, e.maxMessages-len(ids))))
+		if err != nil {
+			return Result{}, fmt.Errorf("svc.ListMessages failed: %w", err)
+		}
+		for _, ref := range result.Messages {
+			ids = append(ids, ref.Id)
+		}
+		if pageToken = result.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	added, err := e.fetch(ctx, ids)
+	if err != nil {
+		return Result{}, err
+	}
+
+	c := change{
+		historyID: profile.HistoryId,
+		syncedAt:  e.now().UTC(),
+		replace:   true,
+		added:     added,
+		limit:     e.maxMessages,
+	}
+	if err := e.store.apply(c); err != nil {
+		return Result{}, fmt.Errorf("store.apply failed: %w", err)
+	}
+	return e.result(c, true), nil
+}
+
+func (e *Engine) syncHistory(ctx context.Context) (Result, error) {
+	start := e.store.HistoryID()
+	changes := newHistoryChanges()
+	historyID := start
+	pageToken := ""
+	for {
+		result, err := e.svc.ListMailboxHistory(ctx, start, pageToken)
+		if err != nil {
+			return Result{}, fmt.Errorf("svc.ListMailboxHistory failed: %w", err)
+		}
+		historyID = max(historyID, result.HistoryId)
+		for _, record := range result.History {
+			changes.add(record)
+		}
+		if pageToken = result.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	messages, err := e.fetch(ctx, changes.addedIDs())
+	if err != nil {
+		return Result{}, err
+	}
+
+	c := change{
+		historyID: historyID,
+		syncedAt:  e.now().UTC(),
+		added:     messages,
+		deleted:   changes.deleted,
+		labels:    changes.labels,
+		limit:     e.maxMessages,
+	}
+	if err := e.store.apply(c); err != nil {
+		return Result{}, fmt.Errorf("store.apply failed: %w", err)
+	}
+	return e.result(c, false), nil
+}
+
+// historyChanges collects the changes of history records in order: the labels a message ends
+// up with and messages added and deleted, where a later deletion cancels an addition.
+type historyChanges struct {
+	added   map[string]bool
+	order   []string
+	deleted []string
+	labels  map[string][]string
+}
+
+func newHistoryChanges() *historyChanges {
+	return &historyChanges{added: make(map[string]bool), labels: make(map[string][]string)}
+}
+
+func (h *historyChanges) add(record *gmail.History) {
+	for _, m := range record.MessagesAdded {
+		if m.Message != nil && !h.added[m.Message.Id] {
+			h.added[m.Message.Id] = true
+			h.order = append(h.order, m.Message.Id)
+		}
+	}
+	for _, m := range record.LabelsAdded {
+		if m.Message != nil {
+			h.labels[m.Message.Id] = m.Message.LabelIds
+		}
+	}
+	for _, m := range record.LabelsRemoved {
+		if m.Message != nil {
+			h.labels[m.Message.Id] = m.Message.LabelIds
+		}
+	}
+	for _, m := range record.MessagesDeleted {
+		if m.Message != nil {
+			delete(h.added, m.Message.Id)
+			delete(h.labels, m.Message.Id)
+			h.deleted = append(h.deleted, m.Message.Id)
+		}
+	}
+}
+
+// addedIDs returns the messages added and not deleted again, in the order they were added.
+func (h *historyChanges) addedIDs() []string {
+	var ids []string
+	for _, id := range h.order {
+		if h.added[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// fetch reads the metadata of messages in batches. Messages deleted since they were listed are
+// skipped.
+func (e *Engine) fetch(ctx context.Context, ids []string) ([]Message, error) {
+	messages := make([]Message, 0, len(ids))
+	for start := 0; start < len(ids); start += metadataBatchSize {
+		batch := ids[start:min(start+metadataBatchSize, len(ids))]
+		fetched, err := e.svc.GetMessagesMetadata(ctx, batch)
+		if errors.Is(err, gservice.ErrNotFound) {
+			fetched, err = e.fetchEach(ctx, batch)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("svc.GetMessagesMetadata failed: %w", err)
+		}
+		for _, msg := range fetched {
+			messages = append(messages, newMessage(msg))
+		}
+	}
+	return messages, nil
+}
+
+func (e *Engine) fetchEach(ctx context.Context, ids []string) ([]*gmail.Message, error) {
+	var messages []*gmail.Message
+	for _, id := range ids {
+		msg, err := e.svc.GetMessageMetadata(ctx, id)
+		if errors.Is(err, gservice.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("svc.GetMessageMetadata failed: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func (e *Engine) result(c change, full bool) Result {
+	return Result{
+		Full:         full,
+		Added:        len(c.added),
+		Deleted:      len(c.deleted),
+		LabelChanges: len(c.labels),
+		HistoryID:    c.historyID,
+		Messages:     e.store.Len(),
+		SyncedAt:     c.syncedAt,
+	}
+}
+
+func newMessage(msg *gmail.Message) Message {
+	m := Message{
+		ID:           msg.Id,
+		ThreadID:     msg.ThreadId,
+		LabelIDs:     msg.LabelIds,
+		Snippet:      msg.Snippet,
+		InternalDate: msg.InternalDate,
+		SizeEstimate: msg.SizeEstimate,
+	}
+	if msg.Payload == nil {
+		return m
+	}
+	for _, h := range msg.Payload.Headers {
+		switch strings.ToLower(h.Name) {
+		case "from":
+			m.From = h.Value
+		case "to":
+			m.To = h.Value
+		case "subject":
+			m.Subject = h.Value
+		}
+	}
+	return m
+}
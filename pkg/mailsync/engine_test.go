@@ -0,0 +1,164 @@
+package mailsync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+)
+
+type fakeGmail struct {
+	historyID uint64
+	messages  map[string]*gmail.Message
+	history   []*gmail.History
+	expired   bool
+}
+
+func (f *fakeGmail) GetProfile(context.Context) (*gmail.Profile, error) {
+	return &gmail.Profile{HistoryId: f.historyID}, nil
+}
+
+func (f *fakeGmail) ListMessages(_ context.Context, _, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+	result := &gmail.ListMessagesResponse{}
+	for id := range f.messages {
+		result.Messages = append(result.Messages, &gmail.Message{Id: id})
+	}
+	return result, nil
+}
+
+func (f *fakeGmail) ListMailboxHistory(_ context.Context, start uint64, _ string) (*gmail.ListHistoryResponse, error) {
+	if f.expired {
+		return nil, fmt.Errorf("history.List failed: %w", gservice.ErrNotFound)
+	}
+	result := &gmail.ListHistoryResponse{HistoryId: f.historyID}
+	for _, record := range f.history {
+		if record.Id > start {
+			result.History = append(result.History, record)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeGmail) GetMessageMetadata(_ context.Context, id string) (*gmail.Message, error) {
+	msg, ok := f.messages[id]
+	if !ok {
+		return nil, fmt.Errorf("messages.Get failed: %w", gservice.ErrNotFound)
+	}
+	return msg, nil
+}
+
+func (f *fakeGmail) GetMessagesMetadata(ctx context.Context, ids []string) ([]*gmail.Message, error) {
+	var messages []*gmail.Message
+	for _, id := range ids {
+		msg, err := f.GetMessageMetadata(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func fakeMessage(id string, date int64, labels ...string) *gmail.Message {
+	return &gmail.Message{
+		Id:           id,
+		ThreadId:     "t-" + id,
+		LabelIds:     labels,
+		InternalDate: date,
+		Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+			{Name: "From", Value: "alice@example.com"},
+			{Name: "Subject", Value: "Subject " + id},
+		}},
+	}
+}
+
+func messageIDs(messages []Message) []string {
+	var ids []string
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
+
+func TestEngineSync(t *testing.T) {
+	svc := &fakeGmail{
+		historyID: 100,
+		messages: map[string]*gmail.Message{
+			"m1": fakeMessage("m1", 1000, "INBOX"),
+			"m2": fakeMessage("m2", 2000, "INBOX", "UNREAD"),
+		},
+	}
+	path := filepath.Join(t.TempDir(), "sync.json")
+	store, err := NewStore(path)
+	require.NoError(t, err)
+	engine := NewEngine(svc, store, 0)
+	ctx := context.Background()
+
+	result, err := engine.Sync(ctx, false)
+	require.NoError(t, err)
+	assert.True(t, result.Full, "the first pass lists the mailbox")
+	assert.Equal(t, 2, result.Added)
+	assert.Equal(t, uint64(100), store.HistoryID())
+	assert.Equal(t, []string{"m2", "m1"}, messageIDs(store.Messages()), "newest first")
+	m2, ok := store.Message("m2")
+	require.True(t, ok)
+	assert.Equal(t, "Subject m2", m2.Subject)
+	assert.Equal(t, "alice@example.com", m2.From)
+
+	// m3 arrives, m2 is read, m1 is deleted, m4 comes and goes.
+	svc.historyID = 110
+	svc.messages["m3"] = fakeMessage("m3", 3000, "INBOX")
+	delete(svc.messages, "m1")
+	svc.history = []*gmail.History{
+		{Id: 101, MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "m3"}}}},
+		{Id: 102, LabelsRemoved: []*gmail.HistoryLabelRemoved{{Message: &gmail.Message{Id: "m2", LabelIds: []string{"INBOX"}}}}},
+		{Id: 103, MessagesDeleted: []*gmail.HistoryMessageDeleted{{Message: &gmail.Message{Id: "m1"}}}},
+		{Id: 104, MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "m4"}}}},
+		{Id: 105, MessagesDeleted: []*gmail.HistoryMessageDeleted{{Message: &gmail.Message{Id: "m4"}}}},
+	}
+
+	result, err = engine.Sync(ctx, false)
+	require.NoError(t, err)
+	assert.False(t, result.Full)
+	assert.Equal(t, 1, result.Added)
+	assert.Equal(t, 1, result.LabelChanges)
+	assert.Equal(t, uint64(110), result.HistoryID)
+	assert.Equal(t, []string{"m3", "m2"}, messageIDs(store.Messages()))
+	m2, _ = store.Message("m2")
+	assert.False(t, slices.Contains(m2.LabelIDs, "UNREAD"))
+
+	reloaded, err := NewStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(110), reloaded.HistoryID())
+	assert.Equal(t, store.Messages(), reloaded.Messages())
+
+	// Gmail keeps history for about a week; an expired start lists the mailbox again.
+	svc.expired = true
+	svc.historyID = 200
+	result, err = engine.Sync(ctx, false)
+	require.NoError(t, err)
+	assert.True(t, result.Full)
+	assert.Equal(t, uint64(200), store.HistoryID())
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestEngineSyncLimit(t *testing.T) {
+	svc := &fakeGmail{historyID: 1, messages: map[string]*gmail.Message{}}
+	for i := range 5 {
+		id := fmt.Sprintf("m%d", i)
+		svc.messages[id] = fakeMessage(id, int64(i))
+	}
+	store, err := NewStore("")
+	require.NoError(t, err)
+
+	_, err = NewEngine(svc, store, 3).Sync(context.Background(), true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.Len())
+}
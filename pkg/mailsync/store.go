@@ -0,0 +1,183 @@
+// Package mailsync keeps a local snapshot of the mailbox up to date through the Gmail History
+// API, so searches, digests and change notifications can work without listing the mailbox.
+package mailsync
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hal9000y/gmail-mcp/internal/jsonfile"
+)
+
+// Message is the snapshot of one message: its identity, labels and headers.
+type Message struct {
+	ID       string   `json:"id"`
+	ThreadID string   `json:"thread_id"`
+	LabelIDs []string `json:"label_ids,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       string   `json:"to,omitempty"`
+	Subject  string   `json:"subject,omitempty"`
+	Snippet  string   `json:"snippet,omitempty"`
+	// InternalDate is when Gmail received the message in unix milliseconds.
+	InternalDate int64 `json:"internal_date"`
+	SizeEstimate int64 `json:"size_estimate,omitempty"`
+}
+
+type snapshot struct {
+	HistoryID uint64    `json:"history_id"`
+	SyncedAt  time.Time `json:"synced_at"`
+	Messages  []Message `json:"messages"`
+}
+
+// Store keeps the snapshot in memory and mirrors every change to a JSON file.
+type Store struct {
+	mu          sync.RWMutex
+	persistPath string
+	historyID   uint64
+	syncedAt    time.Time
+	messages    map[string]Message
+}
+
+// NewStore creates a Store, loading the snapshot from disk if path provided.
+// An empty path keeps the snapshot in memory only.
+func NewStore(persistPath string) (*Store, error) {
+	s := &Store{
+		persistPath: persistPath,
+		messages:    make(map[string]Message),
+	}
+	if persistPath == "" {
+		return s, nil
+	}
+
+	var snap snapshot
+	if err := jsonfile.Load(persistPath, &snap); err != nil {
+		return nil, fmt.Errorf("jsonfile.Load failed: %w", err)
+	}
+	s.historyID = snap.HistoryID
+	s.syncedAt = snap.SyncedAt
+	for _, msg := range snap.Messages {
+		s.messages[msg.ID] = msg
+	}
+
+	return s, nil
+}
+
+// HistoryID returns the history ID the snapshot is current at, zero before the first sync.
+func (s *Store) HistoryID() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.historyID
+}
+
+// SyncedAt returns when the snapshot was last synced.
+func (s *Store) SyncedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.syncedAt
+}
+
+// Len returns the number of messages in the snapshot.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.messages)
+}
+
+// Message returns the snapshot of a message and whether it exists.
+func (s *Store) Message(id string) (Message, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	msg, ok := s.messages[id]
+	return msg, ok
+}
+
+// Messages returns the messages of the snapshot, newest first.
+func (s *Store) Messages() []Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted()
+}
+
+// change is a set of updates applied to the snapshot at once.
+type change struct {
+	historyID uint64
+	syncedAt  time.Time
+	// replace drops all messages before applying the change.
+	replace bool
+	added   []Message
+	deleted []string
+	labels  map[string][]string
+	// limit bounds the messages kept, dropping the oldest.
+	limit int
+}
+
+// apply updates the snapshot and persists it. On failure the snapshot is left unchanged.
+func (s *Store) apply(c change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make(map[string]Message, len(s.messages)+len(c.added))
+	if !c.replace {
+		for id, msg := range s.messages {
+			messages[id] = msg
+		}
+	}
+	for id, labelIDs := range c.labels {
+		if msg, ok := messages[id]; ok {
+			msg.LabelIDs = labelIDs
+			messages[id] = msg
+		}
+	}
+	for _, msg := range c.added {
+		messages[msg.ID] = msg
+	}
+	for _, id := range c.deleted {
+		delete(messages, id)
+	}
+
+	prevHistoryID, prevSyncedAt, prevMessages := s.historyID, s.syncedAt, s.messages
+	s.historyID, s.syncedAt, s.messages = c.historyID, c.syncedAt, messages
+	if c.limit > 0 && len(messages) > c.limit {
+		for _, msg := range s.sorted()[c.limit:] {
+			delete(messages, msg.ID)
+		}
+	}
+
+	if err := s.persist(); err != nil {
+		s.historyID, s.syncedAt, s.messages = prevHistoryID, prevSyncedAt, prevMessages
+		return fmt.Errorf("persist failed: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) sorted() []Message {
+	messages := make([]Message, 0, len(s.messages))
+	for _, msg := range s.messages {
+		messages = append(messages, msg)
+	}
+	slices.SortFunc(messages, func(a, b Message) int {
+		return cmp.Or(cmp.Compare(b.InternalDate, a.InternalDate), cmp.Compare(a.ID, b.ID))
+	})
+	return messages
+}
+
+// persist writes the snapshot, replacing its file atomically. It isn't indented, as it holds
+// the whole mailbox.
+func (s *Store) persist() error {
+	if s.persistPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(snapshot{HistoryID: s.historyID, SyncedAt: s.syncedAt, Messages: s.sorted()})
+	if err != nil {
+		return fmt.Errorf("json.Marshal failed: %w", err)
+	}
+	if err := jsonfile.Write(s.persistPath, data); err != nil {
+		return fmt.Errorf("jsonfile.Write failed: %w", err)
+	}
+	return nil
+}
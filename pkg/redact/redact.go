@@ -0,0 +1,171 @@
+// Package redact masks personal data in text before it leaves the server.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Kinds of personal data a Redactor recognizes.
+const (
+	KindEmail = "email"
+	KindPhone = "phone"
+	KindCard  = "card"
+	KindIBAN  = "iban"
+)
+
+// Kinds lists the built-in kinds in the order they are applied.
+var Kinds = []string{KindEmail, KindCard, KindIBAN, KindPhone}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	ibanPattern  = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`)
+	phonePattern = regexp.MustCompile(`(?:\+|\b)\d[\d ().\-/]{5,}\d\b`)
+	datePattern  = regexp.MustCompile(`^\d{1,4}[./\-]\d{1,2}[./\-]\d{1,4}$`)
+)
+
+type rule struct {
+	label   string
+	pattern *regexp.Regexp
+	// valid filters matches, like card numbers failing the Luhn check.
+	valid func(match string) bool
+}
+
+// Redactor replaces personal data in text with placeholders like "[redacted email]".
+type Redactor struct {
+	rules []rule
+}
+
+// New creates a Redactor for the given built-in kinds and custom regular expressions, whose
+// matches become "[redacted]". It returns nil when there is nothing to redact.
+func New(kinds, patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, kind := range kinds {
+		switch kind = strings.ToLower(strings.TrimSpace(kind)); kind {
+		case "":
+		case KindEmail:
+			r.rules = append(r.rules, rule{label: KindEmail, pattern: emailPattern})
+		case KindCard:
+			r.rules = append(r.rules, rule{label: KindCard, pattern: cardPattern, valid: validCard})
+		case KindIBAN:
+			r.rules = append(r.rules, rule{label: KindIBAN, pattern: ibanPattern, valid: validIBAN})
+		case KindPhone:
+			r.rules = append(r.rules, rule{label: KindPhone, pattern: phonePattern, valid: validPhone})
+		default:
+			return nil, fmt.Errorf("unknown redaction kind %q, want one of %s", kind, strings.Join(Kinds, ", "))
+		}
+	}
+	// Card and account numbers go before phone numbers, which would match them too.
+	r.rules = sortRules(r.rules)
+
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.rules = append(r.rules, rule{pattern: re})
+	}
+
+	if len(r.rules) == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+func sortRules(rules []rule) []rule {
+	sorted := make([]rule, 0, len(rules))
+	for _, kind := range Kinds {
+		for _, r := range rules {
+			if r.label == kind {
+				sorted = append(sorted, r)
+				break
+			}
+		}
+	}
+	return sorted
+}
+
+// Redact returns text with personal data replaced. A nil Redactor returns text unchanged.
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	for _, rule := range r.rules {
+		placeholder := "[redacted]"
+		if rule.label != "" {
+			placeholder = "[redacted " + rule.label + "]"
+		}
+		text = rule.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if rule.valid != nil && !rule.valid(match) {
+				return match
+			}
+			return placeholder
+		})
+	}
+	return text
+}
+
+func digits(s string) []int {
+	var out []int
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			out = append(out, int(c-'0'))
+		}
+	}
+	return out
+}
+
+// validCard applies the Luhn checksum card numbers carry.
+func validCard(match string) bool {
+	d := digits(match)
+	if len(d) < 13 || len(d) > 19 {
+		return false
+	}
+	sum := 0
+	for i := range d {
+		n := d[len(d)-1-i]
+		if i%2 == 1 {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+	}
+	return sum%10 == 0
+}
+
+// validIBAN applies the ISO 13616 mod 97 check.
+func validIBAN(match string) bool {
+	iban := strings.ReplaceAll(match, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, c := range rearranged {
+		var value int
+		switch {
+		case c >= '0' && c <= '9':
+			value = int(c - '0')
+		case c >= 'A' && c <= 'Z':
+			value = int(c-'A') + 10
+		default:
+			return false
+		}
+		if value >= 10 {
+			remainder = (remainder*100 + value) % 97
+		} else {
+			remainder = (remainder*10 + value) % 97
+		}
+	}
+	return remainder == 1
+}
+
+// validPhone accepts 7 to 15 digits, the E.164 range, and rejects dates.
+func validPhone(match string) bool {
+	n := len(digits(match))
+	return n >= 7 && n <= 15 && !datePattern.MatchString(match)
+}
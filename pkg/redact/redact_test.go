@@ -0,0 +1,72 @@
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/redact"
+)
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		name     string
+		kinds    []string
+		patterns []string
+		input    string
+		expected string
+	}{
+		{
+			name:     "email",
+			kinds:    []string{"email"},
+			input:    "Write to jane.doe+work@mail.example.co.uk today.",
+			expected: "Write to [redacted email] today.",
+		},
+		{
+			name:     "phone numbers but not dates",
+			kinds:    []string{"phone"},
+			input:    "Call +1 (415) 555-0132 or 030 1234567 before 2026-01-05, room 12.",
+			expected: "Call [redacted phone] or [redacted phone] before 2026-01-05, room 12.",
+		},
+		{
+			name:     "card numbers pass the Luhn check",
+			kinds:    []string{"card", "phone"},
+			input:    "Card 4111-1111-1111-1111, order 4111 1111 1111 1112.",
+			expected: "Card [redacted card], order 4111 1111 1111 1112.",
+		},
+		{
+			name:     "iban",
+			kinds:    []string{"IBAN"},
+			input:    "Pay to DE89 3704 0044 0532 0130 00 or GB00WEST12345698765432.",
+			expected: "Pay to [redacted iban] or GB00WEST12345698765432.",
+		},
+		{
+			name:     "custom pattern",
+			patterns: []string{`EMP-\d{5}`},
+			input:    "Employee EMP-12345 joined.",
+			expected: "Employee [redacted] joined.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := redact.New(tc.kinds, tc.patterns)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, r.Redact(tc.input))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	r, err := redact.New([]string{""}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, r)
+	assert.Equal(t, "jane@example.com", r.Redact("jane@example.com"))
+
+	_, err = redact.New([]string{"ssn"}, nil)
+	require.EqualError(t, err, `unknown redaction kind "ssn", want one of email, card, iban, phone`)
+
+	_, err = redact.New(nil, []string{"("})
+	require.ErrorContains(t, err, `invalid redaction pattern "("`)
+}
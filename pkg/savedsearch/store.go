@@ -0,0 +1,140 @@
+// Package savedsearch stores named Gmail search queries with file persistence.
+package savedsearch
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hal9000y/gmail-mcp/internal/jsonfile"
+)
+
+// ErrNotFound indicates no saved search exists under the requested name.
+var ErrNotFound = errors.New("saved search not found")
+
+// Search is a named Gmail query.
+type Search struct {
+	Name        string `json:"name"`
+	Query       string `json:"query"`
+	Description string `json:"description,omitempty"`
+}
+
+// Store keeps saved searches in memory and mirrors every change to a JSON file.
+type Store struct {
+	mu          sync.RWMutex
+	persistPath string
+	searches    map[string]Search
+}
+
+// NewStore creates a Store, loading searches from disk if path provided.
+// An empty path keeps searches in memory only.
+func NewStore(persistPath string) (*Store, error) {
+	s := &Store{
+		persistPath: persistPath,
+		searches:    make(map[string]Search),
+	}
+	if persistPath == "" {
+		return s, nil
+	}
+
+	var searches []Search
+	if err := jsonfile.Load(persistPath, &searches); err != nil {
+		return nil, fmt.Errorf("jsonfile.Load failed: %w", err)
+	}
+	for _, search := range searches {
+		s.searches[search.Name] = search
+	}
+
+	return s, nil
+}
+
+// Save creates or replaces a saved search, persists the store and returns the stored search.
+func (s *Store) Save(search Search) (Search, error) {
+	search.Name = strings.TrimSpace(search.Name)
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Name == "" {
+		return Search{}, errors.New("name is required")
+	}
+	if search.Query == "" {
+		return Search{}, errors.New("query is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.searches[search.Name]
+	s.searches[search.Name] = search
+
+	if err := s.persist(); err != nil {
+		if existed {
+			s.searches[search.Name] = previous
+		} else {
+			delete(s.searches, search.Name)
+		}
+		return Search{}, fmt.Errorf("persist failed: %w", err)
+	}
+
+	return search, nil
+}
+
+// Get returns the saved search with the given name.
+func (s *Store) Get(name string) (Search, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	search, ok := s.searches[name]
+	if !ok {
+		return Search{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	return search, nil
+}
+
+// List returns all saved searches ordered by name.
+func (s *Store) List() []Search {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sorted()
+}
+
+// Delete removes a saved search and persists the store.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	search, ok := s.searches[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(s.searches, name)
+
+	if err := s.persist(); err != nil {
+		s.searches[name] = search
+		return fmt.Errorf("persist failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) sorted() []Search {
+	return slices.SortedFunc(maps.Values(s.searches), func(a, b Search) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+}
+
+// persist writes the store, replacing its file atomically.
+func (s *Store) persist() error {
+	if s.persistPath == "" {
+		return nil
+	}
+
+	if err := jsonfile.Save(s.persistPath, s.sorted()); err != nil {
+		return fmt.Errorf("jsonfile.Save failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package savedsearch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/savedsearch"
+)
+
+func TestStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "saved-searches.json")
+
+	store, err := savedsearch.NewStore(path)
+	require.NoError(t, err)
+	assert.Empty(t, store.List())
+
+	saved, err := store.Save(savedsearch.Search{Name: " weekly-invoices ", Query: " has:attachment subject:invoice newer_than:7d "})
+	require.NoError(t, err)
+	assert.Equal(t, savedsearch.Search{Name: "weekly-invoices", Query: "has:attachment subject:invoice newer_than:7d"}, saved)
+
+	_, err = store.Save(savedsearch.Search{Name: "boss", Query: "from:boss@example.com is:unread", Description: "unread mail from the boss"})
+	require.NoError(t, err)
+	require.NoError(t, store.Delete("boss"))
+	_, err = store.Save(savedsearch.Search{Name: "alerts", Query: "from:alerts@example.com"})
+	require.NoError(t, err)
+
+	reloaded, err := savedsearch.NewStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, []savedsearch.Search{
+		{Name: "alerts", Query: "from:alerts@example.com"},
+		{Name: "weekly-invoices", Query: "has:attachment subject:invoice newer_than:7d"},
+	}, reloaded.List())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should not be left behind")
+}
+
+func TestStoreErrors(t *testing.T) {
+	store, err := savedsearch.NewStore("")
+	require.NoError(t, err)
+
+	_, err = store.Save(savedsearch.Search{Name: "empty"})
+	assert.EqualError(t, err, "query is required")
+
+	_, err = store.Save(savedsearch.Search{Query: "in:inbox"})
+	assert.EqualError(t, err, "name is required")
+
+	_, err = store.Get("missing")
+	assert.ErrorIs(t, err, savedsearch.ErrNotFound)
+
+	assert.ErrorIs(t, store.Delete("missing"), savedsearch.ErrNotFound)
+
+	path := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	_, err = savedsearch.NewStore(path)
+	assert.Error(t, err)
+}
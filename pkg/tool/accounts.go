@@ -0,0 +1,331 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+)
+
+// accountParam is the tool parameter selecting the account of a call.
+const accountParam = "account"
+
+// accountSvc is the service of one account.
+type accountSvc interface {
+	gmailSvc
+	calendarSvc
+	driveSvc
+}
+
+// Account is a Gmail account tool calls can be routed to.
+type Account struct {
+	// Name is the value of the account parameter selecting the account, e.g. "work".
+	Name string
+	Svc  accountSvc
+}
+
+// NewAccounts routes the calls of tools to the account named by their account parameter, the
+// first account when it is omitted.
+func NewAccounts(accounts ...Account) (*Accounts, error) {
+	if len(accounts) == 0 {
+		return nil, errors.New("no accounts")
+	}
+	a := &Accounts{byName: make(map[string]accountSvc, len(accounts))}
+	for _, account := range accounts {
+		if account.Name == "" || strings.TrimSpace(account.Name) != account.Name {
+			return nil, fmt.Errorf("invalid account name %q", account.Name)
+		}
+		if _, ok := a.byName[account.Name]; ok {
+			return nil, fmt.Errorf("duplicate account %q", account.Name)
+		}
+		a.byName[account.Name] = account.Svc
+		a.names = append(a.names, account.Name)
+	}
+	return a, nil
+}
+
+// Accounts routes Gmail, Calendar and Drive calls to the account selected by the tool call.
+type Accounts struct {
+	names  []string
+	byName map[string]accountSvc
+}
+
+type accountKey struct{}
+
+// selectedAccount returns the account a tool call selected, empty for the default account.
+func selectedAccount(ctx context.Context) string {
+	name, _ := ctx.Value(accountKey{}).(string)
+	return name
+}
+
+func (a *Accounts) svc(ctx context.Context) accountSvc {
+	if svc, ok := a.byName[selectedAccount(ctx)]; ok {
+		return svc
+	}
+	return a.byName[a.names[0]]
+}
+
+// middleware documents the account parameter in the input schemas of listed tools and takes it
+// out of the arguments of tool calls, which are validated against the schemas of the tools
+// without it, into the context.
+func (a *Accounts) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		switch method {
+		case "tools/list":
+			res, err := next(ctx, method, req)
+			if result, ok := res.(*mcp.ListToolsResult); ok && err == nil {
+				a.addParam(result)
+			}
+			return res, err
+		case "tools/call":
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok {
+				break
+			}
+			name, err := a.takeParam(call.Params)
+			if err != nil {
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}, IsError: true}, nil
+			}
+			if name != "" && name != a.names[0] {
+				ctx = context.WithValue(ctx, accountKey{}, name)
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// accountless are the tools without an account parameter.
+var accountless = []string{"list_accounts", "sync_mailbox"}
+
+// addParam adds the account parameter to copies of the listed tools, whose schemas are shared
+// with the server.
+func (a *Accounts) addParam(result *mcp.ListToolsResult) {
+	param := &jsonschema.Schema{
+		Type:        "string",
+		Description: fmt.Sprintf("account to use, one of %s; default %s", strings.Join(a.names, ", "), a.names[0]),
+		Enum:        make([]any, 0, len(a.names)),
+	}
+	for _, name := range a.names {
+		param.Enum = append(param.Enum, name)
+	}
+
+	for i, t := range result.Tools {
+		if t.InputSchema == nil || slices.Contains(accountless, t.Name) {
+			continue
+		}
+		schema := *t.InputSchema
+		schema.Properties = maps.Clone(schema.Properties)
+		if schema.Properties == nil {
+			schema.Properties = map[string]*jsonschema.Schema{}
+		}
+		schema.Properties[accountParam] = param
+		withParam := *t
+		withParam.InputSchema = &schema
+		result.Tools[i] = &withParam
+	}
+}
+
+// takeParam removes the account parameter from the arguments and returns its value.
+func (a *Accounts) takeParam(params *mcp.CallToolParamsRaw) (string, error) {
+	if params == nil || len(params.Arguments) == 0 || slices.Contains(accountless, params.Name) {
+		return "", nil
+	}
+	var args map[string]json.RawMessage
+	if json.Unmarshal(params.Arguments, &args) != nil {
+		// Arguments that aren't an object are left for the tool to reject.
+		return "", nil
+	}
+	raw, ok := args[accountParam]
+	if !ok {
+		return "", nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return "", fmt.Errorf("invalid account: %w", err)
+	}
+	if name != "" {
+		if _, ok := a.byName[name]; !ok {
+			return "", fmt.Errorf("unknown account %q, expected one of %s", name, strings.Join(a.names, ", "))
+		}
+	}
+
+	delete(args, accountParam)
+	arguments, err := json.Marshal(args)
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal failed: %w", err)
+	}
+	params.Arguments = arguments
+	return name, nil
+}
+
+// ListAccountsRequest has no parameters.
+type ListAccountsRequest struct{}
+
+// ListAccountsResponse lists the configured accounts.
+type ListAccountsResponse struct {
+	Accounts []AccountInfo `json:"accounts" jsonschema:"configured accounts, the default first"`
+}
+
+// AccountInfo describes an account.
+type AccountInfo struct {
+	Name    string `json:"name" jsonschema:"value of the account parameter of other tools"`
+	Default bool   `json:"default,omitempty" jsonschema:"true for the account used when the account parameter is omitted"`
+	Email   string `json:"email,omitempty" jsonschema:"address of the mailbox"`
+	Error   string `json:"error,omitempty" jsonschema:"why the mailbox couldn't be read, e.g. the account isn't authorized yet"`
+}
+
+// ListAccounts lists the accounts with the address of their mailbox.
+func (a *Accounts) ListAccounts(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	_ ListAccountsRequest,
+) (*mcp.CallToolResult, ListAccountsResponse, error) {
+	response := ListAccountsResponse{Accounts: make([]AccountInfo, 0, len(a.names))}
+	for i, name := range a.names {
+		info := AccountInfo{Name: name, Default: i == 0}
+		profile, err := a.byName[name].GetProfile(ctx)
+		if err != nil {
+			info.Error = fmt.Errorf("svc.GetProfile failed: %w", err).Error()
+		} else {
+			info.Email = profile.EmailAddress
+		}
+		response.Accounts = append(response.Accounts, info)
+	}
+	return nil, response, nil
+}
+
+// CreateLabel creates a label in the selected account.
+func (a *Accounts) CreateLabel(ctx context.Context, name string) (*gmail.Label, error) {
+	return a.svc(ctx).CreateLabel(ctx, name)
+}
+
+// DeleteLabel deletes a label of the selected account.
+func (a *Accounts) DeleteLabel(ctx context.Context, labelID string) error {
+	return a.svc(ctx).DeleteLabel(ctx, labelID)
+}
+
+// GetAttachment retrieves an attachment of the selected account.
+func (a *Accounts) GetAttachment(ctx context.Context, msgID, attachmentID string) (*gmail.MessagePartBody, error) {
+	return a.svc(ctx).GetAttachment(ctx, msgID, attachmentID)
+}
+
+// GetLabel retrieves a label of the selected account.
+func (a *Accounts) GetLabel(ctx context.Context, labelID string) (*gmail.Label, error) {
+	return a.svc(ctx).GetLabel(ctx, labelID)
+}
+
+// GetMessage retrieves a message of the selected account.
+func (a *Accounts) GetMessage(ctx context.Context, msgID string) (*gmail.Message, error) {
+	return a.svc(ctx).GetMessage(ctx, msgID)
+}
+
+// GetMessageMetadata retrieves message metadata of the selected account.
+func (a *Accounts) GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error) {
+	return a.svc(ctx).GetMessageMetadata(ctx, msgID)
+}
+
+// GetMessageRaw retrieves a raw message of the selected account.
+func (a *Accounts) GetMessageRaw(ctx context.Context, msgID string) (*gmail.Message, error) {
+	return a.svc(ctx).GetMessageRaw(ctx, msgID)
+}
+
+// GetMessages retrieves messages of the selected account.
+func (a *Accounts) GetMessages(ctx context.Context, msgIDs []string) ([]*gmail.Message, error) {
+	return a.svc(ctx).GetMessages(ctx, msgIDs)
+}
+
+// GetMessagesMetadata retrieves message metadata of the selected account.
+func (a *Accounts) GetMessagesMetadata(ctx context.Context, msgIDs []string) ([]*gmail.Message, error) {
+	return a.svc(ctx).GetMessagesMetadata(ctx, msgIDs)
+}
+
+// GetProfile retrieves the mailbox profile of the selected account.
+func (a *Accounts) GetProfile(ctx context.Context) (*gmail.Profile, error) {
+	return a.svc(ctx).GetProfile(ctx)
+}
+
+// GetThread retrieves a thread of the selected account.
+func (a *Accounts) GetThread(ctx context.Context, threadID string) (*gmail.Thread, error) {
+	return a.svc(ctx).GetThread(ctx, threadID)
+}
+
+// GetThreadMetadata retrieves thread metadata of the selected account.
+func (a *Accounts) GetThreadMetadata(ctx context.Context, threadID string) (*gmail.Thread, error) {
+	return a.svc(ctx).GetThreadMetadata(ctx, threadID)
+}
+
+// ListHistory lists mailbox changes of the selected account.
+func (a *Accounts) ListHistory(ctx context.Context, startHistoryID uint64, labelID, pageToken string) (*gmail.ListHistoryResponse, error) {
+	return a.svc(ctx).ListHistory(ctx, startHistoryID, labelID, pageToken)
+}
+
+// ListLabelMessages lists labeled messages of the selected account.
+func (a *Accounts) ListLabelMessages(ctx context.Context, labelID, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+	return a.svc(ctx).ListLabelMessages(ctx, labelID, pageToken, maxResults)
+}
+
+// ListLabels lists the labels of the selected account.
+func (a *Accounts) ListLabels(ctx context.Context) ([]*gmail.Label, error) {
+	return a.svc(ctx).ListLabels(ctx)
+}
+
+// ListMailboxHistory lists every mailbox change of the selected account.
+func (a *Accounts) ListMailboxHistory(ctx context.Context, startHistoryID uint64, pageToken string) (*gmail.ListHistoryResponse, error) {
+	return a.svc(ctx).ListMailboxHistory(ctx, startHistoryID, pageToken)
+}
+
+// ListMessages searches messages of the selected account.
+func (a *Accounts) ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+	return a.svc(ctx).ListMessages(ctx, Q, pageToken, maxResults)
+}
+
+// ModifyThread changes the labels of a thread of the selected account.
+func (a *Accounts) ModifyThread(ctx context.Context, threadID string, addLabelIDs, removeLabelIDs []string) (*gmail.Thread, error) {
+	return a.svc(ctx).ModifyThread(ctx, threadID, addLabelIDs, removeLabelIDs)
+}
+
+// RenameLabel renames a label of the selected account.
+func (a *Accounts) RenameLabel(ctx context.Context, labelID, name string) (*gmail.Label, error) {
+	return a.svc(ctx).RenameLabel(ctx, labelID, name)
+}
+
+// ListEvents lists calendar events of the selected account.
+func (a *Accounts) ListEvents(ctx context.Context, calendarID string, timeMin, timeMax time.Time, Q string, maxResults int64) (*calendar.Events, error) {
+	return a.svc(ctx).ListEvents(ctx, calendarID, timeMin, timeMax, Q, maxResults)
+}
+
+// CreateEvent adds a calendar event to the selected account.
+func (a *Accounts) CreateEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error) {
+	return a.svc(ctx).CreateEvent(ctx, calendarID, event)
+}
+
+// ImportEvent imports a calendar event into the selected account.
+func (a *Accounts) ImportEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error) {
+	return a.svc(ctx).ImportEvent(ctx, calendarID, event)
+}
+
+// GetDriveFile retrieves a Drive file of the selected account.
+func (a *Accounts) GetDriveFile(ctx context.Context, fileID string) (*drive.File, error) {
+	return a.svc(ctx).GetDriveFile(ctx, fileID)
+}
+
+// ExportDriveFile exports a Drive file of the selected account.
+func (a *Accounts) ExportDriveFile(ctx context.Context, fileID, mimeType string, maxBytes int64) ([]byte, error) {
+	return a.svc(ctx).ExportDriveFile(ctx, fileID, mimeType, maxBytes)
+}
+
+// DownloadDriveFile downloads a Drive file of the selected account.
+func (a *Accounts) DownloadDriveFile(ctx context.Context, fileID string, maxBytes int64) ([]byte, error) {
+	return a.svc(ctx).DownloadDriveFile(ctx, fileID, maxBytes)
+}
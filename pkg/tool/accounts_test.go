@@ -0,0 +1,109 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+type accountSvcMock struct {
+	*gmailSvcMock
+	*calendarSvcMock
+	*driveSvcMock
+}
+
+func newAccountSvcMock(email string, estimate int64) accountSvcMock {
+	return accountSvcMock{
+		gmailSvcMock: &gmailSvcMock{
+			GetProfileFunc: func(_ context.Context) (*gmail.Profile, error) {
+				return &gmail.Profile{EmailAddress: email}, nil
+			},
+			ListMessagesFunc: func(_ context.Context, _, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+				return &gmail.ListMessagesResponse{ResultSizeEstimate: estimate}, nil
+			},
+		},
+		calendarSvcMock: &calendarSvcMock{},
+		driveSvcMock:    &driveSvcMock{},
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	work := newAccountSvcMock("me@work.example", 3)
+	personal := newAccountSvcMock("me@home.example", 7)
+	accounts, err := tool.NewAccounts(tool.Account{Name: "work", Svc: work}, tool.Account{Name: "personal", Svc: personal})
+	require.NoError(t, err)
+
+	server := tool.NewServer(work, &converterMock{}, tool.WithAccounts(accounts))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	listed, err := clientSession.ListTools(ctx, nil)
+	require.NoError(t, err)
+	schemas := map[string]map[string]any{}
+	for _, listedTool := range listed.Tools {
+		data, err := json.Marshal(listedTool.InputSchema)
+		require.NoError(t, err)
+		var schema map[string]any
+		require.NoError(t, json.Unmarshal(data, &schema))
+		schemas[listedTool.Name] = schema
+	}
+	require.Contains(t, schemas, "list_accounts")
+	param := schemas["count_messages"]["properties"].(map[string]any)["account"].(map[string]any)
+	assert.Equal(t, []any{"work", "personal"}, param["enum"])
+	assert.NotContains(t, schemas["sync_mailbox"]["properties"], "account", "the snapshot is of the default account")
+
+	count := func(args map[string]any) *mcp.CallToolResult {
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "count_messages", Arguments: args})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := count(map[string]any{"query": "is:unread"})
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `"estimated_count":3`)
+
+	result = count(map[string]any{"query": "is:unread", "account": "personal"})
+	require.False(t, result.IsError, result.Content)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `"estimated_count":7`)
+	assert.Len(t, personal.ListMessagesCalls(), 1)
+
+	result = count(map[string]any{"query": "is:unread", "account": "shared"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `unknown account "shared"`)
+
+	result, err = clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "list_accounts", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	var response tool.ListAccountsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	assert.Equal(t, []tool.AccountInfo{
+		{Name: "work", Default: true, Email: "me@work.example"},
+		{Name: "personal", Email: "me@home.example"},
+	}, response.Accounts)
+}
+
+func TestNewAccountsInvalid(t *testing.T) {
+	svc := newAccountSvcMock("me@example.com", 0)
+
+	_, err := tool.NewAccounts()
+	assert.Error(t, err)
+	_, err = tool.NewAccounts(tool.Account{Name: "", Svc: svc})
+	assert.Error(t, err)
+	_, err = tool.NewAccounts(tool.Account{Name: "work", Svc: svc}, tool.Account{Name: "work", Svc: svc})
+	assert.ErrorContains(t, err, "duplicate")
+}
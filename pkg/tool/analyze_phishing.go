@@ -0,0 +1,359 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+const (
+	severityLow    = "low"
+	severityMedium = "medium"
+	severityHigh   = "high"
+
+	indicatorLookalikeDomain = "lookalike_domain"
+	indicatorDisplayName     = "display_name_mismatch"
+	indicatorReplyTo         = "reply_to_mismatch"
+	indicatorLinkMismatch    = "link_text_mismatch"
+	indicatorUrgentLanguage  = "urgent_language"
+	indicatorAuthFailure     = "auth_failure"
+)
+
+var (
+	severityScores = map[string]int{severityLow: 10, severityMedium: 25, severityHigh: 40}
+
+	// Brands commonly impersonated in credential phishing, matched against display names and domains.
+	impersonatedBrands = []struct{ marker, domain string }{
+		{"paypal", "paypal.com"},
+		{"google", "google.com"},
+		{"microsoft", "microsoft.com"},
+		{"apple", "apple.com"},
+		{"amazon", "amazon.com"},
+		{"netflix", "netflix.com"},
+		{"facebook", "facebook.com"},
+		{"instagram", "instagram.com"},
+		{"linkedin", "linkedin.com"},
+		{"dropbox", "dropbox.com"},
+		{"docusign", "docusign.net"},
+		{"dhl", "dhl.com"},
+		{"fedex", "fedex.com"},
+	}
+
+	urgentPhrases = []string{
+		"urgent", "immediately", "within 24 hours", "account will be suspended", "account has been suspended",
+		"verify your account", "confirm your identity", "unusual activity", "unusual sign-in", "final notice",
+		"action required", "password will expire", "payment failed", "wire transfer", "gift card",
+	}
+
+	domainLikePattern = regexp.MustCompile(`(?i)^(https?://)?([a-z0-9-]+\.)+[a-z]{2,}(/\S*)?$`)
+	embeddedAddress   = regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+	homoglyphs = strings.NewReplacer("0", "o", "1", "l", "rn", "m", "vv", "w", "3", "e", "5", "s")
+)
+
+// AnalyzePhishingRequest contains the message to analyze.
+type AnalyzePhishingRequest struct {
+	MessageID string `json:"message_id" jsonschema:"ID of the message to analyze"`
+}
+
+// AnalyzePhishingResponse is a structured phishing risk report.
+type AnalyzePhishingResponse struct {
+	MessageID  string              `json:"message_id" jsonschema:"message ID"`
+	From       EmailAddress        `json:"from" jsonschema:"sender information"`
+	RiskScore  int                 `json:"risk_score" jsonschema:"heuristic risk score from 0 to 100"`
+	RiskLevel  string              `json:"risk_level" jsonschema:"low, medium or high"`
+	Indicators []PhishingIndicator `json:"indicators" jsonschema:"detected phishing indicators"`
+}
+
+// PhishingIndicator is a single suspicious finding.
+type PhishingIndicator struct {
+	Type     string `json:"type" jsonschema:"indicator type, e.g. lookalike_domain, link_text_mismatch"`
+	Severity string `json:"severity" jsonschema:"low, medium or high"`
+	Detail   string `json:"detail" jsonschema:"human readable explanation"`
+}
+
+type analyzePhishingSvc interface {
+	GetMessage(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewAnalyzePhishing creates a new AnalyzePhishing tool.
+func NewAnalyzePhishing(svc analyzePhishingSvc) *AnalyzePhishing {
+	return &AnalyzePhishing{
+		svc: svc,
+	}
+}
+
+// AnalyzePhishing scores messages against common phishing heuristics.
+type AnalyzePhishing struct {
+	svc analyzePhishingSvc
+}
+
+// AnalyzePhishing returns a risk report for a single message.
+func (t *AnalyzePhishing) AnalyzePhishing(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input AnalyzePhishingRequest,
+) (*mcp.CallToolResult, AnalyzePhishingResponse, error) {
+	if input.MessageID == "" {
+		return nil, AnalyzePhishingResponse{}, errors.New("message_id is required")
+	}
+
+	msg, err := t.svc.GetMessage(ctx, input.MessageID)
+	if err != nil {
+		return nil, AnalyzePhishingResponse{}, fmt.Errorf("get message %s failed: %w", input.MessageID, err)
+	}
+
+	summary := extractMessageSummary(msg)
+	indicators := []PhishingIndicator{}
+
+	if msg.Payload != nil {
+		fromDomain := strings.ToLower(addressDomain(summary.From.Email))
+		indicators = append(indicators, lookalikeIndicators(fromDomain)...)
+		indicators = append(indicators, displayNameIndicators(summary.From, fromDomain)...)
+		indicators = append(indicators, replyToIndicators(msg.Payload.Headers, fromDomain)...)
+		indicators = append(indicators, authIndicators(extractMessageAuth(msg))...)
+
+		textBody, htmlBody := extractMessageBodies(msg.Payload)
+		if htmlBody != "" {
+			indicators = append(indicators, linkMismatchIndicators(format.ExtractLinks([]byte(htmlBody)))...)
+		}
+		indicators = append(indicators, urgencyIndicators(summary.Subject+"\n"+textBody+"\n"+htmlBody)...)
+	}
+
+	score := 0
+	for _, ind := range indicators {
+		score += severityScores[ind.Severity]
+	}
+	score = min(score, 100)
+
+	return nil, AnalyzePhishingResponse{
+		MessageID:  msg.Id,
+		From:       summary.From,
+		RiskScore:  score,
+		RiskLevel:  riskLevel(score),
+		Indicators: indicators,
+	}, nil
+}
+
+func riskLevel(score int) string {
+	switch {
+	case score >= 60:
+		return severityHigh
+	case score >= 25:
+		return severityMedium
+	default:
+		return severityLow
+	}
+}
+
+func lookalikeIndicators(domain string) []PhishingIndicator {
+	if domain == "" {
+		return nil
+	}
+
+	var indicators []PhishingIndicator
+	if strings.Contains(domain, "xn--") {
+		indicators = append(indicators, PhishingIndicator{
+			Type:     indicatorLookalikeDomain,
+			Severity: severityMedium,
+			Detail:   fmt.Sprintf("sender domain %s uses punycode, which can hide lookalike characters", domain),
+		})
+	}
+
+	registered := registeredDomain(domain)
+	for _, brand := range impersonatedBrands {
+		if registered == brand.domain {
+			return indicators
+		}
+	}
+	for _, brand := range impersonatedBrands {
+		if homoglyphs.Replace(registered) == brand.domain || editDistance(registered, brand.domain) == 1 {
+			return append(indicators, PhishingIndicator{
+				Type:     indicatorLookalikeDomain,
+				Severity: severityHigh,
+				Detail:   fmt.Sprintf("sender domain %s resembles %s", domain, brand.domain),
+			})
+		}
+	}
+
+	return indicators
+}
+
+func displayNameIndicators(from EmailAddress, fromDomain string) []PhishingIndicator {
+	name := strings.ToLower(from.Name)
+	if name == "" || fromDomain == "" {
+		return nil
+	}
+
+	if m := embeddedAddress.FindStringSubmatch(name); m != nil && !sameOrgDomain(m[1], fromDomain) {
+		return []PhishingIndicator{{
+			Type:     indicatorDisplayName,
+			Severity: severityHigh,
+			Detail:   fmt.Sprintf("display name shows %s but the message was sent from %s", m[0], from.Email),
+		}}
+	}
+
+	for _, brand := range impersonatedBrands {
+		if containsWord(name, brand.marker) && !sameOrgDomain(fromDomain, brand.domain) {
+			return []PhishingIndicator{{
+				Type:     indicatorDisplayName,
+				Severity: severityMedium,
+				Detail:   fmt.Sprintf("display name %q mentions %s but the sender domain is %s", from.Name, brand.marker, fromDomain),
+			}}
+		}
+	}
+
+	return nil
+}
+
+func replyToIndicators(headers []*gmail.MessagePartHeader, fromDomain string) []PhishingIndicator {
+	replyTo := parseEmailAddress(headerValue(headers, "Reply-To"))
+	replyDomain := strings.ToLower(addressDomain(replyTo.Email))
+	if replyDomain == "" || fromDomain == "" || sameOrgDomain(replyDomain, fromDomain) {
+		return nil
+	}
+
+	return []PhishingIndicator{{
+		Type:     indicatorReplyTo,
+		Severity: severityMedium,
+		Detail:   fmt.Sprintf("replies go to %s instead of the sender domain %s", replyTo.Email, fromDomain),
+	}}
+}
+
+func authIndicators(auth MessageAuth) []PhishingIndicator {
+	var failed []string
+	if auth.SPF != nil && slices.Contains([]string{"fail", "softfail"}, auth.SPF.Result) {
+		failed = append(failed, "spf="+auth.SPF.Result)
+	}
+	if auth.DMARC != nil && auth.DMARC.Result == "fail" {
+		failed = append(failed, "dmarc=fail")
+	}
+	if len(auth.DKIM) > 0 && !slices.ContainsFunc(auth.DKIM, func(c AuthCheck) bool { return c.Result == "pass" }) {
+		failed = append(failed, "dkim="+auth.DKIM[0].Result)
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return []PhishingIndicator{{
+		Type:     indicatorAuthFailure,
+		Severity: severityHigh,
+		Detail:   "sender authentication failed: " + strings.Join(failed, ", "),
+	}}
+}
+
+func linkMismatchIndicators(links []format.Link) []PhishingIndicator {
+	var indicators []PhishingIndicator
+
+	for _, l := range links {
+		text := strings.TrimSpace(l.Text)
+		if !domainLikePattern.MatchString(text) {
+			continue
+		}
+
+		textHost := linkHost(text)
+		hrefHost := linkHost(l.URL)
+		if textHost == "" || hrefHost == "" || sameOrgDomain(textHost, hrefHost) {
+			continue
+		}
+
+		indicators = append(indicators, PhishingIndicator{
+			Type:     indicatorLinkMismatch,
+			Severity: severityHigh,
+			Detail:   fmt.Sprintf("link text shows %s but points to %s", textHost, hrefHost),
+		})
+	}
+
+	return indicators
+}
+
+func urgencyIndicators(text string) []PhishingIndicator {
+	lower := strings.ToLower(text)
+
+	var found []string
+	for _, phrase := range urgentPhrases {
+		if strings.Contains(lower, phrase) {
+			found = append(found, phrase)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	severity := severityLow
+	if len(found) >= 3 {
+		severity = severityMedium
+	}
+
+	return []PhishingIndicator{{
+		Type:     indicatorUrgentLanguage,
+		Severity: severity,
+		Detail:   "pressure phrases: " + strings.Join(found, ", "),
+	}}
+}
+
+func linkHost(raw string) string {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// registeredDomain approximates the registrable domain by keeping the last two labels.
+func registeredDomain(host string) string {
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	return strings.Join(labels[len(labels)-2:], ".")
+}
+
+func sameOrgDomain(a, b string) bool {
+	return registeredDomain(strings.ToLower(a)) == registeredDomain(strings.ToLower(b))
+}
+
+func containsWord(s, word string) bool {
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || '0' <= r && r <= '9')
+	}) {
+		if field == word {
+			return true
+		}
+	}
+	return false
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
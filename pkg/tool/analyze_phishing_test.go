@@ -0,0 +1,143 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestAnalyzePhishing(t *testing.T) {
+	encode := func(s string) string {
+		return base64.URLEncoding.EncodeToString([]byte(s))
+	}
+
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			switch msgID {
+			case "msg-phish":
+				return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{
+					MimeType: "multipart/alternative",
+					Headers: []*gmail.MessagePartHeader{
+						{Name: "From", Value: "PayPal Support <service@paypa1.com>"},
+						{Name: "Reply-To", Value: "collector@mailbox.example"},
+						{Name: "Subject", Value: "Urgent: verify your account"},
+						{Name: "Authentication-Results", Value: "mx.google.com; spf=softfail smtp.mailfrom=service@paypa1.com"},
+					},
+					Parts: []*gmail.MessagePart{
+						{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: encode("Act immediately.")}},
+						{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: encode(
+							`<p><a href="https://evil.example/login">https://www.paypal.com/signin</a></p>`,
+						)}},
+					},
+				}}, nil
+			case "msg-benign":
+				return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{
+					MimeType: "text/plain",
+					Headers: []*gmail.MessagePartHeader{
+						{Name: "From", Value: "Alice <alice@example.com>"},
+						{Name: "Subject", Value: "Lunch tomorrow?"},
+					},
+					Body: &gmail.MessagePartBody{Data: encode("See you at noon.")},
+				}}, nil
+			}
+			return nil, fmt.Errorf("message not found: %s", msgID)
+		},
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.AnalyzePhishingRequest
+		expected    tool.AnalyzePhishingResponse
+		expectedErr error
+	}{
+		{
+			name: "phishing message",
+			req:  tool.AnalyzePhishingRequest{MessageID: "msg-phish"},
+			expected: tool.AnalyzePhishingResponse{
+				MessageID: "msg-phish",
+				From:      tool.EmailAddress{Name: "PayPal Support", Email: "service@paypa1.com"},
+				RiskScore: 100,
+				RiskLevel: "high",
+				Indicators: []tool.PhishingIndicator{
+					{Type: "lookalike_domain", Severity: "high", Detail: "sender domain paypa1.com resembles paypal.com"},
+					{Type: "display_name_mismatch", Severity: "medium", Detail: `display name "PayPal Support" mentions paypal but the sender domain is paypa1.com`},
+					{Type: "reply_to_mismatch", Severity: "medium", Detail: "replies go to collector@mailbox.example instead of the sender domain paypa1.com"},
+					{Type: "auth_failure", Severity: "high", Detail: "sender authentication failed: spf=softfail"},
+					{Type: "link_text_mismatch", Severity: "high", Detail: "link text shows www.paypal.com but points to evil.example"},
+					{Type: "urgent_language", Severity: "medium", Detail: "pressure phrases: urgent, immediately, verify your account"},
+				},
+			},
+		},
+		{
+			name: "benign message",
+			req:  tool.AnalyzePhishingRequest{MessageID: "msg-benign"},
+			expected: tool.AnalyzePhishingResponse{
+				MessageID:  "msg-benign",
+				From:       tool.EmailAddress{Name: "Alice", Email: "alice@example.com"},
+				RiskLevel:  "low",
+				Indicators: []tool.PhishingIndicator{},
+			},
+		},
+		{
+			name:        "missing message_id",
+			req:         tool.AnalyzePhishingRequest{},
+			expectedErr: fmt.Errorf("message_id is required"),
+		},
+		{
+			name:        "error case",
+			req:         tool.AnalyzePhishingRequest{MessageID: "error-msg"},
+			expectedErr: fmt.Errorf("message not found: error-msg"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "analyze_phishing",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.AnalyzePhishingResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
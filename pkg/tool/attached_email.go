@@ -0,0 +1,159 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+const mimeTypeRFC822 = "message/rfc822"
+
+// attachedEmailHeaders are the headers shown above the body of an attached email.
+var attachedEmailHeaders = []string{"From", "To", "Cc", "Date", "Subject"}
+
+// renderAttachedEmail renders a forwarded-as-attachment email with its headers, body and
+// attachment list. The body goes through the same extraction as Gmail messages.
+func renderAttachedEmail(conv htmlConverter, raw []byte) (string, error) {
+	payload, err := parseEmailPart(raw)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, name := range attachedEmailHeaders {
+		if value := headerValue(payload.Headers, name); value != "" {
+			sb.WriteString(name + ": " + value + "\n")
+		}
+	}
+
+	body, err := renderMessageBody(conv, payload, bodyOptions{})
+	if err != nil {
+		return "", fmt.Errorf("renderMessageBody failed: %w", err)
+	}
+	if body.text != "" {
+		sb.WriteString("\n" + strings.TrimRight(body.text, "\n") + "\n")
+	}
+
+	if attachments := attachedEmailAttachments(payload); len(attachments) > 0 {
+		sb.WriteString("\nAttachments:\n")
+		for _, a := range attachments {
+			fmt.Fprintf(&sb, "- %s (%s, %d bytes)\n", a.Filename, a.MimeType, a.Body.Size)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// parseEmailPart parses an RFC 822 message into a Gmail message part tree, so the body can be
+// extracted like that of any Gmail message. Text parts carry their decoded content, other parts
+// only their metadata.
+func parseEmailPart(raw []byte) (*gmail.MessagePart, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("mail.ReadMessage failed: %w", err)
+	}
+
+	return mimePart(textproto.MIMEHeader(msg.Header), msg.Body)
+}
+
+func mimePart(header textproto.MIMEHeader, body io.Reader) (*gmail.MessagePart, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	part := &gmail.MessagePart{
+		MimeType: mediaType,
+		Headers:  mimeHeaders(header),
+		Body:     &gmail.MessagePartBody{},
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			child, err := mr.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("read %s part failed: %w", mediaType, err)
+			}
+			childPart, err := mimePart(child.Header, child)
+			if err != nil {
+				return nil, err
+			}
+			part.Parts = append(part.Parts, childPart)
+		}
+		return part, nil
+	}
+
+	content, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return nil, fmt.Errorf("read %s body failed: %w", mediaType, err)
+	}
+
+	part.Filename = mimeFilename(header, params)
+	part.Body.Size = int64(len(content))
+	if strings.HasPrefix(mediaType, "text/") && part.Filename == "" {
+		part.Body.Data = base64.URLEncoding.EncodeToString(content)
+	}
+
+	return part, nil
+}
+
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		// The decoder skips the line breaks MIME wraps base64 content at.
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// mimeHeaders converts headers, decoding RFC 2047 encoded words.
+func mimeHeaders(header textproto.MIMEHeader) []*gmail.MessagePartHeader {
+	dec := new(mime.WordDecoder)
+
+	headers := make([]*gmail.MessagePartHeader, 0, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			if decoded, err := dec.DecodeHeader(value); err == nil {
+				value = decoded
+			}
+			headers = append(headers, &gmail.MessagePartHeader{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+func mimeFilename(header textproto.MIMEHeader, params map[string]string) string {
+	if _, dispParams, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && dispParams["filename"] != "" {
+		return dispParams["filename"]
+	}
+	return params["name"]
+}
+
+// attachedEmailAttachments lists the file parts of a parsed email.
+func attachedEmailAttachments(payload *gmail.MessagePart) []*gmail.MessagePart {
+	var attachments []*gmail.MessagePart
+	if payload.Filename != "" {
+		attachments = append(attachments, payload)
+	}
+	for _, part := range payload.Parts {
+		attachments = append(attachments, attachedEmailAttachments(part)...)
+	}
+	return attachments
+}
@@ -0,0 +1,45 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// AuthStatusRequest has no parameters; with WithAccounts the account parameter selects the
+// account to report on.
+type AuthStatusRequest struct{}
+
+// AuthStatusResponse tells whether an account is authorized and where to authorize it if not.
+type AuthStatusResponse struct {
+	Account    string `json:"account,omitempty" jsonschema:"the account reported on, empty for the default account"`
+	Authorized bool   `json:"authorized" jsonschema:"true when the server holds a token for the account"`
+	Expiry     string `json:"expiry,omitempty" jsonschema:"RFC 3339 time the current access token expires, it is refreshed automatically"`
+	AuthURL    string `json:"auth_url,omitempty" jsonschema:"URL for the user to open in a browser to authorize the account, when it is not"`
+}
+
+// NewAuthStatus creates a new AuthStatus tool asking status about the account of the call, empty
+// for the default account.
+func NewAuthStatus(status func(account string) AuthStatusResponse) *AuthStatus {
+	return &AuthStatus{
+		status: status,
+	}
+}
+
+// AuthStatus reports whether the server is authorized for an account.
+type AuthStatus struct {
+	status func(account string) AuthStatusResponse
+}
+
+// AuthStatus returns the authorization of the selected account. It works without a token, so an
+// agent can hand the user the URL to authorize a headless server at.
+func (t *AuthStatus) AuthStatus(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	_ AuthStatusRequest,
+) (*mcp.CallToolResult, AuthStatusResponse, error) {
+	account := selectedAccount(ctx)
+	status := t.status(account)
+	status.Account = account
+	return nil, status, nil
+}
@@ -0,0 +1,49 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestAuthStatus(t *testing.T) {
+	work := newAccountSvcMock("me@work.example", 0)
+	personal := newAccountSvcMock("me@home.example", 0)
+	accounts, err := tool.NewAccounts(tool.Account{Name: "work", Svc: work}, tool.Account{Name: "personal", Svc: personal})
+	require.NoError(t, err)
+
+	server := tool.NewServer(work, &converterMock{}, tool.WithAccounts(accounts), tool.WithAuthStatus(func(account string) tool.AuthStatusResponse {
+		if account == "personal" {
+			return tool.AuthStatusResponse{AuthURL: "http://localhost/oauth?redirect=1&account=personal"}
+		}
+		return tool.AuthStatusResponse{Authorized: true, Expiry: "2026-01-02T15:04:05Z"}
+	}))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "auth_status", Arguments: map[string]any{"account": "personal"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content)
+
+	var response tool.AuthStatusResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	assert.Equal(t, tool.AuthStatusResponse{
+		Account: "personal",
+		AuthURL: "http://localhost/oauth?redirect=1&account=personal",
+	}, response)
+}
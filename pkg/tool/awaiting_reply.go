@@ -0,0 +1,142 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	draftLabelID            = "DRAFT"
+	defaultAwaitingDays     = 14
+	defaultAwaitingMessages = 100
+)
+
+// AwaitingReplyRequest contains parameters for awaiting reply detection.
+type AwaitingReplyRequest struct {
+	Days        int    `json:"days,omitempty" jsonschema:"look at messages sent within this many days, default 14"`
+	Query       string `json:"query,omitempty" jsonschema:"additional Gmail search filters for sent messages, e.g. to:bob@example.com"`
+	MaxMessages int    `json:"max_messages,omitempty" jsonschema:"max sent messages to scan, default 100, up to 1000"`
+}
+
+// AwaitingReplyResponse contains threads still waiting for a reply.
+type AwaitingReplyResponse struct {
+	Threads         []AwaitingThread `json:"threads" jsonschema:"threads whose last sent message has no later inbound reply, newest first"`
+	ScannedMessages int              `json:"scanned_messages" jsonschema:"number of sent messages scanned"`
+	Truncated       bool             `json:"truncated" jsonschema:"true when more sent messages matched than were scanned"`
+}
+
+// AwaitingThread describes a thread where the last word is ours.
+type AwaitingThread struct {
+	ThreadID     string         `json:"thread_id" jsonschema:"thread ID"`
+	MessageID    string         `json:"message_id" jsonschema:"ID of the last sent message"`
+	Subject      string         `json:"subject" jsonschema:"subject of the last sent message"`
+	To           []EmailAddress `json:"to,omitempty" jsonschema:"recipients of the last sent message"`
+	SentAt       string         `json:"sent_at" jsonschema:"date of the last sent message"`
+	MessageCount int            `json:"message_count" jsonschema:"number of messages in the thread"`
+}
+
+type awaitingReplySvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	GetThreadMetadata(ctx context.Context, threadID string) (*gmail.Thread, error)
+}
+
+// NewAwaitingReply creates a new AwaitingReply tool.
+func NewAwaitingReply(svc awaitingReplySvc) *AwaitingReply {
+	return &AwaitingReply{
+		svc: svc,
+	}
+}
+
+// AwaitingReply finds sent messages nobody has answered yet.
+type AwaitingReply struct {
+	svc awaitingReplySvc
+}
+
+// AwaitingReply scans recently sent messages and reports threads without a later inbound reply.
+func (t *AwaitingReply) AwaitingReply(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input AwaitingReplyRequest,
+) (*mcp.CallToolResult, AwaitingReplyResponse, error) {
+	days := input.Days
+	if days <= 0 {
+		days = defaultAwaitingDays
+	}
+	query := strings.TrimSpace(fmt.Sprintf("in:sent newer_than:%dd %s", days, input.Query))
+
+	limit := input.MaxMessages
+	if limit <= 0 {
+		limit = defaultAwaitingMessages
+	}
+	refs, truncated, err := scanMessageRefs(ctx, t.svc, query, normalizeScanLimit(limit))
+	if err != nil {
+		return nil, AwaitingReplyResponse{}, fmt.Errorf("scanMessageRefs failed: %w", err)
+	}
+
+	threads := []AwaitingThread{}
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if seen[ref.ThreadId] {
+			continue
+		}
+		seen[ref.ThreadId] = true
+
+		thread, err := t.svc.GetThreadMetadata(ctx, ref.ThreadId)
+		if err != nil {
+			return nil, AwaitingReplyResponse{}, fmt.Errorf("get thread %s failed: %w", ref.ThreadId, err)
+		}
+
+		lastSent := lastUnansweredSent(thread.Messages)
+		if lastSent == nil {
+			continue
+		}
+
+		summary := extractMessageSummary(lastSent)
+		threads = append(threads, AwaitingThread{
+			ThreadID:     ref.ThreadId,
+			MessageID:    lastSent.Id,
+			Subject:      summary.Subject,
+			To:           summary.To,
+			SentAt:       time.UnixMilli(lastSent.InternalDate).UTC().Format(time.RFC3339),
+			MessageCount: len(thread.Messages),
+		})
+	}
+
+	return nil, AwaitingReplyResponse{
+		Threads:         threads,
+		ScannedMessages: len(refs),
+		Truncated:       truncated,
+	}, nil
+}
+
+// lastUnansweredSent returns the newest sent message of a thread unless an inbound
+// message arrived after it. Drafts are neither sent nor inbound and are ignored.
+func lastUnansweredSent(messages []*gmail.Message) *gmail.Message {
+	var lastSent, lastInbound *gmail.Message
+
+	for _, msg := range messages {
+		switch {
+		case slices.Contains(msg.LabelIds, draftLabelID):
+			continue
+		case slices.Contains(msg.LabelIds, sentLabelID):
+			if lastSent == nil || msg.InternalDate >= lastSent.InternalDate {
+				lastSent = msg
+			}
+		default:
+			if lastInbound == nil || msg.InternalDate >= lastInbound.InternalDate {
+				lastInbound = msg
+			}
+		}
+	}
+
+	if lastSent == nil || (lastInbound != nil && lastInbound.InternalDate > lastSent.InternalDate) {
+		return nil
+	}
+	return lastSent
+}
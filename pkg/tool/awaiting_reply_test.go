@@ -0,0 +1,161 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func newThreadMessage(id string, labels []string, internalDate int64, from, to string) *gmail.Message {
+	return &gmail.Message{
+		Id:           id,
+		LabelIds:     labels,
+		InternalDate: internalDate,
+		Payload: &gmail.MessagePart{
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "From", Value: from},
+				{Name: "To", Value: to},
+				{Name: "Subject", Value: "Subject " + id},
+			},
+		},
+	}
+}
+
+func TestAwaitingReply(t *testing.T) {
+	threads := map[string]*gmail.Thread{
+		// last word is ours
+		"t-1": {Id: "t-1", Messages: []*gmail.Message{
+			newThreadMessage("m-1a", []string{"INBOX"}, 1735725600000, "Bob <bob@example.com>", "me@example.com"),
+			newThreadMessage("m-1b", []string{"SENT"}, 1735812000000, "me@example.com", "Bob <bob@example.com>"),
+		}},
+		// replied
+		"t-2": {Id: "t-2", Messages: []*gmail.Message{
+			newThreadMessage("m-2a", []string{"SENT"}, 1735725600000, "me@example.com", "carol@example.com"),
+			newThreadMessage("m-2b", []string{"INBOX"}, 1735812000000, "carol@example.com", "me@example.com"),
+		}},
+		// draft after sent message doesn't count
+		"t-3": {Id: "t-3", Messages: []*gmail.Message{
+			newThreadMessage("m-3a", []string{"SENT"}, 1735725600000, "me@example.com", "Dave <dave@example.com>"),
+			newThreadMessage("m-3b", []string{"DRAFT"}, 1735898400000, "me@example.com", "Dave <dave@example.com>"),
+		}},
+	}
+
+	gmailSvc := &gmailSvcMock{
+		ListMessagesFunc: func(_ context.Context, Q, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+			switch Q {
+			case "in:sent newer_than:14d":
+				return &gmail.ListMessagesResponse{Messages: []*gmail.Message{
+					{Id: "m-1b", ThreadId: "t-1"},
+					{Id: "m-2a", ThreadId: "t-2"},
+					{Id: "m-3a", ThreadId: "t-3"},
+				}}, nil
+			case "in:sent newer_than:3d to:bob@example.com":
+				return &gmail.ListMessagesResponse{Messages: []*gmail.Message{
+					{Id: "m-1b", ThreadId: "t-1"},
+				}}, nil
+			}
+			return nil, fmt.Errorf("simulated error: %s", Q)
+		},
+		GetThreadMetadataFunc: func(_ context.Context, threadID string) (*gmail.Thread, error) {
+			return threads[threadID], nil
+		},
+	}
+
+	bobThread := tool.AwaitingThread{
+		ThreadID:     "t-1",
+		MessageID:    "m-1b",
+		Subject:      "Subject m-1b",
+		To:           []tool.EmailAddress{{Name: "Bob", Email: "bob@example.com"}},
+		SentAt:       "2025-01-02T10:00:00Z",
+		MessageCount: 2,
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.AwaitingReplyRequest
+		expected    tool.AwaitingReplyResponse
+		expectedErr error
+	}{
+		{
+			name: "default window",
+			req:  tool.AwaitingReplyRequest{},
+			expected: tool.AwaitingReplyResponse{
+				ScannedMessages: 3,
+				Threads: []tool.AwaitingThread{
+					bobThread,
+					{
+						ThreadID:     "t-3",
+						MessageID:    "m-3a",
+						Subject:      "Subject m-3a",
+						To:           []tool.EmailAddress{{Name: "Dave", Email: "dave@example.com"}},
+						SentAt:       "2025-01-01T10:00:00Z",
+						MessageCount: 2,
+					},
+				},
+			},
+		},
+		{
+			name: "custom window and filter",
+			req:  tool.AwaitingReplyRequest{Days: 3, Query: "to:bob@example.com"},
+			expected: tool.AwaitingReplyResponse{
+				ScannedMessages: 1,
+				Threads:         []tool.AwaitingThread{bobThread},
+			},
+		},
+		{
+			name:        "list error",
+			req:         tool.AwaitingReplyRequest{Query: "undefined"},
+			expectedErr: fmt.Errorf("simulated error: in:sent newer_than:14d undefined"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "awaiting_reply",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.AwaitingReplyResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
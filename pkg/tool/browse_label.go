@@ -0,0 +1,69 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+// BrowseLabelRequest contains parameters for listing messages of a label.
+type BrowseLabelRequest struct {
+	LabelID    string `json:"label_id" jsonschema:"the label ID, e.g. INBOX, STARRED or Label_123"`
+	MaxResults int64  `json:"max_results,omitempty" jsonschema:"max results per page"`
+	PageToken  string `json:"page_token,omitempty" jsonschema:"token for pagination"`
+}
+
+// BrowseLabelResponse contains label messages with pagination.
+type BrowseLabelResponse struct {
+	Messages      []MessageSummary `json:"messages" jsonschema:"array of message summaries"`
+	NextPageToken string           `json:"next_page_token,omitempty" jsonschema:"token for next page"`
+	TotalResults  int              `json:"total_results" jsonschema:"number of messages returned"`
+}
+
+type browseLabelSvc interface {
+	ListLabelMessages(ctx context.Context, labelID, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	batchMetadataSvc
+}
+
+// NewBrowseLabel creates a new BrowseLabel tool.
+func NewBrowseLabel(svc browseLabelSvc) *BrowseLabel {
+	return &BrowseLabel{
+		svc: svc,
+	}
+}
+
+// BrowseLabel lists messages of a Gmail label.
+type BrowseLabel struct {
+	svc browseLabelSvc
+}
+
+// BrowseLabel lists the most recent messages carrying the label.
+func (t *BrowseLabel) BrowseLabel(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input BrowseLabelRequest,
+) (*mcp.CallToolResult, BrowseLabelResponse, error) {
+	if input.LabelID == "" {
+		return nil, BrowseLabelResponse{}, errors.New("label_id is required")
+	}
+	input.MaxResults = normalizeMaxResults(input.MaxResults)
+
+	result, err := t.svc.ListLabelMessages(ctx, input.LabelID, input.PageToken, input.MaxResults)
+	if err != nil {
+		return nil, BrowseLabelResponse{}, fmt.Errorf("svc.ListLabelMessages failed: %w", err)
+	}
+
+	messages, err := fetchMessageSummaries(ctx, t.svc, result.Messages, nil)
+	if err != nil {
+		return nil, BrowseLabelResponse{}, fmt.Errorf("fetchMessageSummaries failed: %w", err)
+	}
+
+	return nil, BrowseLabelResponse{
+		Messages:      messages,
+		NextPageToken: result.NextPageToken,
+		TotalResults:  len(messages),
+	}, nil
+}
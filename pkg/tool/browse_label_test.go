@@ -0,0 +1,131 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestBrowseLabel(t *testing.T) {
+	cases := []struct {
+		name        string
+		req         tool.BrowseLabelRequest
+		expected    tool.BrowseLabelResponse
+		expectedErr error
+	}{
+		{
+			name: "first page",
+			req:  tool.BrowseLabelRequest{LabelID: "Label_Invoices", MaxResults: 1},
+			expected: tool.BrowseLabelResponse{
+				TotalResults:  1,
+				NextPageToken: "page-2",
+				Messages: []tool.MessageSummary{
+					{
+						ID:           "m-001",
+						ThreadID:     "t-m-001",
+						Timestamp:    "2025-09-14 12:12:32",
+						From:         tool.EmailAddress{Name: "Test User", Email: "test+m-001@test.com"},
+						To:           []tool.EmailAddress{{Name: "My Name", Email: "me+m-001@test.com"}},
+						Subject:      "Super important email m-001",
+						Snippet:      "test summary m-001 & 'more'",
+						LabelIDs:     []string{"INBOX", "UNREAD"},
+						IsUnread:     true,
+						SizeEstimate: 2048,
+					},
+				},
+			},
+		},
+		{
+			name: "second page",
+			req:  tool.BrowseLabelRequest{LabelID: "Label_Invoices", MaxResults: 1, PageToken: "page-2"},
+			expected: tool.BrowseLabelResponse{
+				TotalResults: 1,
+				Messages: []tool.MessageSummary{
+					{
+						ID:           "m-002",
+						ThreadID:     "t-m-002",
+						Timestamp:    "2025-09-14 12:12:32",
+						From:         tool.EmailAddress{Name: "Test User", Email: "test+m-002@test.com"},
+						To:           []tool.EmailAddress{{Name: "My Name", Email: "me+m-002@test.com"}},
+						Subject:      "Super important email m-002",
+						Snippet:      "test summary m-002 & 'more'",
+						LabelIDs:     []string{"INBOX", "UNREAD"},
+						IsUnread:     true,
+						SizeEstimate: 2048,
+					},
+				},
+			},
+		},
+		{
+			name:        "missing label",
+			req:         tool.BrowseLabelRequest{},
+			expectedErr: fmt.Errorf("label_id is required"),
+		},
+		{
+			name:        "unknown label",
+			req:         tool.BrowseLabelRequest{LabelID: "Label_Unknown"},
+			expectedErr: fmt.Errorf("simulated error: Label_Unknown"),
+		},
+	}
+
+	gmailSvc := newSearchMessagesGmailSvc(nil)
+	gmailSvc.ListLabelMessagesFunc = func(_ context.Context, labelID, pageToken string, _ int64) (*gmail.ListMessagesResponse, error) {
+		if labelID != "Label_Invoices" {
+			return nil, fmt.Errorf("simulated error: %s", labelID)
+		}
+		if pageToken == "page-2" {
+			return &gmail.ListMessagesResponse{Messages: []*gmail.Message{{Id: "m-002"}}}, nil
+		}
+		return &gmail.ListMessagesResponse{Messages: []*gmail.Message{{Id: "m-001"}}, NextPageToken: "page-2"}, nil
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "browse_label",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.BrowseLabelResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/internal/ics"
+)
+
+const (
+	defaultCalendarID      = "primary"
+	defaultEventDays       = 7
+	maxEventDays           = 366
+	defaultEventMaxResults = 25
+	maxEventMaxResults     = 250
+	// defaultEventDuration is the length of events created without an end.
+	defaultEventDuration = time.Hour
+)
+
+var eventTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"}
+
+// ListEventsRequest specifies the upcoming events to list.
+type ListEventsRequest struct {
+	From       string `json:"from,omitempty" jsonschema:"start of the range: YYYY-MM-DD, RFC3339, today or yesterday; default now"`
+	Days       int    `json:"days,omitempty" jsonschema:"days after from to list events of, default 7, max 366"`
+	Query      string `json:"query,omitempty" jsonschema:"free text matched against summary, description, location and attendees"`
+	MaxResults int    `json:"max_results,omitempty" jsonschema:"max events to return, default 25, max 250"`
+	CalendarID string `json:"calendar_id,omitempty" jsonschema:"calendar ID, default primary"`
+}
+
+// ListEventsResponse contains upcoming events ordered by start.
+type ListEventsResponse struct {
+	Events []CalendarEvent `json:"events" jsonschema:"events ordered by start, recurring events expanded"`
+}
+
+// CreateEventRequest specifies an event to add to the calendar.
+type CreateEventRequest struct {
+	MessageID   string   `json:"message_id,omitempty" jsonschema:"message the event comes from; linked as the event source, and without summary and start its ICS invitation is imported"`
+	Summary     string   `json:"summary,omitempty" jsonschema:"event title, e.g. Flight LH 400 FRA-JFK"`
+	Start       string   `json:"start,omitempty" jsonschema:"start: RFC3339, YYYY-MM-DDTHH:MM in the server timezone, or YYYY-MM-DD for an all-day event"`
+	End         string   `json:"end,omitempty" jsonschema:"end in the format of start, the last day of all-day events; default an hour after start or a single day"`
+	Location    string   `json:"location,omitempty" jsonschema:"event location"`
+	Description string   `json:"description,omitempty" jsonschema:"event notes, e.g. booking reference and seat"`
+	Attendees   []string `json:"attendees,omitempty" jsonschema:"attendee email addresses; they are not notified"`
+	CalendarID  string   `json:"calendar_id,omitempty" jsonschema:"calendar ID, default primary"`
+}
+
+// CreateEventResponse contains the created events.
+type CreateEventResponse struct {
+	Events   []CalendarEvent `json:"events" jsonschema:"created events, one per event of an imported invitation"`
+	Imported bool            `json:"imported,omitempty" jsonschema:"true when the events were imported from an ICS invitation; importing it again updates them"`
+}
+
+// CalendarEvent is an event of a calendar.
+type CalendarEvent struct {
+	ID          string   `json:"id" jsonschema:"event ID"`
+	Summary     string   `json:"summary,omitempty" jsonschema:"event title"`
+	Start       string   `json:"start" jsonschema:"RFC3339 start, or YYYY-MM-DD for all-day events"`
+	End         string   `json:"end" jsonschema:"RFC3339 end, or the exclusive YYYY-MM-DD end of all-day events"`
+	AllDay      bool     `json:"all_day,omitempty" jsonschema:"true for events spanning whole days"`
+	Location    string   `json:"location,omitempty" jsonschema:"event location"`
+	Description string   `json:"description,omitempty" jsonschema:"event notes"`
+	Attendees   []string `json:"attendees,omitempty" jsonschema:"attendee email addresses"`
+	Status      string   `json:"status,omitempty" jsonschema:"confirmed, tentative or cancelled"`
+	Link        string   `json:"link,omitempty" jsonschema:"URL of the event in Google Calendar"`
+}
+
+type calendarMessageSvc interface {
+	GetMessage(ctx context.Context, msgID string) (*gmail.Message, error)
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+	GetAttachment(ctx context.Context, msgID, attachmentID string) (*gmail.MessagePartBody, error)
+}
+
+// NewCalendar creates the calendar tools; dates without timezone are read in loc.
+func NewCalendar(svc calendarMessageSvc, cal calendarSvc, loc *time.Location) *Calendar {
+	return &Calendar{
+		svc: svc,
+		cal: cal,
+		loc: loc,
+		now: time.Now,
+	}
+}
+
+// Calendar lists upcoming events and creates events from email content.
+type Calendar struct {
+	svc calendarMessageSvc
+	cal calendarSvc
+	loc *time.Location
+	now func() time.Time
+}
+
+// ListEvents returns the events of the next days.
+func (t *Calendar) ListEvents(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input ListEventsRequest,
+) (*mcp.CallToolResult, ListEventsResponse, error) {
+	from := t.now().In(t.loc)
+	if input.From != "" {
+		var err error
+		if from, err = parseSearchDate(input.From, from); err != nil {
+			return nil, ListEventsResponse{}, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	days := input.Days
+	if days <= 0 {
+		days = defaultEventDays
+	}
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultEventMaxResults
+	}
+
+	events, err := t.cal.ListEvents(ctx, calendarID(input.CalendarID), from, from.AddDate(0, 0, min(days, maxEventDays)),
+		input.Query, int64(min(maxResults, maxEventMaxResults)))
+	if err != nil {
+		return nil, ListEventsResponse{}, fmt.Errorf("cal.ListEvents failed: %w", err)
+	}
+
+	response := ListEventsResponse{Events: make([]CalendarEvent, 0, len(events.Items))}
+	for _, event := range events.Items {
+		response.Events = append(response.Events, calendarEvent(event))
+	}
+	return nil, response, nil
+}
+
+// CreateEvent adds an event described by the request or imported from the ICS invitation of a
+// message.
+func (t *Calendar) CreateEvent(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input CreateEventRequest,
+) (*mcp.CallToolResult, CreateEventResponse, error) {
+	if input.Summary == "" && input.Start == "" {
+		if input.MessageID == "" {
+			return nil, CreateEventResponse{}, errors.New("summary and start, or message_id of an invitation, are required")
+		}
+		return t.importInvitation(ctx, input)
+	}
+	if input.Summary == "" || input.Start == "" {
+		return nil, CreateEventResponse{}, errors.New("summary and start are required")
+	}
+
+	event, err := t.newEvent(input)
+	if err != nil {
+		return nil, CreateEventResponse{}, err
+	}
+	if input.MessageID != "" {
+		msg, err := t.svc.GetMessageMetadata(ctx, input.MessageID)
+		if err != nil {
+			return nil, CreateEventResponse{}, fmt.Errorf("svc.GetMessageMetadata failed: %w", err)
+		}
+		event.Source = eventSource(msg)
+	}
+
+	created, err := t.cal.CreateEvent(ctx, calendarID(input.CalendarID), event)
+	if err != nil {
+		return nil, CreateEventResponse{}, fmt.Errorf("cal.CreateEvent failed: %w", err)
+	}
+	return nil, CreateEventResponse{Events: []CalendarEvent{calendarEvent(created)}}, nil
+}
+
+func (t *Calendar) newEvent(input CreateEventRequest) (*calendar.Event, error) {
+	start, allDay, err := parseEventTime(input.Start, t.loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+
+	var end time.Time
+	switch {
+	case input.End != "":
+		var endAllDay bool
+		if end, endAllDay, err = parseEventTime(input.End, t.loc); err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+		if endAllDay != allDay {
+			return nil, errors.New("start and end must both be dates or both be times")
+		}
+		if allDay {
+			// Dates in the request are inclusive, Calendar ends all-day events exclusively.
+			end = end.AddDate(0, 0, 1)
+		}
+	case allDay:
+		end = start.AddDate(0, 0, 1)
+	default:
+		end = start.Add(defaultEventDuration)
+	}
+	if !end.After(start) {
+		return nil, errors.New("end must be after start")
+	}
+
+	return &calendar.Event{
+		Summary:     input.Summary,
+		Location:    input.Location,
+		Description: input.Description,
+		Start:       eventDateTime(start, allDay),
+		End:         eventDateTime(end, allDay),
+		Attendees:   eventAttendees(input.Attendees),
+	}, nil
+}
+
+func (t *Calendar) importInvitation(ctx context.Context, input CreateEventRequest) (*mcp.CallToolResult, CreateEventResponse, error) {
+	msg, err := t.svc.GetMessage(ctx, input.MessageID)
+	if err != nil {
+		return nil, CreateEventResponse{}, fmt.Errorf("svc.GetMessage failed: %w", err)
+	}
+	data, err := t.invitation(ctx, msg)
+	if err != nil {
+		return nil, CreateEventResponse{}, err
+	}
+	invited, err := ics.Parse(data, t.loc)
+	if err != nil {
+		return nil, CreateEventResponse{}, fmt.Errorf("ics.Parse failed: %w", err)
+	}
+
+	response := CreateEventResponse{Imported: true}
+	for _, e := range invited {
+		if e.UID == "" {
+			return nil, CreateEventResponse{}, errors.New("invitation event has no UID")
+		}
+		event := &calendar.Event{
+			ICalUID:     e.UID,
+			Summary:     e.Summary,
+			Location:    e.Location,
+			Description: e.Description,
+			Start:       eventDateTime(e.Start, e.AllDay),
+			End:         eventDateTime(e.End, e.AllDay),
+			Attendees:   eventAttendees(e.Attendees),
+			Source:      eventSource(msg),
+		}
+		if e.Organizer != "" {
+			event.Organizer = &calendar.EventOrganizer{Email: e.Organizer}
+		}
+
+		imported, err := t.cal.ImportEvent(ctx, calendarID(input.CalendarID), event)
+		if err != nil {
+			return nil, CreateEventResponse{}, fmt.Errorf("cal.ImportEvent failed: %w", err)
+		}
+		response.Events = append(response.Events, calendarEvent(imported))
+	}
+	return nil, response, nil
+}
+
+// invitation returns the content of the first text/calendar part or .ics attachment of msg.
+func (t *Calendar) invitation(ctx context.Context, msg *gmail.Message) ([]byte, error) {
+	part := findInvitationPart(msg.Payload)
+	if part == nil || part.Body == nil {
+		return nil, errors.New("message has no ICS invitation")
+	}
+	if part.Body.AttachmentId == "" {
+		return decodeBase64URLBytes(part.Body.Data)
+	}
+
+	attachment, err := t.svc.GetAttachment(ctx, msg.Id, part.Body.AttachmentId)
+	if err != nil {
+		return nil, fmt.Errorf("svc.GetAttachment failed: %w", err)
+	}
+	return decodeBase64URLBytes(attachment.Data)
+}
+
+func findInvitationPart(part *gmail.MessagePart) *gmail.MessagePart {
+	if part == nil {
+		return nil
+	}
+	mimeType := strings.ToLower(part.MimeType)
+	if mimeType == "text/calendar" || mimeType == "application/ics" || strings.HasSuffix(strings.ToLower(part.Filename), ".ics") {
+		return part
+	}
+	for _, child := range part.Parts {
+		if found := findInvitationPart(child); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// parseEventTime accepts RFC3339 times, times without offset in loc and dates, for which it
+// returns allDay.
+func parseEventTime(value string, loc *time.Location) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, false, nil
+	}
+	for _, layout := range eventTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, errors.New("expected RFC3339, YYYY-MM-DDTHH:MM or YYYY-MM-DD")
+}
+
+func eventDateTime(t time.Time, allDay bool) *calendar.EventDateTime {
+	if allDay {
+		return &calendar.EventDateTime{Date: t.Format(time.DateOnly)}
+	}
+	return &calendar.EventDateTime{DateTime: t.Format(time.RFC3339)}
+}
+
+func eventAttendees(emails []string) []*calendar.EventAttendee {
+	var attendees []*calendar.EventAttendee
+	for _, email := range emails {
+		attendees = append(attendees, &calendar.EventAttendee{Email: email})
+	}
+	return attendees
+}
+
+// eventSource links an event to the message it was created from.
+func eventSource(msg *gmail.Message) *calendar.EventSource {
+	title := "Email"
+	if msg.Payload != nil {
+		if subject := headerValue(msg.Payload.Headers, "Subject"); subject != "" {
+			title = subject
+		}
+	}
+	return &calendar.EventSource{Title: title, Url: "https://mail.google.com/mail/#all/" + msg.Id}
+}
+
+func calendarEvent(event *calendar.Event) CalendarEvent {
+	result := CalendarEvent{
+		ID:          event.Id,
+		Summary:     event.Summary,
+		Location:    event.Location,
+		Description: event.Description,
+		Status:      event.Status,
+		Link:        event.HtmlLink,
+	}
+	result.Start, result.AllDay = eventTime(event.Start)
+	result.End, _ = eventTime(event.End)
+	for _, attendee := range event.Attendees {
+		result.Attendees = append(result.Attendees, attendee.Email)
+	}
+	return result
+}
+
+func eventTime(t *calendar.EventDateTime) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	if t.Date != "" {
+		return t.Date, true
+	}
+	return t.DateTime, false
+}
+
+func calendarID(id string) string {
+	if id == "" {
+		return defaultCalendarID
+	}
+	return id
+}
@@ -0,0 +1,209 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+const flightInvitation = "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" +
+	"UID:booking-7QX2@airline.example\r\n" +
+	"SUMMARY:Flight LH 400 FRA-JFK\r\n" +
+	"DTSTART:20250310T093000Z\r\n" +
+	"DTEND:20250310T180000Z\r\n" +
+	"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+func newCalendarSession(t *testing.T, gmailSvc *gmailSvcMock, cal *calendarSvcMock) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	server := tool.NewServer(gmailSvc, &converterMock{}, tool.WithCalendar(cal), tool.WithTimezone(time.UTC))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+	return clientSession
+}
+
+func callCalendarTool[T any](t *testing.T, session *mcp.ClientSession, name string, args any) T {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "Result should not indicate error: %v", result.Content)
+
+	var response T
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	return response
+}
+
+func TestListEvents(t *testing.T) {
+	cal := &calendarSvcMock{
+		ListEventsFunc: func(_ context.Context, _ string, _, _ time.Time, _ string, _ int64) (*calendar.Events, error) {
+			return &calendar.Events{Items: []*calendar.Event{
+				{
+					Id:        "e1",
+					Summary:   "Standup",
+					Start:     &calendar.EventDateTime{DateTime: "2025-03-03T09:00:00Z"},
+					End:       &calendar.EventDateTime{DateTime: "2025-03-03T09:15:00Z"},
+					Attendees: []*calendar.EventAttendee{{Email: "bob@example.com"}},
+				},
+				{
+					Id:      "e2",
+					Summary: "Holiday",
+					Start:   &calendar.EventDateTime{Date: "2025-03-04"},
+					End:     &calendar.EventDateTime{Date: "2025-03-05"},
+				},
+			}}, nil
+		},
+	}
+	session := newCalendarSession(t, &gmailSvcMock{}, cal)
+
+	response := callCalendarTool[tool.ListEventsResponse](t, session, "list_events", tool.ListEventsRequest{From: "2025-03-01", Days: 3})
+	assert.Equal(t, []tool.CalendarEvent{
+		{ID: "e1", Summary: "Standup", Start: "2025-03-03T09:00:00Z", End: "2025-03-03T09:15:00Z", Attendees: []string{"bob@example.com"}},
+		{ID: "e2", Summary: "Holiday", Start: "2025-03-04", End: "2025-03-05", AllDay: true},
+	}, response.Events)
+
+	calls := cal.ListEventsCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "primary", calls[0].CalendarID)
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), calls[0].TimeMin)
+	assert.Equal(t, time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), calls[0].TimeMax)
+	assert.Equal(t, int64(25), calls[0].MaxResults)
+}
+
+func TestCreateEvent(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		GetMessageMetadataFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+				{Name: "Subject", Value: "Your booking 7QX2"},
+			}}}, nil
+		},
+	}
+	cal := &calendarSvcMock{
+		CreateEventFunc: func(_ context.Context, _ string, event *calendar.Event) (*calendar.Event, error) {
+			created := *event
+			created.Id = "e1"
+			return &created, nil
+		},
+	}
+	session := newCalendarSession(t, gmailSvc, cal)
+
+	response := callCalendarTool[tool.CreateEventResponse](t, session, "create_event", tool.CreateEventRequest{
+		MessageID: "m1",
+		Summary:   "Flight LH 400 FRA-JFK",
+		Start:     "2025-03-10T10:30:00+01:00",
+		End:       "2025-03-10T13:00:00-05:00",
+		Location:  "Frankfurt Airport",
+	})
+	require.Len(t, response.Events, 1)
+	assert.Equal(t, "e1", response.Events[0].ID)
+	assert.False(t, response.Imported)
+
+	event := cal.CreateEventCalls()[0].Event
+	assert.Equal(t, "2025-03-10T10:30:00+01:00", event.Start.DateTime)
+	assert.Equal(t, "Frankfurt Airport", event.Location)
+	assert.Equal(t, &calendar.EventSource{Title: "Your booking 7QX2", Url: "https://mail.google.com/mail/#all/m1"}, event.Source)
+
+	response = callCalendarTool[tool.CreateEventResponse](t, session, "create_event", tool.CreateEventRequest{
+		Summary: "Conference",
+		Start:   "2025-04-01",
+		End:     "2025-04-03",
+	})
+	assert.Equal(t, "2025-04-01", response.Events[0].Start)
+	assert.Equal(t, "2025-04-04", response.Events[0].End, "the last day is inclusive in the request")
+	assert.True(t, response.Events[0].AllDay)
+
+	response = callCalendarTool[tool.CreateEventResponse](t, session, "create_event", tool.CreateEventRequest{
+		Summary: "Call",
+		Start:   "2025-04-01T15:00",
+	})
+	assert.Equal(t, "2025-04-01T15:00:00Z", response.Events[0].Start, "read in the server timezone")
+	assert.Equal(t, "2025-04-01T16:00:00Z", response.Events[0].End)
+
+	for name, req := range map[string]tool.CreateEventRequest{
+		"nothing":          {},
+		"no start":         {Summary: "Call"},
+		"end before start": {Summary: "Call", Start: "2025-04-01T15:00", End: "2025-04-01T14:00"},
+		"mixed":            {Summary: "Call", Start: "2025-04-01", End: "2025-04-01T14:00"},
+	} {
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "create_event", Arguments: req})
+		require.NoError(t, err)
+		assert.True(t, result.IsError, name)
+	}
+}
+
+func TestCreateEventImport(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{
+				MimeType: "multipart/mixed",
+				Headers:  []*gmail.MessagePartHeader{{Name: "Subject", Value: "Flight confirmation"}},
+				Parts: []*gmail.MessagePart{
+					{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte("Have a nice flight"))}},
+					{MimeType: "application/octet-stream", Filename: "flight.ics", Body: &gmail.MessagePartBody{AttachmentId: "att-1"}},
+				},
+			}}, nil
+		},
+		GetAttachmentFunc: func(_ context.Context, _, attachmentID string) (*gmail.MessagePartBody, error) {
+			assert.Equal(t, "att-1", attachmentID)
+			return &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(flightInvitation))}, nil
+		},
+	}
+	cal := &calendarSvcMock{
+		ImportEventFunc: func(_ context.Context, _ string, event *calendar.Event) (*calendar.Event, error) {
+			imported := *event
+			imported.Id = "e1"
+			return &imported, nil
+		},
+	}
+	session := newCalendarSession(t, gmailSvc, cal)
+
+	response := callCalendarTool[tool.CreateEventResponse](t, session, "create_event", tool.CreateEventRequest{MessageID: "m1"})
+	assert.True(t, response.Imported)
+	assert.Equal(t, []tool.CalendarEvent{{
+		ID:      "e1",
+		Summary: "Flight LH 400 FRA-JFK",
+		Start:   "2025-03-10T09:30:00Z",
+		End:     "2025-03-10T18:00:00Z",
+	}}, response.Events)
+
+	event := cal.ImportEventCalls()[0].Event
+	assert.Equal(t, "booking-7QX2@airline.example", event.ICalUID)
+	assert.Equal(t, "Flight confirmation", event.Source.Title)
+}
+
+func TestCalendarToolsOptional(t *testing.T) {
+	ctx := context.Background()
+	server := tool.NewServer(&gmailSvcMock{}, &converterMock{})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+	clientSession, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	tools, err := clientSession.ListTools(ctx, nil)
+	require.NoError(t, err)
+	for _, tl := range tools.Tools {
+		assert.NotEqual(t, "list_events", tl.Name)
+		assert.NotEqual(t, "create_event", tl.Name)
+	}
+}
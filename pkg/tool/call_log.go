@@ -0,0 +1,69 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// callLog writes an audit line per MCP request: the method, the tool and account of tool calls,
+// the names of their arguments, the outcome and the duration. Argument values, results and error
+// messages may hold queries or mail content and are never logged.
+type callLog struct {
+	logger *log.Logger
+}
+
+func (c callLog) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		start := time.Now()
+		res, err := next(ctx, method, req)
+
+		fields := []string{"method=" + method}
+		if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok && method == "tools/call" {
+			fields = append(fields, "tool="+params.Name, "args=["+strings.Join(argNames(params.Arguments), ",")+"]")
+			if account := selectedAccount(ctx); account != "" {
+				fields = append(fields, "account="+account)
+			}
+		}
+		fields = append(fields, "status="+c.status(ctx, res, err), "duration="+time.Since(start).Round(time.Millisecond).String())
+		c.logger.Println("mcp", strings.Join(fields, " "))
+
+		return res, err
+	}
+}
+
+// status describes the outcome of a request: ok, error, or the ErrorInfo code of a tool failure.
+func (c callLog) status(ctx context.Context, res mcp.Result, err error) string {
+	if err != nil {
+		return "error"
+	}
+	result, ok := res.(*mcp.CallToolResult)
+	if !ok || !result.IsError {
+		return "ok"
+	}
+	if slot, ok := ctx.Value(toolErrorKey{}).(*toolError); ok {
+		if info, ok := errorInfo(slot.err); ok {
+			return "error:" + info.Code
+		}
+	}
+	return "error"
+}
+
+// argNames returns the sorted names of the arguments of a tool call.
+func argNames(arguments json.RawMessage) []string {
+	var args map[string]json.RawMessage
+	if json.Unmarshal(arguments, &args) != nil {
+		return nil
+	}
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,52 @@
+package tool_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestCallLog(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		ListMessagesFunc: func(_ context.Context, q, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+			if q == "from:boss@example.com" {
+				return nil, fmt.Errorf("messages.List failed: %w", gservice.ErrQuotaExceeded)
+			}
+			return &gmail.ListMessagesResponse{ResultSizeEstimate: 1}, nil
+		},
+	}
+	var buf bytes.Buffer
+	server := tool.NewServer(gmailSvc, &converterMock{}, tool.WithCallLog(log.New(&buf, "", 0)))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, query := range []string{"subject:salary", "from:boss@example.com"} {
+		_, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "count_messages", Arguments: map[string]any{"query": query}})
+		require.NoError(t, err)
+	}
+
+	logged := buf.String()
+	assert.Contains(t, logged, "mcp method=tools/call tool=count_messages args=[query] status=ok duration=")
+	assert.Contains(t, logged, "mcp method=tools/call tool=count_messages args=[query] status=error:quota_exceeded duration=")
+	assert.NotContains(t, logged, "salary", "argument values are not logged")
+	assert.NotContains(t, logged, "boss@example.com")
+}
@@ -0,0 +1,127 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestCheckMessageAuth(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			switch msgID {
+			case "msg-signed":
+				return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+					{Name: "Received", Value: "by 2002:a05:6a10:1234 with SMTP id abc; Wed, 1 Jan 2025 10:00:02 -0800 (PST)"},
+					{Name: "Received", Value: "from mail.example.com (mail.example.com. [203.0.113.5])\r\n" +
+						"        by mx.google.com with ESMTPS id xyz\r\n" +
+						"        for <me@gmail.com>; Wed, 01 Jan 2025 10:00:01 -0800 (PST)"},
+					{Name: "Authentication-Results", Value: "mx.google.com;\r\n" +
+						"       dkim=pass header.i=@example.com header.s=s1 header.b=AbCd;\r\n" +
+						"       dkim=fail (bad signature) header.d=esp.example.net;\r\n" +
+						"       spf=pass (google.com: domain of bounce@example.com designates 203.0.113.5 as permitted sender; ok) smtp.mailfrom=bounce@example.com;\r\n" +
+						"       dmarc=pass (p=REJECT sp=REJECT dis=NONE) header.from=example.com"},
+					{Name: "Authentication-Results", Value: "forged.example; spf=pass smtp.mailfrom=evil.example"},
+					{Name: "Return-Path", Value: "<bounce@example.com>"},
+					{Name: "From", Value: "Example <news@example.com>"},
+				}}}, nil
+			case "msg-plain":
+				return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{Headers: []*gmail.MessagePartHeader{
+					{Name: "From", Value: "someone@example.org"},
+				}}}, nil
+			}
+			return nil, fmt.Errorf("message not found: %s", msgID)
+		},
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.CheckMessageAuthRequest
+		expected    tool.CheckMessageAuthResponse
+		expectedErr error
+	}{
+		{
+			name: "parsed headers",
+			req:  tool.CheckMessageAuthRequest{MessageIDs: []string{"msg-signed", "msg-plain"}},
+			expected: tool.CheckMessageAuthResponse{
+				Messages: []tool.MessageAuth{
+					{
+						MessageID:  "msg-signed",
+						From:       tool.EmailAddress{Name: "Example", Email: "news@example.com"},
+						ReturnPath: "bounce@example.com",
+						AuthServID: "mx.google.com",
+						SPF:        &tool.AuthCheck{Result: "pass", Domain: "example.com"},
+						DKIM: []tool.AuthCheck{
+							{Result: "pass", Domain: "example.com"},
+							{Result: "fail", Domain: "esp.example.net"},
+						},
+						DMARC: &tool.AuthCheck{Result: "pass", Domain: "example.com"},
+						Received: []tool.ReceivedHop{
+							{By: "2002:a05:6a10:1234", Date: "Wed, 1 Jan 2025 10:00:02 -0800 (PST)"},
+							{From: "mail.example.com", By: "mx.google.com", IP: "203.0.113.5", Date: "Wed, 01 Jan 2025 10:00:01 -0800 (PST)"},
+						},
+					},
+					{
+						MessageID: "msg-plain",
+						From:      tool.EmailAddress{Email: "someone@example.org"},
+					},
+				},
+			},
+		},
+		{
+			name:        "error case",
+			req:         tool.CheckMessageAuthRequest{MessageIDs: []string{"error-msg"}},
+			expectedErr: fmt.Errorf("message not found: error-msg"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "check_message_auth",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.CheckMessageAuthResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/watermark"
+)
+
+const (
+	defaultWatermarkName    = "default"
+	defaultNewMailResults   = 25
+	maxNewMailResults       = 100
+	newMailTimestampQuery   = "in:inbox after:%d"
+	newMailTimestampScanCap = maxScanMessages
+)
+
+// CheckNewMailRequest selects where polling resumes from.
+type CheckNewMailRequest struct {
+	Name       string `json:"name,omitempty" jsonschema:"name of the stored watermark, default 'default'; use distinct names for independent pollers"`
+	HistoryID  string `json:"history_id,omitempty" jsonschema:"resume after this history ID instead of the stored watermark"`
+	Since      string `json:"since,omitempty" jsonschema:"resume after this RFC3339 timestamp instead of the stored watermark"`
+	MaxResults int    `json:"max_results,omitempty" jsonschema:"max messages to return, default 25, up to 100"`
+}
+
+// CheckNewMailResponse contains inbox messages newer than the watermark.
+type CheckNewMailResponse struct {
+	Messages    []MessageSummary `json:"messages" jsonschema:"new inbox messages, oldest first"`
+	Watermark   NewMailWatermark `json:"watermark" jsonschema:"new watermark, already stored under its name"`
+	Initialized bool             `json:"initialized,omitempty" jsonschema:"true when no previous position was known and the watermark was set to now"`
+	HasMore     bool             `json:"has_more" jsonschema:"true when more new messages remain, call again to continue"`
+}
+
+// NewMailWatermark is the position up to which the mailbox has been seen.
+type NewMailWatermark struct {
+	Name      string `json:"name" jsonschema:"watermark name"`
+	HistoryID string `json:"history_id,omitempty" jsonschema:"Gmail history ID"`
+	Since     string `json:"since,omitempty" jsonschema:"timestamp of the newest seen message"`
+}
+
+type checkNewMailSvc interface {
+	GetProfile(ctx context.Context) (*gmail.Profile, error)
+	ListHistory(ctx context.Context, startHistoryID uint64, labelID, pageToken string) (*gmail.ListHistoryResponse, error)
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+type watermarkStore interface {
+	Get(name string) (watermark.Mark, bool)
+	Set(mark watermark.Mark) error
+}
+
+// NewCheckNewMail creates a new CheckNewMail tool.
+func NewCheckNewMail(svc checkNewMailSvc, store watermarkStore) *CheckNewMail {
+	return &CheckNewMail{
+		svc:   svc,
+		store: store,
+	}
+}
+
+// CheckNewMail returns only inbox mail that arrived after a stored watermark.
+type CheckNewMail struct {
+	svc   checkNewMailSvc
+	store watermarkStore
+}
+
+// CheckNewMail lists messages newer than the watermark and advances it.
+func (t *CheckNewMail) CheckNewMail(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input CheckNewMailRequest,
+) (*mcp.CallToolResult, CheckNewMailResponse, error) {
+	start, err := t.startMark(ctx, input)
+	if err != nil {
+		return nil, CheckNewMailResponse{}, err
+	}
+
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultNewMailResults
+	}
+	maxResults = min(maxResults, maxNewMailResults)
+
+	// The profile is read before listing, so mail arriving meanwhile is reported again rather than lost.
+	profile, err := t.svc.GetProfile(ctx)
+	if err != nil {
+		return nil, CheckNewMailResponse{}, fmt.Errorf("svc.GetProfile failed: %w", err)
+	}
+
+	response := CheckNewMailResponse{Messages: []MessageSummary{}}
+	next := watermark.Mark{Name: start.Name, HistoryID: profile.HistoryId, Timestamp: start.Timestamp}
+
+	switch {
+	case start.HistoryID != 0:
+		var lastHistoryID uint64
+		response.Messages, next.Timestamp, lastHistoryID, err = t.sinceHistory(ctx, start, maxResults)
+		if err == nil {
+			if lastHistoryID != 0 {
+				next.HistoryID = lastHistoryID
+				response.HasMore = true
+			}
+			break
+		}
+		// Gmail keeps history for about a week; an expired history ID falls back to the timestamp.
+		if !errors.Is(err, gservice.ErrNotFound) || start.Timestamp == 0 {
+			return nil, CheckNewMailResponse{}, err
+		}
+		fallthrough
+	case start.Timestamp != 0:
+		response.Messages, next.Timestamp, response.HasMore, err = t.sinceTimestamp(ctx, start.Timestamp, maxResults)
+		if err != nil {
+			return nil, CheckNewMailResponse{}, err
+		}
+		if response.HasMore {
+			next.HistoryID = 0
+		}
+	default:
+		next.Timestamp = time.Now().UnixMilli()
+		response.Initialized = true
+	}
+
+	if err := t.store.Set(next); err != nil {
+		return nil, CheckNewMailResponse{}, fmt.Errorf("store.Set failed: %w", err)
+	}
+
+	response.Watermark = NewMailWatermark{Name: watermarkName(input)}
+	if next.HistoryID != 0 {
+		response.Watermark.HistoryID = strconv.FormatUint(next.HistoryID, 10)
+	}
+	if next.Timestamp != 0 {
+		response.Watermark.Since = time.UnixMilli(next.Timestamp).UTC().Format(time.RFC3339)
+	}
+
+	return nil, response, nil
+}
+
+// watermarkName returns the requested watermark name or the default one.
+func watermarkName(input CheckNewMailRequest) string {
+	if input.Name == "" {
+		return defaultWatermarkName
+	}
+	return input.Name
+}
+
+// startMark resolves the polling position, explicit parameters taking precedence over the stored watermark.
+// Watermarks of accounts other than the default are stored under "<account>/<name>".
+func (t *CheckNewMail) startMark(ctx context.Context, input CheckNewMailRequest) (watermark.Mark, error) {
+	name := watermarkName(input)
+	if account := selectedAccount(ctx); account != "" {
+		name = account + "/" + name
+	}
+
+	mark, _ := t.store.Get(name)
+	mark.Name = name
+
+	if input.Since != "" {
+		since, err := time.Parse(time.RFC3339, input.Since)
+		if err != nil {
+			return watermark.Mark{}, fmt.Errorf("invalid since %q: %w", input.Since, err)
+		}
+		mark.Timestamp = since.UnixMilli()
+		mark.HistoryID = 0
+	}
+	if input.HistoryID != "" {
+		historyID, err := strconv.ParseUint(input.HistoryID, 10, 64)
+		if err != nil {
+			return watermark.Mark{}, fmt.Errorf("invalid history_id %q: %w", input.HistoryID, err)
+		}
+		mark.HistoryID = historyID
+	}
+
+	return mark, nil
+}
+
+// sinceHistory returns inbox messages added after the start history ID. When more than maxResults
+// messages were added, it also returns the history ID of the last record included.
+func (t *CheckNewMail) sinceHistory(
+	ctx context.Context,
+	start watermark.Mark,
+	maxResults int,
+) ([]MessageSummary, int64, uint64, error) {
+	var ids []string
+	var lastHistoryID uint64
+	seen := make(map[string]bool)
+	pageToken := ""
+
+pages:
+	for {
+		result, err := t.svc.ListHistory(ctx, start.HistoryID, inboxLabelID, pageToken)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("svc.ListHistory failed: %w", err)
+		}
+
+		for _, record := range result.History {
+			var added []string
+			for _, m := range record.MessagesAdded {
+				if m.Message == nil || seen[m.Message.Id] || slices.Contains(m.Message.LabelIds, draftLabelID) {
+					continue
+				}
+				added = append(added, m.Message.Id)
+			}
+			if len(ids) > 0 && len(ids)+len(added) > maxResults {
+				break pages
+			}
+			for _, id := range added {
+				seen[id] = true
+			}
+			ids = append(ids, added...)
+			lastHistoryID = record.Id
+		}
+
+		pageToken = result.NextPageToken
+		if pageToken == "" {
+			lastHistoryID = 0
+			break
+		}
+	}
+
+	messages, newest, err := t.summaries(ctx, ids, start.Timestamp)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return messages, newest, lastHistoryID, nil
+}
+
+// sinceTimestamp returns the oldest inbox messages received after the timestamp.
+func (t *CheckNewMail) sinceTimestamp(ctx context.Context, since int64, maxResults int) ([]MessageSummary, int64, bool, error) {
+	refs, _, err := scanMessageRefs(ctx, t.svc, fmt.Sprintf(newMailTimestampQuery, since/1000), newMailTimestampScanCap)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	// Gmail lists newest first, reversing yields the oldest unseen messages first.
+	slices.Reverse(refs)
+
+	messages := []MessageSummary{}
+	newest := since
+	for i, ref := range refs {
+		if len(messages) == maxResults {
+			return messages, newest, i < len(refs), nil
+		}
+
+		msg, err := t.svc.GetMessageMetadata(ctx, ref.Id)
+		if err != nil {
+			if errors.Is(err, gservice.ErrNotFound) {
+				continue
+			}
+			return nil, 0, false, fmt.Errorf("get message %s failed: %w", ref.Id, err)
+		}
+		// after: has second granularity, messages of the watermark second itself were already seen.
+		if msg.InternalDate <= since {
+			continue
+		}
+
+		messages = append(messages, extractMessageSummary(msg))
+		newest = max(newest, msg.InternalDate)
+	}
+
+	return messages, newest, false, nil
+}
+
+// summaries fetches metadata of messages skipping those deleted since they were added.
+func (t *CheckNewMail) summaries(ctx context.Context, ids []string, since int64) ([]MessageSummary, int64, error) {
+	messages := make([]MessageSummary, 0, len(ids))
+	newest := since
+	for _, id := range ids {
+		msg, err := t.svc.GetMessageMetadata(ctx, id)
+		if err != nil {
+			if errors.Is(err, gservice.ErrNotFound) {
+				continue
+			}
+			return nil, 0, fmt.Errorf("get message %s failed: %w", id, err)
+		}
+
+		messages = append(messages, extractMessageSummary(msg))
+		newest = max(newest, msg.InternalDate)
+	}
+
+	return messages, newest, nil
+}
@@ -0,0 +1,184 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestCheckNewMail(t *testing.T) {
+	internalDates := map[string]time.Time{
+		"m-old": time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		"m-a":   time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC),
+		"m-b":   time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		"m-c":   time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC),
+	}
+	added := func(msgID string, labels ...string) *gmail.HistoryMessageAdded {
+		return &gmail.HistoryMessageAdded{Message: &gmail.Message{Id: msgID, LabelIds: labels}}
+	}
+	history := []*gmail.History{
+		{Id: 201, MessagesAdded: []*gmail.HistoryMessageAdded{added("m-a", "INBOX")}},
+		{Id: 202, MessagesAdded: []*gmail.HistoryMessageAdded{added("m-b", "INBOX"), added("m-draft", "INBOX", "DRAFT")}},
+		{Id: 203, MessagesAdded: []*gmail.HistoryMessageAdded{added("m-c", "INBOX")}},
+	}
+
+	gmailSvc := &gmailSvcMock{
+		GetProfileFunc: func(_ context.Context) (*gmail.Profile, error) {
+			return &gmail.Profile{HistoryId: 210}, nil
+		},
+		ListHistoryFunc: func(_ context.Context, startHistoryID uint64, labelID, _ string) (*gmail.ListHistoryResponse, error) {
+			if labelID != "INBOX" {
+				return nil, fmt.Errorf("unexpected label: %s", labelID)
+			}
+			switch startHistoryID {
+			case 5:
+				return nil, fmt.Errorf("history.List failed: %w", gservice.ErrNotFound)
+			case 7:
+				return nil, fmt.Errorf("simulated history error")
+			}
+			var records []*gmail.History
+			for _, record := range history {
+				if record.Id > startHistoryID {
+					records = append(records, record)
+				}
+			}
+			return &gmail.ListHistoryResponse{History: records}, nil
+		},
+		ListMessagesFunc: func(_ context.Context, Q, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+			switch Q {
+			case "in:inbox after:1735725600":
+				return &gmail.ListMessagesResponse{Messages: []*gmail.Message{
+					{Id: "m-c"}, {Id: "m-b"}, {Id: "m-a"}, {Id: "m-old"},
+				}}, nil
+			case "in:inbox after:1735736400":
+				return &gmail.ListMessagesResponse{Messages: []*gmail.Message{{Id: "m-c"}}}, nil
+			}
+			return nil, fmt.Errorf("simulated error: %s", Q)
+		},
+		GetMessageMetadataFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			date, ok := internalDates[msgID]
+			if !ok {
+				return nil, fmt.Errorf("message not found: %s", msgID)
+			}
+			return &gmail.Message{Id: msgID, InternalDate: date.UnixMilli()}, nil
+		},
+	}
+
+	cases := []struct {
+		name         string
+		req          tool.CheckNewMailRequest
+		expectedIDs  []string
+		expectedMark tool.NewMailWatermark
+		hasMore      bool
+		initialized  bool
+		expectedErr  error
+	}{
+		{
+			name:         "first call sets the watermark",
+			req:          tool.CheckNewMailRequest{},
+			expectedIDs:  []string{},
+			expectedMark: tool.NewMailWatermark{Name: "default", HistoryID: "210"},
+			initialized:  true,
+		},
+		{
+			name:         "explicit history id with limit",
+			req:          tool.CheckNewMailRequest{Name: "history", HistoryID: "200", MaxResults: 2},
+			expectedIDs:  []string{"m-a", "m-b"},
+			expectedMark: tool.NewMailWatermark{Name: "history", HistoryID: "202", Since: "2025-01-01T12:00:00Z"},
+			hasMore:      true,
+		},
+		{
+			name:         "continues from stored watermark",
+			req:          tool.CheckNewMailRequest{Name: "history"},
+			expectedIDs:  []string{"m-c"},
+			expectedMark: tool.NewMailWatermark{Name: "history", HistoryID: "210", Since: "2025-01-01T13:00:00Z"},
+		},
+		{
+			name:         "since timestamp",
+			req:          tool.CheckNewMailRequest{Name: "other", Since: "2025-01-01T10:00:00Z"},
+			expectedIDs:  []string{"m-a", "m-b", "m-c"},
+			expectedMark: tool.NewMailWatermark{Name: "other", HistoryID: "210", Since: "2025-01-01T13:00:00Z"},
+		},
+		{
+			name:         "expired history falls back to timestamp",
+			req:          tool.CheckNewMailRequest{Name: "other", HistoryID: "5"},
+			expectedIDs:  []string{},
+			expectedMark: tool.NewMailWatermark{Name: "other", HistoryID: "210", Since: "2025-01-01T13:00:00Z"},
+		},
+		{
+			name:        "invalid since",
+			req:         tool.CheckNewMailRequest{Since: "yesterday"},
+			expectedErr: fmt.Errorf(`invalid since "yesterday"`),
+		},
+		{
+			name:        "history error",
+			req:         tool.CheckNewMailRequest{HistoryID: "7"},
+			expectedErr: fmt.Errorf("simulated history error"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "check_new_mail",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.CheckNewMailResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+
+			ids := make([]string, 0, len(response.Messages))
+			for _, msg := range response.Messages {
+				ids = append(ids, msg.ID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.hasMore, response.HasMore)
+			assert.Equal(t, tc.initialized, response.Initialized)
+
+			if tc.initialized {
+				assert.NotEmpty(t, response.Watermark.Since)
+				response.Watermark.Since = ""
+			}
+			assert.Equal(t, tc.expectedMark, response.Watermark)
+		})
+	}
+}
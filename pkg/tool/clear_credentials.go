@@ -0,0 +1,46 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ClearCredentialsRequest has no parameters; with WithAccounts the account parameter selects the
+// account to log out.
+type ClearCredentialsRequest struct{}
+
+// ClearCredentialsResponse confirms the credentials were cleared.
+type ClearCredentialsResponse struct {
+	Cleared bool   `json:"cleared" jsonschema:"true when the token was forgotten and removed from its store"`
+	Account string `json:"account,omitempty" jsonschema:"the account logged out, empty for the default account"`
+}
+
+// NewClearCredentials creates a new ClearCredentials tool calling clear with the account of the
+// call, empty for the default account.
+func NewClearCredentials(clear func(account string) error) *ClearCredentials {
+	return &ClearCredentials{
+		clear: clear,
+	}
+}
+
+// ClearCredentials logs the server out of an account.
+type ClearCredentials struct {
+	clear func(account string) error
+}
+
+// ClearCredentials wipes the in-memory and persisted token of the selected account, leaving other
+// accounts and configuration alone. Later calls fail until the account is authorized again.
+func (t *ClearCredentials) ClearCredentials(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	_ ClearCredentialsRequest,
+) (*mcp.CallToolResult, ClearCredentialsResponse, error) {
+	account := selectedAccount(ctx)
+	if err := t.clear(account); err != nil {
+		return nil, ClearCredentialsResponse{}, fmt.Errorf("clear failed: %w", err)
+	}
+
+	return nil, ClearCredentialsResponse{Cleared: true, Account: account}, nil
+}
@@ -0,0 +1,48 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestClearCredentials(t *testing.T) {
+	work := newAccountSvcMock("me@work.example", 0)
+	personal := newAccountSvcMock("me@home.example", 0)
+	accounts, err := tool.NewAccounts(tool.Account{Name: "work", Svc: work}, tool.Account{Name: "personal", Svc: personal})
+	require.NoError(t, err)
+
+	var cleared []string
+	server := tool.NewServer(work, &converterMock{}, tool.WithAccounts(accounts), tool.WithClearCredentials(func(account string) error {
+		cleared = append(cleared, account)
+		return nil
+	}))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, args := range []map[string]any{{"account": "personal"}, {}} {
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "clear_credentials", Arguments: args})
+		require.NoError(t, err)
+		require.False(t, result.IsError, result.Content)
+
+		var response tool.ClearCredentialsResponse
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+		assert.True(t, response.Cleared)
+	}
+	assert.Equal(t, []string{"personal", ""}, cleared, "the default account is passed as empty")
+}
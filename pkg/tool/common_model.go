@@ -0,0 +1,27 @@
+package tool
+
+// EmailAddress represents an email address with optional display name.
+type EmailAddress struct {
+	Name  string `json:"name,omitempty" jsonschema:"the display name"`
+	Email string `json:"email" jsonschema:"the email address"`
+}
+
+// MessageSummary contains essential message metadata.
+type MessageSummary struct {
+	ID           string         `json:"id" jsonschema:"message ID"`
+	ThreadID     string         `json:"thread_id" jsonschema:"thread ID"`
+	Timestamp    string         `json:"timestamp" jsonschema:"message timestamp"`
+	From         EmailAddress   `json:"from" jsonschema:"sender information"`
+	To           []EmailAddress `json:"to,omitempty" jsonschema:"recipients"`
+	CC           []EmailAddress `json:"cc,omitempty" jsonschema:"CC recipients"`
+	BCC          []EmailAddress `json:"bcc,omitempty" jsonschema:"BCC recipients, only present on mail you sent"`
+	ReplyTo      []EmailAddress `json:"reply_to,omitempty" jsonschema:"addresses replies should go to when they differ from the sender"`
+	Subject      string         `json:"subject" jsonschema:"email subject"`
+	Snippet      string         `json:"snippet" jsonschema:"message preview"`
+	LabelIDs     []string       `json:"label_ids,omitempty" jsonschema:"label IDs such as INBOX, UNREAD, STARRED and user label IDs"`
+	IsUnread     bool           `json:"is_unread" jsonschema:"true when the message carries the UNREAD label"`
+	SizeEstimate int64          `json:"size_estimate,omitempty" jsonschema:"estimated message size in bytes, check before fetching full content"`
+	MessageID    string         `json:"message_id,omitempty" jsonschema:"RFC 5322 Message-ID header"`
+	InReplyTo    string         `json:"in_reply_to,omitempty" jsonschema:"Message-ID of the message this one replies to"`
+	References   []string       `json:"references,omitempty" jsonschema:"Message-IDs of earlier messages in the conversation, oldest first"`
+}
@@ -0,0 +1,39 @@
+package tool
+
+import (
+	"crypto/sha256"
+
+	"github.com/hal9000y/gmail-mcp/internal/lru"
+)
+
+// cachedConverter remembers HTML conversions, since agents often fetch the same message several
+// times in a session and every conversion may run pandoc. Conversions are keyed by a digest of
+// the HTML, which covers the message as well as the options applied before conversion, like
+// strip_quotes or hidden text removal.
+type cachedConverter struct {
+	converter
+	bodies *lru.Cache[[sha256.Size]byte, string]
+}
+
+func newCachedConverter(cnv converter, entries int) cachedConverter {
+	return cachedConverter{
+		converter: cnv,
+		bodies:    lru.New[[sha256.Size]byte, string](entries),
+	}
+}
+
+// HTML2MD returns the cached conversion of raw, converting it on a miss. Failed conversions
+// are not cached.
+func (c cachedConverter) HTML2MD(raw []byte) (string, error) {
+	key := sha256.Sum256(raw)
+	if markdown, ok := c.bodies.Get(key); ok {
+		return markdown, nil
+	}
+
+	markdown, err := c.converter.HTML2MD(raw)
+	if err != nil {
+		return "", err
+	}
+	c.bodies.Add(key, markdown)
+	return markdown, nil
+}
@@ -0,0 +1,80 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestConversionCache(t *testing.T) {
+	cases := []struct {
+		name          string
+		opts          []tool.Option
+		expectedCalls int
+	}{
+		{name: "cached", opts: []tool.Option{tool.WithConversionCache(10)}, expectedCalls: 1},
+		{name: "disabled", expectedCalls: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gmailSvc := newGetMessagesGmailSvc()
+			// The mock messages have a plain text body, drop it to convert the HTML one.
+			getMessage := gmailSvc.GetMessageFunc
+			gmailSvc.GetMessageFunc = func(ctx context.Context, msgID string) (*gmail.Message, error) {
+				msg, err := getMessage(ctx, msgID)
+				if err == nil {
+					msg.Payload.Parts = msg.Payload.Parts[1:]
+				}
+				return msg, err
+			}
+			converter := &converterMock{
+				HTML2MDFunc: func(raw []byte) (string, error) {
+					return "converted " + string(raw), nil
+				},
+			}
+
+			server := tool.NewServer(gmailSvc, converter, tc.opts...)
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+			ctx := context.Background()
+
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+
+			clientSession, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer clientSession.Close()
+
+			for range 2 {
+				result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+					Name:      "get_messages",
+					Arguments: tool.GetMessagesRequest{MessageIDs: []string{"msg-1"}},
+				})
+				require.NoError(t, err)
+				require.False(t, result.IsError, "Result should not indicate error")
+
+				var response tool.GetMessagesResponse
+				require.NoError(t,
+					json.Unmarshal(
+						[]byte(result.Content[0].(*mcp.TextContent).Text),
+						&response,
+					),
+				)
+				require.Len(t, response.Messages, 1)
+				assert.Contains(t, response.Messages[0].BodyText, "converted ")
+			}
+
+			assert.Len(t, converter.HTML2MDCalls(), tc.expectedCalls)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+// CountMessagesRequest contains the query to count messages for.
+type CountMessagesRequest struct {
+	Query string `json:"query" jsonschema:"the Gmail search query"`
+}
+
+// CountMessagesResponse contains the estimated number of matching messages.
+type CountMessagesResponse struct {
+	Query          string `json:"query" jsonschema:"the Gmail search query"`
+	EstimatedCount int64  `json:"estimated_count" jsonschema:"estimated number of matching messages"`
+}
+
+type countMessagesSvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+}
+
+// NewCountMessages creates a new CountMessages tool.
+func NewCountMessages(svc countMessagesSvc) *CountMessages {
+	return &CountMessages{
+		svc: svc,
+	}
+}
+
+// CountMessages estimates the number of messages matching a query.
+type CountMessages struct {
+	svc countMessagesSvc
+}
+
+// CountMessages returns Gmail's result size estimate without fetching message metadata.
+func (t *CountMessages) CountMessages(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input CountMessagesRequest,
+) (*mcp.CallToolResult, CountMessagesResponse, error) {
+	result, err := t.svc.ListMessages(ctx, input.Query, "", 1)
+	if err != nil {
+		return nil, CountMessagesResponse{}, fmt.Errorf("svc.ListMessages failed: %w", err)
+	}
+
+	return nil, CountMessagesResponse{
+		Query:          input.Query,
+		EstimatedCount: result.ResultSizeEstimate,
+	}, nil
+}
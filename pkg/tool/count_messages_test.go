@@ -0,0 +1,89 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestCountMessages(t *testing.T) {
+	cases := []struct {
+		req         tool.CountMessagesRequest
+		expected    tool.CountMessagesResponse
+		expectedErr error
+	}{
+		{
+			req:      tool.CountMessagesRequest{Query: "is:unread from:test@test.com"},
+			expected: tool.CountMessagesResponse{Query: "is:unread from:test@test.com", EstimatedCount: 42},
+		},
+		{
+			req:         tool.CountMessagesRequest{Query: "undefined@undefined"},
+			expectedErr: fmt.Errorf("simulated error: undefined@undefined"),
+		},
+	}
+
+	gmailSvc := &gmailSvcMock{
+		ListMessagesFunc: func(_ context.Context, Q, _ string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+			if Q != "is:unread from:test@test.com" {
+				return nil, fmt.Errorf("simulated error: %s", Q)
+			}
+			if maxResults != 1 {
+				return nil, fmt.Errorf("unexpected maxResults: %d", maxResults)
+			}
+			return &gmail.ListMessagesResponse{
+				Messages:           []*gmail.Message{{Id: "m-001"}},
+				ResultSizeEstimate: 42,
+			}, nil
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.req.Query, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "count_messages",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.CountMessagesResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
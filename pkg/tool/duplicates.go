@@ -0,0 +1,74 @@
+package tool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	duplicateByMessageID = "message_id"
+	duplicateByContent   = "content"
+)
+
+// DuplicateMessage reports a message collapsed into an earlier copy.
+type DuplicateMessage struct {
+	ID          string `json:"id" jsonschema:"ID of the collapsed message"`
+	DuplicateOf string `json:"duplicate_of" jsonschema:"ID of the message kept in the result"`
+	Reason      string `json:"reason" jsonschema:"how the duplicate was detected: message_id or content"`
+}
+
+// duplicateDetector remembers seen messages by RFC 822 Message-ID and content hash,
+// so mailing-list and direct copies of the same mail are reported only once.
+type duplicateDetector struct {
+	byMessageID map[string]string
+	byContent   map[string]string
+}
+
+func newDuplicateDetector() *duplicateDetector {
+	return &duplicateDetector{
+		byMessageID: make(map[string]string),
+		byContent:   make(map[string]string),
+	}
+}
+
+// check registers the message and reports whether an earlier copy was already seen.
+func (d *duplicateDetector) check(msg *gmail.Message, content MessageContent) (DuplicateMessage, bool) {
+	var messageID string
+	if msg.Payload != nil {
+		messageID = strings.TrimSpace(headerValue(msg.Payload.Headers, "Message-ID"))
+	}
+
+	if messageID != "" {
+		if keptID, ok := d.byMessageID[messageID]; ok {
+			return DuplicateMessage{ID: msg.Id, DuplicateOf: keptID, Reason: duplicateByMessageID}, true
+		}
+	}
+
+	hash := contentHash(content)
+	if keptID, ok := d.byContent[hash]; ok {
+		return DuplicateMessage{ID: msg.Id, DuplicateOf: keptID, Reason: duplicateByContent}, true
+	}
+
+	if messageID != "" {
+		d.byMessageID[messageID] = msg.Id
+	}
+	d.byContent[hash] = msg.Id
+
+	return DuplicateMessage{}, false
+}
+
+func contentHash(content MessageContent) string {
+	h := sha256.New()
+	for _, part := range []string{
+		strings.ToLower(content.Summary.From.Email),
+		strings.TrimSpace(content.Summary.Subject),
+		strings.TrimSpace(content.BodyText),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
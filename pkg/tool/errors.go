@@ -0,0 +1,101 @@
+package tool
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+)
+
+// ErrorMetaKey is the _meta key of failed tool results carrying an ErrorInfo, so clients can
+// react to a failure without parsing its message.
+const ErrorMetaKey = "gmail-mcp/error"
+
+// Error codes of ErrorInfo.
+const (
+	ErrorCodeNotFound      = "not_found"
+	ErrorCodeQuotaExceeded = "quota_exceeded"
+	ErrorCodeAuthExpired   = "auth_expired"
+	ErrorCodePermission    = "permission_denied"
+)
+
+// ErrorInfo describes the kind of a tool failure.
+type ErrorInfo struct {
+	Code      string `json:"code"`
+	Retryable bool   `json:"retryable"`
+	// Action suggests what resolves the failure.
+	Action string `json:"action,omitempty"`
+	// AuthURL starts the OAuth flow authorizing the server again, for auth_expired failures, or
+	// granting the missing scopes, for permission_denied failures, when the server knows it.
+	AuthURL string `json:"auth_url,omitempty"`
+}
+
+var errorInfos = []struct {
+	err  error
+	info ErrorInfo
+}{
+	{gservice.ErrAuthExpired, ErrorInfo{Code: ErrorCodeAuthExpired, Action: "authorize the server again"}},
+	{gservice.ErrQuotaExceeded, ErrorInfo{Code: ErrorCodeQuotaExceeded, Retryable: true, Action: "retry later"}},
+	{gservice.ErrPermission, ErrorInfo{Code: ErrorCodePermission, Action: "grant the account access or the missing scope"}},
+	{gservice.ErrNotFound, ErrorInfo{Code: ErrorCodeNotFound, Action: "check the ID, the item may have been deleted"}},
+}
+
+// errorInfo returns the ErrorInfo of a recognized failure.
+func errorInfo(err error) (ErrorInfo, bool) {
+	for _, e := range errorInfos {
+		if errors.Is(err, e.err) {
+			return e.info, true
+		}
+	}
+	return ErrorInfo{}, false
+}
+
+type toolErrorKey struct{}
+
+// toolError holds the error a tool handler failed with, which the SDK only passes on as text,
+// and the URL authorizing the server again if the reauthorizer set it.
+type toolError struct {
+	err     error
+	authURL string
+}
+
+// addTool registers a tool like mcp.AddTool, keeping the error it fails with for errorMeta.
+// Tools without annotations get those of their mode.
+func addTool[In, Out any](server *mcp.Server, t *mcp.Tool, h mcp.ToolHandlerFor[In, Out]) {
+	if t.Annotations == nil {
+		t.Annotations = annotations(t.Name)
+	}
+	mcp.AddTool(server, t, func(ctx context.Context, req *mcp.CallToolRequest, in In) (*mcp.CallToolResult, Out, error) {
+		res, out, err := h(ctx, req, in)
+		if slot, ok := ctx.Value(toolErrorKey{}).(*toolError); ok && err != nil {
+			slot.err = err
+		}
+		return res, out, err
+	})
+}
+
+// errorMeta is a middleware adding the ErrorInfo of recognized tool failures to their results.
+func errorMeta(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+
+		slot := &toolError{}
+		res, err := next(context.WithValue(ctx, toolErrorKey{}, slot), method, req)
+		result, ok := res.(*mcp.CallToolResult)
+		if !ok || !result.IsError || slot.err == nil {
+			return res, err
+		}
+		if info, ok := errorInfo(slot.err); ok {
+			info.AuthURL = slot.authURL
+			if result.Meta == nil {
+				result.Meta = mcp.Meta{}
+			}
+			result.Meta[ErrorMetaKey] = info
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,71 @@
+package tool_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestToolErrorMeta(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCode  string
+		retryable bool
+	}{
+		{name: "not found", err: gservice.ErrNotFound, wantCode: tool.ErrorCodeNotFound},
+		{name: "quota", err: gservice.ErrQuotaExceeded, wantCode: tool.ErrorCodeQuotaExceeded, retryable: true},
+		{name: "auth", err: gservice.ErrAuthExpired, wantCode: tool.ErrorCodeAuthExpired},
+		{name: "permission", err: gservice.ErrPermission, wantCode: tool.ErrorCodePermission},
+		{name: "unrecognized", err: fmt.Errorf("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gmailSvc := withBatchGets(&gmailSvcMock{
+				GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+					return nil, fmt.Errorf("messages.Get failed: %w", tt.err)
+				},
+			})
+
+			server := tool.NewServer(gmailSvc, &converterMock{})
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+			ctx := context.Background()
+
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+
+			clientSession, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer clientSession.Close()
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "get_messages",
+				Arguments: tool.GetMessagesRequest{MessageIDs: []string{"msg-1"}},
+			})
+			require.NoError(t, err)
+			require.True(t, result.IsError, "Result should indicate error")
+			assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, tt.err.Error())
+
+			if tt.wantCode == "" {
+				assert.NotContains(t, result.Meta, tool.ErrorMetaKey)
+				return
+			}
+			info, ok := result.Meta[tool.ErrorMetaKey].(map[string]any)
+			require.True(t, ok, "Result should carry error info")
+			assert.Equal(t, tt.wantCode, info["code"])
+			assert.Equal(t, tt.retryable, info["retryable"])
+		})
+	}
+}
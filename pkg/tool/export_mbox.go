@@ -0,0 +1,139 @@
+package tool
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const mboxDefaultSender = "MAILER-DAEMON"
+
+var mboxFromLine = regexp.MustCompile(`^>*From `)
+
+// ExportMboxRequest specifies messages to export and the target file.
+type ExportMboxRequest struct {
+	MessageIDs []string `json:"message_ids" jsonschema:"array of message IDs to export"`
+	Filename   string   `json:"filename,omitempty" jsonschema:"mbox file name inside the export directory, defaults to a timestamped name"`
+}
+
+// ExportMboxResponse describes the written mbox file.
+type ExportMboxResponse struct {
+	SavedPath    string `json:"saved_path" jsonschema:"path of the written mbox file"`
+	MessageCount int    `json:"message_count" jsonschema:"number of exported messages"`
+	Bytes        int    `json:"bytes" jsonschema:"size of the written file in bytes"`
+}
+
+type exportMboxSvc interface {
+	GetMessageRaw(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewExportMbox creates a new ExportMbox tool.
+func NewExportMbox(svc exportMboxSvc, exportDir string) *ExportMbox {
+	return &ExportMbox{
+		svc:       svc,
+		exportDir: exportDir,
+		now:       time.Now,
+	}
+}
+
+// ExportMbox writes raw messages into mbox files for backup and e-discovery.
+type ExportMbox struct {
+	svc       exportMboxSvc
+	exportDir string
+	now       func() time.Time
+}
+
+// ExportMessagesMbox downloads raw messages and writes them as an mboxrd file.
+func (t *ExportMbox) ExportMessagesMbox(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input ExportMboxRequest,
+) (*mcp.CallToolResult, ExportMboxResponse, error) {
+	if len(input.MessageIDs) == 0 {
+		return nil, ExportMboxResponse{}, errors.New("message_ids is required")
+	}
+	if t.exportDir == "" {
+		return nil, ExportMboxResponse{}, ErrDirNotConfigured
+	}
+
+	filename := input.Filename
+	if filename == "" {
+		filename = fmt.Sprintf("messages-%s.mbox", t.now().UTC().Format("20060102-150405"))
+	}
+
+	var buf bytes.Buffer
+	for _, msgID := range input.MessageIDs {
+		msg, err := t.svc.GetMessageRaw(ctx, msgID)
+		if err != nil {
+			return nil, ExportMboxResponse{}, fmt.Errorf("get raw message %s failed: %w", msgID, err)
+		}
+
+		if err := writeMboxMessage(&buf, msg); err != nil {
+			return nil, ExportMboxResponse{}, fmt.Errorf("writeMboxMessage %s failed: %w", msgID, err)
+		}
+	}
+
+	savedPath, err := writeSandboxedFile(t.exportDir, filename, buf.Bytes())
+	if err != nil {
+		return nil, ExportMboxResponse{}, fmt.Errorf("writeSandboxedFile failed: %w", err)
+	}
+
+	return nil, ExportMboxResponse{
+		SavedPath:    savedPath,
+		MessageCount: len(input.MessageIDs),
+		Bytes:        buf.Len(),
+	}, nil
+}
+
+// writeMboxMessage appends a message in mboxrd format: a "From " separator line,
+// LF line endings and ">" quoting of body lines that would look like separators.
+func writeMboxMessage(buf *bytes.Buffer, msg *gmail.Message) error {
+	raw, err := decodeBase64URLBytes(msg.Raw)
+	if err != nil {
+		return fmt.Errorf("decodeBase64URLBytes failed: %w", err)
+	}
+
+	received := time.UnixMilli(msg.InternalDate).UTC()
+	fmt.Fprintf(buf, "From %s %s\n", mboxSender(raw), received.Format(time.ANSIC))
+
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(raw)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSuffix(scanner.Bytes(), []byte("\r"))
+		if mboxFromLine.Match(line) {
+			buf.WriteByte('>')
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner.Scan failed: %w", err)
+	}
+
+	buf.WriteByte('\n')
+
+	return nil
+}
+
+func mboxSender(raw []byte) string {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return mboxDefaultSender
+	}
+
+	for _, header := range []string{"Return-Path", "From"} {
+		if addr, err := mail.ParseAddress(msg.Header.Get(header)); err == nil && addr.Address != "" {
+			return addr.Address
+		}
+	}
+
+	return mboxDefaultSender
+}
@@ -0,0 +1,144 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+const expectedMbox = "From bounce@lists.example.com Wed Jan  1 10:00:00 2025\n" +
+	"Return-Path: <bounce@lists.example.com>\n" +
+	"From: Alice <alice@example.com>\n" +
+	"Subject: Hi\n" +
+	"\n" +
+	"Hello\n" +
+	">From the team\n" +
+	">>From quoted\n" +
+	"\n" +
+	"From bob@example.com Thu Jan  2 10:00:00 2025\n" +
+	"From: Bob <bob@example.com>\n" +
+	"Subject: Second\n" +
+	"\n" +
+	"Bye\n" +
+	"\n"
+
+func newExportMboxGmailSvc() *gmailSvcMock {
+	raw := map[string]*gmail.Message{
+		"m-001": {
+			Id:           "m-001",
+			InternalDate: 1735725600000,
+			Raw:          "UmV0dXJuLVBhdGg6IDxib3VuY2VAbGlzdHMuZXhhbXBsZS5jb20-DQpGcm9tOiBBbGljZSA8YWxpY2VAZXhhbXBsZS5jb20-DQpTdWJqZWN0OiBIaQ0KDQpIZWxsbw0KRnJvbSB0aGUgdGVhbQ0KPkZyb20gcXVvdGVkDQo=",
+		},
+		"m-002": {
+			Id:           "m-002",
+			InternalDate: 1735812000000,
+			Raw:          "RnJvbTogQm9iIDxib2JAZXhhbXBsZS5jb20-DQpTdWJqZWN0OiBTZWNvbmQNCg0KQnllDQo=",
+		},
+	}
+
+	return &gmailSvcMock{
+		GetMessageRawFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			msg, ok := raw[msgID]
+			if !ok {
+				return nil, fmt.Errorf("message not found: %s", msgID)
+			}
+			return msg, nil
+		},
+	}
+}
+
+func TestExportMessagesMbox(t *testing.T) {
+	exportDir := t.TempDir()
+
+	cases := []struct {
+		name        string
+		req         tool.ExportMboxRequest
+		exportDir   string
+		expected    tool.ExportMboxResponse
+		expectedErr error
+	}{
+		{
+			name:      "export to named file",
+			req:       tool.ExportMboxRequest{MessageIDs: []string{"m-001", "m-002"}, Filename: "backup.mbox"},
+			exportDir: exportDir,
+			expected: tool.ExportMboxResponse{
+				SavedPath:    filepath.Join(exportDir, "backup.mbox"),
+				MessageCount: 2,
+				Bytes:        len(expectedMbox),
+			},
+		},
+		{
+			name:        "path traversal is refused",
+			req:         tool.ExportMboxRequest{MessageIDs: []string{"m-001"}, Filename: "../escape.mbox"},
+			exportDir:   exportDir,
+			expectedErr: fmt.Errorf("path escapes from parent"),
+		},
+		{
+			name:        "export dir not configured",
+			req:         tool.ExportMboxRequest{MessageIDs: []string{"m-001"}},
+			expectedErr: tool.ErrDirNotConfigured,
+		},
+		{
+			name:        "message not found",
+			req:         tool.ExportMboxRequest{MessageIDs: []string{"m-404"}},
+			exportDir:   exportDir,
+			expectedErr: fmt.Errorf("message not found: m-404"),
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := tool.NewServer(newExportMboxGmailSvc(), &converterMock{}, tool.WithExportDir(tc.exportDir))
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+
+			clientSession, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer clientSession.Close()
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "export_messages_mbox",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.ExportMboxResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+
+			saved, err := os.ReadFile(response.SavedPath)
+			require.NoError(t, err)
+			assert.Equal(t, expectedMbox, string(saved))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/redact"
+)
+
+// ExportThreadRequest specifies the thread to export.
+type ExportThreadRequest struct {
+	ThreadID   string `json:"thread_id" jsonschema:"ID of the thread to export"`
+	Save       bool   `json:"save,omitempty" jsonschema:"also write the document to the server export directory"`
+	KeepQuotes bool   `json:"keep_quotes,omitempty" jsonschema:"keep the quoted reply history each message repeats, by default it is removed"`
+}
+
+// ExportThreadResponse contains the rendered thread document.
+type ExportThreadResponse struct {
+	ThreadID        string `json:"thread_id" jsonschema:"thread ID"`
+	MessageCount    int    `json:"message_count" jsonschema:"number of messages in the thread"`
+	Markdown        string `json:"markdown" jsonschema:"thread rendered as markdown"`
+	EstimatedTokens int    `json:"estimated_tokens,omitempty" jsonschema:"approximate model tokens of markdown, about 4 bytes each"`
+	SavedPath       string `json:"saved_path,omitempty" jsonschema:"path of the written file when save was requested"`
+}
+
+type exportThreadSvc interface {
+	GetThread(ctx context.Context, threadID string) (*gmail.Thread, error)
+}
+
+// NewExportThread creates a new ExportThread tool. A non-nil redactor masks personal data in
+// the exported bodies.
+func NewExportThread(svc exportThreadSvc, conv htmlConverter, exportDir string, redactor *redact.Redactor) *ExportThread {
+	return &ExportThread{
+		svc:       svc,
+		conv:      conv,
+		exportDir: exportDir,
+		redactor:  redactor,
+	}
+}
+
+// ExportThread renders whole threads into markdown documents.
+type ExportThread struct {
+	svc       exportThreadSvc
+	conv      htmlConverter
+	exportDir string
+	redactor  *redact.Redactor
+}
+
+// ExportThreadMarkdown renders the thread and optionally saves it to the export directory.
+func (t *ExportThread) ExportThreadMarkdown(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input ExportThreadRequest,
+) (*mcp.CallToolResult, ExportThreadResponse, error) {
+	if input.ThreadID == "" {
+		return nil, ExportThreadResponse{}, errors.New("thread_id is required")
+	}
+
+	thread, err := t.svc.GetThread(ctx, input.ThreadID)
+	if err != nil {
+		return nil, ExportThreadResponse{}, fmt.Errorf("svc.GetThread failed: %w", err)
+	}
+
+	contents := make([]MessageContent, 0, len(thread.Messages))
+	for _, msg := range thread.Messages {
+		// Every reply quotes the messages before it, which the thread already shows.
+		content, err := extractMessageContent(msg, t.conv, bodyOptions{stripQuotes: !input.KeepQuotes, redactor: t.redactor})
+		if err != nil {
+			return nil, ExportThreadResponse{}, fmt.Errorf("extractMessageContent %s failed: %w", msg.Id, err)
+		}
+		contents = append(contents, content)
+	}
+
+	response := ExportThreadResponse{
+		ThreadID:     input.ThreadID,
+		MessageCount: len(contents),
+		Markdown:     renderThreadMarkdown(input.ThreadID, contents),
+	}
+	response.EstimatedTokens = estimateTokens(response.Markdown)
+
+	if input.Save {
+		response.SavedPath, err = writeSandboxedFile(t.exportDir, "thread-"+input.ThreadID+".md", []byte(response.Markdown))
+		if err != nil {
+			return nil, ExportThreadResponse{}, fmt.Errorf("writeSandboxedFile failed: %w", err)
+		}
+	}
+
+	return nil, response, nil
+}
+
+func renderThreadMarkdown(threadID string, messages []MessageContent) string {
+	var b strings.Builder
+
+	subject := "(no subject)"
+	if len(messages) > 0 && messages[0].Summary.Subject != "" {
+		subject = messages[0].Summary.Subject
+	}
+
+	fmt.Fprintf(&b, "# %s\n\n", subject)
+	fmt.Fprintf(&b, "Thread ID: %s, messages: %d\n", threadID, len(messages))
+
+	for i, m := range messages {
+		fmt.Fprintf(&b, "\n---\n\n## %d. %s\n\n", i+1, formatEmailAddress(m.Summary.From))
+		writeHeaderLine(&b, "Date", m.Summary.Timestamp)
+		writeHeaderLine(&b, "To", formatEmailAddressList(m.Summary.To))
+		writeHeaderLine(&b, "Cc", formatEmailAddressList(m.Summary.CC))
+		writeHeaderLine(&b, "Subject", m.Summary.Subject)
+
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(m.BodyText))
+
+		if len(m.Attachments) > 0 {
+			b.WriteString("\n**Attachments:**\n\n")
+			for _, a := range m.Attachments {
+				fmt.Fprintf(&b, "- %s (%s, %d bytes)\n", a.Filename, a.MimeType, a.Size)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func writeHeaderLine(b *strings.Builder, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "- **%s:** %s\n", name, value)
+}
+
+func formatEmailAddress(addr EmailAddress) string {
+	if addr.Name == "" {
+		return addr.Email
+	}
+	return fmt.Sprintf("%s <%s>", addr.Name, addr.Email)
+}
+
+func formatEmailAddressList(addrs []EmailAddress) string {
+	formatted := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		formatted = append(formatted, formatEmailAddress(a))
+	}
+	return strings.Join(formatted, ", ")
+}
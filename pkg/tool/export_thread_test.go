@@ -0,0 +1,211 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+const expectedThreadMarkdown = `# Project kickoff
+
+Thread ID: t-001, messages: 2
+
+---
+
+## 1. Alice <alice@example.com>
+
+- **Date:** 2025-01-01 10:00:00
+- **To:** Bob <bob@example.com>
+- **Subject:** Project kickoff
+
+Plain body of m-001
+
+**Attachments:**
+
+- agenda.pdf (application/pdf, 1024 bytes)
+
+---
+
+## 2. bob@example.com
+
+- **Date:** 2025-01-02 11:00:00
+- **To:** Alice <alice@example.com>
+- **Subject:** Re: Project kickoff
+
+**Converted from HTML**
+`
+
+const quotedReply = "\n\nOn Wed, Jan 1, 2025 Alice <alice@example.com> wrote:\n\n> Plain body of m-001"
+
+func newExportThreadGmailSvc() *gmailSvcMock {
+	return &gmailSvcMock{
+		GetThreadFunc: func(_ context.Context, threadID string) (*gmail.Thread, error) {
+			if threadID != "t-001" {
+				return nil, fmt.Errorf("thread not found: %s", threadID)
+			}
+			return &gmail.Thread{
+				Id: threadID,
+				Messages: []*gmail.Message{
+					{
+						Id: "m-001",
+						Payload: &gmail.MessagePart{
+							Headers: []*gmail.MessagePartHeader{
+								{Name: "From", Value: "Alice <alice@example.com>"},
+								{Name: "To", Value: "Bob <bob@example.com>"},
+								{Name: "Subject", Value: "Project kickoff"},
+								{Name: "Date", Value: "2025-01-01 10:00:00"},
+							},
+							MimeType: "multipart/mixed",
+							Parts: []*gmail.MessagePart{
+								{
+									MimeType: "text/plain",
+									Body:     &gmail.MessagePartBody{Data: "UGxhaW4gYm9keSBvZiBtLTAwMQ=="}, // "Plain body of m-001"
+								},
+								{
+									PartId:   "1",
+									Filename: "agenda.pdf",
+									MimeType: "application/pdf",
+									Body:     &gmail.MessagePartBody{AttachmentId: "att-1", Size: 1024},
+								},
+							},
+						},
+					},
+					{
+						Id: "m-002",
+						Payload: &gmail.MessagePart{
+							Headers: []*gmail.MessagePartHeader{
+								{Name: "From", Value: "bob@example.com"},
+								{Name: "To", Value: "Alice <alice@example.com>"},
+								{Name: "Subject", Value: "Re: Project kickoff"},
+								{Name: "Date", Value: "2025-01-02 11:00:00"},
+							},
+							MimeType: "text/html",
+							Body:     &gmail.MessagePartBody{Data: "PGI+SFRNTDwvYj4="}, // "<b>HTML</b>"
+						},
+					},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestExportThreadMarkdown(t *testing.T) {
+	exportDir := t.TempDir()
+
+	cases := []struct {
+		name          string
+		req           tool.ExportThreadRequest
+		exportDir     string
+		expected      tool.ExportThreadResponse
+		expectedSaved string
+		expectedErr   error
+	}{
+		{
+			name: "render only",
+			req:  tool.ExportThreadRequest{ThreadID: "t-001"},
+			expected: tool.ExportThreadResponse{
+				ThreadID:     "t-001",
+				MessageCount: 2,
+				Markdown:     expectedThreadMarkdown,
+			},
+		},
+		{
+			name: "keep quotes",
+			req:  tool.ExportThreadRequest{ThreadID: "t-001", KeepQuotes: true},
+			expected: tool.ExportThreadResponse{
+				ThreadID:     "t-001",
+				MessageCount: 2,
+				Markdown:     strings.Replace(expectedThreadMarkdown, "**Converted from HTML**", "**Converted from HTML**"+quotedReply, 1),
+			},
+		},
+		{
+			name:      "render and save",
+			req:       tool.ExportThreadRequest{ThreadID: "t-001", Save: true},
+			exportDir: exportDir,
+			expected: tool.ExportThreadResponse{
+				ThreadID:     "t-001",
+				MessageCount: 2,
+				Markdown:     expectedThreadMarkdown,
+				SavedPath:    filepath.Join(exportDir, "thread-t-001.md"),
+			},
+			expectedSaved: filepath.Join(exportDir, "thread-t-001.md"),
+		},
+		{
+			name:        "save without export dir",
+			req:         tool.ExportThreadRequest{ThreadID: "t-001", Save: true},
+			expectedErr: tool.ErrDirNotConfigured,
+		},
+		{
+			name:        "thread not found",
+			req:         tool.ExportThreadRequest{ThreadID: "t-404"},
+			expectedErr: fmt.Errorf("thread not found: t-404"),
+		},
+	}
+
+	converter := &converterMock{
+		HTML2MDFunc: func(_ []byte) (string, error) {
+			return "**Converted from HTML**" + quotedReply, nil
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := tool.NewServer(newExportThreadGmailSvc(), converter, tool.WithExportDir(tc.exportDir))
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+
+			clientSession, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer clientSession.Close()
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "export_thread_markdown",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.ExportThreadResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			// About four bytes of markdown per token.
+			tc.expected.EstimatedTokens = (len(tc.expected.Markdown) + 3) / 4
+			assert.Equal(t, tc.expected, response)
+
+			if tc.expectedSaved != "" {
+				saved, err := os.ReadFile(tc.expectedSaved)
+				require.NoError(t, err)
+				assert.Equal(t, expectedThreadMarkdown, string(saved))
+			}
+		})
+	}
+}
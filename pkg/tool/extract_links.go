@@ -0,0 +1,100 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+)
+
+// ExtractLinksRequest contains message IDs to extract links from.
+type ExtractLinksRequest struct {
+	MessageIDs []string `json:"message_ids" jsonschema:"array of message IDs to extract links from"`
+}
+
+// ExtractLinksResponse contains links per message.
+type ExtractLinksResponse struct {
+	Messages []MessageLinks `json:"messages" jsonschema:"links grouped by message"`
+}
+
+// MessageLinks contains hyperlinks of a single message.
+type MessageLinks struct {
+	MessageID string `json:"message_id" jsonschema:"message ID"`
+	Links     []Link `json:"links" jsonschema:"hyperlinks in order of appearance"`
+}
+
+// Link represents a classified hyperlink.
+type Link struct {
+	URL  string `json:"url" jsonschema:"link target"`
+	Text string `json:"text,omitempty" jsonschema:"anchor text"`
+	Kind string `json:"kind" jsonschema:"classification: unsubscribe, tracking, document, login or other"`
+
+	DriveFileID string `json:"drive_file_id,omitempty" jsonschema:"ID of the linked Google Drive file, readable with preview_drive_files when the server enables Drive"`
+}
+
+type extractLinksSvc interface {
+	GetMessage(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewExtractLinks creates a new ExtractLinks tool.
+func NewExtractLinks(svc extractLinksSvc) *ExtractLinks {
+	return &ExtractLinks{
+		svc: svc,
+	}
+}
+
+// ExtractLinks lists hyperlinks found in message bodies.
+type ExtractLinks struct {
+	svc extractLinksSvc
+}
+
+// ExtractLinks returns classified links of the given messages.
+func (t *ExtractLinks) ExtractLinks(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input ExtractLinksRequest,
+) (*mcp.CallToolResult, ExtractLinksResponse, error) {
+	messages := make([]MessageLinks, 0, len(input.MessageIDs))
+
+	for _, msgID := range input.MessageIDs {
+		msg, err := t.svc.GetMessage(ctx, msgID)
+		if err != nil {
+			return nil, ExtractLinksResponse{}, fmt.Errorf("get message %s failed: %w", msgID, err)
+		}
+
+		messages = append(messages, MessageLinks{
+			MessageID: msgID,
+			Links:     extractMessageLinks(msg),
+		})
+	}
+
+	return nil, ExtractLinksResponse{
+		Messages: messages,
+	}, nil
+}
+
+// extractMessageLinks prefers the HTML body because anchor text is lost in plain-text alternatives.
+func extractMessageLinks(msg *gmail.Message) []Link {
+	links := []Link{}
+	if msg.Payload == nil {
+		return links
+	}
+
+	textBody, htmlBody := extractMessageBodies(msg.Payload)
+
+	var found []format.Link
+	if htmlBody != "" {
+		found = format.ExtractLinks([]byte(htmlBody))
+	} else {
+		found = format.ExtractTextLinks(textBody)
+	}
+
+	for _, l := range found {
+		links = append(links, Link{URL: l.URL, Text: l.Text, Kind: l.Kind, DriveFileID: format.DriveFileID(l.URL)})
+	}
+
+	return links
+}
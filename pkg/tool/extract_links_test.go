@@ -0,0 +1,121 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestExtractLinks(t *testing.T) {
+	encode := func(s string) string {
+		return base64.URLEncoding.EncodeToString([]byte(s))
+	}
+
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			switch msgID {
+			case "msg-html":
+				return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{
+					MimeType: "multipart/alternative",
+					Parts: []*gmail.MessagePart{
+						{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: encode("Plain https://example.com/plain")}},
+						{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: encode(
+							`<p><a href="https://example.com/report.pdf">Report</a> <a href="https://example.com/unsubscribe">Unsubscribe</a></p>`,
+						)}},
+					},
+				}}, nil
+			case "msg-text":
+				return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{
+					MimeType: "text/plain",
+					Body:     &gmail.MessagePartBody{Data: encode("Sign in at https://example.com/signin now")},
+				}}, nil
+			}
+			return nil, fmt.Errorf("message not found: %s", msgID)
+		},
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.ExtractLinksRequest
+		expected    tool.ExtractLinksResponse
+		expectedErr error
+	}{
+		{
+			name: "html and text bodies",
+			req:  tool.ExtractLinksRequest{MessageIDs: []string{"msg-html", "msg-text"}},
+			expected: tool.ExtractLinksResponse{
+				Messages: []tool.MessageLinks{
+					{
+						MessageID: "msg-html",
+						Links: []tool.Link{
+							{URL: "https://example.com/report.pdf", Text: "Report", Kind: "document"},
+							{URL: "https://example.com/unsubscribe", Text: "Unsubscribe", Kind: "unsubscribe"},
+						},
+					},
+					{
+						MessageID: "msg-text",
+						Links: []tool.Link{
+							{URL: "https://example.com/signin", Kind: "login"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "error case",
+			req:         tool.ExtractLinksRequest{MessageIDs: []string{"error-msg"}},
+			expectedErr: fmt.Errorf("message not found: error-msg"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "extract_links",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.ExtractLinksResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	hasAttachmentQuery         = "has:attachment"
+	defaultAttachmentsMessages = 50
+)
+
+// FindAttachmentsRequest contains attachment search parameters.
+type FindAttachmentsRequest struct {
+	Query       string `json:"query,omitempty" jsonschema:"additional Gmail search filters, e.g. from:maria after:2025/03/01"`
+	Filename    string `json:"filename,omitempty" jsonschema:"case-insensitive substring the filename must contain"`
+	MimeType    string `json:"mime_type,omitempty" jsonschema:"MIME type prefix, e.g. application/pdf or image/"`
+	MaxMessages int    `json:"max_messages,omitempty" jsonschema:"max messages to scan, default 50, up to 1000"`
+}
+
+// FindAttachmentsResponse contains a flat list of matching attachments.
+type FindAttachmentsResponse struct {
+	Attachments     []FoundAttachment `json:"attachments" jsonschema:"matching attachments"`
+	ScannedMessages int               `json:"scanned_messages" jsonschema:"number of messages scanned"`
+	Truncated       bool              `json:"truncated" jsonschema:"true when more messages matched than were scanned"`
+}
+
+// FoundAttachment describes an attachment together with its message.
+type FoundAttachment struct {
+	ID        string       `json:"id" jsonschema:"attachment ID (Part ID)"`
+	Filename  string       `json:"filename" jsonschema:"original filename"`
+	MimeType  string       `json:"mime_type" jsonschema:"MIME type"`
+	Size      int64        `json:"size" jsonschema:"size in bytes"`
+	MessageID string       `json:"message_id" jsonschema:"ID of the message containing the attachment"`
+	From      EmailAddress `json:"from" jsonschema:"sender information"`
+	Subject   string       `json:"subject" jsonschema:"message subject"`
+	Timestamp string       `json:"timestamp" jsonschema:"message timestamp"`
+}
+
+type findAttachmentsSvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	GetMessage(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewFindAttachments creates a new FindAttachments tool.
+func NewFindAttachments(svc findAttachmentsSvc) *FindAttachments {
+	return &FindAttachments{
+		svc: svc,
+	}
+}
+
+// FindAttachments searches attachments across many messages.
+type FindAttachments struct {
+	svc findAttachmentsSvc
+}
+
+// FindAttachments scans messages with attachments and lists those matching the filters.
+func (t *FindAttachments) FindAttachments(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input FindAttachmentsRequest,
+) (*mcp.CallToolResult, FindAttachmentsResponse, error) {
+	query := strings.TrimSpace(hasAttachmentQuery + " " + input.Query)
+
+	limit := input.MaxMessages
+	if limit <= 0 {
+		limit = defaultAttachmentsMessages
+	}
+	refs, truncated, err := scanMessageRefs(ctx, t.svc, query, normalizeScanLimit(limit))
+	if err != nil {
+		return nil, FindAttachmentsResponse{}, fmt.Errorf("scanMessageRefs failed: %w", err)
+	}
+
+	found := []FoundAttachment{}
+	for _, ref := range refs {
+		msg, err := t.svc.GetMessage(ctx, ref.Id)
+		if err != nil {
+			return nil, FindAttachmentsResponse{}, fmt.Errorf("get message %s failed: %w", ref.Id, err)
+		}
+		if msg.Payload == nil {
+			continue
+		}
+
+		summary := extractMessageSummary(msg)
+		for _, a := range extractAttachments(msg.Payload) {
+			if !matchAttachment(a, input.Filename, input.MimeType) {
+				continue
+			}
+			found = append(found, FoundAttachment{
+				ID:        a.ID,
+				Filename:  a.Filename,
+				MimeType:  a.MimeType,
+				Size:      a.Size,
+				MessageID: msg.Id,
+				From:      summary.From,
+				Subject:   summary.Subject,
+				Timestamp: summary.Timestamp,
+			})
+		}
+	}
+
+	return nil, FindAttachmentsResponse{
+		Attachments:     found,
+		ScannedMessages: len(refs),
+		Truncated:       truncated,
+	}, nil
+}
+
+func matchAttachment(a Attachment, filename, mimeType string) bool {
+	if filename != "" && !strings.Contains(strings.ToLower(a.Filename), strings.ToLower(filename)) {
+		return false
+	}
+	if mimeType != "" && !strings.HasPrefix(strings.ToLower(a.MimeType), strings.ToLower(mimeType)) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestFindAttachments(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		ListMessagesFunc: func(_ context.Context, Q, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+			if Q != "has:attachment from:maria" && Q != "has:attachment" {
+				return nil, fmt.Errorf("simulated error: %s", Q)
+			}
+			return &gmail.ListMessagesResponse{
+				Messages: []*gmail.Message{{Id: "msg-001"}, {Id: "msg-002"}},
+			}, nil
+		},
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			msg := &gmail.Message{
+				Id: msgID,
+				Payload: &gmail.MessagePart{
+					MimeType: "multipart/mixed",
+					Headers: []*gmail.MessagePartHeader{
+						{Name: "From", Value: "Maria <maria@example.com>"},
+						{Name: "Subject", Value: "Invoice " + msgID},
+						{Name: "Date", Value: "2025-03-10 10:00:00"},
+					},
+				},
+			}
+			switch msgID {
+			case "msg-001":
+				msg.Payload.Parts = []*gmail.MessagePart{
+					{PartId: "0", MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: "dGV4dA=="}},
+					{PartId: "1", Filename: "invoice-march.pdf", MimeType: "application/pdf", Body: &gmail.MessagePartBody{AttachmentId: "att-1", Size: 2048}},
+				}
+			case "msg-002":
+				msg.Payload.Parts = []*gmail.MessagePart{
+					{PartId: "1", Filename: "photo.jpg", MimeType: "image/jpeg", Body: &gmail.MessagePartBody{AttachmentId: "att-2", Size: 4096}},
+				}
+			}
+			return msg, nil
+		},
+	}
+
+	invoice := tool.FoundAttachment{
+		ID:        "1",
+		Filename:  "invoice-march.pdf",
+		MimeType:  "application/pdf",
+		Size:      2048,
+		MessageID: "msg-001",
+		From:      tool.EmailAddress{Name: "Maria", Email: "maria@example.com"},
+		Subject:   "Invoice msg-001",
+		Timestamp: "2025-03-10 10:00:00",
+	}
+	photo := tool.FoundAttachment{
+		ID:        "1",
+		Filename:  "photo.jpg",
+		MimeType:  "image/jpeg",
+		Size:      4096,
+		MessageID: "msg-002",
+		From:      tool.EmailAddress{Name: "Maria", Email: "maria@example.com"},
+		Subject:   "Invoice msg-002",
+		Timestamp: "2025-03-10 10:00:00",
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.FindAttachmentsRequest
+		expected    tool.FindAttachmentsResponse
+		expectedErr error
+	}{
+		{
+			name: "all attachments",
+			req:  tool.FindAttachmentsRequest{},
+			expected: tool.FindAttachmentsResponse{
+				Attachments:     []tool.FoundAttachment{invoice, photo},
+				ScannedMessages: 2,
+			},
+		},
+		{
+			name: "filtered by mime type and filename",
+			req:  tool.FindAttachmentsRequest{Query: "from:maria", Filename: "INVOICE", MimeType: "application/"},
+			expected: tool.FindAttachmentsResponse{
+				Attachments:     []tool.FoundAttachment{invoice},
+				ScannedMessages: 2,
+			},
+		},
+		{
+			name: "no matches",
+			req:  tool.FindAttachmentsRequest{MimeType: "text/csv"},
+			expected: tool.FindAttachmentsResponse{
+				Attachments:     []tool.FoundAttachment{},
+				ScannedMessages: 2,
+			},
+		},
+		{
+			name:        "list error",
+			req:         tool.FindAttachmentsRequest{Query: "undefined"},
+			expectedErr: fmt.Errorf("simulated error: has:attachment undefined"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "find_attachments",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.FindAttachmentsResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package tool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	sentLabelID                = "SENT"
+	defaultCorrespondentsQuery = "newer_than:90d"
+	defaultTopCorrespondents   = 20
+)
+
+// FrequentCorrespondentsRequest contains parameters for correspondent ranking.
+type FrequentCorrespondentsRequest struct {
+	Query       string `json:"query,omitempty" jsonschema:"the Gmail search query limiting scanned messages, default newer_than:90d"`
+	MaxMessages int    `json:"max_messages,omitempty" jsonschema:"max messages to scan, default 200, up to 1000"`
+	Top         int    `json:"top,omitempty" jsonschema:"number of correspondents to return, default 20"`
+}
+
+// FrequentCorrespondentsResponse contains correspondents ranked by interactions.
+type FrequentCorrespondentsResponse struct {
+	Correspondents  []Correspondent `json:"correspondents" jsonschema:"correspondents ranked by number of messages exchanged"`
+	ScannedMessages int             `json:"scanned_messages" jsonschema:"number of messages aggregated"`
+	Truncated       bool            `json:"truncated" jsonschema:"true when more messages matched than were scanned"`
+}
+
+// Correspondent aggregates messages exchanged with a single address.
+type Correspondent struct {
+	Address      EmailAddress `json:"address" jsonschema:"correspondent information"`
+	Sent         int          `json:"sent" jsonschema:"number of messages sent to the correspondent"`
+	Received     int          `json:"received" jsonschema:"number of messages received from the correspondent"`
+	LastSent     string       `json:"last_sent,omitempty" jsonschema:"date of the newest message sent to the correspondent"`
+	LastReceived string       `json:"last_received,omitempty" jsonschema:"date of the newest message received from the correspondent"`
+	LastContact  string       `json:"last_contact" jsonschema:"date of the newest message in either direction"`
+}
+
+type frequentCorrespondentsSvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewFrequentCorrespondents creates a new FrequentCorrespondents tool.
+func NewFrequentCorrespondents(svc frequentCorrespondentsSvc) *FrequentCorrespondents {
+	return &FrequentCorrespondents{
+		svc: svc,
+	}
+}
+
+// FrequentCorrespondents ranks people the mailbox owner exchanges mail with.
+type FrequentCorrespondents struct {
+	svc frequentCorrespondentsSvc
+}
+
+type correspondentAggregate struct {
+	stats        Correspondent
+	lastSent     time.Time
+	lastReceived time.Time
+	lastContact  time.Time
+}
+
+// FrequentCorrespondents scans sent and received messages and ranks their counterparts.
+func (t *FrequentCorrespondents) FrequentCorrespondents(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input FrequentCorrespondentsRequest,
+) (*mcp.CallToolResult, FrequentCorrespondentsResponse, error) {
+	query := input.Query
+	if query == "" {
+		query = defaultCorrespondentsQuery
+	}
+
+	refs, truncated, err := scanMessageRefs(ctx, t.svc, query, normalizeScanLimit(input.MaxMessages))
+	if err != nil {
+		return nil, FrequentCorrespondentsResponse{}, fmt.Errorf("scanMessageRefs failed: %w", err)
+	}
+
+	byAddress := make(map[string]*correspondentAggregate)
+	for _, ref := range refs {
+		msg, err := t.svc.GetMessageMetadata(ctx, ref.Id)
+		if err != nil {
+			return nil, FrequentCorrespondentsResponse{}, fmt.Errorf("get message %s failed: %w", ref.Id, err)
+		}
+
+		summary := extractMessageSummary(msg)
+		received := time.UnixMilli(msg.InternalDate).UTC()
+
+		if !slices.Contains(msg.LabelIds, sentLabelID) {
+			correspondentFor(byAddress, summary.From).addReceived(received)
+			continue
+		}
+		for _, addr := range slices.Concat(summary.To, summary.CC) {
+			correspondentFor(byAddress, addr).addSent(received)
+		}
+	}
+
+	return nil, FrequentCorrespondentsResponse{
+		Correspondents:  rankCorrespondents(byAddress, input.Top),
+		ScannedMessages: len(refs),
+		Truncated:       truncated,
+	}, nil
+}
+
+func correspondentFor(byAddress map[string]*correspondentAggregate, addr EmailAddress) *correspondentAggregate {
+	key := strings.ToLower(addr.Email)
+	agg, ok := byAddress[key]
+	if !ok {
+		agg = &correspondentAggregate{stats: Correspondent{Address: addr}}
+		byAddress[key] = agg
+	}
+	if agg.stats.Address.Name == "" {
+		agg.stats.Address.Name = addr.Name
+	}
+	return agg
+}
+
+func (a *correspondentAggregate) addSent(date time.Time) {
+	a.stats.Sent++
+	if date.After(a.lastSent) {
+		a.lastSent = date
+		a.stats.LastSent = date.Format(time.RFC3339)
+	}
+	a.touch(date)
+}
+
+func (a *correspondentAggregate) addReceived(date time.Time) {
+	a.stats.Received++
+	if date.After(a.lastReceived) {
+		a.lastReceived = date
+		a.stats.LastReceived = date.Format(time.RFC3339)
+	}
+	a.touch(date)
+}
+
+func (a *correspondentAggregate) touch(date time.Time) {
+	if date.After(a.lastContact) {
+		a.lastContact = date
+		a.stats.LastContact = date.Format(time.RFC3339)
+	}
+}
+
+func rankCorrespondents(byAddress map[string]*correspondentAggregate, top int) []Correspondent {
+	if top <= 0 {
+		top = defaultTopCorrespondents
+	}
+
+	ranked := make([]*correspondentAggregate, 0, len(byAddress))
+	for _, agg := range byAddress {
+		ranked = append(ranked, agg)
+	}
+
+	slices.SortFunc(ranked, func(a, b *correspondentAggregate) int {
+		return cmp.Or(
+			cmp.Compare(b.stats.Sent+b.stats.Received, a.stats.Sent+a.stats.Received),
+			b.lastContact.Compare(a.lastContact),
+			strings.Compare(a.stats.Address.Email, b.stats.Address.Email),
+		)
+	})
+
+	correspondents := make([]Correspondent, 0, min(top, len(ranked)))
+	for _, agg := range ranked[:min(top, len(ranked))] {
+		correspondents = append(correspondents, agg.stats)
+	}
+	return correspondents
+}
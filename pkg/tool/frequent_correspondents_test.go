@@ -0,0 +1,105 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestFrequentCorrespondents(t *testing.T) {
+	fixtures := map[string]scanFixture{
+		"m-1": {from: "Alice <alice@example.com>", to: "me@example.com", labels: []string{"INBOX"}, internalDate: 1735725600000},
+		"m-2": {from: "me@example.com", to: "alice@example.com, Bob <bob@example.com>", labels: []string{"SENT"}, internalDate: 1735812000000},
+		"m-3": {from: "Carol <carol@example.com>", to: "me@example.com", labels: []string{"INBOX"}, internalDate: 1735898400000},
+		"m-4": {from: "Bob <bob@example.com>", to: "me@example.com", labels: []string{"INBOX"}, internalDate: 1735639200000},
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.FrequentCorrespondentsRequest
+		expected    tool.FrequentCorrespondentsResponse
+		expectedErr error
+	}{
+		{
+			name: "default query",
+			req:  tool.FrequentCorrespondentsRequest{Top: 2},
+			expected: tool.FrequentCorrespondentsResponse{
+				ScannedMessages: 4,
+				Correspondents: []tool.Correspondent{
+					{
+						Address:      tool.EmailAddress{Name: "Alice", Email: "alice@example.com"},
+						Sent:         1,
+						Received:     1,
+						LastSent:     "2025-01-02T10:00:00Z",
+						LastReceived: "2025-01-01T10:00:00Z",
+						LastContact:  "2025-01-02T10:00:00Z",
+					},
+					{
+						Address:      tool.EmailAddress{Name: "Bob", Email: "bob@example.com"},
+						Sent:         1,
+						Received:     1,
+						LastSent:     "2025-01-02T10:00:00Z",
+						LastReceived: "2024-12-31T10:00:00Z",
+						LastContact:  "2025-01-02T10:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name:        "list error",
+			req:         tool.FrequentCorrespondentsRequest{Query: "undefined"},
+			expectedErr: fmt.Errorf("simulated error: undefined"),
+		},
+	}
+
+	gmailSvc := newScanGmailSvc("newer_than:90d", [][]string{{"m-1", "m-2"}, {"m-3", "m-4"}}, fixtures)
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "frequent_correspondents",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.FrequentCorrespondentsResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
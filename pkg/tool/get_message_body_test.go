@@ -0,0 +1,147 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestGetMessageBody(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			if msgID != "msg-long" {
+				return nil, fmt.Errorf("message not found: %s", msgID)
+			}
+			return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{
+				MimeType: "text/plain",
+				Body: &gmail.MessagePartBody{
+					Data: base64.URLEncoding.EncodeToString([]byte("line one\nline two\nline three")),
+				},
+			}}, nil
+		},
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.GetMessageBodyRequest
+		expected    tool.GetMessageBodyResponse
+		expectedErr error
+	}{
+		{
+			name: "first chunk ends on line break",
+			req:  tool.GetMessageBodyRequest{MessageID: "msg-long", Length: 12},
+			expected: tool.GetMessageBodyResponse{
+				MessageID:       "msg-long",
+				Content:         "line one\n",
+				EstimatedTokens: 3,
+				TotalLength:     28,
+				NextOffset:      9,
+				HasMore:         true,
+			},
+		},
+		{
+			name: "middle chunk",
+			req:  tool.GetMessageBodyRequest{MessageID: "msg-long", Offset: 9, Length: 12},
+			expected: tool.GetMessageBodyResponse{
+				MessageID:       "msg-long",
+				Content:         "line two\n",
+				EstimatedTokens: 3,
+				Offset:          9,
+				TotalLength:     28,
+				NextOffset:      18,
+				HasMore:         true,
+			},
+		},
+		{
+			name: "last chunk",
+			req:  tool.GetMessageBodyRequest{MessageID: "msg-long", Offset: 18, Length: 12},
+			expected: tool.GetMessageBodyResponse{
+				MessageID:       "msg-long",
+				Content:         "line three",
+				EstimatedTokens: 3,
+				Offset:          18,
+				TotalLength:     28,
+			},
+		},
+		{
+			name: "whole body by default",
+			req:  tool.GetMessageBodyRequest{MessageID: "msg-long"},
+			expected: tool.GetMessageBodyResponse{
+				MessageID:       "msg-long",
+				Content:         "line one\nline two\nline three",
+				EstimatedTokens: 7,
+				TotalLength:     28,
+			},
+		},
+		{
+			name: "offset past the end",
+			req:  tool.GetMessageBodyRequest{MessageID: "msg-long", Offset: 100},
+			expected: tool.GetMessageBodyResponse{
+				MessageID:   "msg-long",
+				Offset:      28,
+				TotalLength: 28,
+			},
+		},
+		{
+			name:        "negative offset",
+			req:         tool.GetMessageBodyRequest{MessageID: "msg-long", Offset: -1},
+			expectedErr: fmt.Errorf("offset must not be negative"),
+		},
+		{
+			name:        "error case",
+			req:         tool.GetMessageBodyRequest{MessageID: "error-msg"},
+			expectedErr: fmt.Errorf("message not found: error-msg"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "get_message_body",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.GetMessageBodyResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
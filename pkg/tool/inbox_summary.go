@@ -0,0 +1,128 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	inboxUnreadQuery        = "in:inbox is:unread"
+	defaultInboxTopSenders  = 5
+	defaultInboxUnreadScan  = 100
+	systemLabelType         = "system"
+	labelListVisibilityHide = "labelHide"
+)
+
+// InboxSummaryRequest contains parameters for the inbox briefing.
+type InboxSummaryRequest struct {
+	TopSenders    int `json:"top_senders,omitempty" jsonschema:"number of top unread senders, default 5"`
+	MaxUnreadScan int `json:"max_unread_scan,omitempty" jsonschema:"max unread inbox messages scanned for senders, default 100"`
+}
+
+// InboxSummaryResponse contains label counters and top unread senders.
+type InboxSummaryResponse struct {
+	Labels           []LabelCounts `json:"labels" jsonschema:"message and thread counters per system label"`
+	TopUnreadSenders []SenderStats `json:"top_unread_senders" jsonschema:"senders with the most unread inbox messages"`
+	UnreadScanned    int           `json:"unread_scanned" jsonschema:"number of unread inbox messages scanned for senders"`
+	Truncated        bool          `json:"truncated" jsonschema:"true when more unread messages exist than were scanned"`
+}
+
+// LabelCounts contains message and thread counters of a label.
+type LabelCounts struct {
+	ID             string `json:"id" jsonschema:"label ID"`
+	Name           string `json:"name" jsonschema:"label name"`
+	MessagesTotal  int64  `json:"messages_total" jsonschema:"total messages"`
+	MessagesUnread int64  `json:"messages_unread" jsonschema:"unread messages"`
+	ThreadsTotal   int64  `json:"threads_total" jsonschema:"total threads"`
+	ThreadsUnread  int64  `json:"threads_unread" jsonschema:"unread threads"`
+}
+
+type inboxSummarySvc interface {
+	ListLabels(ctx context.Context) ([]*gmail.Label, error)
+	GetLabel(ctx context.Context, labelID string) (*gmail.Label, error)
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewInboxSummary creates a new InboxSummary tool.
+func NewInboxSummary(svc inboxSummarySvc) *InboxSummary {
+	return &InboxSummary{
+		svc: svc,
+	}
+}
+
+// InboxSummary builds a one-call inbox briefing.
+type InboxSummary struct {
+	svc inboxSummarySvc
+}
+
+// InboxSummary returns counters per system label and the top unread senders.
+func (t *InboxSummary) InboxSummary(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input InboxSummaryRequest,
+) (*mcp.CallToolResult, InboxSummaryResponse, error) {
+	labels, err := t.systemLabelCounts(ctx)
+	if err != nil {
+		return nil, InboxSummaryResponse{}, fmt.Errorf("systemLabelCounts failed: %w", err)
+	}
+
+	scanLimit := input.MaxUnreadScan
+	if scanLimit <= 0 {
+		scanLimit = defaultInboxUnreadScan
+	}
+	refs, truncated, err := scanMessageRefs(ctx, t.svc, inboxUnreadQuery, normalizeScanLimit(scanLimit))
+	if err != nil {
+		return nil, InboxSummaryResponse{}, fmt.Errorf("scanMessageRefs failed: %w", err)
+	}
+
+	bySender, err := aggregateSenders(ctx, t.svc, refs)
+	if err != nil {
+		return nil, InboxSummaryResponse{}, fmt.Errorf("aggregateSenders failed: %w", err)
+	}
+
+	topSenders := input.TopSenders
+	if topSenders <= 0 {
+		topSenders = defaultInboxTopSenders
+	}
+
+	return nil, InboxSummaryResponse{
+		Labels:           labels,
+		TopUnreadSenders: rankSenders(bySender, topSenders),
+		UnreadScanned:    len(refs),
+		Truncated:        truncated,
+	}, nil
+}
+
+func (t *InboxSummary) systemLabelCounts(ctx context.Context) ([]LabelCounts, error) {
+	labels, err := t.svc.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("svc.ListLabels failed: %w", err)
+	}
+
+	counts := make([]LabelCounts, 0, len(labels))
+	for _, l := range labels {
+		if l.Type != systemLabelType || l.LabelListVisibility == labelListVisibilityHide {
+			continue
+		}
+
+		label, err := t.svc.GetLabel(ctx, l.Id)
+		if err != nil {
+			return nil, fmt.Errorf("get label %s failed: %w", l.Id, err)
+		}
+
+		counts = append(counts, LabelCounts{
+			ID:             label.Id,
+			Name:           label.Name,
+			MessagesTotal:  label.MessagesTotal,
+			MessagesUnread: label.MessagesUnread,
+			ThreadsTotal:   label.ThreadsTotal,
+			ThreadsUnread:  label.ThreadsUnread,
+		})
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,133 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestInboxSummary(t *testing.T) {
+	fixtures := map[string]scanFixture{
+		"m-1": {from: "Alice <alice@example.com>", internalDate: 1735725600000, size: 100},
+		"m-2": {from: "Bob <bob@example.com>", internalDate: 1735812000000, size: 5000},
+		"m-3": {from: "Alice <alice@example.com>", internalDate: 1735898400000, size: 200},
+	}
+
+	gmailSvc := newScanGmailSvc("in:inbox is:unread", [][]string{{"m-1", "m-2"}, {"m-3"}}, fixtures)
+	gmailSvc.ListLabelsFunc = func(_ context.Context) ([]*gmail.Label, error) {
+		return []*gmail.Label{
+			{Id: "INBOX", Name: "INBOX", Type: "system"},
+			{Id: "CHAT", Name: "CHAT", Type: "system", LabelListVisibility: "labelHide"},
+			{Id: "Label_1", Name: "Work", Type: "user"},
+			{Id: "SPAM", Name: "SPAM", Type: "system"},
+		}, nil
+	}
+	gmailSvc.GetLabelFunc = func(_ context.Context, labelID string) (*gmail.Label, error) {
+		switch labelID {
+		case "INBOX":
+			return &gmail.Label{Id: "INBOX", Name: "INBOX", MessagesTotal: 120, MessagesUnread: 3, ThreadsTotal: 80, ThreadsUnread: 2}, nil
+		case "SPAM":
+			return &gmail.Label{Id: "SPAM", Name: "SPAM", MessagesTotal: 7, MessagesUnread: 7, ThreadsTotal: 7, ThreadsUnread: 7}, nil
+		}
+		return nil, fmt.Errorf("simulated error: %s", labelID)
+	}
+
+	cases := []struct {
+		name     string
+		req      tool.InboxSummaryRequest
+		expected tool.InboxSummaryResponse
+	}{
+		{
+			name: "defaults",
+			req:  tool.InboxSummaryRequest{},
+			expected: tool.InboxSummaryResponse{
+				Labels: []tool.LabelCounts{
+					{ID: "INBOX", Name: "INBOX", MessagesTotal: 120, MessagesUnread: 3, ThreadsTotal: 80, ThreadsUnread: 2},
+					{ID: "SPAM", Name: "SPAM", MessagesTotal: 7, MessagesUnread: 7, ThreadsTotal: 7, ThreadsUnread: 7},
+				},
+				TopUnreadSenders: []tool.SenderStats{
+					{
+						Sender:    tool.EmailAddress{Name: "Alice", Email: "alice@example.com"},
+						Count:     2,
+						TotalSize: 300,
+						FirstDate: "2025-01-01T10:00:00Z",
+						LastDate:  "2025-01-03T10:00:00Z",
+					},
+					{
+						Sender:    tool.EmailAddress{Name: "Bob", Email: "bob@example.com"},
+						Count:     1,
+						TotalSize: 5000,
+						FirstDate: "2025-01-02T10:00:00Z",
+						LastDate:  "2025-01-02T10:00:00Z",
+					},
+				},
+				UnreadScanned: 3,
+			},
+		},
+		{
+			name: "limited scan",
+			req:  tool.InboxSummaryRequest{TopSenders: 1, MaxUnreadScan: 1},
+			expected: tool.InboxSummaryResponse{
+				Labels: []tool.LabelCounts{
+					{ID: "INBOX", Name: "INBOX", MessagesTotal: 120, MessagesUnread: 3, ThreadsTotal: 80, ThreadsUnread: 2},
+					{ID: "SPAM", Name: "SPAM", MessagesTotal: 7, MessagesUnread: 7, ThreadsTotal: 7, ThreadsUnread: 7},
+				},
+				TopUnreadSenders: []tool.SenderStats{
+					{
+						Sender:    tool.EmailAddress{Name: "Alice", Email: "alice@example.com"},
+						Count:     1,
+						TotalSize: 100,
+						FirstDate: "2025-01-01T10:00:00Z",
+						LastDate:  "2025-01-01T10:00:00Z",
+					},
+				},
+				UnreadScanned: 1,
+				Truncated:     true,
+			},
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "inbox_summary",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+			require.False(t, result.IsError, "Result should not indicate error")
+
+			var response tool.InboxSummaryResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
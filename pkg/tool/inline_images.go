@@ -0,0 +1,153 @@
+package tool
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+	"github.com/hal9000y/gmail-mcp/pkg/redact"
+)
+
+// InlineImage is an image the HTML body embeds by Content-ID.
+type InlineImage struct {
+	ContentID    string `json:"content_id" jsonschema:"Content-ID the body references"`
+	AttachmentID string `json:"attachment_id,omitempty" jsonschema:"ID to fetch the image with preview_attachments or save_attachment"`
+	Filename     string `json:"filename,omitempty" jsonschema:"original filename"`
+	MimeType     string `json:"mime_type,omitempty" jsonschema:"MIME type"`
+	Size         int64  `json:"size,omitempty" jsonschema:"size in bytes"`
+	Alt          string `json:"alt,omitempty" jsonschema:"alt text of the image"`
+}
+
+// footnoteURLLength is the URL length from which footnote_links moves URLs out of the text.
+const footnoteURLLength = 40
+
+// bodyOptions adjusts how message bodies are rendered.
+type bodyOptions struct {
+	// stripQuotes removes the quoted history of replies.
+	stripQuotes bool
+	// footnoteLinks moves long URLs into numbered references at the end.
+	footnoteLinks bool
+	// redactor masks personal data, nil to keep it.
+	redactor *redact.Redactor
+}
+
+// renderedBody is a message body converted to text.
+type renderedBody struct {
+	text string
+	// images are the inline images of an HTML body, replaced in text with placeholders.
+	images []InlineImage
+	// hidden counts content a reader wouldn't see, removed from text.
+	hidden format.HiddenContent
+}
+
+// renderMessageBody converts the message body to text. Inline images of HTML bodies are
+// replaced with descriptive placeholders and returned separately, hidden text and invisible
+// characters are removed.
+func renderMessageBody(conv htmlConverter, payload *gmail.MessagePart, opts bodyOptions) (renderedBody, error) {
+	textBody, htmlBody := extractMessageBodies(payload)
+	if textBody != "" || htmlBody == "" {
+		body, err := previewText(conv, textBody, htmlBody)
+		if err != nil {
+			return renderedBody{}, fmt.Errorf("previewText failed: %w", err)
+		}
+		var rendered renderedBody
+		body, rendered.hidden.InvisibleChars = format.StripInvisibleChars(body)
+		body = format.CleanTextURLs(body)
+		if opts.stripQuotes {
+			body = format.StripQuotedText(body)
+		}
+		rendered.text = opts.finish(body)
+		return rendered, nil
+	}
+
+	var rendered renderedBody
+	visible, hidden := format.StripHiddenHTML([]byte(htmlBody))
+	rendered.hidden = hidden
+	if opts.stripQuotes {
+		visible = format.StripQuotedHTML(visible)
+	}
+
+	parts := inlineImageParts(payload)
+
+	replaced, _ := format.ReplaceInlineImages(visible, func(ref format.InlineImage) string {
+		image := parts[ref.ContentID]
+		image.ContentID = ref.ContentID
+		image.Alt = ref.Alt
+		rendered.images = append(rendered.images, image)
+		return inlineImagePlaceholder(image)
+	})
+
+	body, err := previewText(conv, "", string(replaced))
+	if err != nil {
+		return renderedBody{}, fmt.Errorf("previewText failed: %w", err)
+	}
+	if opts.stripQuotes {
+		// Quotes without client markup survive as markdown blockquotes.
+		body = format.StripQuotedText(body)
+	}
+	rendered.text = opts.finish(body)
+
+	return rendered, nil
+}
+
+// finish applies the options that work on the final text of a body.
+func (o bodyOptions) finish(body string) string {
+	if o.footnoteLinks {
+		body = format.FootnoteLinks(body, footnoteURLLength)
+	}
+	return o.redactor.Redact(body)
+}
+
+// inlineImageParts indexes message parts carrying a Content-ID header by that ID.
+func inlineImageParts(payload *gmail.MessagePart) map[string]InlineImage {
+	parts := make(map[string]InlineImage)
+
+	var walk func(part *gmail.MessagePart)
+	walk = func(part *gmail.MessagePart) {
+		if contentID := headerValue(part.Headers, "Content-ID"); contentID != "" {
+			image := InlineImage{
+				Filename: part.Filename,
+				MimeType: part.MimeType,
+			}
+			if part.Body != nil {
+				image.Size = part.Body.Size
+				if part.Body.AttachmentId != "" {
+					image.AttachmentID = attachmentRef(part)
+				}
+			}
+			parts[format.NormalizeContentID(contentID)] = image
+		}
+
+		for _, child := range part.Parts {
+			walk(child)
+		}
+	}
+	walk(payload)
+
+	return parts
+}
+
+func inlineImagePlaceholder(image InlineImage) string {
+	details := []string{}
+	if image.MimeType != "" {
+		details = append(details, image.MimeType)
+	}
+	if image.Size > 0 {
+		details = append(details, fmt.Sprintf("%d bytes", image.Size))
+	}
+
+	name := image.Filename
+	if name == "" {
+		name = image.ContentID
+	}
+	if len(details) > 0 {
+		name += " (" + strings.Join(details, ", ") + ")"
+	}
+	if image.Alt != "" {
+		name += ": " + image.Alt
+	}
+
+	return "[inline image " + name + "]"
+}
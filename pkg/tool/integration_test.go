@@ -15,10 +15,10 @@ import (
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/gmail/v1"
 
-	"github.com/hal9000y/gmail-mcp/internal/auth"
-	"github.com/hal9000y/gmail-mcp/internal/format"
-	"github.com/hal9000y/gmail-mcp/internal/gservice"
-	"github.com/hal9000y/gmail-mcp/internal/tool"
+	"github.com/hal9000y/gmail-mcp/pkg/auth"
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
 )
 
 func TestIntegrationGmailMCP(t *testing.T) {
@@ -150,7 +150,7 @@ func analyzeMessage(ctx context.Context, t *testing.T, client *mcp.ClientSession
 
 	stats.BodyPreview = truncateString(fullMsg.BodyText, 200)
 	stats.BodySize = len(fullMsg.BodyText)
-	stats.EstimatedTokens = estimateTokens(fullMsg.BodyText)
+	stats.EstimatedTokens = fullMsg.EstimatedTokens
 
 	if len(fullMsg.Attachments) > 0 {
 		analyzeAttachments(ctx, t, client, msg.ID, fullMsg.Attachments, &stats)
@@ -194,7 +194,7 @@ func analyzeAttachments(ctx context.Context, t *testing.T, client *mcp.ClientSes
 	previews := getAttachmentPreviews(ctx, t, client, messageID, attachmentIDs)
 	for _, preview := range previews {
 		if preview.Error == "" && preview.Content != "" {
-			attTokens := estimateTokens(preview.Content)
+			attTokens := preview.EstimatedTokens
 			stats.AttachmentTokens += attTokens
 			stats.AttachmentPreviews = append(stats.AttachmentPreviews, attachmentPreview{
 				Filename: preview.Filename,
@@ -323,10 +323,6 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-func estimateTokens(text string) int {
-	return len(text) / 4
-}
-
 func maxInt(a, b int) int {
 	if a > b {
 		return a
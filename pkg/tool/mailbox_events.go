@@ -0,0 +1,92 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MailboxChangesURI is the resource clients subscribe to for new-mail events.
+const MailboxChangesURI = "gmail://mailbox/changes"
+
+// MailboxChange is the latest change of the mailbox Gmail pushed.
+type MailboxChange struct {
+	EmailAddress string `json:"email_address,omitempty"`
+	HistoryID    string `json:"history_id,omitempty"`
+	ReceivedAt   string `json:"received_at,omitempty"`
+}
+
+// MailboxEvents turns Gmail push notifications into resource updates of MailboxChangesURI for
+// subscribed MCP sessions, which read the resource and call check_new_mail or sync_mailbox.
+type MailboxEvents struct {
+	mu     sync.Mutex
+	latest MailboxChange
+	server *mcp.Server
+}
+
+// NewMailboxEvents creates MailboxEvents to pass to WithMailboxEvents.
+func NewMailboxEvents() *MailboxEvents {
+	return &MailboxEvents{}
+}
+
+// Publish records a change of the mailbox and notifies subscribed sessions.
+func (e *MailboxEvents) Publish(ctx context.Context, emailAddress string, historyID uint64) {
+	e.mu.Lock()
+	e.latest = MailboxChange{
+		EmailAddress: emailAddress,
+		HistoryID:    strconv.FormatUint(historyID, 10),
+		ReceivedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	server := e.server
+	e.mu.Unlock()
+
+	if server != nil {
+		_ = server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: MailboxChangesURI})
+	}
+}
+
+// register adds the resource to server and makes it the one notified.
+func (e *MailboxEvents) register(server *mcp.Server) {
+	e.mu.Lock()
+	e.server = server
+	e.mu.Unlock()
+
+	server.AddResource(&mcp.Resource{
+		URI:         MailboxChangesURI,
+		Name:        "mailbox_changes",
+		Description: "Latest Gmail push notification; subscribe to be notified of new mail",
+		MIMEType:    "application/json",
+	}, e.read)
+}
+
+func (e *MailboxEvents) read(_ context.Context, _ *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	e.mu.Lock()
+	latest := e.latest
+	e.mu.Unlock()
+
+	data, err := json.Marshal(latest)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal failed: %w", err)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+		URI:      MailboxChangesURI,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}}, nil
+}
+
+func (e *MailboxEvents) subscribe(_ context.Context, req *mcp.SubscribeRequest) error {
+	if req.Params.URI != MailboxChangesURI {
+		return fmt.Errorf("unknown resource %q", req.Params.URI)
+	}
+	return nil
+}
+
+func (e *MailboxEvents) unsubscribe(_ context.Context, _ *mcp.UnsubscribeRequest) error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestMailboxEvents(t *testing.T) {
+	events := tool.NewMailboxEvents()
+	server := tool.NewServer(&gmailSvcMock{}, &converterMock{}, tool.WithMailboxEvents(events))
+
+	updated := make(chan string, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	require.NoError(t, clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: tool.MailboxChangesURI}))
+	require.Error(t, clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: "gmail://unknown"}))
+
+	events.Publish(ctx, "user@example.com", 4242)
+
+	select {
+	case uri := <-updated:
+		assert.Equal(t, tool.MailboxChangesURI, uri)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no resource update received")
+	}
+
+	result, err := clientSession.ReadResource(ctx, &mcp.ReadResourceParams{URI: tool.MailboxChangesURI})
+	require.NoError(t, err)
+	require.Len(t, result.Contents, 1)
+
+	var change tool.MailboxChange
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].Text), &change))
+	assert.Equal(t, "user@example.com", change.EmailAddress)
+	assert.Equal(t, "4242", change.HistoryID)
+	assert.NotEmpty(t, change.ReceivedAt)
+}
@@ -0,0 +1,224 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const labelPathSeparator = "/"
+
+// Label represents a Gmail label.
+type Label struct {
+	ID   string `json:"id" jsonschema:"label ID"`
+	Name string `json:"name" jsonschema:"label name, nested labels are separated by /"`
+	Type string `json:"type,omitempty" jsonschema:"system or user"`
+}
+
+// CreateLabelRequest contains the label path to create.
+type CreateLabelRequest struct {
+	Name string `json:"name" jsonschema:"label name, use / for nesting e.g. Clients/Acme"`
+}
+
+// CreateLabelResponse contains the created label and any parents created on the way.
+type CreateLabelResponse struct {
+	Label          Label   `json:"label" jsonschema:"the created label"`
+	CreatedParents []Label `json:"created_parents,omitempty" jsonschema:"missing parent labels created for the nested path"`
+}
+
+// RenameLabelRequest contains the label to rename and its new name.
+type RenameLabelRequest struct {
+	LabelID string `json:"label_id" jsonschema:"ID of the user label to rename"`
+	NewName string `json:"new_name" jsonschema:"new label name, use / for nesting"`
+}
+
+// RenameLabelResponse contains all labels renamed, including nested children.
+type RenameLabelResponse struct {
+	Renamed []Label `json:"renamed" jsonschema:"renamed label and its nested children"`
+}
+
+// DeleteLabelRequest contains the label to delete.
+type DeleteLabelRequest struct {
+	LabelID string `json:"label_id" jsonschema:"ID of the user label to delete"`
+}
+
+// DeleteLabelResponse confirms label deletion.
+type DeleteLabelResponse struct {
+	DeletedID string `json:"deleted_id" jsonschema:"ID of the deleted label"`
+}
+
+type manageLabelsSvc interface {
+	ListLabels(ctx context.Context) ([]*gmail.Label, error)
+	CreateLabel(ctx context.Context, name string) (*gmail.Label, error)
+	RenameLabel(ctx context.Context, labelID, name string) (*gmail.Label, error)
+	DeleteLabel(ctx context.Context, labelID string) error
+}
+
+// NewManageLabels creates a new ManageLabels tool set.
+func NewManageLabels(svc manageLabelsSvc) *ManageLabels {
+	return &ManageLabels{
+		svc: svc,
+	}
+}
+
+// ManageLabels implements label create, rename and delete operations.
+type ManageLabels struct {
+	svc manageLabelsSvc
+}
+
+// CreateLabel creates a label, creating missing parents of nested paths first.
+func (t *ManageLabels) CreateLabel(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input CreateLabelRequest,
+) (*mcp.CallToolResult, CreateLabelResponse, error) {
+	segments, err := splitLabelPath(input.Name)
+	if err != nil {
+		return nil, CreateLabelResponse{}, err
+	}
+
+	existing, err := t.labelsByName(ctx)
+	if err != nil {
+		return nil, CreateLabelResponse{}, err
+	}
+
+	name := strings.Join(segments, labelPathSeparator)
+	if _, ok := existing[name]; ok {
+		return nil, CreateLabelResponse{}, fmt.Errorf("label %q already exists", name)
+	}
+
+	var response CreateLabelResponse
+	for i := 1; i < len(segments); i++ {
+		parent := strings.Join(segments[:i], labelPathSeparator)
+		if _, ok := existing[parent]; ok {
+			continue
+		}
+
+		created, err := t.svc.CreateLabel(ctx, parent)
+		if err != nil {
+			return nil, CreateLabelResponse{}, fmt.Errorf("create parent label %q failed: %w", parent, err)
+		}
+		response.CreatedParents = append(response.CreatedParents, toLabel(created))
+	}
+
+	created, err := t.svc.CreateLabel(ctx, name)
+	if err != nil {
+		return nil, CreateLabelResponse{}, fmt.Errorf("create label %q failed: %w", name, err)
+	}
+	response.Label = toLabel(created)
+
+	return nil, response, nil
+}
+
+// RenameLabel renames a user label and moves its nested children along with it.
+func (t *ManageLabels) RenameLabel(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input RenameLabelRequest,
+) (*mcp.CallToolResult, RenameLabelResponse, error) {
+	segments, err := splitLabelPath(input.NewName)
+	if err != nil {
+		return nil, RenameLabelResponse{}, err
+	}
+	newName := strings.Join(segments, labelPathSeparator)
+
+	labels, err := t.svc.ListLabels(ctx)
+	if err != nil {
+		return nil, RenameLabelResponse{}, fmt.Errorf("svc.ListLabels failed: %w", err)
+	}
+
+	target := findLabelByID(labels, input.LabelID)
+	if target == nil {
+		return nil, RenameLabelResponse{}, fmt.Errorf("label %s not found", input.LabelID)
+	}
+	if target.Type == "system" {
+		return nil, RenameLabelResponse{}, fmt.Errorf("system label %s cannot be renamed", input.LabelID)
+	}
+
+	renamed, err := t.svc.RenameLabel(ctx, target.Id, newName)
+	if err != nil {
+		return nil, RenameLabelResponse{}, fmt.Errorf("rename label %s failed: %w", target.Id, err)
+	}
+	response := RenameLabelResponse{Renamed: []Label{toLabel(renamed)}}
+
+	oldPrefix := target.Name + labelPathSeparator
+	for _, l := range labels {
+		if !strings.HasPrefix(l.Name, oldPrefix) {
+			continue
+		}
+
+		childName := newName + labelPathSeparator + strings.TrimPrefix(l.Name, oldPrefix)
+		child, err := t.svc.RenameLabel(ctx, l.Id, childName)
+		if err != nil {
+			return nil, RenameLabelResponse{}, fmt.Errorf("rename nested label %s failed: %w", l.Id, err)
+		}
+		response.Renamed = append(response.Renamed, toLabel(child))
+	}
+
+	return nil, response, nil
+}
+
+// DeleteLabel deletes a user label.
+func (t *ManageLabels) DeleteLabel(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input DeleteLabelRequest,
+) (*mcp.CallToolResult, DeleteLabelResponse, error) {
+	if input.LabelID == "" {
+		return nil, DeleteLabelResponse{}, errors.New("label_id is required")
+	}
+
+	if err := t.svc.DeleteLabel(ctx, input.LabelID); err != nil {
+		return nil, DeleteLabelResponse{}, fmt.Errorf("svc.DeleteLabel failed: %w", err)
+	}
+
+	return nil, DeleteLabelResponse{DeletedID: input.LabelID}, nil
+}
+
+func (t *ManageLabels) labelsByName(ctx context.Context) (map[string]*gmail.Label, error) {
+	labels, err := t.svc.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("svc.ListLabels failed: %w", err)
+	}
+
+	byName := make(map[string]*gmail.Label, len(labels))
+	for _, l := range labels {
+		byName[l.Name] = l
+	}
+
+	return byName, nil
+}
+
+func splitLabelPath(name string) ([]string, error) {
+	segments := strings.Split(name, labelPathSeparator)
+	for i, s := range segments {
+		segments[i] = strings.TrimSpace(s)
+		if segments[i] == "" {
+			return nil, fmt.Errorf("invalid label name %q", name)
+		}
+	}
+
+	return segments, nil
+}
+
+func findLabelByID(labels []*gmail.Label, labelID string) *gmail.Label {
+	for _, l := range labels {
+		if l.Id == labelID {
+			return l
+		}
+	}
+
+	return nil
+}
+
+func toLabel(l *gmail.Label) Label {
+	return Label{
+		ID:   l.Id,
+		Name: l.Name,
+		Type: l.Type,
+	}
+}
@@ -0,0 +1,158 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func newManageLabelsGmailSvc() *gmailSvcMock {
+	return &gmailSvcMock{
+		ListLabelsFunc: func(_ context.Context) ([]*gmail.Label, error) {
+			return []*gmail.Label{
+				{Id: "INBOX", Name: "INBOX", Type: "system"},
+				{Id: "Label_1", Name: "Clients", Type: "user"},
+				{Id: "Label_2", Name: "Clients/Acme", Type: "user"},
+				{Id: "Label_3", Name: "Clients/Acme/Invoices", Type: "user"},
+			}, nil
+		},
+		CreateLabelFunc: func(_ context.Context, name string) (*gmail.Label, error) {
+			return &gmail.Label{Id: "new-" + name, Name: name, Type: "user"}, nil
+		},
+		RenameLabelFunc: func(_ context.Context, labelID, name string) (*gmail.Label, error) {
+			return &gmail.Label{Id: labelID, Name: name, Type: "user"}, nil
+		},
+		DeleteLabelFunc: func(_ context.Context, labelID string) error {
+			if labelID == "Label_404" {
+				return fmt.Errorf("simulated error: %s", labelID)
+			}
+			return nil
+		},
+	}
+}
+
+func TestManageLabels(t *testing.T) {
+	cases := []struct {
+		name        string
+		tool        string
+		req         any
+		resp        any
+		expected    any
+		expectedErr error
+	}{
+		{
+			name: "create nested label with missing parents",
+			tool: "create_label",
+			req:  tool.CreateLabelRequest{Name: "Projects / Apollo/Specs"},
+			resp: &tool.CreateLabelResponse{},
+			expected: &tool.CreateLabelResponse{
+				Label: tool.Label{ID: "new-Projects/Apollo/Specs", Name: "Projects/Apollo/Specs", Type: "user"},
+				CreatedParents: []tool.Label{
+					{ID: "new-Projects", Name: "Projects", Type: "user"},
+					{ID: "new-Projects/Apollo", Name: "Projects/Apollo", Type: "user"},
+				},
+			},
+		},
+		{
+			name: "create under existing parent",
+			tool: "create_label",
+			req:  tool.CreateLabelRequest{Name: "Clients/Globex"},
+			resp: &tool.CreateLabelResponse{},
+			expected: &tool.CreateLabelResponse{
+				Label: tool.Label{ID: "new-Clients/Globex", Name: "Clients/Globex", Type: "user"},
+			},
+		},
+		{
+			name:        "create existing label",
+			tool:        "create_label",
+			req:         tool.CreateLabelRequest{Name: "Clients/Acme"},
+			expectedErr: fmt.Errorf(`label "Clients/Acme" already exists`),
+		},
+		{
+			name:        "create invalid path",
+			tool:        "create_label",
+			req:         tool.CreateLabelRequest{Name: "Clients//Acme"},
+			expectedErr: fmt.Errorf(`invalid label name "Clients//Acme"`),
+		},
+		{
+			name: "rename moves nested labels",
+			tool: "rename_label",
+			req:  tool.RenameLabelRequest{LabelID: "Label_2", NewName: "Clients/Acme Corp"},
+			resp: &tool.RenameLabelResponse{},
+			expected: &tool.RenameLabelResponse{
+				Renamed: []tool.Label{
+					{ID: "Label_2", Name: "Clients/Acme Corp", Type: "user"},
+					{ID: "Label_3", Name: "Clients/Acme Corp/Invoices", Type: "user"},
+				},
+			},
+		},
+		{
+			name:        "rename system label",
+			tool:        "rename_label",
+			req:         tool.RenameLabelRequest{LabelID: "INBOX", NewName: "Mail"},
+			expectedErr: fmt.Errorf("system label INBOX cannot be renamed"),
+		},
+		{
+			name:     "delete label",
+			tool:     "delete_label",
+			req:      tool.DeleteLabelRequest{LabelID: "Label_1"},
+			resp:     &tool.DeleteLabelResponse{},
+			expected: &tool.DeleteLabelResponse{DeletedID: "Label_1"},
+		},
+		{
+			name:        "delete error",
+			tool:        "delete_label",
+			req:         tool.DeleteLabelRequest{LabelID: "Label_404"},
+			expectedErr: fmt.Errorf("simulated error: Label_404"),
+		},
+	}
+
+	server := tool.NewServer(newManageLabelsGmailSvc(), &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      tc.tool,
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					tc.resp,
+				),
+			)
+			assert.Equal(t, tc.expected, tc.resp)
+		})
+	}
+}
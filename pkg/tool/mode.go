@@ -0,0 +1,102 @@
+package tool
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+// Mode is what the server may do in the mailbox: it decides both the Gmail scopes to request and
+// which tools are registered.
+type Mode string
+
+const (
+	// ModeReadonly only reads mail: tools changing labels or threads are not registered.
+	ModeReadonly Mode = "readonly"
+	// ModeModify also labels, archives and mutes messages and manages labels.
+	ModeModify Mode = "modify"
+	// ModeFull has full access to the mailbox; it registers the tools of ModeModify.
+	ModeFull Mode = "full"
+)
+
+// modeRanks orders modes by what they allow.
+var modeRanks = map[Mode]int{ModeReadonly: 0, ModeModify: 1, ModeFull: 2}
+
+// ParseMode returns the mode named s.
+func ParseMode(s string) (Mode, error) {
+	m := Mode(s)
+	if _, ok := modeRanks[m]; !ok {
+		return "", fmt.Errorf("unknown mode %q, expected readonly, modify or full", s)
+	}
+	return m, nil
+}
+
+// ModeForScopes returns the mode the Gmail scopes of scopes allow.
+func ModeForScopes(scopes []string) Mode {
+	switch {
+	case slices.Contains(scopes, gmail.MailGoogleComScope):
+		return ModeFull
+	case slices.Contains(scopes, gmail.GmailModifyScope):
+		return ModeModify
+	default:
+		return ModeReadonly
+	}
+}
+
+// Scopes returns the Gmail scopes the tools of the mode need.
+func (m Mode) Scopes() []string {
+	switch m {
+	case ModeFull:
+		return []string{gmail.MailGoogleComScope}
+	case ModeModify:
+		return []string{gmail.GmailReadonlyScope, gmail.GmailLabelsScope, gmail.GmailModifyScope}
+	default:
+		return []string{gmail.GmailReadonlyScope}
+	}
+}
+
+// Allows reports whether the mode permits what other permits.
+func (m Mode) Allows(other Mode) bool {
+	return modeRanks[m] >= modeRanks[other]
+}
+
+// toolModes are the modes the tools changing the mailbox or calendar need, which addTools
+// registers only when the mode of the server allows it; the other tools work in any mode.
+var toolModes = map[string]Mode{
+	"create_label": ModeModify,
+	"rename_label": ModeModify,
+	"delete_label": ModeModify,
+	"mute_thread":  ModeModify,
+	"create_event": ModeModify,
+}
+
+// destructiveTools remove what they act on for good: labels, or the stored credentials.
+var destructiveTools = map[string]bool{
+	"delete_label":      true,
+	"clear_credentials": true,
+}
+
+// localWriteTools leave the mailbox alone but write the server's own files and stores.
+var localWriteTools = map[string]bool{
+	"check_new_mail":         true,
+	"sync_mailbox":           true,
+	"export_thread_markdown": true,
+	"export_messages_mbox":   true,
+	"save_attachment":        true,
+	"save_search":            true,
+	"delete_saved_search":    true,
+	"clear_credentials":      true,
+}
+
+// annotations returns the hints of the tool named name: tools of ModeReadonly that write nothing
+// are read-only, the others say whether they destroy anything, so clients can confirm them.
+func annotations(name string) *mcp.ToolAnnotations {
+	_, modifies := toolModes[name]
+	if !modifies && !localWriteTools[name] {
+		return &mcp.ToolAnnotations{ReadOnlyHint: true}
+	}
+	destructive := destructiveTools[name]
+	return &mcp.ToolAnnotations{DestructiveHint: &destructive}
+}
@@ -0,0 +1,28 @@
+package tool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestModeScopes(t *testing.T) {
+	tests := []struct {
+		mode tool.Mode
+		want []string
+	}{
+		{mode: tool.ModeReadonly, want: []string{gmail.GmailReadonlyScope}},
+		{mode: tool.ModeModify, want: []string{gmail.GmailReadonlyScope, gmail.GmailLabelsScope, gmail.GmailModifyScope}},
+		{mode: tool.ModeFull, want: []string{gmail.MailGoogleComScope}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.mode.Scopes())
+			assert.Equal(t, tt.mode, tool.ModeForScopes(tt.mode.Scopes()))
+		})
+	}
+}
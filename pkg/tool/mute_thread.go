@@ -0,0 +1,98 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+// Gmail API doesn't expose the native mute action, so muted threads are archived
+// and tagged with a dedicated user label that can be searched for or filtered on.
+// Unlike a native mute, replies arriving later still reach the inbox.
+const (
+	mutedLabelName = "Muted"
+	inboxLabelID   = "INBOX"
+	mutedNote      = "The thread is archived, but unlike Gmail's mute new replies will still reach the inbox; mute it again to archive them."
+)
+
+// MuteThreadRequest contains the thread to mute.
+type MuteThreadRequest struct {
+	ThreadID string `json:"thread_id" jsonschema:"ID of the thread to mute"`
+}
+
+// MuteThreadResponse contains the muted thread state.
+type MuteThreadResponse struct {
+	ThreadID     string `json:"thread_id" jsonschema:"ID of the muted thread"`
+	MutedLabelID string `json:"muted_label_id" jsonschema:"ID of the label marking muted threads"`
+	MessageCount int    `json:"message_count" jsonschema:"number of messages in the thread"`
+	Note         string `json:"note" jsonschema:"what muting does not cover"`
+}
+
+type muteThreadSvc interface {
+	ListLabels(ctx context.Context) ([]*gmail.Label, error)
+	CreateLabel(ctx context.Context, name string) (*gmail.Label, error)
+	ModifyThread(ctx context.Context, threadID string, addLabelIDs, removeLabelIDs []string) (*gmail.Thread, error)
+}
+
+// NewMuteThread creates a new MuteThread tool.
+func NewMuteThread(svc muteThreadSvc) *MuteThread {
+	return &MuteThread{
+		svc: svc,
+	}
+}
+
+// MuteThread silences noisy threads.
+type MuteThread struct {
+	svc muteThreadSvc
+}
+
+// MuteThread archives the thread and marks it with the Muted label.
+func (t *MuteThread) MuteThread(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input MuteThreadRequest,
+) (*mcp.CallToolResult, MuteThreadResponse, error) {
+	if input.ThreadID == "" {
+		return nil, MuteThreadResponse{}, errors.New("thread_id is required")
+	}
+
+	labelID, err := t.mutedLabelID(ctx)
+	if err != nil {
+		return nil, MuteThreadResponse{}, fmt.Errorf("mutedLabelID failed: %w", err)
+	}
+
+	thread, err := t.svc.ModifyThread(ctx, input.ThreadID, []string{labelID}, []string{inboxLabelID})
+	if err != nil {
+		return nil, MuteThreadResponse{}, fmt.Errorf("svc.ModifyThread failed: %w", err)
+	}
+
+	return nil, MuteThreadResponse{
+		ThreadID:     input.ThreadID,
+		MutedLabelID: labelID,
+		MessageCount: len(thread.Messages),
+		Note:         mutedNote,
+	}, nil
+}
+
+func (t *MuteThread) mutedLabelID(ctx context.Context) (string, error) {
+	labels, err := t.svc.ListLabels(ctx)
+	if err != nil {
+		return "", fmt.Errorf("svc.ListLabels failed: %w", err)
+	}
+
+	for _, l := range labels {
+		if l.Name == mutedLabelName {
+			return l.Id, nil
+		}
+	}
+
+	label, err := t.svc.CreateLabel(ctx, mutedLabelName)
+	if err != nil {
+		return "", fmt.Errorf("svc.CreateLabel failed: %w", err)
+	}
+
+	return label.Id, nil
+}
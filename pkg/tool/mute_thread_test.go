@@ -0,0 +1,111 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestMuteThread(t *testing.T) {
+	cases := []struct {
+		name           string
+		req            tool.MuteThreadRequest
+		existingLabels []*gmail.Label
+		expected       tool.MuteThreadResponse
+		expectedCreate int
+		expectedErr    error
+	}{
+		{
+			name:           "creates muted label on first use",
+			req:            tool.MuteThreadRequest{ThreadID: "t-001"},
+			existingLabels: []*gmail.Label{{Id: "INBOX", Name: "INBOX"}},
+			expected:       tool.MuteThreadResponse{ThreadID: "t-001", MutedLabelID: "Label_Muted", MessageCount: 2},
+			expectedCreate: 1,
+		},
+		{
+			name:           "reuses existing muted label",
+			req:            tool.MuteThreadRequest{ThreadID: "t-001"},
+			existingLabels: []*gmail.Label{{Id: "Label_9", Name: "Muted"}},
+			expected:       tool.MuteThreadResponse{ThreadID: "t-001", MutedLabelID: "Label_9", MessageCount: 2},
+		},
+		{
+			name:           "modify error",
+			req:            tool.MuteThreadRequest{ThreadID: "t-404"},
+			existingLabels: []*gmail.Label{{Id: "Label_9", Name: "Muted"}},
+			expectedErr:    fmt.Errorf("simulated error: t-404"),
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gmailSvc := &gmailSvcMock{
+				ListLabelsFunc: func(_ context.Context) ([]*gmail.Label, error) {
+					return tc.existingLabels, nil
+				},
+				CreateLabelFunc: func(_ context.Context, name string) (*gmail.Label, error) {
+					return &gmail.Label{Id: "Label_" + name, Name: name}, nil
+				},
+				ModifyThreadFunc: func(_ context.Context, threadID string, _, _ []string) (*gmail.Thread, error) {
+					if threadID == "t-404" {
+						return nil, fmt.Errorf("simulated error: %s", threadID)
+					}
+					return &gmail.Thread{Id: threadID, Messages: []*gmail.Message{{Id: "m-1"}, {Id: "m-2"}}}, nil
+				},
+			}
+
+			server := tool.NewServer(gmailSvc, &converterMock{})
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+
+			clientSession, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer clientSession.Close()
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "mute_thread",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.MuteThreadResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Contains(t, response.Note, "new replies will still reach the inbox")
+			response.Note = ""
+			assert.Equal(t, tc.expected, response)
+			assert.Len(t, gmailSvc.CreateLabelCalls(), tc.expectedCreate)
+
+			modify := gmailSvc.ModifyThreadCalls()
+			require.Len(t, modify, 1)
+			assert.Equal(t, []string{tc.expected.MutedLabelID}, modify[0].AddLabelIDs)
+			assert.Equal(t, []string{"INBOX"}, modify[0].RemoveLabelIDs)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package tool
+
+import "sync"
+
+// defaultMessageWorkers is how many messages get_messages converts at once unless configured.
+const defaultMessageWorkers = 4
+
+// mapParallel applies fn to every item with at most workers calls at once and returns the
+// results in the order of items. Once a call fails, items not started yet are skipped and the
+// first error is returned.
+func mapParallel[T, R any](items []T, workers int, fn func(T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	sem := make(chan struct{}, max(workers, 1))
+	for i, item := range items {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+			result, err := fn(item)
+			if err != nil {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+			results[i], errs[i] = result, err
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,748 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func newPreviewAttachmentsGmailSvc() *gmailSvcMock {
+	return &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			if msgID == "error-msg" {
+				return nil, fmt.Errorf("message not found: %s", msgID)
+			}
+			return &gmail.Message{
+				Id: msgID,
+				Payload: &gmail.MessagePart{
+					Parts: []*gmail.MessagePart{
+						{
+							PartId:   "1",
+							Filename: "document.txt",
+							MimeType: "text/plain",
+							Body: &gmail.MessagePartBody{
+								AttachmentId: "attach-txt-" + msgID,
+								Size:         100,
+							},
+						},
+						{
+							PartId:   "2",
+							Filename: "report.pdf",
+							MimeType: "application/pdf",
+							Body: &gmail.MessagePartBody{
+								AttachmentId: "attach-pdf-" + msgID,
+								Size:         200,
+							},
+						},
+						{
+							PartId:   "3",
+							Filename: "logo.png",
+							MimeType: "image/png",
+							Headers:  []*gmail.MessagePartHeader{{Name: "Content-ID", Value: "<logo>"}},
+							Body: &gmail.MessagePartBody{
+								AttachmentId: "attach-img-" + msgID,
+								Size:         3,
+							},
+						},
+					},
+				},
+			}, nil
+		},
+		GetAttachmentFunc: func(_ context.Context, msgID, attachmentID string) (*gmail.MessagePartBody, error) {
+			switch attachmentID {
+			case "attach-txt-" + msgID:
+				// "Text content for " + msgID in base64
+				return &gmail.MessagePartBody{
+					Data: "VGV4dCBjb250ZW50IGZvciA=",
+				}, nil
+			case "attach-img-" + msgID:
+				return &gmail.MessagePartBody{
+					Data: "UE5H", // "PNG"
+				}, nil
+			case "attach-pdf-" + msgID:
+				// Simulate PDF binary data (just placeholder)
+				return &gmail.MessagePartBody{
+					Data: "UERGIGNvbnRlbnQgZm9yIA==",
+				}, nil
+			default:
+				return nil, fmt.Errorf("attachment not found: %s", attachmentID)
+			}
+		},
+	}
+}
+
+func TestPreviewAttachments(t *testing.T) {
+	cases := []struct {
+		name        string
+		req         tool.PreviewAttachmentsRequest
+		expected    tool.PreviewAttachmentsResponse
+		expectedErr error
+	}{
+		{
+			name: "success with text and pdf attachments",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID:     "msg-001",
+				AttachmentIDs: []string{"1", "2"},
+			},
+			expected: tool.PreviewAttachmentsResponse{
+				Attachments: []tool.AttachmentPreview{
+					{
+						ID:              "1",
+						Filename:        "document.txt",
+						MimeType:        "text/plain",
+						Content:         "Text content for ",
+						EstimatedTokens: 5,
+					},
+					{
+						ID:              "2",
+						Filename:        "report.pdf",
+						MimeType:        "application/pdf",
+						Content:         "PDF content as plain text",
+						EstimatedTokens: 7,
+						TotalPages:      1,
+					},
+				},
+			},
+		},
+		{
+			name: "gmail attachment id",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID:     "msg-001",
+				AttachmentIDs: []string{"attach-txt-msg-001"},
+			},
+			expected: tool.PreviewAttachmentsResponse{
+				Attachments: []tool.AttachmentPreview{
+					{
+						ID:              "attach-txt-msg-001",
+						Filename:        "document.txt",
+						MimeType:        "text/plain",
+						Content:         "Text content for ",
+						EstimatedTokens: 5,
+					},
+				},
+			},
+		},
+		{
+			name: "content with hashes",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID:     "msg-001",
+				AttachmentIDs: []string{"1"},
+				IncludeHash:   true,
+			},
+			expected: tool.PreviewAttachmentsResponse{
+				Attachments: []tool.AttachmentPreview{
+					{
+						ID:              "1",
+						Filename:        "document.txt",
+						MimeType:        "text/plain",
+						Size:            17,
+						SHA256:          "2bf5176d4332c197b82a8644a149931d50818cba9c47513d62f2a85cf64091b8",
+						Content:         "Text content for ",
+						EstimatedTokens: 5,
+					},
+				},
+			},
+		},
+		{
+			name: "hashes only",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID:     "msg-001",
+				AttachmentIDs: []string{"1", "2"},
+				HashOnly:      true,
+			},
+			expected: tool.PreviewAttachmentsResponse{
+				Attachments: []tool.AttachmentPreview{
+					{
+						ID:       "1",
+						Filename: "document.txt",
+						MimeType: "text/plain",
+						Size:     17,
+						SHA256:   "2bf5176d4332c197b82a8644a149931d50818cba9c47513d62f2a85cf64091b8",
+					},
+					{
+						ID:       "2",
+						Filename: "report.pdf",
+						MimeType: "application/pdf",
+						Size:     16,
+						SHA256:   "7092bc265f60a5af0413405608324853b0918c103054db4a6c6233a8222d317e",
+					},
+				},
+			},
+		},
+		{
+			name: "all attachments without inline images",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID: "msg-001",
+				HashOnly:  true,
+			},
+			expected: tool.PreviewAttachmentsResponse{
+				Attachments: []tool.AttachmentPreview{
+					{
+						ID:       "1",
+						Filename: "document.txt",
+						MimeType: "text/plain",
+						Size:     17,
+						SHA256:   "2bf5176d4332c197b82a8644a149931d50818cba9c47513d62f2a85cf64091b8",
+					},
+					{
+						ID:       "2",
+						Filename: "report.pdf",
+						MimeType: "application/pdf",
+						Size:     16,
+						SHA256:   "7092bc265f60a5af0413405608324853b0918c103054db4a6c6233a8222d317e",
+					},
+				},
+			},
+		},
+		{
+			name: "all attachments with inline images",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID:     "msg-001",
+				HashOnly:      true,
+				IncludeInline: true,
+			},
+			expected: tool.PreviewAttachmentsResponse{
+				Attachments: []tool.AttachmentPreview{
+					{
+						ID:       "1",
+						Filename: "document.txt",
+						MimeType: "text/plain",
+						Size:     17,
+						SHA256:   "2bf5176d4332c197b82a8644a149931d50818cba9c47513d62f2a85cf64091b8",
+					},
+					{
+						ID:       "2",
+						Filename: "report.pdf",
+						MimeType: "application/pdf",
+						Size:     16,
+						SHA256:   "7092bc265f60a5af0413405608324853b0918c103054db4a6c6233a8222d317e",
+					},
+					{
+						ID:       "3",
+						Filename: "logo.png",
+						MimeType: "image/png",
+						Size:     3,
+						SHA256:   "796120837694d3f3f29259cfeb25091698c2a0aa87873658d840b4993ee889b3",
+					},
+				},
+			},
+		},
+		{
+			name: "attachment above size limit",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID:     "msg-001",
+				AttachmentIDs: []string{"1", "2"},
+				MaxBytes:      150,
+			},
+			expected: tool.PreviewAttachmentsResponse{
+				Attachments: []tool.AttachmentPreview{
+					{
+						ID:              "1",
+						Filename:        "document.txt",
+						MimeType:        "text/plain",
+						Content:         "Text content for ",
+						EstimatedTokens: 5,
+					},
+					{
+						ID:         "2",
+						Filename:   "report.pdf",
+						MimeType:   "application/pdf",
+						Size:       200,
+						Error:      "attachment is 200 bytes, above the 150 byte limit",
+						TooLarge:   true,
+						LimitBytes: 150,
+					},
+				},
+			},
+		},
+		{
+			name: "error case - message not found",
+			req: tool.PreviewAttachmentsRequest{
+				MessageID:     "error-msg",
+				AttachmentIDs: []string{"1"},
+			},
+			expectedErr: fmt.Errorf("message not found: error-msg"),
+		},
+	}
+
+	gmailSvc := newPreviewAttachmentsGmailSvc()
+	converter := &converterMock{
+		PDF2TextFunc: func(_ []byte, _ format.PDFOptions) (format.PDFText, error) {
+			return format.PDFText{Text: "PDF content as plain text\f", TotalPages: 1}, nil
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, converter)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "preview_attachments",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.PreviewAttachmentsResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
+
+func TestPreviewAttachmentsPaging(t *testing.T) {
+	cases := []struct {
+		name     string
+		req      tool.PreviewAttachmentsRequest
+		expected tool.AttachmentPreview
+	}{
+		{
+			name: "pdf page range",
+			req:  tool.PreviewAttachmentsRequest{MessageID: "msg-001", AttachmentIDs: []string{"2"}, FirstPage: 2, LastPage: 3},
+			expected: tool.AttachmentPreview{
+				ID:              "2",
+				Filename:        "report.pdf",
+				MimeType:        "application/pdf",
+				Content:         "page two\fpage three",
+				EstimatedTokens: 5,
+				TotalPages:      3,
+				TotalLength:     19,
+			},
+		},
+		{
+			name: "pdf pages capped by the server",
+			req:  tool.PreviewAttachmentsRequest{MessageID: "msg-001", AttachmentIDs: []string{"2"}},
+			expected: tool.AttachmentPreview{
+				ID:              "2",
+				Filename:        "report.pdf",
+				MimeType:        "application/pdf",
+				Content:         "page one\fpage two",
+				EstimatedTokens: 5,
+				TotalPages:      3,
+				NextPage:        3,
+			},
+		},
+		{
+			name: "pdf pages past the end",
+			req:  tool.PreviewAttachmentsRequest{MessageID: "msg-001", AttachmentIDs: []string{"2"}, FirstPage: 5},
+			expected: tool.AttachmentPreview{
+				ID:         "2",
+				Filename:   "report.pdf",
+				MimeType:   "application/pdf",
+				TotalPages: 3,
+			},
+		},
+		{
+			name: "text segment",
+			req:  tool.PreviewAttachmentsRequest{MessageID: "msg-001", AttachmentIDs: []string{"1"}, Offset: 5, Length: 7},
+			expected: tool.AttachmentPreview{
+				ID:              "1",
+				Filename:        "document.txt",
+				MimeType:        "text/plain",
+				Content:         "content",
+				EstimatedTokens: 2,
+				TotalLength:     17,
+				NextOffset:      12,
+				HasMore:         true,
+			},
+		},
+	}
+
+	gmailSvc := newPreviewAttachmentsGmailSvc()
+	converter := &converterMock{
+		PDF2TextFunc: func(_ []byte, opts format.PDFOptions) (format.PDFText, error) {
+			pages := []string{"page one\f", "page two\f", "page three\f"}
+			first, last := max(opts.FirstPage, 1), len(pages)
+			if opts.LastPage > 0 {
+				last = min(opts.LastPage, last)
+			}
+			if first > last {
+				return format.PDFText{TotalPages: len(pages)}, nil
+			}
+			return format.PDFText{Text: strings.Join(pages[first-1:last], ""), TotalPages: len(pages)}, nil
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, converter, tool.WithMaxPDFPages(2))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "preview_attachments",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.False(t, result.IsError, "Result should not indicate error")
+
+			var response tool.PreviewAttachmentsResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, []tool.AttachmentPreview{tc.expected}, response.Attachments)
+		})
+	}
+}
+
+func TestPreviewAttachmentsOfficeDocuments(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{
+				Id: msgID,
+				Payload: &gmail.MessagePart{
+					Parts: []*gmail.MessagePart{
+						{
+							PartId:   "1",
+							Filename: "notes.docx",
+							MimeType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-docx", Size: 4},
+						},
+						{
+							PartId:   "2",
+							Filename: "forwarded.docx",
+							MimeType: "application/octet-stream",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-octet", Size: 4},
+						},
+						{
+							PartId:   "3",
+							Filename: "deck.pptx",
+							MimeType: "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-pptx", Size: 4},
+						},
+					},
+				},
+			}, nil
+		},
+		GetAttachmentFunc: func(_ context.Context, _, _ string) (*gmail.MessagePartBody, error) {
+			return &gmail.MessagePartBody{Data: "RE9DWA=="}, nil // "DOCX"
+		},
+	}
+	converter := &converterMock{
+		DOCX2MDFunc: func(raw []byte) (string, error) {
+			return "# Notes\n\nconverted " + string(raw), nil
+		},
+		PPTX2TextFunc: func(_ []byte) (string, error) {
+			return "## Slide 1: Agenda\n", nil
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, converter)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "preview_attachments",
+		Arguments: tool.PreviewAttachmentsRequest{MessageID: "msg-001"},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var response tool.PreviewAttachmentsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+
+	require.Len(t, response.Attachments, 3)
+	for _, preview := range response.Attachments[:2] {
+		assert.Empty(t, preview.Error)
+		assert.Equal(t, "# Notes\n\nconverted DOCX", preview.Content)
+	}
+	assert.Len(t, converter.DOCX2MDCalls(), 2)
+	assert.Equal(t, "## Slide 1: Agenda\n", response.Attachments[2].Content)
+}
+
+func TestPreviewAttachmentsXLSX(t *testing.T) {
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{
+				Id: msgID,
+				Payload: &gmail.MessagePart{
+					Parts: []*gmail.MessagePart{
+						{
+							PartId:   "1",
+							Filename: "q3-report.xlsx",
+							MimeType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-xlsx", Size: 4},
+						},
+					},
+				},
+			}, nil
+		},
+		GetAttachmentFunc: func(_ context.Context, _, _ string) (*gmail.MessagePartBody, error) {
+			return &gmail.MessagePartBody{Data: "WExTWA=="}, nil // "XLSX"
+		},
+	}
+	converter := &converterMock{
+		XLSX2TextFunc: func(_ []byte, opts format.SheetOptions) (string, error) {
+			return fmt.Sprintf("## Sheet1\n\nformat=%s sheets=%d rows=%d\n", opts.Format, opts.MaxSheets, opts.MaxRows), nil
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, converter)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name: "preview_attachments",
+		Arguments: tool.PreviewAttachmentsRequest{
+			MessageID:    "msg-001",
+			SheetFormat:  "CSV",
+			MaxSheets:    2,
+			MaxSheetRows: 50,
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var response tool.PreviewAttachmentsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+
+	require.Len(t, response.Attachments, 1)
+	assert.Equal(t, "## Sheet1\n\nformat=csv sheets=2 rows=50\n", response.Attachments[0].Content)
+}
+
+func TestPreviewAttachmentsAttachedEmail(t *testing.T) {
+	emails := map[string]string{
+		"attach-eml-text": "From: Alice <alice@example.com>\r\n" +
+			"To: bob@example.com\r\n" +
+			"Subject: =?UTF-8?Q?Q3_numbers_=E2=80=93_final?=\r\n" +
+			"Date: Mon, 6 Oct 2025 09:30:00 +0000\r\n" +
+			"MIME-Version: 1.0\r\n" +
+			"Content-Type: multipart/mixed; boundary=outer\r\n" +
+			"\r\n" +
+			"--outer\r\n" +
+			"Content-Type: text/plain; charset=utf-8\r\n" +
+			"Content-Transfer-Encoding: quoted-printable\r\n" +
+			"\r\n" +
+			"Numbers are final =E2=80=93 see the report.\r\n" +
+			"--outer\r\n" +
+			"Content-Type: application/pdf; name=report.pdf\r\n" +
+			"Content-Disposition: attachment; filename=report.pdf\r\n" +
+			"Content-Transfer-Encoding: base64\r\n" +
+			"\r\n" +
+			"JVBERi0xLjQK\r\n" +
+			"--outer--\r\n",
+		"attach-eml-html": "From: news@example.com\r\n" +
+			"Subject: Newsletter\r\n" +
+			"Content-Type: text/html; charset=utf-8\r\n" +
+			"\r\n" +
+			"<p>Hello <b>world</b></p>\r\n",
+	}
+
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{
+				Id: msgID,
+				Payload: &gmail.MessagePart{
+					Parts: []*gmail.MessagePart{
+						{
+							PartId:   "1",
+							Filename: "Q3 numbers.eml",
+							MimeType: "message/rfc822",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-eml-text", Size: 10},
+						},
+						{
+							PartId:   "2",
+							Filename: "newsletter.eml",
+							MimeType: "application/octet-stream",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-eml-html", Size: 10},
+						},
+					},
+				},
+			}, nil
+		},
+		GetAttachmentFunc: func(_ context.Context, _, attachmentID string) (*gmail.MessagePartBody, error) {
+			return &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(emails[attachmentID]))}, nil
+		},
+	}
+	converter := &converterMock{
+		HTML2MDFunc: func(raw []byte) (string, error) {
+			return "Hello **world**\n", nil
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, converter)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "preview_attachments",
+		Arguments: tool.PreviewAttachmentsRequest{MessageID: "msg-001"},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var response tool.PreviewAttachmentsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+
+	require.Len(t, response.Attachments, 2)
+	assert.Equal(t,
+		"From: Alice <alice@example.com>\n"+
+			"To: bob@example.com\n"+
+			"Date: Mon, 6 Oct 2025 09:30:00 +0000\n"+
+			"Subject: Q3 numbers – final\n"+
+			"\nNumbers are final – see the report.\n"+
+			"\nAttachments:\n"+
+			"- report.pdf (application/pdf, 9 bytes)\n",
+		response.Attachments[0].Content,
+	)
+	assert.Equal(t,
+		"From: news@example.com\n"+
+			"Subject: Newsletter\n"+
+			"\nHello **world**\n",
+		response.Attachments[1].Content,
+	)
+	require.Len(t, converter.HTML2MDCalls(), 1)
+	assert.Contains(t, string(converter.HTML2MDCalls()[0].Raw), "<b>world</b>")
+}
+
+func TestPreviewAttachmentsCSV(t *testing.T) {
+	files := map[string]string{
+		"attach-csv":     "month,revenue,cost\nJan,100,80\nFeb,120,90\nMar,130,95\n",
+		"attach-invalid": "a,\"unterminated\n",
+	}
+
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{
+				Id: msgID,
+				Payload: &gmail.MessagePart{
+					Parts: []*gmail.MessagePart{
+						{
+							PartId:   "1",
+							Filename: "revenue.csv",
+							MimeType: "text/csv",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-csv", Size: 10},
+						},
+						{
+							PartId:   "2",
+							Filename: "broken.CSV",
+							MimeType: "application/octet-stream",
+							Body:     &gmail.MessagePartBody{AttachmentId: "attach-invalid", Size: 10},
+						},
+					},
+				},
+			}, nil
+		},
+		GetAttachmentFunc: func(_ context.Context, _, attachmentID string) (*gmail.MessagePartBody, error) {
+			return &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(files[attachmentID]))}, nil
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name: "preview_attachments",
+		Arguments: tool.PreviewAttachmentsRequest{
+			MessageID:       "msg-001",
+			MaxTableRows:    2,
+			MaxTableColumns: 2,
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var response tool.PreviewAttachmentsResponse
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+
+	require.Len(t, response.Attachments, 2)
+	assert.Equal(t,
+		"| month | revenue |\n"+
+			"| --- | --- |\n"+
+			"| Jan | 100 |\n"+
+			"| Feb | 120 |\n"+
+			"\n(showing 2 of 3 rows, 2 of 3 columns)\n",
+		response.Attachments[0].Content,
+	)
+	assert.Equal(t, files["attach-invalid"], response.Attachments[1].Content)
+}
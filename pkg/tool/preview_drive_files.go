@@ -0,0 +1,188 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+)
+
+const (
+	mimeTypeGoogleApps   = "application/vnd.google-apps."
+	mimeTypeGoogleDoc    = mimeTypeGoogleApps + "document"
+	mimeTypeGoogleSheet  = mimeTypeGoogleApps + "spreadsheet"
+	mimeTypeGoogleSlides = mimeTypeGoogleApps + "presentation"
+	// maxDriveFiles bounds the files one call previews.
+	maxDriveFiles = 10
+)
+
+// driveExports are the formats Google Docs, Sheets and Slides are exported to: Docs as HTML
+// converted like message bodies, the first sheet of Sheets as CSV and Slides as text.
+var driveExports = map[string]string{
+	mimeTypeGoogleDoc:    "text/html",
+	mimeTypeGoogleSheet:  "text/csv",
+	mimeTypeGoogleSlides: "text/plain",
+}
+
+// PreviewDriveFilesRequest specifies Google Drive files to preview.
+type PreviewDriveFilesRequest struct {
+	MessageID   string   `json:"message_id,omitempty" jsonschema:"message whose Google Drive links are previewed when files is omitted"`
+	Files       []string `json:"files,omitempty" jsonschema:"Google Drive or Docs URLs or file IDs, at most 10"`
+	SheetFormat string   `json:"sheet_format,omitempty" jsonschema:"Google Sheets output: 'markdown' table (default) or 'csv'"`
+	Offset      int      `json:"offset,omitempty" jsonschema:"character offset into the extracted content, use next_offset of the previous call"`
+	Length      int      `json:"length,omitempty" jsonschema:"max characters of content to return per file, up to 50000"`
+}
+
+// PreviewDriveFilesResponse contains extracted Drive file content.
+type PreviewDriveFilesResponse struct {
+	Files []AttachmentPreview `json:"files" jsonschema:"file previews; id is the Drive file ID and mime_type the exported format of Docs, Sheets and Slides"`
+}
+
+type previewDriveFilesSvc interface {
+	GetMessage(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewPreviewDriveFiles creates a new PreviewDriveFiles tool extracting files like
+// preview_attachments: files above maxBytes are skipped, PDFs are extracted at most maxPDFPages
+// pages at a time and content is filtered.
+func NewPreviewDriveFiles(
+	svc previewDriveFilesSvc, drv driveSvc, conv attachmentConverter, maxBytes int64, maxPDFPages int, filter ContentFilter,
+) *PreviewDriveFiles {
+	return &PreviewDriveFiles{
+		svc:      svc,
+		drive:    drv,
+		maxBytes: maxBytes,
+		preview:  &PreviewAttachments{conv: conv, maxBytes: maxBytes, maxPDFPages: maxPDFPages, filter: filter},
+	}
+}
+
+// PreviewDriveFiles reads Google Drive files linked from messages, which often stand in for
+// attachments.
+type PreviewDriveFiles struct {
+	svc      previewDriveFilesSvc
+	drive    driveSvc
+	maxBytes int64
+	preview  *PreviewAttachments
+}
+
+// PreviewDriveFiles extracts text from the requested or linked Drive files.
+func (t *PreviewDriveFiles) PreviewDriveFiles(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input PreviewDriveFilesRequest,
+) (*mcp.CallToolResult, PreviewDriveFilesResponse, error) {
+	ids, err := t.fileIDs(ctx, input)
+	if err != nil {
+		return nil, PreviewDriveFilesResponse{}, err
+	}
+
+	previews := make([]AttachmentPreview, 0, len(ids))
+	for _, id := range ids {
+		previews = append(previews, t.previewFile(ctx, id, input))
+	}
+	return nil, PreviewDriveFilesResponse{Files: previews}, nil
+}
+
+// fileIDs resolves the requested files, or the Drive links of the message, to unique file IDs.
+func (t *PreviewDriveFiles) fileIDs(ctx context.Context, input PreviewDriveFilesRequest) ([]string, error) {
+	refs := input.Files
+	if len(refs) == 0 {
+		if input.MessageID == "" {
+			return nil, errors.New("files or message_id is required")
+		}
+		msg, err := t.svc.GetMessage(ctx, input.MessageID)
+		if err != nil {
+			return nil, fmt.Errorf("svc.GetMessage failed: %w", err)
+		}
+		for _, link := range extractMessageLinks(msg) {
+			refs = append(refs, link.URL)
+		}
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		id := format.DriveFileID(ref)
+		if id == "" && !strings.Contains(ref, "/") {
+			id = strings.TrimSpace(ref)
+		}
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("no Google Drive files found")
+	}
+	if len(ids) > maxDriveFiles {
+		return nil, fmt.Errorf("%d Drive files requested, at most %d per call", len(ids), maxDriveFiles)
+	}
+	return ids, nil
+}
+
+func (t *PreviewDriveFiles) previewFile(ctx context.Context, id string, input PreviewDriveFilesRequest) AttachmentPreview {
+	preview := AttachmentPreview{ID: id}
+	file, err := t.drive.GetDriveFile(ctx, id)
+	if err != nil {
+		preview.Error = fmt.Errorf("drive.GetDriveFile failed: %w", err).Error()
+		return preview
+	}
+	preview.Filename, preview.MimeType = file.Name, file.MimeType
+
+	data, err := t.download(ctx, file, &preview)
+	if errors.Is(err, gservice.ErrTooLarge) || preview.TooLarge {
+		preview.TooLarge, preview.LimitBytes = true, t.maxBytes
+	}
+	if err != nil {
+		preview.Error = err.Error()
+		return preview
+	}
+
+	request := PreviewAttachmentsRequest{Offset: input.Offset, Length: input.Length}
+	var content string
+	switch {
+	case preview.MimeType == "text/html":
+		content, err = t.preview.conv.HTML2MD(data)
+	case preview.MimeType == "text/csv" && strings.EqualFold(strings.TrimSpace(input.SheetFormat), "csv"):
+		content = string(data)
+	default:
+		content, err = t.preview.extractAttachmentContent(&preview, data, request)
+	}
+	t.preview.finishPreview(&preview, content, err, fmt.Sprintf("Drive file %q", file.Name), request)
+	return preview
+}
+
+// download exports Google Docs, Sheets and Slides, setting the MIME type of preview to the
+// exported format, and downloads other files.
+func (t *PreviewDriveFiles) download(ctx context.Context, file *drive.File, preview *AttachmentPreview) ([]byte, error) {
+	if exportType, ok := driveExports[file.MimeType]; ok {
+		preview.MimeType = exportType
+		data, err := t.drive.ExportDriveFile(ctx, file.Id, exportType, t.maxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("drive.ExportDriveFile failed: %w", err)
+		}
+		return data, nil
+	}
+	if strings.HasPrefix(file.MimeType, mimeTypeGoogleApps) {
+		return nil, fmt.Errorf("unsupported Google file type: %s", file.MimeType)
+	}
+
+	if t.maxBytes > 0 && file.Size > t.maxBytes {
+		preview.Size, preview.TooLarge = int(file.Size), true
+		return nil, fmt.Errorf("file is %d bytes, above the %d byte limit", file.Size, t.maxBytes)
+	}
+	data, err := t.drive.DownloadDriveFile(ctx, file.Id, t.maxBytes)
+	if err != nil {
+		return nil, fmt.Errorf("drive.DownloadDriveFile failed: %w", err)
+	}
+	return data, nil
+}
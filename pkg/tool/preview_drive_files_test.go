@@ -0,0 +1,137 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/format"
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+const driveLinksBody = `<p>Please review <a href="https://docs.google.com/document/d/doc-123456789/edit">the plan</a>,
+<a href="https://docs.google.com/spreadsheets/d/sheet-123456789/edit#gid=0">the budget</a>,
+<a href="https://drive.google.com/file/d/pdf-1234567890/view">the contract</a> and
+<a href="https://drive.google.com/file/d/big-1234567890/view">the video</a>.
+Everything is in <a href="https://drive.google.com/drive/folders/folder-123456789">this folder</a>,
+the plan again: <a href="https://docs.google.com/document/d/doc-123456789/edit?usp=sharing">plan</a>.</p>`
+
+func newDriveSvc() *driveSvcMock {
+	files := map[string]*drive.File{
+		"doc-123456789":   {Id: "doc-123456789", Name: "Plan", MimeType: "application/vnd.google-apps.document"},
+		"sheet-123456789": {Id: "sheet-123456789", Name: "Budget", MimeType: "application/vnd.google-apps.spreadsheet"},
+		"pdf-1234567890":  {Id: "pdf-1234567890", Name: "contract.pdf", MimeType: "application/pdf", Size: 100},
+		"big-1234567890":  {Id: "big-1234567890", Name: "video.mp4", MimeType: "video/mp4", Size: 1 << 30},
+	}
+	return &driveSvcMock{
+		GetDriveFileFunc: func(_ context.Context, fileID string) (*drive.File, error) {
+			file, ok := files[fileID]
+			if !ok {
+				return nil, fmt.Errorf("files.Get failed: %w", gservice.ErrNotFound)
+			}
+			return file, nil
+		},
+		ExportDriveFileFunc: func(_ context.Context, fileID, mimeType string, _ int64) ([]byte, error) {
+			switch mimeType {
+			case "text/html":
+				return []byte("<h1>Plan</h1>"), nil
+			case "text/csv":
+				return []byte("item,cost\nrent,100\n"), nil
+			}
+			return nil, fmt.Errorf("unexpected export of %s as %s", fileID, mimeType)
+		},
+		DownloadDriveFileFunc: func(_ context.Context, _ string, _ int64) ([]byte, error) {
+			return []byte("%PDF-1.7"), nil
+		},
+	}
+}
+
+func callPreviewDriveFiles(t *testing.T, drv *driveSvcMock, req tool.PreviewDriveFilesRequest) (*mcp.CallToolResult, tool.PreviewDriveFilesResponse) {
+	t.Helper()
+	gmailSvc := &gmailSvcMock{
+		GetMessageFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{Id: msgID, Payload: &gmail.MessagePart{
+				MimeType: "text/html",
+				Body:     &gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString([]byte(driveLinksBody))},
+			}}, nil
+		},
+	}
+	conv := &converterMock{
+		HTML2MDFunc: func(raw []byte) (string, error) {
+			return "# " + string(raw[4:8]), nil
+		},
+		PDF2TextFunc: func(_ []byte, _ format.PDFOptions) (format.PDFText, error) {
+			return format.PDFText{Text: "Contract text", TotalPages: 1}, nil
+		},
+	}
+
+	ctx := context.Background()
+	server := tool.NewServer(gmailSvc, conv, tool.WithDrive(drv), tool.WithMaxAttachmentBytes(10<<20))
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	clientSession, err := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientSession.Close() })
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: "preview_drive_files", Arguments: req})
+	require.NoError(t, err)
+	var response tool.PreviewDriveFilesResponse
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	}
+	return result, response
+}
+
+func TestPreviewDriveFilesOfMessage(t *testing.T) {
+	drv := newDriveSvc()
+	result, response := callPreviewDriveFiles(t, drv, tool.PreviewDriveFilesRequest{MessageID: "m1"})
+	require.False(t, result.IsError)
+	require.Len(t, response.Files, 4, "folders are skipped and repeated links previewed once")
+
+	doc := response.Files[0]
+	assert.Equal(t, "doc-123456789", doc.ID)
+	assert.Equal(t, "Plan", doc.Filename)
+	assert.Equal(t, "text/html", doc.MimeType)
+	assert.Equal(t, "# Plan", doc.Content)
+
+	sheet := response.Files[1]
+	assert.Equal(t, "text/csv", sheet.MimeType)
+	assert.Contains(t, sheet.Content, "| rent | 100 |")
+
+	pdf := response.Files[2]
+	assert.Equal(t, "Contract text", pdf.Content)
+	assert.Empty(t, pdf.Error)
+
+	video := response.Files[3]
+	assert.True(t, video.TooLarge)
+	assert.Equal(t, int64(10<<20), video.LimitBytes)
+	assert.Empty(t, video.Content)
+
+	downloads := drv.DownloadDriveFileCalls()
+	require.Len(t, downloads, 1, "files above the limit are not downloaded")
+	assert.Equal(t, "pdf-1234567890", downloads[0].FileID)
+}
+
+func TestPreviewDriveFilesByURL(t *testing.T) {
+	_, response := callPreviewDriveFiles(t, newDriveSvc(), tool.PreviewDriveFilesRequest{
+		Files:       []string{"https://docs.google.com/spreadsheets/d/sheet-123456789/edit", "missing-123456789"},
+		SheetFormat: "csv",
+	})
+	require.Len(t, response.Files, 2)
+	assert.Equal(t, "item,cost\nrent,100\n", response.Files[0].Content)
+	assert.Contains(t, response.Files[1].Error, "not found")
+
+	result, _ := callPreviewDriveFiles(t, newDriveSvc(), tool.PreviewDriveFilesRequest{Files: []string{"https://example.com/report.pdf"}})
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,48 @@
+package tool_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestPrompts(t *testing.T) {
+	server := tool.NewServer(&gmailSvcMock{}, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	listed, err := clientSession.ListPrompts(ctx, nil)
+	require.NoError(t, err)
+	names := make([]string, 0, len(listed.Prompts))
+	for _, prompt := range listed.Prompts {
+		names = append(names, prompt.Name)
+	}
+	assert.ElementsMatch(t, []string{"summarize_unread", "find_invoice", "draft_reply"}, names)
+
+	result, err := clientSession.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "find_invoice",
+		Arguments: map[string]string{"vendor": "acme.example", "range": "last_month"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	assert.Contains(t, text, `from "acme.example" and range "last_month"`)
+	assert.Contains(t, text, "find_attachments")
+
+	_, err = clientSession.GetPrompt(ctx, &mcp.GetPromptParams{Name: "draft_reply"})
+	assert.ErrorContains(t, err, "thread_id is required")
+}
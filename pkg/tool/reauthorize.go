@@ -0,0 +1,93 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+)
+
+// consentScopes are the scopes beyond read-only access the tools changing mail or events need,
+// granted incrementally with WithIncrementalConsent.
+var consentScopes = map[string][]string{
+	"create_label": {gmail.GmailLabelsScope},
+	"rename_label": {gmail.GmailLabelsScope},
+	"delete_label": {gmail.GmailLabelsScope},
+	"mute_thread":  {gmail.GmailLabelsScope, gmail.GmailModifyScope},
+	"create_event": {calendar.CalendarEventsScope},
+}
+
+// reauthorizer lets the user authorize the server again when a tool call fails for an expired or
+// revoked token, or grant the scopes a tool lacks, instead of restarting it.
+type reauthorizer struct {
+	// authURL returns the URL starting the OAuth flow of the account, empty for the default one.
+	authURL func(account string) string
+	// consentURL returns the URL granting the account the scopes in addition to those it has.
+	consentURL func(account string, scopes []string) string
+}
+
+// middleware asks the user through elicitation to authorize again at the auth URL of the account
+// of a tool call failing with gservice.ErrAuthExpired, or to grant the scopes of a tool failing
+// with gservice.ErrPermission, and retries the call once they accept. Clients without elicitation
+// get the URL in the ErrorInfo of the result.
+func (r reauthorizer) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		res, err := next(ctx, method, req)
+		slot, ok := ctx.Value(toolErrorKey{}).(*toolError)
+		if method != "tools/call" || !ok || slot.err == nil {
+			return res, err
+		}
+
+		message := r.prompt(ctx, req, slot)
+		session, ok := req.GetSession().(*mcp.ServerSession)
+		if message == "" || !ok || !canElicit(session) {
+			return res, err
+		}
+
+		elicited, elicitErr := session.Elicit(ctx, &mcp.ElicitParams{
+			Message:         message,
+			RequestedSchema: &jsonschema.Schema{Type: "object"},
+		})
+		if elicitErr != nil || elicited.Action != "accept" {
+			return res, err
+		}
+
+		slot.err, slot.authURL = nil, ""
+		return next(ctx, method, req)
+	}
+}
+
+// prompt sets the URL resolving the failure of slot, if any, and returns the message asking the
+// user to open it.
+func (r reauthorizer) prompt(ctx context.Context, req mcp.Request, slot *toolError) string {
+	account := selectedAccount(ctx)
+	switch {
+	case errors.Is(slot.err, gservice.ErrAuthExpired) && r.authURL != nil:
+		slot.authURL = r.authURL(account)
+		return fmt.Sprintf("Gmail authorization expired or was revoked. Authorize the server again at %s, then accept to retry.",
+			slot.authURL)
+	case errors.Is(slot.err, gservice.ErrPermission) && r.consentURL != nil:
+		params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+		if !ok || len(consentScopes[params.Name]) == 0 {
+			return ""
+		}
+		scopes := consentScopes[params.Name]
+		slot.authURL = r.consentURL(account, scopes)
+		return fmt.Sprintf("%s needs permission the server wasn't granted yet (%s). Grant it at %s, then accept to retry.",
+			params.Name, strings.Join(scopes, ", "), slot.authURL)
+	}
+	return ""
+}
+
+// canElicit reports whether the client of session declared the elicitation capability.
+func canElicit(session *mcp.ServerSession) bool {
+	params := session.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Elicitation != nil
+}
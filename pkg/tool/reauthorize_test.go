@@ -0,0 +1,140 @@
+package tool_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/gservice"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestReauthorization(t *testing.T) {
+	tests := []struct {
+		name        string
+		clientOpts  *mcp.ClientOptions
+		wantRetried bool
+	}{
+		{
+			name: "elicitation accepted",
+			clientOpts: &mcp.ClientOptions{
+				ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+					assert.Contains(t, req.Params.Message, "http://localhost/oauth?redirect=1")
+					return &mcp.ElicitResult{Action: "accept"}, nil
+				},
+			},
+			wantRetried: true,
+		},
+		{
+			name: "elicitation declined",
+			clientOpts: &mcp.ClientOptions{
+				ElicitationHandler: func(_ context.Context, _ *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+					return &mcp.ElicitResult{Action: "decline"}, nil
+				},
+			},
+		},
+		{name: "no elicitation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			gmailSvc := &gmailSvcMock{
+				ListMessagesFunc: func(_ context.Context, _, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+					calls++
+					if calls == 1 {
+						return nil, fmt.Errorf("messages.List failed: %w", gservice.ErrAuthExpired)
+					}
+					return &gmail.ListMessagesResponse{ResultSizeEstimate: 5}, nil
+				},
+			}
+
+			server := tool.NewServer(gmailSvc, &converterMock{}, tool.WithReauthorization(func(account string) string {
+				return "http://localhost/oauth?redirect=1" + account
+			}))
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, tt.clientOpts)
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+			ctx := context.Background()
+
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+
+			clientSession, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer clientSession.Close()
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "count_messages",
+				Arguments: map[string]any{"query": "is:unread"},
+			})
+			require.NoError(t, err)
+
+			if tt.wantRetried {
+				require.False(t, result.IsError, result.Content)
+				assert.Equal(t, 2, calls)
+				assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `"estimated_count":5`)
+				return
+			}
+			require.True(t, result.IsError)
+			assert.Equal(t, 1, calls)
+			info, ok := result.Meta[tool.ErrorMetaKey].(map[string]any)
+			require.True(t, ok, "Result should carry error info")
+			assert.Equal(t, tool.ErrorCodeAuthExpired, info["code"])
+			assert.Equal(t, "http://localhost/oauth?redirect=1", info["auth_url"])
+		})
+	}
+}
+
+func TestIncrementalConsent(t *testing.T) {
+	calls := 0
+	gmailSvc := &gmailSvcMock{
+		ListLabelsFunc: func(_ context.Context) ([]*gmail.Label, error) {
+			return nil, nil
+		},
+		CreateLabelFunc: func(_ context.Context, name string) (*gmail.Label, error) {
+			calls++
+			if calls == 1 {
+				return nil, fmt.Errorf("labels.Create failed: %w", gservice.ErrPermission)
+			}
+			return &gmail.Label{Id: "Label_1", Name: name}, nil
+		},
+	}
+
+	var consented []string
+	server := tool.NewServer(gmailSvc, &converterMock{}, tool.WithIncrementalConsent(func(_ string, scopes []string) string {
+		consented = scopes
+		return "http://localhost/oauth?redirect=1&scope=labels"
+	}))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			assert.Contains(t, req.Params.Message, "create_label")
+			assert.Contains(t, req.Params.Message, "http://localhost/oauth?redirect=1&scope=labels")
+			return &mcp.ElicitResult{Action: "accept"}, nil
+		},
+	})
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "create_label",
+		Arguments: map[string]any{"name": "Clients"},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError, result.Content)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{gmail.GmailLabelsScope}, consented)
+}
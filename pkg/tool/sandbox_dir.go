@@ -0,0 +1,37 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrDirNotConfigured indicates a tool needs a server directory that wasn't configured.
+var ErrDirNotConfigured = errors.New("directory is not configured on the server")
+
+// writeSandboxedFile writes data under dir, refusing names that escape it
+// through traversal or symlinks, and returns the path of the written file.
+func writeSandboxedFile(dir, name string, data []byte) (string, error) {
+	if dir == "" {
+		return "", ErrDirNotConfigured
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return "", fmt.Errorf("os.OpenRoot failed: %w", err)
+	}
+	defer func() { _ = root.Close() }()
+
+	if sub := filepath.Dir(name); sub != "." {
+		if err := root.MkdirAll(sub, 0700); err != nil {
+			return "", fmt.Errorf("root.MkdirAll failed: %w", err)
+		}
+	}
+
+	if err := root.WriteFile(name, data, 0600); err != nil {
+		return "", fmt.Errorf("root.WriteFile failed: %w", err)
+	}
+
+	return filepath.Join(dir, name), nil
+}
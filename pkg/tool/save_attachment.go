@@ -0,0 +1,104 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+// SaveAttachmentRequest specifies the attachment to save.
+type SaveAttachmentRequest struct {
+	MessageID    string `json:"message_id" jsonschema:"message ID containing the attachment"`
+	AttachmentID string `json:"attachment_id" jsonschema:"attachment ID as returned by get_messages (part or Gmail attachment ID)"`
+	Filename     string `json:"filename,omitempty" jsonschema:"target path relative to the files directory, defaults to <message_id>/<original filename>"`
+}
+
+// SaveAttachmentResponse describes the saved file.
+type SaveAttachmentResponse struct {
+	SavedPath string `json:"saved_path" jsonschema:"path of the saved file"`
+	MimeType  string `json:"mime_type" jsonschema:"MIME type"`
+	Size      int    `json:"size" jsonschema:"size in bytes"`
+}
+
+type saveAttachmentSvc interface {
+	GetMessage(ctx context.Context, msgID string) (*gmail.Message, error)
+	GetAttachment(ctx context.Context, msgID, attachmentID string) (*gmail.MessagePartBody, error)
+}
+
+// NewSaveAttachment creates a new SaveAttachment tool.
+func NewSaveAttachment(svc saveAttachmentSvc, filesDir string) *SaveAttachment {
+	return &SaveAttachment{
+		svc:      svc,
+		filesDir: filesDir,
+	}
+}
+
+// SaveAttachment stores attachments on disk for downstream local tools.
+type SaveAttachment struct {
+	svc      saveAttachmentSvc
+	filesDir string
+}
+
+// SaveAttachment downloads the attachment and writes it under the files directory.
+func (t *SaveAttachment) SaveAttachment(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input SaveAttachmentRequest,
+) (*mcp.CallToolResult, SaveAttachmentResponse, error) {
+	if input.MessageID == "" || input.AttachmentID == "" {
+		return nil, SaveAttachmentResponse{}, errors.New("message_id and attachment_id are required")
+	}
+	if t.filesDir == "" {
+		return nil, SaveAttachmentResponse{}, ErrDirNotConfigured
+	}
+
+	msg, err := t.svc.GetMessage(ctx, input.MessageID)
+	if err != nil {
+		return nil, SaveAttachmentResponse{}, fmt.Errorf("get message failed: %w", err)
+	}
+
+	part, err := findAttachmentPart(msg, input.AttachmentID)
+	if err != nil {
+		return nil, SaveAttachmentResponse{}, err
+	}
+
+	attachment, err := t.svc.GetAttachment(ctx, input.MessageID, part.Body.AttachmentId)
+	if err != nil {
+		return nil, SaveAttachmentResponse{}, fmt.Errorf("get attachment %s failed: %w", part.Body.AttachmentId, err)
+	}
+
+	data, err := decodeBase64URLBytes(attachment.Data)
+	if err != nil {
+		return nil, SaveAttachmentResponse{}, fmt.Errorf("failed to decode attachment: %w", err)
+	}
+
+	savedPath, err := writeSandboxedFile(t.filesDir, attachmentTargetName(input, part), data)
+	if err != nil {
+		return nil, SaveAttachmentResponse{}, fmt.Errorf("writeSandboxedFile failed: %w", err)
+	}
+
+	return nil, SaveAttachmentResponse{
+		SavedPath: savedPath,
+		MimeType:  part.MimeType,
+		Size:      len(data),
+	}, nil
+}
+
+// attachmentTargetName keeps only the base of sender-controlled filenames so they
+// can't pick a directory, while explicit names from the caller may use subdirectories.
+func attachmentTargetName(input SaveAttachmentRequest, part *gmail.MessagePart) string {
+	if input.Filename != "" {
+		return input.Filename
+	}
+
+	name := filepath.Base(filepath.Clean("/" + part.Filename))
+	if name == "/" || name == "." {
+		name = "attachment-" + input.AttachmentID
+	}
+
+	return filepath.Join(filepath.Base(filepath.Clean("/"+input.MessageID)), name)
+}
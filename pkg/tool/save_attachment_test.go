@@ -0,0 +1,127 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func newSaveAttachmentGmailSvc() *gmailSvcMock {
+	gmailSvc := newPreviewAttachmentsGmailSvc()
+	getMessage := gmailSvc.GetMessageFunc
+	gmailSvc.GetMessageFunc = func(ctx context.Context, msgID string) (*gmail.Message, error) {
+		msg, err := getMessage(ctx, msgID)
+		if err != nil {
+			return nil, err
+		}
+		msg.Payload.Parts[0].Filename = "../../" + msg.Payload.Parts[0].Filename
+		return msg, nil
+	}
+	return gmailSvc
+}
+
+func TestSaveAttachment(t *testing.T) {
+	filesDir := t.TempDir()
+
+	cases := []struct {
+		name        string
+		req         tool.SaveAttachmentRequest
+		filesDir    string
+		expected    tool.SaveAttachmentResponse
+		expectedErr error
+	}{
+		{
+			name:     "default name strips sender directories",
+			req:      tool.SaveAttachmentRequest{MessageID: "msg-001", AttachmentID: "1"},
+			filesDir: filesDir,
+			expected: tool.SaveAttachmentResponse{
+				SavedPath: filepath.Join(filesDir, "msg-001", "document.txt"),
+				MimeType:  "text/plain",
+				Size:      17,
+			},
+		},
+		{
+			name:     "explicit name in subdirectory",
+			req:      tool.SaveAttachmentRequest{MessageID: "msg-001", AttachmentID: "1", Filename: "reports/doc.txt"},
+			filesDir: filesDir,
+			expected: tool.SaveAttachmentResponse{
+				SavedPath: filepath.Join(filesDir, "reports", "doc.txt"),
+				MimeType:  "text/plain",
+				Size:      17,
+			},
+		},
+		{
+			name:        "path traversal is refused",
+			req:         tool.SaveAttachmentRequest{MessageID: "msg-001", AttachmentID: "1", Filename: "../../etc/passwd"},
+			filesDir:    filesDir,
+			expectedErr: fmt.Errorf("path escapes from parent"),
+		},
+		{
+			name:        "files dir not configured",
+			req:         tool.SaveAttachmentRequest{MessageID: "msg-001", AttachmentID: "1"},
+			expectedErr: tool.ErrDirNotConfigured,
+		},
+		{
+			name:        "unknown attachment",
+			req:         tool.SaveAttachmentRequest{MessageID: "msg-001", AttachmentID: "9"},
+			filesDir:    filesDir,
+			expectedErr: fmt.Errorf("no attachmentID found for msg-001/9"),
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := tool.NewServer(newSaveAttachmentGmailSvc(), &converterMock{}, tool.WithFilesDir(tc.filesDir))
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+			serverSession, err := server.Connect(ctx, serverTransport, nil)
+			require.NoError(t, err)
+			defer serverSession.Close()
+
+			clientSession, err := client.Connect(ctx, clientTransport, nil)
+			require.NoError(t, err)
+			defer clientSession.Close()
+
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "save_attachment",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.SaveAttachmentResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+
+			saved, err := os.ReadFile(response.SavedPath)
+			require.NoError(t, err)
+			assert.Equal(t, "Text content for ", string(saved))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/hal9000y/gmail-mcp/pkg/savedsearch"
+)
+
+// SaveSearchRequest contains a named query to store.
+type SaveSearchRequest struct {
+	Name        string `json:"name" jsonschema:"unique name of the search, e.g. weekly-invoices"`
+	Query       string `json:"query" jsonschema:"the Gmail search query"`
+	Description string `json:"description,omitempty" jsonschema:"what the search is used for"`
+}
+
+// SaveSearchResponse contains the stored search.
+type SaveSearchResponse struct {
+	Search SavedSearch `json:"search" jsonschema:"stored search"`
+}
+
+// ListSavedSearchesRequest has no parameters.
+type ListSavedSearchesRequest struct{}
+
+// ListSavedSearchesResponse contains all stored searches.
+type ListSavedSearchesResponse struct {
+	Searches []SavedSearch `json:"searches" jsonschema:"stored searches ordered by name"`
+}
+
+// RunSavedSearchRequest contains the search to run with pagination.
+type RunSavedSearchRequest struct {
+	Name       string `json:"name" jsonschema:"name of the saved search"`
+	MaxResults int64  `json:"max_results,omitempty" jsonschema:"max results per page"`
+	PageToken  string `json:"page_token,omitempty" jsonschema:"token for pagination"`
+}
+
+// RunSavedSearchResponse contains the search that ran and its results.
+type RunSavedSearchResponse struct {
+	Search  SavedSearch            `json:"search" jsonschema:"search that ran"`
+	Results SearchMessagesResponse `json:"results" jsonschema:"search results"`
+}
+
+// DeleteSavedSearchRequest contains the search to remove.
+type DeleteSavedSearchRequest struct {
+	Name string `json:"name" jsonschema:"name of the saved search"`
+}
+
+// DeleteSavedSearchResponse confirms the removal.
+type DeleteSavedSearchResponse struct {
+	Name string `json:"name" jsonschema:"name of the removed search"`
+}
+
+// SavedSearch is a named Gmail query.
+type SavedSearch struct {
+	Name        string `json:"name" jsonschema:"search name"`
+	Query       string `json:"query" jsonschema:"the Gmail search query"`
+	Description string `json:"description,omitempty" jsonschema:"what the search is used for"`
+}
+
+type savedSearchStore interface {
+	Save(search savedsearch.Search) (savedsearch.Search, error)
+	Get(name string) (savedsearch.Search, error)
+	List() []savedsearch.Search
+	Delete(name string) error
+}
+
+// NewSavedSearches creates a new SavedSearches tool.
+func NewSavedSearches(svc searchMessagesSvc, store savedSearchStore, loc *time.Location) *SavedSearches {
+	return &SavedSearches{
+		search: NewSearchMessages(svc, loc),
+		store:  store,
+	}
+}
+
+// SavedSearches stores named queries and runs them on demand.
+type SavedSearches struct {
+	search *SearchMessages
+	store  savedSearchStore
+}
+
+// SaveSearch creates or replaces a named query.
+func (t *SavedSearches) SaveSearch(
+	_ context.Context,
+	_ *mcp.CallToolRequest,
+	input SaveSearchRequest,
+) (*mcp.CallToolResult, SaveSearchResponse, error) {
+	saved, err := t.store.Save(savedsearch.Search{Name: input.Name, Query: input.Query, Description: input.Description})
+	if err != nil {
+		return nil, SaveSearchResponse{}, fmt.Errorf("store.Save failed: %w", err)
+	}
+
+	return nil, SaveSearchResponse{
+		Search: toSavedSearch(saved),
+	}, nil
+}
+
+// ListSavedSearches returns all stored queries.
+func (t *SavedSearches) ListSavedSearches(
+	_ context.Context,
+	_ *mcp.CallToolRequest,
+	_ ListSavedSearchesRequest,
+) (*mcp.CallToolResult, ListSavedSearchesResponse, error) {
+	stored := t.store.List()
+
+	searches := make([]SavedSearch, 0, len(stored))
+	for _, s := range stored {
+		searches = append(searches, toSavedSearch(s))
+	}
+
+	return nil, ListSavedSearchesResponse{
+		Searches: searches,
+	}, nil
+}
+
+// RunSavedSearch runs a stored query like search_messages.
+func (t *SavedSearches) RunSavedSearch(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input RunSavedSearchRequest,
+) (*mcp.CallToolResult, RunSavedSearchResponse, error) {
+	search, err := t.store.Get(input.Name)
+	if err != nil {
+		return nil, RunSavedSearchResponse{}, fmt.Errorf("store.Get failed: %w", err)
+	}
+
+	_, results, err := t.search.SearchMessages(ctx, req, SearchMessagesRequest{
+		Query:      search.Query,
+		MaxResults: input.MaxResults,
+		PageToken:  input.PageToken,
+	})
+	if err != nil {
+		return nil, RunSavedSearchResponse{}, fmt.Errorf("search.SearchMessages failed: %w", err)
+	}
+
+	return nil, RunSavedSearchResponse{
+		Search:  toSavedSearch(search),
+		Results: results,
+	}, nil
+}
+
+// DeleteSavedSearch removes a stored query.
+func (t *SavedSearches) DeleteSavedSearch(
+	_ context.Context,
+	_ *mcp.CallToolRequest,
+	input DeleteSavedSearchRequest,
+) (*mcp.CallToolResult, DeleteSavedSearchResponse, error) {
+	if err := t.store.Delete(input.Name); err != nil {
+		return nil, DeleteSavedSearchResponse{}, fmt.Errorf("store.Delete failed: %w", err)
+	}
+
+	return nil, DeleteSavedSearchResponse{
+		Name: input.Name,
+	}, nil
+}
+
+func toSavedSearch(s savedsearch.Search) SavedSearch {
+	return SavedSearch{Name: s.Name, Query: s.Query, Description: s.Description}
+}
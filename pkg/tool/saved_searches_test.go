@@ -0,0 +1,72 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/savedsearch"
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestSavedSearches(t *testing.T) {
+	store, err := savedsearch.NewStore("")
+	require.NoError(t, err)
+
+	gmailSvc := newSearchMessagesGmailSvc(map[string]*gmail.ListMessagesResponse{
+		"has:attachment subject:invoice": {Messages: []*gmail.Message{{Id: "msg-001"}}},
+	})
+
+	server := tool.NewServer(gmailSvc, &converterMock{}, tool.WithSavedSearches(store))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	callTool := func(t *testing.T, name string, args any, response any) *mcp.CallToolResult {
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Content)
+		if !result.IsError {
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), response))
+		}
+		return result
+	}
+
+	invoices := tool.SavedSearch{Name: "weekly-invoices", Query: "has:attachment subject:invoice", Description: "invoices"}
+
+	var saveResp tool.SaveSearchResponse
+	callTool(t, "save_search", tool.SaveSearchRequest{Name: invoices.Name, Query: invoices.Query, Description: invoices.Description}, &saveResp)
+	assert.Equal(t, invoices, saveResp.Search)
+
+	var listResp tool.ListSavedSearchesResponse
+	callTool(t, "list_saved_searches", tool.ListSavedSearchesRequest{}, &listResp)
+	assert.Equal(t, []tool.SavedSearch{invoices}, listResp.Searches)
+
+	var runResp tool.RunSavedSearchResponse
+	callTool(t, "run_saved_search", tool.RunSavedSearchRequest{Name: "weekly-invoices"}, &runResp)
+	assert.Equal(t, invoices, runResp.Search)
+	require.Len(t, runResp.Results.Messages, 1)
+	assert.Equal(t, "msg-001", runResp.Results.Messages[0].ID)
+
+	var deleteResp tool.DeleteSavedSearchResponse
+	callTool(t, "delete_saved_search", tool.DeleteSavedSearchRequest{Name: "weekly-invoices"}, &deleteResp)
+	assert.Equal(t, "weekly-invoices", deleteResp.Name)
+
+	result := callTool(t, "run_saved_search", tool.RunSavedSearchRequest{Name: "weekly-invoices"}, &runResp)
+	require.True(t, result.IsError, "Result should indicate error")
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "saved search not found: weekly-invoices")
+}
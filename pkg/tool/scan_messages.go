@@ -0,0 +1,51 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	scanPageSize        = 100
+	defaultScanMessages = 200
+	maxScanMessages     = 1000
+)
+
+type listMessagesSvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+}
+
+// scanMessageRefs pages through Messages.List until limit references are collected,
+// reporting whether more matches were left behind.
+func scanMessageRefs(ctx context.Context, svc listMessagesSvc, query string, limit int) ([]*gmail.Message, bool, error) {
+	var refs []*gmail.Message
+	pageToken := ""
+
+	for {
+		pageSize := min(int64(limit-len(refs)), scanPageSize)
+
+		result, err := svc.ListMessages(ctx, query, pageToken, pageSize)
+		if err != nil {
+			return nil, false, fmt.Errorf("svc.ListMessages failed: %w", err)
+		}
+
+		refs = append(refs, result.Messages...)
+		pageToken = result.NextPageToken
+
+		if pageToken == "" {
+			return refs, false, nil
+		}
+		if len(refs) >= limit {
+			return refs[:limit], true, nil
+		}
+	}
+}
+
+func normalizeScanLimit(limit int) int {
+	if limit <= 0 {
+		return defaultScanMessages
+	}
+	return min(limit, maxScanMessages)
+}
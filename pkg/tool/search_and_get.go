@@ -0,0 +1,156 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	defaultSearchAndGetResults = 5
+	maxSearchAndGetResults     = 20
+	defaultBodyCharsBudget     = 20000
+	budgetTruncationMarker     = "\n\n[truncated: result budget exhausted]"
+)
+
+// SearchAndGetRequest contains search parameters and the result budget.
+type SearchAndGetRequest struct {
+	Query         string `json:"query" jsonschema:"the Gmail search query"`
+	MaxResults    int64  `json:"max_results,omitempty" jsonschema:"max messages to retrieve, default 5, up to 20"`
+	PageToken     string `json:"page_token,omitempty" jsonschema:"token for pagination"`
+	MaxTotalChars int    `json:"max_total_chars,omitempty" jsonschema:"budget of body characters across all messages, default 20000"`
+	StripQuotes   bool   `json:"strip_quotes,omitempty" jsonschema:"remove quoted reply history (\"On ... wrote:\" blocks) from bodies"`
+	FootnoteLinks bool   `json:"footnote_links,omitempty" jsonschema:"move URLs of 40 or more characters into numbered references at the end of bodies"`
+
+	MaxResponseTokens int    `json:"max_response_tokens,omitempty" jsonschema:"stop adding messages once their estimated tokens would exceed this budget and return next_cursor"`
+	Cursor            string `json:"cursor,omitempty" jsonschema:"next_cursor of a previous call with the same arguments, used instead of page_token"`
+}
+
+// SearchAndGetResponse contains full contents of matching messages.
+type SearchAndGetResponse struct {
+	Messages      []MessageContent `json:"messages" jsonschema:"full contents of matching messages"`
+	RemainingIDs  []string         `json:"remaining_ids,omitempty" jsonschema:"matches left out once the budget was spent, fetch them with get_messages"`
+	NextPageToken string           `json:"next_page_token,omitempty" jsonschema:"token for next page"`
+	Truncated     bool             `json:"truncated" jsonschema:"true when the budget cut a body or left messages out"`
+	NextCursor    string           `json:"next_cursor,omitempty" jsonschema:"set when max_response_tokens cut the page, pass as cursor with the same arguments to continue"`
+}
+
+type searchAndGetSvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	GetMessage(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewSearchAndGet creates a new SearchAndGet tool.
+// The filter redacts and guards the returned content.
+func NewSearchAndGet(svc searchAndGetSvc, conv htmlConverter, filter ContentFilter) *SearchAndGet {
+	return &SearchAndGet{
+		svc:    svc,
+		conv:   conv,
+		filter: filter,
+	}
+}
+
+// SearchAndGet combines search and full message retrieval in one call.
+type SearchAndGet struct {
+	svc    searchAndGetSvc
+	conv   htmlConverter
+	filter ContentFilter
+}
+
+// SearchAndGet searches messages and returns their full contents within the character budget.
+func (t *SearchAndGet) SearchAndGet(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input SearchAndGetRequest,
+) (*mcp.CallToolResult, SearchAndGetResponse, error) {
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultSearchAndGetResults
+	}
+	maxResults = min(maxResults, maxSearchAndGetResults)
+
+	pageToken, offset, err := cursorPage(input.Cursor, input.PageToken)
+	if err != nil {
+		return nil, SearchAndGetResponse{}, err
+	}
+
+	result, err := t.svc.ListMessages(ctx, input.Query, pageToken, maxResults)
+	if err != nil {
+		return nil, SearchAndGetResponse{}, fmt.Errorf("svc.ListMessages failed: %w", err)
+	}
+	refs := result.Messages[min(offset, len(result.Messages)):]
+
+	response := SearchAndGetResponse{
+		Messages:      make([]MessageContent, 0, len(refs)),
+		NextPageToken: result.NextPageToken,
+	}
+	if err := t.fetchContents(ctx, refs, input, &response); err != nil {
+		return nil, SearchAndGetResponse{}, err
+	}
+	if cut := len(response.Messages) + len(response.RemainingIDs); cut < len(refs) {
+		// The rest of this page comes first, the next page token is in the cursor's page.
+		response.NextCursor = encodeCursor(pageToken, offset+cut)
+		response.NextPageToken = ""
+	}
+
+	return nil, response, nil
+}
+
+// fetchContents adds the contents of refs to response until the character or token budget runs
+// out. Messages left out by the character budget become RemainingIDs.
+func (t *SearchAndGet) fetchContents(
+	ctx context.Context,
+	refs []*gmail.Message,
+	input SearchAndGetRequest,
+	response *SearchAndGetResponse,
+) error {
+	budget := input.MaxTotalChars
+	if budget <= 0 {
+		budget = defaultBodyCharsBudget
+	}
+	tokens := newTokenBudget(input.MaxResponseTokens)
+
+	for i, ref := range refs {
+		if budget <= 0 {
+			for _, rest := range refs[i:] {
+				response.RemainingIDs = append(response.RemainingIDs, rest.Id)
+			}
+			response.Truncated = true
+			break
+		}
+
+		msg, err := t.svc.GetMessage(ctx, ref.Id)
+		if err != nil {
+			return fmt.Errorf("get message %s failed: %w", ref.Id, err)
+		}
+
+		content, err := extractMessageContent(msg, t.conv, bodyOptions{
+			stripQuotes:   input.StripQuotes,
+			footnoteLinks: input.FootnoteLinks,
+			redactor:      t.filter.Redactor,
+		})
+		if err != nil {
+			return fmt.Errorf("extractMessageContent failed: %w", err)
+		}
+
+		if body := []rune(content.BodyText); len(body) > budget {
+			content.BodyText = string(body[:budget]) + budgetTruncationMarker
+			response.Truncated = true
+			budget = 0
+		} else {
+			budget -= len(body)
+		}
+		t.filter.message(&content)
+		content.EstimatedTokens = estimateTokens(content.BodyText)
+
+		if !tokens.fits(content) {
+			response.Truncated = true
+			break
+		}
+		response.Messages = append(response.Messages, content)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestSearchAndGet(t *testing.T) {
+	gmailSvc := newGetMessagesGmailSvc()
+	gmailSvc.ListMessagesFunc = func(_ context.Context, Q, _ string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+		if Q != "in:inbox" {
+			return nil, fmt.Errorf("simulated error: %s", Q)
+		}
+		res := &gmail.ListMessagesResponse{NextPageToken: "page-2"}
+		for i := range maxResults {
+			res.Messages = append(res.Messages, &gmail.Message{Id: fmt.Sprintf("msg-%03d", i+1)})
+		}
+		return res, nil
+	}
+
+	cases := []struct {
+		name           string
+		req            tool.SearchAndGetRequest
+		expectedIDs    []string
+		expectedBodies []string
+		expectedRest   []string
+		truncated      bool
+		expectedErr    error
+	}{
+		{
+			name:           "within budget",
+			req:            tool.SearchAndGetRequest{Query: "in:inbox", MaxResults: 2},
+			expectedIDs:    []string{"msg-001", "msg-002"},
+			expectedBodies: []string{"Test plain text body for ", "Test plain text body for "},
+		},
+		{
+			name:        "budget exhausted",
+			req:         tool.SearchAndGetRequest{Query: "in:inbox", MaxResults: 3, MaxTotalChars: 30},
+			expectedIDs: []string{"msg-001", "msg-002"},
+			expectedBodies: []string{
+				"Test plain text body for ",
+				"Test \n\n[truncated: result budget exhausted]",
+			},
+			expectedRest: []string{"msg-003"},
+			truncated:    true,
+		},
+		{
+			name:        "list error",
+			req:         tool.SearchAndGetRequest{Query: "undefined"},
+			expectedErr: fmt.Errorf("simulated error: undefined"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "search_and_get",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.SearchAndGetResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+
+			ids := make([]string, 0, len(response.Messages))
+			bodies := make([]string, 0, len(response.Messages))
+			for _, msg := range response.Messages {
+				ids = append(ids, msg.Summary.ID)
+				bodies = append(bodies, msg.BodyText)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+			assert.Equal(t, tc.expectedBodies, bodies)
+			assert.Equal(t, tc.expectedRest, response.RemainingIDs)
+			assert.Equal(t, tc.truncated, response.Truncated)
+			assert.Equal(t, "page-2", response.NextPageToken)
+		})
+	}
+}
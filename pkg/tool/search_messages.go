@@ -0,0 +1,259 @@
+// Package tool implements MCP tools for Gmail operations.
+package tool
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const unreadLabelID = "UNREAD"
+
+// SearchMessagesRequest contains parameters for message search.
+type SearchMessagesRequest struct {
+	Query             string `json:"query,omitempty" jsonschema:"the Gmail search query, combined with the structured fields"`
+	From              string `json:"from,omitempty" jsonschema:"sender address or name"`
+	To                string `json:"to,omitempty" jsonschema:"recipient address or name"`
+	Subject           string `json:"subject,omitempty" jsonschema:"phrase the subject contains"`
+	After             string `json:"after,omitempty" jsonschema:"only messages on or after this date: YYYY-MM-DD, RFC3339, today or yesterday"`
+	Before            string `json:"before,omitempty" jsonschema:"only messages before this date: YYYY-MM-DD, RFC3339, today or yesterday"`
+	Range             string `json:"range,omitempty" jsonschema:"relative range: today, yesterday, this_week, last_week, this_month, last_month, this_year, last_year or last_N_days/weeks/months/years"`
+	Label             string `json:"label,omitempty" jsonschema:"label name, e.g. INBOX or Work/Projects"`
+	HasAttachment     *bool  `json:"has_attachment,omitempty" jsonschema:"true for messages with attachments, false for messages without"`
+	IsUnread          *bool  `json:"is_unread,omitempty" jsonschema:"true for unread messages, false for read messages"`
+	IDsOnly           bool   `json:"ids_only,omitempty" jsonschema:"return only message and thread IDs in refs, skipping per-message metadata lookups"`
+	MaxResults        int64  `json:"max_results,omitempty" jsonschema:"max results per page"`
+	PageToken         string `json:"page_token,omitempty" jsonschema:"token for pagination"`
+	MaxResponseTokens int    `json:"max_response_tokens,omitempty" jsonschema:"stop adding messages once their estimated tokens would exceed this budget and return next_cursor"`
+	Cursor            string `json:"cursor,omitempty" jsonschema:"next_cursor of a previous call with the same arguments, used instead of page_token"`
+}
+
+// SearchMessagesResponse contains search results with pagination.
+type SearchMessagesResponse struct {
+	Query         string           `json:"query,omitempty" jsonschema:"the Gmail query that ran"`
+	Messages      []MessageSummary `json:"messages" jsonschema:"array of message summaries"`
+	Refs          []MessageRef     `json:"refs,omitempty" jsonschema:"message and thread IDs, set instead of messages when ids_only is requested"`
+	NextPageToken string           `json:"next_page_token,omitempty" jsonschema:"token for next page"`
+	TotalResults  int              `json:"total_results" jsonschema:"number of messages returned"`
+	NextCursor    string           `json:"next_cursor,omitempty" jsonschema:"set when max_response_tokens cut the page, pass as cursor with the same arguments to continue"`
+}
+
+// MessageRef identifies a message and its thread.
+type MessageRef struct {
+	ID       string `json:"id" jsonschema:"message ID"`
+	ThreadID string `json:"thread_id" jsonschema:"thread ID"`
+}
+
+type searchMessagesSvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	batchMetadataSvc
+}
+
+// NewSearchMessages creates a new SearchMessages tool resolving dates in loc.
+func NewSearchMessages(svc searchMessagesSvc, loc *time.Location) *SearchMessages {
+	return &SearchMessages{
+		svc: svc,
+		loc: loc,
+	}
+}
+
+// SearchMessages implements Gmail message search functionality.
+type SearchMessages struct {
+	svc searchMessagesSvc
+	loc *time.Location
+}
+
+// SearchMessages searches for Gmail messages matching the query.
+func (t *SearchMessages) SearchMessages(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input SearchMessagesRequest,
+) (*mcp.CallToolResult, SearchMessagesResponse, error) {
+	input.MaxResults = normalizeMaxResults(input.MaxResults)
+
+	query, err := compileSearchQuery(input, t.loc, time.Now())
+	if err != nil {
+		return nil, SearchMessagesResponse{}, err
+	}
+
+	pageToken, offset, err := cursorPage(input.Cursor, input.PageToken)
+	if err != nil {
+		return nil, SearchMessagesResponse{}, err
+	}
+
+	result, err := t.svc.ListMessages(ctx, query, pageToken, input.MaxResults)
+	if err != nil {
+		return nil, SearchMessagesResponse{}, fmt.Errorf("svc.ListMessages failed: %w", err)
+	}
+	refs := result.Messages[min(offset, len(result.Messages)):]
+
+	if input.IDsOnly {
+		ids := make([]MessageRef, 0, len(refs))
+		for _, m := range refs {
+			ids = append(ids, MessageRef{ID: m.Id, ThreadID: m.ThreadId})
+		}
+
+		return nil, SearchMessagesResponse{
+			Query:         query,
+			Messages:      []MessageSummary{},
+			Refs:          ids,
+			NextPageToken: result.NextPageToken,
+			TotalResults:  len(ids),
+		}, nil
+	}
+
+	messages, err := fetchMessageSummaries(ctx, t.svc, refs, newTokenBudget(input.MaxResponseTokens))
+	if err != nil {
+		return nil, SearchMessagesResponse{}, fmt.Errorf("fetchMessageSummaries failed: %w", err)
+	}
+
+	response := SearchMessagesResponse{
+		Query:         query,
+		Messages:      messages,
+		NextPageToken: result.NextPageToken,
+		TotalResults:  len(messages),
+	}
+	if len(messages) < len(refs) {
+		// The rest of this page comes first, the next page token is in the cursor's page.
+		response.NextCursor = encodeCursor(pageToken, offset+len(messages))
+		response.NextPageToken = ""
+	}
+	return nil, response, nil
+}
+
+type messageMetadataSvc interface {
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+type batchMetadataSvc interface {
+	GetMessagesMetadata(ctx context.Context, msgIDs []string) ([]*gmail.Message, error)
+}
+
+// fetchMessageSummaries looks up summaries of refs in one batch and keeps them in order until
+// budget runs out.
+func fetchMessageSummaries(ctx context.Context, svc batchMetadataSvc, refs []*gmail.Message, budget *tokenBudget) ([]MessageSummary, error) {
+	ids := make([]string, len(refs))
+	for i, ref := range refs {
+		ids[i] = ref.Id
+	}
+	batch, err := svc.GetMessagesMetadata(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("svc.GetMessagesMetadata failed: %w", err)
+	}
+
+	messages := make([]MessageSummary, 0, len(batch))
+	for _, msg := range batch {
+		summary := extractMessageSummary(msg)
+		if !budget.fits(summary) {
+			break
+		}
+		messages = append(messages, summary)
+	}
+
+	return messages, nil
+}
+
+func extractMessageSummary(msg *gmail.Message) MessageSummary {
+	summary := MessageSummary{
+		ID:           msg.Id,
+		ThreadID:     msg.ThreadId,
+		Snippet:      html.UnescapeString(msg.Snippet),
+		LabelIDs:     msg.LabelIds,
+		IsUnread:     slices.Contains(msg.LabelIds, unreadLabelID),
+		SizeEstimate: msg.SizeEstimate,
+	}
+
+	if msg.Payload != nil && msg.Payload.Headers != nil {
+		extractHeadersToSummary(msg.Payload.Headers, &summary)
+	}
+
+	return summary
+}
+
+func normalizeMaxResults(maxResults int64) int64 {
+	if maxResults == 0 {
+		return 10
+	}
+	if maxResults > 50 {
+		return 50
+	}
+	return maxResults
+}
+
+func extractHeadersToSummary(headers []*gmail.MessagePartHeader, summary *MessageSummary) {
+	for _, header := range headers {
+		switch strings.ToLower(header.Name) {
+		case "from":
+			summary.From = parseEmailAddress(header.Value)
+		case "to":
+			summary.To = parseEmailAddressList(header.Value)
+		case "cc":
+			summary.CC = parseEmailAddressList(header.Value)
+		case "bcc":
+			summary.BCC = parseEmailAddressList(header.Value)
+		case "reply-to":
+			summary.ReplyTo = parseEmailAddressList(header.Value)
+		case "subject":
+			// Some senders escape entities in subjects, and Gmail always does in snippets.
+			summary.Subject = html.UnescapeString(header.Value)
+		case "date":
+			summary.Timestamp = header.Value
+		case "message-id":
+			summary.MessageID = strings.TrimSpace(header.Value)
+		case "in-reply-to":
+			summary.InReplyTo = strings.TrimSpace(header.Value)
+		case "references":
+			summary.References = strings.Fields(header.Value)
+		}
+	}
+}
+
+// headerValue returns the first header with the given case-insensitive name.
+func headerValue(headers []*gmail.MessagePartHeader, name string) string {
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value
+		}
+	}
+	return ""
+}
+
+func parseEmailAddress(from string) EmailAddress {
+	addr := EmailAddress{}
+
+	if idx := strings.Index(from, "<"); idx != -1 {
+		addr.Name = strings.TrimSpace(from[:idx])
+		if endIdx := strings.Index(from[idx:], ">"); endIdx != -1 {
+			addr.Email = strings.TrimSpace(from[idx+1 : idx+endIdx])
+		}
+	} else {
+		addr.Email = strings.TrimSpace(from)
+	}
+
+	addr.Name = strings.Trim(addr.Name, "\"")
+
+	return addr
+}
+
+func parseEmailAddressList(addresses string) []EmailAddress {
+	if addresses == "" {
+		return nil
+	}
+
+	parts := strings.Split(addresses, ",")
+	result := make([]EmailAddress, 0, len(parts))
+
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			result = append(result, parseEmailAddress(trimmed))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,284 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func newSearchMessagesGmailSvc(byQuery map[string]*gmail.ListMessagesResponse) *gmailSvcMock {
+	return withBatchGets(&gmailSvcMock{
+		ListMessagesFunc: func(
+			_ context.Context,
+			Q, _ string,
+			_ int64) (*gmail.ListMessagesResponse, error) {
+			res, ok := byQuery[Q]
+			if !ok {
+				return nil, fmt.Errorf("simulated error: %s", Q)
+			}
+			return res, nil
+		},
+		GetMessageMetadataFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{
+				Id:           msgID,
+				ThreadId:     "t-" + msgID,
+				Snippet:      "test summary " + msgID + " &amp; &#39;more&#39;",
+				LabelIds:     []string{"INBOX", "UNREAD"},
+				SizeEstimate: 2048,
+				Payload: &gmail.MessagePart{
+					Headers: []*gmail.MessagePartHeader{
+						{Name: "From", Value: fmt.Sprintf("Test User <test+%s@test.com>", msgID)},
+						{Name: "To", Value: fmt.Sprintf("My Name <me+%s@test.com>", msgID)},
+						{Name: "Subject", Value: "Super important email " + msgID},
+						{Name: "Date", Value: "2025-09-14 12:12:32"},
+					},
+				},
+			}, nil
+		},
+	})
+}
+
+func TestSearchMessages(t *testing.T) {
+	hasAttachment, isUnread := true, false
+	structuredQuery := `in:inbox from:"Jane Doe" subject:"quarterly report" newer_than:7d after:1735707600 before:1738386000 ` +
+		`label:work-projects has:attachment -is:unread`
+
+	cases := []struct {
+		req         tool.SearchMessagesRequest
+		expected    tool.SearchMessagesResponse
+		expectedErr error
+	}{
+		{
+			req: tool.SearchMessagesRequest{Query: "test@test.com", MaxResults: 2},
+			expected: tool.SearchMessagesResponse{
+				Query:         "test@test.com",
+				TotalResults:  2,
+				NextPageToken: "next-page-token-1",
+				Messages: []tool.MessageSummary{
+					{
+						ID:           "m-001",
+						ThreadID:     "t-m-001",
+						Timestamp:    "2025-09-14 12:12:32",
+						From:         tool.EmailAddress{Name: "Test User", Email: "test+m-001@test.com"},
+						To:           []tool.EmailAddress{{Name: "My Name", Email: "me+m-001@test.com"}},
+						Subject:      "Super important email m-001",
+						Snippet:      "test summary m-001 & 'more'",
+						LabelIDs:     []string{"INBOX", "UNREAD"},
+						IsUnread:     true,
+						SizeEstimate: 2048,
+					},
+					{
+						ID:           "m-002",
+						ThreadID:     "t-m-002",
+						Timestamp:    "2025-09-14 12:12:32",
+						From:         tool.EmailAddress{Name: "Test User", Email: "test+m-002@test.com"},
+						To:           []tool.EmailAddress{{Name: "My Name", Email: "me+m-002@test.com"}},
+						Subject:      "Super important email m-002",
+						Snippet:      "test summary m-002 & 'more'",
+						LabelIDs:     []string{"INBOX", "UNREAD"},
+						IsUnread:     true,
+						SizeEstimate: 2048,
+					},
+				},
+			},
+		},
+		{
+			req: tool.SearchMessagesRequest{
+				Query:         "in:inbox",
+				From:          "Jane Doe",
+				Subject:       `quarterly "report"`,
+				Range:         "last_7_days",
+				After:         "2025-01-01",
+				Before:        "2025/02/01",
+				Label:         "Work/Projects",
+				HasAttachment: &hasAttachment,
+				IsUnread:      &isUnread,
+			},
+			expected: tool.SearchMessagesResponse{
+				Query:        structuredQuery,
+				TotalResults: 1,
+				Messages: []tool.MessageSummary{
+					{
+						ID:           "m-003",
+						ThreadID:     "t-m-003",
+						Timestamp:    "2025-09-14 12:12:32",
+						From:         tool.EmailAddress{Name: "Test User", Email: "test+m-003@test.com"},
+						To:           []tool.EmailAddress{{Name: "My Name", Email: "me+m-003@test.com"}},
+						Subject:      "Super important email m-003",
+						Snippet:      "test summary m-003 & 'more'",
+						LabelIDs:     []string{"INBOX", "UNREAD"},
+						IsUnread:     true,
+						SizeEstimate: 2048,
+					},
+				},
+			},
+		},
+		{
+			req: tool.SearchMessagesRequest{Query: "ids only", IDsOnly: true},
+			expected: tool.SearchMessagesResponse{
+				Query:         "ids only",
+				TotalResults:  2,
+				NextPageToken: "next-page-token-2",
+				Messages:      []tool.MessageSummary{},
+				Refs: []tool.MessageRef{
+					{ID: "m-004", ThreadID: "t-1"},
+					{ID: "m-005", ThreadID: "t-1"},
+				},
+			},
+		},
+		{
+			req: tool.SearchMessagesRequest{Query: "weeks", Range: "last_2_weeks"},
+			expected: tool.SearchMessagesResponse{
+				Query:    "weeks newer_than:14d",
+				Messages: []tool.MessageSummary{},
+			},
+		},
+		{
+			req:         tool.SearchMessagesRequest{Query: "invalid date", After: "last week"},
+			expectedErr: fmt.Errorf(`invalid after "last week"`),
+		},
+		{
+			req:         tool.SearchMessagesRequest{Query: "invalid range", Range: "fortnight"},
+			expectedErr: fmt.Errorf(`invalid range "fortnight"`),
+		},
+		{
+			req:         tool.SearchMessagesRequest{Query: "undefined@undefined"},
+			expectedErr: fmt.Errorf("simulated error: undefined@undefined"),
+		},
+	}
+
+	gmailSvc := newSearchMessagesGmailSvc(map[string]*gmail.ListMessagesResponse{
+		"test@test.com": {
+			Messages: []*gmail.Message{
+				{Id: "m-001"},
+				{Id: "m-002"},
+			},
+			NextPageToken: "next-page-token-1",
+		},
+		"ids only": {
+			Messages: []*gmail.Message{
+				{Id: "m-004", ThreadId: "t-1"},
+				{Id: "m-005", ThreadId: "t-1"},
+			},
+			NextPageToken: "next-page-token-2",
+		},
+		"weeks newer_than:14d": {},
+		structuredQuery: {
+			Messages: []*gmail.Message{
+				{Id: "m-003"},
+			},
+		},
+	})
+
+	server := tool.NewServer(gmailSvc, &converterMock{}, tool.WithTimezone(time.FixedZone("UTC-5", -5*60*60)))
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.req.Query, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "search_messages",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.SearchMessagesResponse
+
+			require.NoError(
+				t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
+
+func TestSearchMessagesMaxResponseTokens(t *testing.T) {
+	gmailSvc := newSearchMessagesGmailSvc(map[string]*gmail.ListMessagesResponse{
+		"test@test.com": {
+			Messages: []*gmail.Message{
+				{Id: "m-001"},
+				{Id: "m-002"},
+			},
+			NextPageToken: "next-page-token-1",
+		},
+	})
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	search := func(req tool.SearchMessagesRequest) tool.SearchMessagesResponse {
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "search_messages",
+			Arguments: req,
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, "Result should not indicate error")
+
+		var response tool.SearchMessagesResponse
+		require.NoError(t,
+			json.Unmarshal(
+				[]byte(result.Content[0].(*mcp.TextContent).Text),
+				&response,
+			),
+		)
+		return response
+	}
+
+	first := search(tool.SearchMessagesRequest{Query: "test@test.com", MaxResults: 2, MaxResponseTokens: 1})
+	require.Len(t, first.Messages, 1)
+	assert.Equal(t, "m-001", first.Messages[0].ID)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Empty(t, first.NextPageToken, "the rest of the page comes before the next page")
+
+	second := search(tool.SearchMessagesRequest{
+		Query: "test@test.com", MaxResults: 2, MaxResponseTokens: 1, Cursor: first.NextCursor,
+	})
+	require.Len(t, second.Messages, 1)
+	assert.Equal(t, "m-002", second.Messages[0].ID)
+	assert.Empty(t, second.NextCursor)
+	assert.Equal(t, "next-page-token-1", second.NextPageToken)
+}
@@ -0,0 +1,194 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	labelOperator       = "label:"
+	maxCompletionValues = 100
+)
+
+// SearchOperator describes a Gmail search operator.
+type SearchOperator struct {
+	Operator    string `json:"operator" jsonschema:"operator as typed in a query"`
+	Description string `json:"description" jsonschema:"what the operator matches"`
+	Example     string `json:"example" jsonschema:"example usage"`
+}
+
+var searchOperators = []SearchOperator{
+	{"from:", "sender", "from:amy@example.com"},
+	{"to:", "recipient", "to:david"},
+	{"cc:", "recipient in the Cc field", "cc:david"},
+	{"bcc:", "recipient in the Bcc field", "bcc:david"},
+	{"subject:", "words in the subject line", "subject:dinner"},
+	{"label:", "messages with a label", "label:friends"},
+	{"category:", "messages in a category: primary, social, promotions, updates, forums, reservations, purchases", "category:updates"},
+	{"has:attachment", "messages with an attachment", "has:attachment"},
+	{"has:drive", "messages with a Google Drive link", "has:drive"},
+	{"has:document", "messages with a Google Docs link", "has:document"},
+	{"has:spreadsheet", "messages with a Google Sheets link", "has:spreadsheet"},
+	{"has:presentation", "messages with a Google Slides link", "has:presentation"},
+	{"has:youtube", "messages with a YouTube video", "has:youtube"},
+	{"has:userlabels", "messages with a user label", "has:userlabels"},
+	{"has:nouserlabels", "messages without a user label", "has:nouserlabels"},
+	{"filename:", "attachments by name or file type", "filename:pdf"},
+	{"in:inbox", "messages in the inbox", "in:inbox"},
+	{"in:sent", "sent messages", "in:sent"},
+	{"in:draft", "drafts", "in:draft"},
+	{"in:spam", "messages in spam", "in:spam"},
+	{"in:trash", "messages in trash", "in:trash"},
+	{"in:snoozed", "snoozed messages", "in:snoozed"},
+	{"in:anywhere", "messages anywhere, including spam and trash", "in:anywhere movie"},
+	{"is:unread", "unread messages", "is:unread"},
+	{"is:read", "read messages", "is:read"},
+	{"is:starred", "starred messages", "is:starred"},
+	{"is:important", "messages marked important", "is:important"},
+	{"is:muted", "muted conversations", "is:muted"},
+	{"after:", "messages received after a date (YYYY/MM/DD)", "after:2025/04/16"},
+	{"before:", "messages received before a date (YYYY/MM/DD)", "before:2025/04/18"},
+	{"older_than:", "messages older than a period (d, m, y)", "older_than:1y"},
+	{"newer_than:", "messages newer than a period (d, m, y)", "newer_than:2d"},
+	{"larger:", "messages larger than a size in bytes (k, M suffixes allowed)", "larger:10M"},
+	{"smaller:", "messages smaller than a size in bytes (k, M suffixes allowed)", "smaller:1M"},
+	{"list:", "messages from a mailing list", "list:info@example.com"},
+	{"deliveredto:", "messages delivered to an address", "deliveredto:username@example.com"},
+	{"rfc822msgid:", "message with a Message-ID header", "rfc822msgid:200503292@example.com"},
+	{"OR", "either term, also written as { }", "from:amy OR from:david"},
+	{"-", "exclude a term", "dinner -movie"},
+	{"AROUND", "words near each other", "holiday AROUND 10 vacation"},
+	{"\"\"", "exact phrase", "\"dinner and movie tonight\""},
+}
+
+// ListSearchOperatorsRequest filters the operator listing.
+type ListSearchOperatorsRequest struct {
+	Prefix string `json:"prefix,omitempty" jsonschema:"only return operators and labels starting with this prefix"`
+}
+
+// ListSearchOperatorsResponse contains supported operators and label names usable with label:.
+type ListSearchOperatorsResponse struct {
+	Operators []SearchOperator `json:"operators" jsonschema:"supported Gmail search operators"`
+	Labels    []string         `json:"labels" jsonschema:"label names in the form accepted by label:"`
+}
+
+type searchOperatorsSvc interface {
+	ListLabels(ctx context.Context) ([]*gmail.Label, error)
+}
+
+// NewSearchOperators creates a new SearchOperators tool.
+func NewSearchOperators(svc searchOperatorsSvc) *SearchOperators {
+	return &SearchOperators{
+		svc: svc,
+	}
+}
+
+// SearchOperators lists and completes Gmail search syntax.
+type SearchOperators struct {
+	svc searchOperatorsSvc
+}
+
+// ListSearchOperators returns supported operators and the user's label names.
+func (t *SearchOperators) ListSearchOperators(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input ListSearchOperatorsRequest,
+) (*mcp.CallToolResult, ListSearchOperatorsResponse, error) {
+	labels, err := t.labelQueryNames(ctx)
+	if err != nil {
+		return nil, ListSearchOperatorsResponse{}, fmt.Errorf("labelQueryNames failed: %w", err)
+	}
+
+	prefix := strings.ToLower(input.Prefix)
+	operators := []SearchOperator{}
+	for _, op := range searchOperators {
+		if strings.HasPrefix(strings.ToLower(op.Operator), prefix) {
+			operators = append(operators, op)
+		}
+	}
+
+	matched := []string{}
+	for _, name := range labels {
+		if strings.HasPrefix(name, strings.TrimPrefix(prefix, labelOperator)) {
+			matched = append(matched, name)
+		}
+	}
+
+	return nil, ListSearchOperatorsResponse{
+		Operators: operators,
+		Labels:    matched,
+	}, nil
+}
+
+// Complete handles completion/complete for query arguments by completing the last query term
+// with an operator or, after label:, with a label name.
+func (t *SearchOperators) Complete(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	values := []string{}
+
+	if !slices.Contains([]string{"query", "q"}, req.Params.Argument.Name) {
+		return &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: values}}, nil
+	}
+
+	value := req.Params.Argument.Value
+	head, term := "", value
+	if i := strings.LastIndexAny(value, " ("); i >= 0 {
+		head, term = value[:i+1], value[i+1:]
+	}
+	negation := ""
+	if strings.HasPrefix(term, "-") {
+		negation, term = "-", term[1:]
+	}
+
+	if labelPrefix, ok := strings.CutPrefix(strings.ToLower(term), labelOperator); ok {
+		labels, err := t.labelQueryNames(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("labelQueryNames failed: %w", err)
+		}
+		for _, name := range labels {
+			if strings.HasPrefix(name, labelPrefix) {
+				values = append(values, head+negation+labelOperator+name)
+			}
+		}
+	} else if term != "" {
+		lowerTerm := strings.ToLower(term)
+		for _, op := range searchOperators {
+			if strings.HasPrefix(strings.ToLower(op.Operator), lowerTerm) {
+				values = append(values, head+negation+op.Operator)
+			}
+		}
+	}
+
+	total := len(values)
+	return &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{
+		Values:  values[:min(total, maxCompletionValues)],
+		Total:   total,
+		HasMore: total > maxCompletionValues,
+	}}, nil
+}
+
+// labelQueryNames returns label names the way Gmail expects them after label:,
+// lowercase with spaces and slashes replaced by dashes.
+func (t *SearchOperators) labelQueryNames(ctx context.Context) ([]string, error) {
+	labels, err := t.svc.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("svc.ListLabels failed: %w", err)
+	}
+
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, labelQueryName(l.Name))
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// labelQueryName converts a label name into the form Gmail accepts after label:.
+func labelQueryName(name string) string {
+	return strings.NewReplacer(" ", "-", "/", "-").Replace(strings.ToLower(name))
+}
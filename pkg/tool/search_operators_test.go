@@ -0,0 +1,109 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func newSearchOperatorsGmailSvc() *gmailSvcMock {
+	return &gmailSvcMock{
+		ListLabelsFunc: func(_ context.Context) ([]*gmail.Label, error) {
+			return []*gmail.Label{
+				{Id: "INBOX", Name: "INBOX", Type: "system"},
+				{Id: "Label_1", Name: "Work/Project X", Type: "user"},
+				{Id: "Label_2", Name: "Receipts", Type: "user"},
+			}, nil
+		},
+	}
+}
+
+func TestListSearchOperators(t *testing.T) {
+	server := tool.NewServer(newSearchOperatorsGmailSvc(), &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	t.Run("tool listing", func(t *testing.T) {
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "list_search_operators",
+			Arguments: tool.ListSearchOperatorsRequest{Prefix: "is:"},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, "Result should not indicate error")
+
+		var response tool.ListSearchOperatorsResponse
+		require.NoError(t,
+			json.Unmarshal(
+				[]byte(result.Content[0].(*mcp.TextContent).Text),
+				&response,
+			),
+		)
+
+		operators := make([]string, 0, len(response.Operators))
+		for _, op := range response.Operators {
+			operators = append(operators, op.Operator)
+		}
+		assert.Equal(t, []string{"is:unread", "is:read", "is:starred", "is:important", "is:muted"}, operators)
+		assert.Empty(t, response.Labels)
+	})
+
+	completionCases := []struct {
+		name     string
+		argument mcp.CompleteParamsArgument
+		expected []string
+	}{
+		{
+			name:     "operator",
+			argument: mcp.CompleteParamsArgument{Name: "query", Value: "from:amy has:att"},
+			expected: []string{"from:amy has:attachment"},
+		},
+		{
+			name:     "negated operator",
+			argument: mcp.CompleteParamsArgument{Name: "query", Value: "-in:sp"},
+			expected: []string{"-in:spam"},
+		},
+		{
+			name:     "label names",
+			argument: mcp.CompleteParamsArgument{Name: "query", Value: "is:unread label:"},
+			expected: []string{"is:unread label:inbox", "is:unread label:receipts", "is:unread label:work-project-x"},
+		},
+		{
+			name:     "label prefix",
+			argument: mcp.CompleteParamsArgument{Name: "query", Value: "label:Wo"},
+			expected: []string{"label:work-project-x"},
+		},
+		{
+			name:     "other argument",
+			argument: mcp.CompleteParamsArgument{Name: "thread_id", Value: "is:"},
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range completionCases {
+		t.Run("complete "+tc.name, func(t *testing.T) {
+			result, err := clientSession.Complete(ctx, &mcp.CompleteParams{
+				Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "search"},
+				Argument: tc.argument,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result.Completion.Values)
+		})
+	}
+}
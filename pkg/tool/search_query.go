@@ -0,0 +1,172 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchDateLayouts are the calendar date formats accepted by structured search fields.
+var searchDateLayouts = []string{
+	time.DateOnly,
+	"2006/01/02",
+}
+
+// lastPeriodRange matches relative ranges like last_7_days.
+var lastPeriodRange = regexp.MustCompile(`^last_(\d+)_(day|week|month|year)s?$`)
+
+// newerThanUnits maps relative range units onto newer_than: suffixes. Gmail has no week unit,
+// weeks are sent as days.
+var newerThanUnits = map[string]string{
+	"day":   "d",
+	"month": "m",
+	"year":  "y",
+}
+
+// compileSearchQuery combines the free-form query with structured search fields into one Gmail query.
+// Calendar dates are resolved to midnight in loc and sent as epoch seconds, because Gmail
+// otherwise interprets after:/before: dates in its own timezone.
+func compileSearchQuery(input SearchMessagesRequest, loc *time.Location, now time.Time) (string, error) {
+	terms := []string{}
+	if q := strings.TrimSpace(input.Query); q != "" {
+		terms = append(terms, q)
+	}
+
+	for _, field := range []struct{ operator, value string }{
+		{"from:", input.From},
+		{"to:", input.To},
+		{"subject:", input.Subject},
+	} {
+		if value := strings.TrimSpace(field.value); value != "" {
+			terms = append(terms, field.operator+quoteSearchValue(value))
+		}
+	}
+
+	if input.Range != "" {
+		rangeTerms, err := relativeRangeTerms(strings.ToLower(strings.TrimSpace(input.Range)), now.In(loc))
+		if err != nil {
+			return "", fmt.Errorf("invalid range %q: %w", input.Range, err)
+		}
+		terms = append(terms, rangeTerms...)
+	}
+
+	for _, field := range []struct{ name, operator, value string }{
+		{"after", "after:", input.After},
+		{"before", "before:", input.Before},
+	} {
+		if field.value == "" {
+			continue
+		}
+		instant, err := parseSearchDate(field.value, now.In(loc))
+		if err != nil {
+			return "", fmt.Errorf("invalid %s %q: %w", field.name, field.value, err)
+		}
+		terms = append(terms, field.operator+strconv.FormatInt(instant.Unix(), 10))
+	}
+
+	if label := strings.TrimSpace(input.Label); label != "" {
+		terms = append(terms, labelOperator+labelQueryName(label))
+	}
+
+	if input.HasAttachment != nil {
+		terms = append(terms, negateIf(!*input.HasAttachment, "has:attachment"))
+	}
+	if input.IsUnread != nil {
+		terms = append(terms, negateIf(!*input.IsUnread, "is:unread"))
+	}
+
+	return strings.Join(terms, " "), nil
+}
+
+// relativeRangeTerms translates a named range into Gmail operators. Rolling ranges map onto
+// newer_than:, calendar ranges onto after:/before: bounds in the timezone of now.
+func relativeRangeTerms(name string, now time.Time) ([]string, error) {
+	if m := lastPeriodRange.FindStringSubmatch(name); m != nil {
+		if m[2] == "week" {
+			weeks, err := strconv.Atoi(m[1])
+			if err != nil {
+				return nil, fmt.Errorf("strconv.Atoi failed: %w", err)
+			}
+			return []string{"newer_than:" + strconv.Itoa(weeks*7) + "d"}, nil
+		}
+		return []string{"newer_than:" + m[1] + newerThanUnits[m[2]]}, nil
+	}
+
+	today := startOfDay(now)
+	weekStart := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+	yearStart := time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location())
+
+	var from, to time.Time
+	switch name {
+	case "today":
+		from, to = today, today.AddDate(0, 0, 1)
+	case "yesterday":
+		from, to = today.AddDate(0, 0, -1), today
+	case "this_week":
+		from, to = weekStart, weekStart.AddDate(0, 0, 7)
+	case "last_week":
+		from, to = weekStart.AddDate(0, 0, -7), weekStart
+	case "this_month":
+		from, to = monthStart, monthStart.AddDate(0, 1, 0)
+	case "last_month":
+		from, to = monthStart.AddDate(0, -1, 0), monthStart
+	case "this_year":
+		from, to = yearStart, yearStart.AddDate(1, 0, 0)
+	case "last_year":
+		from, to = yearStart.AddDate(-1, 0, 0), yearStart
+	default:
+		return nil, errors.New("expected today, yesterday, this_/last_ week, month or year, or last_N_days/weeks/months/years")
+	}
+
+	return []string{
+		"after:" + strconv.FormatInt(from.Unix(), 10),
+		"before:" + strconv.FormatInt(to.Unix(), 10),
+	}, nil
+}
+
+// quoteSearchValue wraps values containing whitespace in quotes so Gmail treats them as a phrase.
+func quoteSearchValue(value string) string {
+	value = strings.ReplaceAll(value, `"`, "")
+	if strings.ContainsAny(value, " \t") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// parseSearchDate accepts today, yesterday, calendar dates (midnight in the timezone of now)
+// and RFC3339 timestamps.
+func parseSearchDate(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+
+	if instant, err := time.Parse(time.RFC3339, value); err == nil {
+		return instant, nil
+	}
+	for _, layout := range searchDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, errors.New("expected YYYY-MM-DD, RFC3339, today or yesterday")
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func negateIf(negate bool, term string) string {
+	if negate {
+		return "-" + term
+	}
+	return term
+}
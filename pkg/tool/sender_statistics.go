@@ -0,0 +1,142 @@
+package tool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const defaultTopSenders = 20
+
+// SenderStatisticsRequest contains parameters for sender aggregation.
+type SenderStatisticsRequest struct {
+	Query       string `json:"query,omitempty" jsonschema:"the Gmail search query limiting scanned messages"`
+	MaxMessages int    `json:"max_messages,omitempty" jsonschema:"max messages to scan, default 200, up to 1000"`
+	Top         int    `json:"top,omitempty" jsonschema:"number of senders to return, default 20"`
+}
+
+// SenderStatisticsResponse contains senders ranked by message count.
+type SenderStatisticsResponse struct {
+	Senders         []SenderStats `json:"senders" jsonschema:"senders ranked by message count"`
+	ScannedMessages int           `json:"scanned_messages" jsonschema:"number of messages aggregated"`
+	Truncated       bool          `json:"truncated" jsonschema:"true when more messages matched than were scanned"`
+}
+
+// SenderStats aggregates messages of a single sender.
+type SenderStats struct {
+	Sender    EmailAddress `json:"sender" jsonschema:"sender information"`
+	Count     int          `json:"count" jsonschema:"number of messages"`
+	TotalSize int64        `json:"total_size" jsonschema:"total size of messages in bytes"`
+	FirstDate string       `json:"first_date" jsonschema:"date of the oldest message"`
+	LastDate  string       `json:"last_date" jsonschema:"date of the newest message"`
+}
+
+type senderStatisticsSvc interface {
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+}
+
+// NewSenderStatistics creates a new SenderStatistics tool.
+func NewSenderStatistics(svc senderStatisticsSvc) *SenderStatistics {
+	return &SenderStatistics{
+		svc: svc,
+	}
+}
+
+// SenderStatistics aggregates search results by sender.
+type SenderStatistics struct {
+	svc senderStatisticsSvc
+}
+
+type senderAggregate struct {
+	stats SenderStats
+	first time.Time
+	last  time.Time
+}
+
+// SenderStatistics scans matching messages and ranks their senders.
+func (t *SenderStatistics) SenderStatistics(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input SenderStatisticsRequest,
+) (*mcp.CallToolResult, SenderStatisticsResponse, error) {
+	refs, truncated, err := scanMessageRefs(ctx, t.svc, input.Query, normalizeScanLimit(input.MaxMessages))
+	if err != nil {
+		return nil, SenderStatisticsResponse{}, fmt.Errorf("scanMessageRefs failed: %w", err)
+	}
+
+	bySender, err := aggregateSenders(ctx, t.svc, refs)
+	if err != nil {
+		return nil, SenderStatisticsResponse{}, fmt.Errorf("aggregateSenders failed: %w", err)
+	}
+
+	return nil, SenderStatisticsResponse{
+		Senders:         rankSenders(bySender, input.Top),
+		ScannedMessages: len(refs),
+		Truncated:       truncated,
+	}, nil
+}
+
+func aggregateSenders(ctx context.Context, svc messageMetadataSvc, refs []*gmail.Message) (map[string]*senderAggregate, error) {
+	bySender := make(map[string]*senderAggregate)
+
+	for _, ref := range refs {
+		msg, err := svc.GetMessageMetadata(ctx, ref.Id)
+		if err != nil {
+			return nil, fmt.Errorf("get message %s failed: %w", ref.Id, err)
+		}
+
+		summary := extractMessageSummary(msg)
+		key := strings.ToLower(summary.From.Email)
+		agg, ok := bySender[key]
+		if !ok {
+			agg = &senderAggregate{stats: SenderStats{Sender: summary.From}}
+			bySender[key] = agg
+		}
+		agg.add(msg)
+	}
+
+	return bySender, nil
+}
+
+func (a *senderAggregate) add(msg *gmail.Message) {
+	a.stats.Count++
+	a.stats.TotalSize += msg.SizeEstimate
+
+	received := time.UnixMilli(msg.InternalDate).UTC()
+	if a.first.IsZero() || received.Before(a.first) {
+		a.first = received
+		a.stats.FirstDate = received.Format(time.RFC3339)
+	}
+	if received.After(a.last) {
+		a.last = received
+		a.stats.LastDate = received.Format(time.RFC3339)
+	}
+}
+
+func rankSenders(bySender map[string]*senderAggregate, top int) []SenderStats {
+	if top <= 0 {
+		top = defaultTopSenders
+	}
+
+	ranked := make([]SenderStats, 0, len(bySender))
+	for _, agg := range bySender {
+		ranked = append(ranked, agg.stats)
+	}
+
+	slices.SortFunc(ranked, func(a, b SenderStats) int {
+		return cmp.Or(
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(b.TotalSize, a.TotalSize),
+			strings.Compare(a.Sender.Email, b.Sender.Email),
+		)
+	})
+
+	return ranked[:min(top, len(ranked))]
+}
@@ -0,0 +1,178 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+type scanFixture struct {
+	from         string
+	to           string
+	labels       []string
+	internalDate int64
+	size         int64
+}
+
+func newScanGmailSvc(query string, pages [][]string, fixtures map[string]scanFixture) *gmailSvcMock {
+	return &gmailSvcMock{
+		ListMessagesFunc: func(_ context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error) {
+			if Q != query {
+				return nil, fmt.Errorf("simulated error: %s", Q)
+			}
+
+			page := 0
+			if pageToken != "" {
+				_, _ = fmt.Sscanf(pageToken, "page-%d", &page)
+			}
+
+			res := &gmail.ListMessagesResponse{}
+			for _, id := range pages[page][:min(int(maxResults), len(pages[page]))] {
+				res.Messages = append(res.Messages, &gmail.Message{Id: id, ThreadId: "t-" + id})
+			}
+			if page+1 < len(pages) {
+				res.NextPageToken = fmt.Sprintf("page-%d", page+1)
+			}
+			return res, nil
+		},
+		GetMessageMetadataFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			f := fixtures[msgID]
+			return &gmail.Message{
+				Id:           msgID,
+				ThreadId:     "t-" + msgID,
+				LabelIds:     f.labels,
+				InternalDate: f.internalDate,
+				SizeEstimate: f.size,
+				Payload: &gmail.MessagePart{
+					Headers: []*gmail.MessagePartHeader{
+						{Name: "From", Value: f.from},
+						{Name: "To", Value: f.to},
+						{Name: "Subject", Value: "Subject " + msgID},
+					},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestSenderStatistics(t *testing.T) {
+	fixtures := map[string]scanFixture{
+		"m-1": {from: "Alice <alice@example.com>", internalDate: 1735725600000, size: 100},
+		"m-2": {from: "Bob <bob@example.com>", internalDate: 1735812000000, size: 5000},
+		"m-3": {from: "ALICE@example.com", internalDate: 1735898400000, size: 200},
+		"m-4": {from: "Carol <carol@example.com>", internalDate: 1735984800000, size: 10},
+		"m-5": {from: "Alice <alice@example.com>", internalDate: 1735639200000, size: 300},
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.SenderStatisticsRequest
+		expected    tool.SenderStatisticsResponse
+		expectedErr error
+	}{
+		{
+			name: "all pages",
+			req:  tool.SenderStatisticsRequest{Query: "in:inbox", Top: 2},
+			expected: tool.SenderStatisticsResponse{
+				ScannedMessages: 5,
+				Senders: []tool.SenderStats{
+					{
+						Sender:    tool.EmailAddress{Name: "Alice", Email: "alice@example.com"},
+						Count:     3,
+						TotalSize: 600,
+						FirstDate: "2024-12-31T10:00:00Z",
+						LastDate:  "2025-01-03T10:00:00Z",
+					},
+					{
+						Sender:    tool.EmailAddress{Name: "Bob", Email: "bob@example.com"},
+						Count:     1,
+						TotalSize: 5000,
+						FirstDate: "2025-01-02T10:00:00Z",
+						LastDate:  "2025-01-02T10:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name: "truncated scan",
+			req:  tool.SenderStatisticsRequest{Query: "in:inbox", MaxMessages: 2},
+			expected: tool.SenderStatisticsResponse{
+				ScannedMessages: 2,
+				Truncated:       true,
+				Senders: []tool.SenderStats{
+					{
+						Sender:    tool.EmailAddress{Name: "Bob", Email: "bob@example.com"},
+						Count:     1,
+						TotalSize: 5000,
+						FirstDate: "2025-01-02T10:00:00Z",
+						LastDate:  "2025-01-02T10:00:00Z",
+					},
+					{
+						Sender:    tool.EmailAddress{Name: "Alice", Email: "alice@example.com"},
+						Count:     1,
+						TotalSize: 100,
+						FirstDate: "2025-01-01T10:00:00Z",
+						LastDate:  "2025-01-01T10:00:00Z",
+					},
+				},
+			},
+		},
+		{
+			name:        "list error",
+			req:         tool.SenderStatisticsRequest{Query: "undefined"},
+			expectedErr: fmt.Errorf("simulated error: undefined"),
+		},
+	}
+
+	gmailSvc := newScanGmailSvc("in:inbox", [][]string{{"m-1", "m-2", "m-3"}, {"m-4", "m-5"}}, fixtures)
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "sender_statistics",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.SenderStatisticsResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
@@ -0,0 +1,561 @@
+package tool
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/drive/v3"
+
+	"github.com/hal9000y/gmail-mcp/pkg/mailsync"
+	"github.com/hal9000y/gmail-mcp/pkg/redact"
+	"github.com/hal9000y/gmail-mcp/pkg/savedsearch"
+	"github.com/hal9000y/gmail-mcp/pkg/watermark"
+)
+
+//go:generate moq -rm -pkg tool_test -out moq_gmail_svc_test.go -skip-ensure . gmailSvc:gmailSvcMock
+type gmailSvc interface {
+	getMessagesSvc
+	batchMessagesSvc
+	searchMessagesSvc
+	previewAttachmentsSvc
+	countMessagesSvc
+	browseLabelSvc
+	manageLabelsSvc
+	muteThreadSvc
+	exportThreadSvc
+	exportMboxSvc
+	saveAttachmentSvc
+	senderStatisticsSvc
+	inboxSummarySvc
+	findAttachmentsSvc
+	frequentCorrespondentsSvc
+	awaitingReplySvc
+	extractLinksSvc
+	checkMessageAuthSvc
+	analyzePhishingSvc
+	searchOperatorsSvc
+	searchAndGetSvc
+	threadParticipantsSvc
+	checkNewMailSvc
+	syncMailboxSvc
+	calendarMessageSvc
+}
+
+//go:generate moq -rm -pkg tool_test -out moq_calendar_svc_test.go -skip-ensure . calendarSvc:calendarSvcMock
+type calendarSvc interface {
+	ListEvents(ctx context.Context, calendarID string, timeMin, timeMax time.Time, Q string, maxResults int64) (*calendar.Events, error)
+	CreateEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error)
+	ImportEvent(ctx context.Context, calendarID string, event *calendar.Event) (*calendar.Event, error)
+}
+
+//go:generate moq -rm -pkg tool_test -out moq_drive_svc_test.go -skip-ensure . driveSvc:driveSvcMock
+type driveSvc interface {
+	GetDriveFile(ctx context.Context, fileID string) (*drive.File, error)
+	ExportDriveFile(ctx context.Context, fileID, mimeType string, maxBytes int64) ([]byte, error)
+	DownloadDriveFile(ctx context.Context, fileID string, maxBytes int64) ([]byte, error)
+}
+
+//go:generate moq -rm -pkg tool_test -out moq_converter_test.go -skip-ensure . converter:converterMock
+type converter interface {
+	htmlConverter
+	attachmentConverter
+}
+
+// Option configures optional server features.
+type Option func(*options)
+
+type options struct {
+	exportDir          string
+	filesDir           string
+	savedSearches      savedSearchStore
+	watermarks         watermarkStore
+	timezone           *time.Location
+	maxAttachmentBytes int64
+	maxPDFPages        int
+	filter             ContentFilter
+	conversionCache    int
+	messageWorkers     int
+	mailboxSync        mailboxSyncer
+	mailboxEvents      *MailboxEvents
+	calendar           calendarSvc
+	drive              driveSvc
+	accounts           *Accounts
+	mode               Mode
+	authURL            func(account string) string
+	clearCredentials   func(account string) error
+	authStatus         func(account string) AuthStatusResponse
+	consentURL         func(account string, scopes []string) string
+	callLogger         *log.Logger
+}
+
+// WithExportDir sets the directory export tools are allowed to write into.
+func WithExportDir(dir string) Option {
+	return func(o *options) {
+		o.exportDir = dir
+	}
+}
+
+// WithFilesDir sets the directory attachments may be saved into.
+func WithFilesDir(dir string) Option {
+	return func(o *options) {
+		o.filesDir = dir
+	}
+}
+
+// WithSavedSearches sets the store backing saved search tools.
+// Without it saved searches live in memory for the lifetime of the server.
+func WithSavedSearches(store *savedsearch.Store) Option {
+	return func(o *options) {
+		o.savedSearches = store
+	}
+}
+
+// WithWatermarks sets the store keeping check_new_mail positions.
+// Without it watermarks live in memory for the lifetime of the server.
+func WithWatermarks(store *watermark.Store) Option {
+	return func(o *options) {
+		o.watermarks = store
+	}
+}
+
+// WithTimezone sets the timezone calendar dates in search input are resolved in.
+// Without it the system local timezone is used.
+func WithTimezone(loc *time.Location) Option {
+	return func(o *options) {
+		o.timezone = loc
+	}
+}
+
+// WithMaxAttachmentBytes sets the size above which preview_attachments refuses to download attachments.
+// Without it attachment sizes are not limited.
+func WithMaxAttachmentBytes(n int64) Option {
+	return func(o *options) {
+		o.maxAttachmentBytes = n
+	}
+}
+
+// WithMaxPDFPages sets how many PDF pages preview_attachments extracts per call.
+// Without it whole documents are extracted.
+func WithMaxPDFPages(n int) Option {
+	return func(o *options) {
+		o.maxPDFPages = n
+	}
+}
+
+// WithUntrustedContentGuard wraps message bodies and attachment content in delimiters marking
+// them as untrusted data and neutralizes instruction-like phrases in them.
+func WithUntrustedContentGuard(enabled bool) Option {
+	return func(o *options) {
+		o.filter.Guard = enabled
+	}
+}
+
+// WithRedactor masks personal data in message bodies, snippets and attachment content.
+// Without it content is returned as is.
+func WithRedactor(r *redact.Redactor) Option {
+	return func(o *options) {
+		o.filter.Redactor = r
+	}
+}
+
+// WithConversionCache keeps up to entries HTML to markdown conversions in memory, so fetching a
+// message again doesn't convert its body again. Without it every fetch converts.
+func WithConversionCache(entries int) Option {
+	return func(o *options) {
+		o.conversionCache = entries
+	}
+}
+
+// WithMessageWorkers sets how many messages get_messages converts at once.
+// Without it defaultMessageWorkers are used.
+func WithMessageWorkers(n int) Option {
+	return func(o *options) {
+		o.messageWorkers = n
+	}
+}
+
+// WithMailboxSync sets the engine sync_mailbox runs, which may also sync in the background.
+// Without it sync_mailbox keeps a snapshot in memory for the lifetime of the server.
+func WithMailboxSync(syncer mailboxSyncer) Option {
+	return func(o *options) {
+		o.mailboxSync = syncer
+	}
+}
+
+// WithMailboxEvents serves the mailbox_changes resource, updated for subscribed sessions with
+// every change events publishes. Without it the server has no resources.
+func WithMailboxEvents(events *MailboxEvents) Option {
+	return func(o *options) {
+		o.mailboxEvents = events
+	}
+}
+
+// WithCalendar adds the list_events and create_event tools backed by cal, which needs the
+// calendar.events scope. Without it the server has no calendar tools.
+func WithCalendar(cal calendarSvc) Option {
+	return func(o *options) {
+		o.calendar = cal
+	}
+}
+
+// WithDrive adds the preview_drive_files tool backed by drv, which needs the drive.readonly
+// scope. Without it the server has no Drive tools.
+func WithDrive(drv driveSvc) Option {
+	return func(o *options) {
+		o.drive = drv
+	}
+}
+
+// WithAccounts routes the calls of tools, including Calendar and Drive tools, to the account
+// selected by their account parameter and adds the list_accounts tool. The svc of the server
+// then only backs sync_mailbox when WithMailboxSync isn't set.
+func WithAccounts(accounts *Accounts) Option {
+	return func(o *options) {
+		o.accounts = accounts
+	}
+}
+
+// WithMode registers only the tools mode permits, e.g. no label or thread changes with
+// ModeReadonly. Without it all tools are registered.
+func WithMode(mode Mode) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// WithReauthorization prompts the user through MCP elicitation to authorize the server again at
+// authURL(account) when a tool call fails for an expired or revoked token, and retries the call
+// once they accept; account is empty for the default account. Clients without elicitation get
+// the URL as auth_url of the ErrorInfo. Without it such calls just fail.
+func WithReauthorization(authURL func(account string) string) Option {
+	return func(o *options) {
+		o.authURL = authURL
+	}
+}
+
+// WithIncrementalConsent lets the server be authorized with read-only scopes at first: when a
+// tool changing labels, threads or events fails for lack of its scope, the user is prompted like
+// with WithReauthorization to grant it at consentURL(account, scopes), and the call is retried.
+// Without it such calls just fail.
+func WithIncrementalConsent(consentURL func(account string, scopes []string) string) Option {
+	return func(o *options) {
+		o.consentURL = consentURL
+	}
+}
+
+// WithClearCredentials adds the clear_credentials tool, which logs the server out of the account
+// of the call by passing it to clear; account is empty for the default account. Without it the
+// server has no logout tool.
+func WithClearCredentials(clear func(account string) error) Option {
+	return func(o *options) {
+		o.clearCredentials = clear
+	}
+}
+
+// WithAuthStatus adds the auth_status tool, which reports status(account) for the account of the
+// call; account is empty for the default account. Without it the server has no such tool.
+func WithAuthStatus(status func(account string) AuthStatusResponse) Option {
+	return func(o *options) {
+		o.authStatus = status
+	}
+}
+
+// WithCallLog writes a line per MCP request to logger: the method, tool, account and argument
+// names of tool calls, the outcome and the duration, never argument values or content. Without
+// it requests are not logged.
+func WithCallLog(logger *log.Logger) Option {
+	return func(o *options) {
+		o.callLogger = logger
+	}
+}
+
+// NewServer creates an MCP server with Gmail tools.
+func NewServer(svc gmailSvc, cnv converter, opts ...Option) *mcp.Server {
+	o := newOptions(svc, opts)
+	svc = o.route(svc)
+	operators := NewSearchOperators(svc)
+	serverOpts := &mcp.ServerOptions{
+		CompletionHandler: operators.Complete,
+	}
+	if o.mailboxEvents != nil {
+		serverOpts.SubscribeHandler = o.mailboxEvents.subscribe
+		serverOpts.UnsubscribeHandler = o.mailboxEvents.unsubscribe
+	}
+	server := mcp.NewServer(&mcp.Implementation{Name: "gmail-helper", Version: "v1.0.0"}, serverOpts)
+	addTools(server, svc, cnv, operators, o)
+	return server
+}
+
+// AddTools adds the Gmail tools to server, for programs embedding them in their own MCP server.
+// Completion of search operators and subscriptions to the WithMailboxEvents resource need
+// handlers of the server options, which only NewServer sets.
+func AddTools(server *mcp.Server, svc gmailSvc, cnv converter, opts ...Option) {
+	o := newOptions(svc, opts)
+	svc = o.route(svc)
+	addTools(server, svc, cnv, NewSearchOperators(svc), o)
+}
+
+// newOptions applies opts over the defaults: in-memory saved searches, watermarks and mailbox
+// snapshot, the local timezone and ModeFull.
+func newOptions(svc gmailSvc, opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.savedSearches == nil {
+		store, _ := savedsearch.NewStore("")
+		o.savedSearches = store
+	}
+	if o.watermarks == nil {
+		store, _ := watermark.NewStore("")
+		o.watermarks = store
+	}
+	if o.mailboxSync == nil {
+		store, _ := mailsync.NewStore("")
+		o.mailboxSync = mailsync.NewEngine(svc, store, 0)
+	}
+	if o.timezone == nil {
+		o.timezone = time.Local
+	}
+	if o.mode == "" {
+		o.mode = ModeFull
+	}
+	return o
+}
+
+// route returns the accounts tools call instead of svc, if any, and routes Calendar and Drive
+// calls through them too.
+func (o *options) route(svc gmailSvc) gmailSvc {
+	if o.accounts == nil {
+		return svc
+	}
+	if o.calendar != nil {
+		o.calendar = o.accounts
+	}
+	if o.drive != nil {
+		o.drive = o.accounts
+	}
+	return o.accounts
+}
+
+func addTools(server *mcp.Server, svc gmailSvc, cnv converter, operators *SearchOperators, o options) {
+	if o.conversionCache > 0 {
+		cnv = newCachedConverter(cnv, o.conversionCache)
+	}
+	if o.mailboxEvents != nil {
+		o.mailboxEvents.register(server)
+	}
+	if o.callLogger != nil {
+		server.AddReceivingMiddleware(callLog{logger: o.callLogger}.middleware)
+	}
+	if o.authURL != nil || o.consentURL != nil {
+		server.AddReceivingMiddleware(reauthorizer{authURL: o.authURL, consentURL: o.consentURL}.middleware)
+	}
+	server.AddReceivingMiddleware(errorMeta)
+	addPrompts(server)
+	if o.accounts != nil {
+		server.AddReceivingMiddleware(o.accounts.middleware)
+		addTool(server, &mcp.Tool{
+			Name:        "list_accounts",
+			Description: "List the Gmail accounts the account parameter of other tools selects, with their addresses",
+		}, o.accounts.ListAccounts)
+	}
+
+	addTool(server, &mcp.Tool{
+		Name:        "search_messages",
+		Description: "Search Gmail messages using Gmail search syntax, structured fields (from, to, subject, after, before, range, label, has_attachment, is_unread) or both",
+	}, NewSearchMessages(svc, o.timezone).SearchMessages)
+
+	addTool(server, &mcp.Tool{
+		Name:        "get_messages",
+		Description: "Get full message content for specified message IDs",
+	}, NewGetMessages(svc, cnv, o.filter, o.messageWorkers).GetMessages)
+
+	addTool(server, &mcp.Tool{
+		Name:        "search_and_get",
+		Description: "Search Gmail and return full contents of matching messages in one call, within a character budget",
+	}, NewSearchAndGet(svc, cnv, o.filter).SearchAndGet)
+
+	addTool(server, &mcp.Tool{
+		Name:        "get_message_body",
+		Description: "Read a long message body in chunks by character offset",
+	}, NewGetMessageBody(svc, cnv, o.filter).GetMessageBody)
+
+	addTool(server, &mcp.Tool{
+		Name:        "check_new_mail",
+		Description: "Return only inbox messages newer than a stored watermark (history ID or timestamp) and advance it, for polling without re-reading old mail",
+	}, NewCheckNewMail(svc, o.watermarks).CheckNewMail)
+
+	addTool(server, &mcp.Tool{
+		Name:        "sync_mailbox",
+		Description: "Bring the local mailbox snapshot up to date from Gmail history, listing the mailbox on the first run or when history expired",
+	}, NewSyncMailbox(o.mailboxSync).SyncMailbox)
+
+	addTool(server, &mcp.Tool{
+		Name:        "preview_attachments",
+		Description: "Extract text content from attachments (PDFs, text files, etc); omit attachment_ids for all attachments of the message",
+	}, NewPreviewAttachments(svc, cnv, o.maxAttachmentBytes, o.maxPDFPages, o.filter).PreviewAttachments)
+
+	addTool(server, &mcp.Tool{
+		Name:        "count_messages",
+		Description: "Estimate the number of messages matching a Gmail search query without fetching them",
+	}, NewCountMessages(svc).CountMessages)
+
+	addTool(server, &mcp.Tool{
+		Name:        "browse_label",
+		Description: "List the latest messages of a Gmail label by label ID with pagination",
+	}, NewBrowseLabel(svc).BrowseLabel)
+
+	if o.mode.Allows(ModeModify) {
+		addModifyTools(server, svc)
+	}
+
+	addTool(server, &mcp.Tool{
+		Name:        "thread_participants",
+		Description: "List deduplicated thread participants with roles (sender/recipient/cc) and message counts",
+	}, NewThreadParticipants(svc).ThreadParticipants)
+
+	addTool(server, &mcp.Tool{
+		Name:        "export_thread_markdown",
+		Description: "Render a whole thread as a single markdown document, optionally saving it to the export directory",
+	}, NewExportThread(svc, cnv, o.exportDir, o.filter.Redactor).ExportThreadMarkdown)
+
+	addTool(server, &mcp.Tool{
+		Name:        "export_messages_mbox",
+		Description: "Download raw messages and write them as an mbox file into the export directory",
+	}, NewExportMbox(svc, o.exportDir).ExportMessagesMbox)
+
+	addTool(server, &mcp.Tool{
+		Name:        "save_attachment",
+		Description: "Save an attachment to the server files directory and return the saved path",
+	}, NewSaveAttachment(svc, o.filesDir).SaveAttachment)
+
+	addTool(server, &mcp.Tool{
+		Name:        "sender_statistics",
+		Description: "Aggregate messages matching a query by sender: count, total size and date range",
+	}, NewSenderStatistics(svc).SenderStatistics)
+
+	addTool(server, &mcp.Tool{
+		Name:        "inbox_summary",
+		Description: "Inbox briefing: unread/total counts per system label and the top unread senders",
+	}, NewInboxSummary(svc).InboxSummary)
+
+	addTool(server, &mcp.Tool{
+		Name:        "find_attachments",
+		Description: "Find attachments across messages by search filters, filename and MIME type",
+	}, NewFindAttachments(svc).FindAttachments)
+
+	addTool(server, &mcp.Tool{
+		Name:        "frequent_correspondents",
+		Description: "Rank people you exchange mail with by sent/received counts with last-contact dates",
+	}, NewFrequentCorrespondents(svc).FrequentCorrespondents)
+
+	addTool(server, &mcp.Tool{
+		Name:        "awaiting_reply",
+		Description: "Find recently sent messages whose threads have no later inbound reply",
+	}, NewAwaitingReply(svc).AwaitingReply)
+
+	addTool(server, &mcp.Tool{
+		Name:        "extract_links",
+		Description: "List hyperlinks of messages with anchor text, classified as unsubscribe, tracking, document or login",
+	}, NewExtractLinks(svc).ExtractLinks)
+
+	addTool(server, &mcp.Tool{
+		Name:        "check_message_auth",
+		Description: "Report SPF/DKIM/DMARC outcomes and delivery hops parsed from message headers",
+	}, NewCheckMessageAuth(svc).CheckMessageAuth)
+
+	addTool(server, &mcp.Tool{
+		Name:        "analyze_phishing",
+		Description: "Score a message for phishing indicators: lookalike domains, display name and link mismatches, urgent language",
+	}, NewAnalyzePhishing(svc).AnalyzePhishing)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_search_operators",
+		Description: "List supported Gmail search operators and label names usable in search_messages queries",
+	}, operators.ListSearchOperators)
+
+	saved := NewSavedSearches(svc, o.savedSearches, o.timezone)
+	addTool(server, &mcp.Tool{
+		Name:        "save_search",
+		Description: "Save a Gmail search query under a name for reuse",
+	}, saved.SaveSearch)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_saved_searches",
+		Description: "List saved Gmail search queries",
+	}, saved.ListSavedSearches)
+
+	addTool(server, &mcp.Tool{
+		Name:        "run_saved_search",
+		Description: "Run a saved Gmail search query by name",
+	}, saved.RunSavedSearch)
+
+	addTool(server, &mcp.Tool{
+		Name:        "delete_saved_search",
+		Description: "Delete a saved Gmail search query by name",
+	}, saved.DeleteSavedSearch)
+
+	if o.clearCredentials != nil {
+		addTool(server, &mcp.Tool{
+			Name:        "clear_credentials",
+			Description: "Log out: wipe the in-memory and stored OAuth token of the account, e.g. before switching mailboxes on a shared machine; tools fail until it is authorized again",
+		}, NewClearCredentials(o.clearCredentials).ClearCredentials)
+	}
+
+	if o.authStatus != nil {
+		addTool(server, &mcp.Tool{
+			Name:        "auth_status",
+			Description: "Tell whether the server is authorized for the Gmail account and, if not, the URL the user has to open to authorize it",
+		}, NewAuthStatus(o.authStatus).AuthStatus)
+	}
+
+	if o.calendar != nil {
+		calendarTools := NewCalendar(svc, o.calendar, o.timezone)
+		addTool(server, &mcp.Tool{
+			Name:        "list_events",
+			Description: "List upcoming Google Calendar events of the next days, ordered by start",
+		}, calendarTools.ListEvents)
+
+		if o.mode.Allows(ModeModify) {
+			addTool(server, &mcp.Tool{
+				Name:        "create_event",
+				Description: "Create a Google Calendar event from email content (e.g. a flight or booking confirmation), or import the ICS invitation of a message",
+			}, calendarTools.CreateEvent)
+		}
+	}
+
+	if o.drive != nil {
+		addTool(server, &mcp.Tool{
+			Name:        "preview_drive_files",
+			Description: "Extract text from Google Drive files linked in a message or given by URL: Docs as markdown, Sheets as a table or CSV, Slides, PDFs and Office files",
+		}, NewPreviewDriveFiles(svc, o.drive, cnv, o.maxAttachmentBytes, o.maxPDFPages, o.filter).PreviewDriveFiles)
+	}
+}
+
+// addModifyTools adds the tools changing labels and threads, which need ModeModify.
+func addModifyTools(server *mcp.Server, svc gmailSvc) {
+	labels := NewManageLabels(svc)
+	addTool(server, &mcp.Tool{
+		Name:        "create_label",
+		Description: "Create a Gmail label, nested paths like Clients/Acme create missing parents",
+	}, labels.CreateLabel)
+
+	addTool(server, &mcp.Tool{
+		Name:        "rename_label",
+		Description: "Rename a Gmail user label together with its nested labels",
+	}, labels.RenameLabel)
+
+	addTool(server, &mcp.Tool{
+		Name:        "delete_label",
+		Description: "Delete a Gmail user label, messages are kept",
+	}, labels.DeleteLabel)
+
+	addTool(server, &mcp.Tool{
+		Name:        "mute_thread",
+		Description: "Mute a thread: archive it and tag it with the Muted label. Unlike Gmail's mute, new replies will still reach the inbox",
+	}, NewMuteThread(svc).MuteThread)
+}
@@ -0,0 +1,120 @@
+package tool_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+type echoRequest struct {
+	Text string `json:"text"`
+}
+
+func TestAddTools(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "host", Version: "v0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo", Description: "Echo text"},
+		func(_ context.Context, _ *mcp.CallToolRequest, in echoRequest) (*mcp.CallToolResult, echoRequest, error) {
+			return nil, in, nil
+		})
+	tool.AddTools(server, &gmailSvcMock{}, &converterMock{}, tool.WithCalendar(nil))
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.ListTools(ctx, nil)
+	require.NoError(t, err)
+	names := make([]string, 0, len(result.Tools))
+	for _, listed := range result.Tools {
+		names = append(names, listed.Name)
+	}
+	assert.Contains(t, names, "echo", "tools of the host server are kept")
+	assert.Contains(t, names, "search_messages")
+	assert.Contains(t, names, "preview_attachments")
+	assert.NotContains(t, names, "list_events", "optional tools need their service")
+}
+
+func TestWithModeReadonly(t *testing.T) {
+	server := tool.NewServer(&gmailSvcMock{}, &converterMock{}, tool.WithMode(tool.ModeReadonly), tool.WithCalendar(&calendarSvcMock{}))
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.ListTools(ctx, nil)
+	require.NoError(t, err)
+	names := make([]string, 0, len(result.Tools))
+	for _, listed := range result.Tools {
+		names = append(names, listed.Name)
+	}
+	assert.Contains(t, names, "search_messages")
+	assert.Contains(t, names, "list_events")
+	for _, name := range []string{"create_label", "rename_label", "delete_label", "mute_thread", "create_event"} {
+		assert.NotContains(t, names, name)
+	}
+}
+
+func TestToolAnnotations(t *testing.T) {
+	server := tool.NewServer(&gmailSvcMock{}, &converterMock{}, tool.WithMode(tool.ModeModify))
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	result, err := clientSession.ListTools(ctx, nil)
+	require.NoError(t, err)
+	tools := make(map[string]*mcp.ToolAnnotations, len(result.Tools))
+	for _, listed := range result.Tools {
+		require.NotNil(t, listed.Annotations, listed.Name)
+		tools[listed.Name] = listed.Annotations
+	}
+	assert.True(t, tools["search_messages"].ReadOnlyHint)
+	assert.False(t, tools["save_search"].ReadOnlyHint, "tools writing server stores are not read-only")
+	assert.False(t, *tools["save_search"].DestructiveHint)
+	assert.False(t, tools["mute_thread"].ReadOnlyHint)
+	assert.False(t, *tools["mute_thread"].DestructiveHint)
+	assert.True(t, *tools["delete_label"].DestructiveHint)
+}
+
+func TestModeForScopes(t *testing.T) {
+	assert.Equal(t, tool.ModeReadonly, tool.ModeForScopes([]string{gmail.GmailReadonlyScope}))
+	assert.Equal(t, tool.ModeModify, tool.ModeForScopes([]string{gmail.GmailReadonlyScope, gmail.GmailModifyScope}))
+	assert.Equal(t, tool.ModeFull, tool.ModeForScopes([]string{gmail.MailGoogleComScope}))
+
+	mode, err := tool.ParseMode("modify")
+	require.NoError(t, err)
+	assert.True(t, mode.Allows(tool.ModeReadonly))
+	assert.False(t, mode.Allows(tool.ModeFull))
+	_, err = tool.ParseMode("send")
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/mailsync"
+)
+
+// SyncMailboxRequest selects the kind of sync pass.
+type SyncMailboxRequest struct {
+	Full bool `json:"full,omitempty" jsonschema:"discard the snapshot and list the mailbox again instead of applying changes since the last sync"`
+}
+
+// SyncMailboxResponse reports what the sync pass changed in the local snapshot.
+type SyncMailboxResponse struct {
+	Mode         string `json:"mode" jsonschema:"full when the mailbox was listed, incremental when changes were read from history"`
+	Added        int    `json:"added" jsonschema:"messages added to the snapshot"`
+	Deleted      int    `json:"deleted" jsonschema:"messages deleted from the mailbox"`
+	LabelChanges int    `json:"label_changes" jsonschema:"messages whose labels changed"`
+	Messages     int    `json:"messages" jsonschema:"messages in the snapshot"`
+	HistoryID    string `json:"history_id" jsonschema:"Gmail history ID the snapshot is current at"`
+	SyncedAt     string `json:"synced_at" jsonschema:"time of the sync, RFC3339"`
+}
+
+type syncMailboxSvc interface {
+	GetProfile(ctx context.Context) (*gmail.Profile, error)
+	ListMessages(ctx context.Context, Q, pageToken string, maxResults int64) (*gmail.ListMessagesResponse, error)
+	ListMailboxHistory(ctx context.Context, startHistoryID uint64, pageToken string) (*gmail.ListHistoryResponse, error)
+	GetMessageMetadata(ctx context.Context, msgID string) (*gmail.Message, error)
+	GetMessagesMetadata(ctx context.Context, msgIDs []string) ([]*gmail.Message, error)
+}
+
+type mailboxSyncer interface {
+	Sync(ctx context.Context, full bool) (mailsync.Result, error)
+}
+
+// NewSyncMailbox creates a new SyncMailbox tool.
+func NewSyncMailbox(syncer mailboxSyncer) *SyncMailbox {
+	return &SyncMailbox{
+		syncer: syncer,
+	}
+}
+
+// SyncMailbox brings the local mailbox snapshot up to date.
+type SyncMailbox struct {
+	syncer mailboxSyncer
+}
+
+// SyncMailbox runs a sync pass and reports its changes.
+func (t *SyncMailbox) SyncMailbox(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input SyncMailboxRequest,
+) (*mcp.CallToolResult, SyncMailboxResponse, error) {
+	result, err := t.syncer.Sync(ctx, input.Full)
+	if err != nil {
+		return nil, SyncMailboxResponse{}, fmt.Errorf("syncer.Sync failed: %w", err)
+	}
+
+	mode := "incremental"
+	if result.Full {
+		mode = "full"
+	}
+	return nil, SyncMailboxResponse{
+		Mode:         mode,
+		Added:        result.Added,
+		Deleted:      result.Deleted,
+		LabelChanges: result.LabelChanges,
+		Messages:     result.Messages,
+		HistoryID:    strconv.FormatUint(result.HistoryID, 10),
+		SyncedAt:     result.SyncedAt.Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestSyncMailbox(t *testing.T) {
+	gmailSvc := withBatchGets(&gmailSvcMock{
+		GetProfileFunc: func(context.Context) (*gmail.Profile, error) {
+			return &gmail.Profile{HistoryId: 100}, nil
+		},
+		ListMessagesFunc: func(_ context.Context, _, _ string, _ int64) (*gmail.ListMessagesResponse, error) {
+			return &gmail.ListMessagesResponse{Messages: []*gmail.Message{{Id: "m1"}, {Id: "m2"}}}, nil
+		},
+		GetMessageMetadataFunc: func(_ context.Context, msgID string) (*gmail.Message, error) {
+			return &gmail.Message{Id: msgID, ThreadId: "t1", LabelIds: []string{"INBOX"}}, nil
+		},
+		ListMailboxHistoryFunc: func(_ context.Context, start uint64, _ string) (*gmail.ListHistoryResponse, error) {
+			assert.Equal(t, uint64(100), start)
+			return &gmail.ListHistoryResponse{
+				HistoryId: 120,
+				History: []*gmail.History{
+					{Id: 110, MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "m3"}}}},
+					{Id: 115, MessagesDeleted: []*gmail.HistoryMessageDeleted{{Message: &gmail.Message{Id: "m1"}}}},
+				},
+			}, nil
+		},
+	})
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	sync := func() tool.SyncMailboxResponse {
+		result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "sync_mailbox",
+			Arguments: tool.SyncMailboxRequest{},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, "Result should not indicate error")
+
+		var response tool.SyncMailboxResponse
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+		return response
+	}
+
+	first := sync()
+	assert.Equal(t, "full", first.Mode)
+	assert.Equal(t, 2, first.Added)
+	assert.Equal(t, 2, first.Messages)
+	assert.Equal(t, "100", first.HistoryID)
+
+	second := sync()
+	assert.Equal(t, "incremental", second.Mode)
+	assert.Equal(t, 1, second.Added)
+	assert.Equal(t, 1, second.Deleted)
+	assert.Equal(t, 2, second.Messages)
+	assert.Equal(t, "120", second.HistoryID)
+}
@@ -0,0 +1,133 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	roleSender    = "sender"
+	roleRecipient = "recipient"
+	roleCC        = "cc"
+)
+
+// ThreadParticipantsRequest contains the thread to inspect.
+type ThreadParticipantsRequest struct {
+	ThreadID string `json:"thread_id" jsonschema:"ID of the thread"`
+}
+
+// ThreadParticipantsResponse contains deduplicated thread participants.
+type ThreadParticipantsResponse struct {
+	ThreadID     string        `json:"thread_id" jsonschema:"thread ID"`
+	MessageCount int           `json:"message_count" jsonschema:"number of messages in the thread"`
+	Participants []Participant `json:"participants" jsonschema:"participants in order of first appearance"`
+}
+
+// Participant describes how an address took part in a thread.
+type Participant struct {
+	Address        EmailAddress `json:"address" jsonschema:"participant information"`
+	Roles          []string     `json:"roles" jsonschema:"roles held in the thread: sender, recipient, cc"`
+	SentCount      int          `json:"sent_count" jsonschema:"messages sent by the participant"`
+	RecipientCount int          `json:"recipient_count" jsonschema:"messages addressed to the participant in To"`
+	CCCount        int          `json:"cc_count" jsonschema:"messages the participant was copied on"`
+	IsSelf         bool         `json:"is_self,omitempty" jsonschema:"true for the mailbox owner, usually left out of a reply-all"`
+}
+
+type threadParticipantsSvc interface {
+	GetThreadMetadata(ctx context.Context, threadID string) (*gmail.Thread, error)
+}
+
+// NewThreadParticipants creates a new ThreadParticipants tool.
+func NewThreadParticipants(svc threadParticipantsSvc) *ThreadParticipants {
+	return &ThreadParticipants{
+		svc: svc,
+	}
+}
+
+// ThreadParticipants lists who takes part in a thread.
+type ThreadParticipants struct {
+	svc threadParticipantsSvc
+}
+
+// ThreadParticipants returns participants of a thread with roles and message counts.
+func (t *ThreadParticipants) ThreadParticipants(
+	ctx context.Context,
+	_ *mcp.CallToolRequest,
+	input ThreadParticipantsRequest,
+) (*mcp.CallToolResult, ThreadParticipantsResponse, error) {
+	if input.ThreadID == "" {
+		return nil, ThreadParticipantsResponse{}, errors.New("thread_id is required")
+	}
+
+	thread, err := t.svc.GetThreadMetadata(ctx, input.ThreadID)
+	if err != nil {
+		return nil, ThreadParticipantsResponse{}, fmt.Errorf("svc.GetThreadMetadata failed: %w", err)
+	}
+
+	var order []string
+	byAddress := make(map[string]*Participant)
+	participant := func(addr EmailAddress) *Participant {
+		key := strings.ToLower(addr.Email)
+		p, ok := byAddress[key]
+		if !ok {
+			p = &Participant{Address: addr}
+			byAddress[key] = p
+			order = append(order, key)
+		}
+		if p.Address.Name == "" {
+			p.Address.Name = addr.Name
+		}
+		return p
+	}
+
+	for _, msg := range thread.Messages {
+		summary := extractMessageSummary(msg)
+
+		if summary.From.Email != "" {
+			sender := participant(summary.From)
+			sender.SentCount++
+			if slices.Contains(msg.LabelIds, sentLabelID) {
+				sender.IsSelf = true
+			}
+		}
+		for _, addr := range summary.To {
+			participant(addr).RecipientCount++
+		}
+		for _, addr := range summary.CC {
+			participant(addr).CCCount++
+		}
+	}
+
+	participants := make([]Participant, 0, len(order))
+	for _, key := range order {
+		p := byAddress[key]
+		p.Roles = participantRoles(p)
+		participants = append(participants, *p)
+	}
+
+	return nil, ThreadParticipantsResponse{
+		ThreadID:     input.ThreadID,
+		MessageCount: len(thread.Messages),
+		Participants: participants,
+	}, nil
+}
+
+func participantRoles(p *Participant) []string {
+	roles := []string{}
+	if p.SentCount > 0 {
+		roles = append(roles, roleSender)
+	}
+	if p.RecipientCount > 0 {
+		roles = append(roles, roleRecipient)
+	}
+	if p.CCCount > 0 {
+		roles = append(roles, roleCC)
+	}
+	return roles
+}
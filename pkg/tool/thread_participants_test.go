@@ -0,0 +1,134 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/hal9000y/gmail-mcp/pkg/tool"
+)
+
+func TestThreadParticipants(t *testing.T) {
+	message := func(labels []string, headers ...string) *gmail.Message {
+		msg := &gmail.Message{LabelIds: labels, Payload: &gmail.MessagePart{}}
+		for i := 0; i < len(headers); i += 2 {
+			msg.Payload.Headers = append(msg.Payload.Headers, &gmail.MessagePartHeader{
+				Name:  headers[i],
+				Value: headers[i+1],
+			})
+		}
+		return msg
+	}
+
+	gmailSvc := &gmailSvcMock{
+		GetThreadMetadataFunc: func(_ context.Context, threadID string) (*gmail.Thread, error) {
+			if threadID != "t-1" {
+				return nil, fmt.Errorf("thread not found: %s", threadID)
+			}
+			return &gmail.Thread{Id: threadID, Messages: []*gmail.Message{
+				message([]string{"INBOX"},
+					"From", "Alice <alice@example.com>",
+					"To", "me@example.com",
+					"Cc", "Bob <bob@example.com>"),
+				message([]string{"SENT"},
+					"From", "Me <ME@example.com>",
+					"To", "alice@example.com",
+					"Cc", "bob@example.com, carol@example.com"),
+			}}, nil
+		},
+	}
+
+	cases := []struct {
+		name        string
+		req         tool.ThreadParticipantsRequest
+		expected    tool.ThreadParticipantsResponse
+		expectedErr error
+	}{
+		{
+			name: "participants with roles",
+			req:  tool.ThreadParticipantsRequest{ThreadID: "t-1"},
+			expected: tool.ThreadParticipantsResponse{
+				ThreadID:     "t-1",
+				MessageCount: 2,
+				Participants: []tool.Participant{
+					{
+						Address:        tool.EmailAddress{Name: "Alice", Email: "alice@example.com"},
+						Roles:          []string{"sender", "recipient"},
+						SentCount:      1,
+						RecipientCount: 1,
+					},
+					{
+						Address:        tool.EmailAddress{Name: "Me", Email: "me@example.com"},
+						Roles:          []string{"sender", "recipient"},
+						SentCount:      1,
+						RecipientCount: 1,
+						IsSelf:         true,
+					},
+					{
+						Address: tool.EmailAddress{Name: "Bob", Email: "bob@example.com"},
+						Roles:   []string{"cc"},
+						CCCount: 2,
+					},
+					{
+						Address: tool.EmailAddress{Email: "carol@example.com"},
+						Roles:   []string{"cc"},
+						CCCount: 1,
+					},
+				},
+			},
+		},
+		{
+			name:        "error case",
+			req:         tool.ThreadParticipantsRequest{ThreadID: "t-missing"},
+			expectedErr: fmt.Errorf("thread not found: t-missing"),
+		},
+	}
+
+	server := tool.NewServer(gmailSvc, &converterMock{})
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+	ctx := context.Background()
+
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer clientSession.Close()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clientSession.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "thread_participants",
+				Arguments: tc.req,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.NotEmpty(t, result.Content)
+
+			if tc.expectedErr != nil {
+				require.True(t, result.IsError, "Result should indicate error")
+				errorText := result.Content[0].(*mcp.TextContent).Text
+				assert.Contains(t, errorText, tc.expectedErr.Error())
+				return
+			}
+
+			var response tool.ThreadParticipantsResponse
+			require.NoError(t,
+				json.Unmarshal(
+					[]byte(result.Content[0].(*mcp.TextContent).Text),
+					&response,
+				),
+			)
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package tool
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bytesPerToken is how much text one model token covers on average for English prose and markup.
+const bytesPerToken = 4
+
+// estimateTokens approximates how many model tokens text takes up, so clients can budget context
+// before requesting more content.
+func estimateTokens(text string) int {
+	return (len(text) + bytesPerToken - 1) / bytesPerToken
+}
+
+// tokenBudget limits the estimated tokens of the items a tool returns, for max_response_tokens.
+// A nil budget has no limit.
+type tokenBudget struct {
+	remaining int
+	taken     bool
+}
+
+// newTokenBudget returns a budget of maxTokens, nil when maxTokens is not positive.
+func newTokenBudget(maxTokens int) *tokenBudget {
+	if maxTokens <= 0 {
+		return nil
+	}
+	return &tokenBudget{remaining: maxTokens}
+}
+
+// fits charges the estimated size of item as JSON and reports whether it fits the budget. The
+// first item always fits, so every call makes progress.
+func (b *tokenBudget) fits(item any) bool {
+	if b == nil {
+		return true
+	}
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return true
+	}
+	tokens := estimateTokens(string(encoded))
+	if b.taken && tokens > b.remaining {
+		b.remaining = 0
+		return false
+	}
+	b.taken = true
+	b.remaining -= tokens
+	return true
+}
+
+// encodeCursor builds the opaque continuation cursor of a result cut by max_response_tokens: the
+// page the result came from and the offset of the first item left out.
+func encodeCursor(pageToken string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + ":" + pageToken))
+}
+
+func decodeCursor(cursor string) (pageToken string, offset int, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+	offsetText, pageToken, ok := strings.Cut(string(raw), ":")
+	if offset, err = strconv.Atoi(offsetText); !ok || err != nil || offset < 0 {
+		return "", 0, errors.New("invalid cursor")
+	}
+	return pageToken, offset, nil
+}
+
+// cursorPage returns the page token and offset to list from: the cursor's when given, else
+// pageToken from the start.
+func cursorPage(cursor, pageToken string) (string, int, error) {
+	if cursor == "" {
+		return pageToken, 0, nil
+	}
+	return decodeCursor(cursor)
+}
+
+// cursorOffset returns the offset of a cursor into a list given by the request, zero without one.
+func cursorOffset(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	_, offset, err := decodeCursor(cursor)
+	return offset, err
+}
@@ -0,0 +1,103 @@
+// Package watermark stores named new-mail polling positions with file persistence.
+package watermark
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hal9000y/gmail-mcp/internal/jsonfile"
+)
+
+// Mark is the position up to which a poller has seen the mailbox.
+type Mark struct {
+	Name      string `json:"name"`
+	HistoryID uint64 `json:"history_id,omitempty"`
+	// Timestamp is the internal date of the newest seen message in unix milliseconds.
+	Timestamp int64 `json:"timestamp,omitempty"`
+}
+
+// Store keeps marks in memory and mirrors every change to a JSON file.
+type Store struct {
+	mu          sync.RWMutex
+	persistPath string
+	marks       map[string]Mark
+}
+
+// NewStore creates a Store, loading marks from disk if path provided.
+// An empty path keeps marks in memory only.
+func NewStore(persistPath string) (*Store, error) {
+	s := &Store{
+		persistPath: persistPath,
+		marks:       make(map[string]Mark),
+	}
+	if persistPath == "" {
+		return s, nil
+	}
+
+	var marks []Mark
+	if err := jsonfile.Load(persistPath, &marks); err != nil {
+		return nil, fmt.Errorf("jsonfile.Load failed: %w", err)
+	}
+	for _, mark := range marks {
+		s.marks[mark.Name] = mark
+	}
+
+	return s, nil
+}
+
+// Get returns the mark with the given name and whether it exists.
+func (s *Store) Get(name string) (Mark, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mark, ok := s.marks[name]
+	return mark, ok
+}
+
+// Set creates or replaces a mark and persists the store.
+func (s *Store) Set(mark Mark) error {
+	mark.Name = strings.TrimSpace(mark.Name)
+	if mark.Name == "" {
+		return errors.New("name is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.marks[mark.Name]
+	s.marks[mark.Name] = mark
+
+	if err := s.persist(); err != nil {
+		if existed {
+			s.marks[mark.Name] = previous
+		} else {
+			delete(s.marks, mark.Name)
+		}
+		return fmt.Errorf("persist failed: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) sorted() []Mark {
+	return slices.SortedFunc(maps.Values(s.marks), func(a, b Mark) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+}
+
+// persist writes the store, replacing its file atomically.
+func (s *Store) persist() error {
+	if s.persistPath == "" {
+		return nil
+	}
+
+	if err := jsonfile.Save(s.persistPath, s.sorted()); err != nil {
+		return fmt.Errorf("jsonfile.Save failed: %w", err)
+	}
+	return nil
+}
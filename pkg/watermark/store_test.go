@@ -0,0 +1,52 @@
+package watermark_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hal9000y/gmail-mcp/pkg/watermark"
+)
+
+func TestStorePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "watermarks.json")
+
+	store, err := watermark.NewStore(path)
+	require.NoError(t, err)
+	_, ok := store.Get("default")
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set(watermark.Mark{Name: " default ", HistoryID: 100, Timestamp: 1735725600000}))
+	require.NoError(t, store.Set(watermark.Mark{Name: "default", HistoryID: 120, Timestamp: 1735729200000}))
+	require.NoError(t, store.Set(watermark.Mark{Name: "alerts", Timestamp: 1735725600000}))
+
+	reloaded, err := watermark.NewStore(path)
+	require.NoError(t, err)
+
+	mark, ok := reloaded.Get("default")
+	require.True(t, ok)
+	assert.Equal(t, watermark.Mark{Name: "default", HistoryID: 120, Timestamp: 1735729200000}, mark)
+
+	mark, ok = reloaded.Get("alerts")
+	require.True(t, ok)
+	assert.Equal(t, watermark.Mark{Name: "alerts", Timestamp: 1735725600000}, mark)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should not be left behind")
+}
+
+func TestStoreErrors(t *testing.T) {
+	store, err := watermark.NewStore("")
+	require.NoError(t, err)
+
+	assert.EqualError(t, store.Set(watermark.Mark{HistoryID: 1}), "name is required")
+
+	path := filepath.Join(t.TempDir(), "broken.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	_, err = watermark.NewStore(path)
+	assert.Error(t, err)
+}
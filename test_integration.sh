@@ -31,4 +31,4 @@ echo "Search query: $GMAIL_SEARCH_QUERY"
 echo "Env file: $ENV_FILE"
 echo ""
 
-go test -v -run TestIntegrationGmailMCP ./internal/tool
+go test -v -run TestIntegrationGmailMCP ./pkg/tool